@@ -0,0 +1,32 @@
+package gomarkdoc
+
+import (
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// SymbolAnchors maps the key of each symbol declared in the package (see
+// lang.Symbol.Key) to the anchor of the header it is documented under when the
+// package is rendered, without the leading "#". Symbols are omitted for
+// formats that don't support anchors.
+func (out *Renderer) SymbolAnchors(pkg *lang.Package) (map[string]string, error) {
+	f := format.Chain(out.format)
+
+	anchors := make(map[string]string)
+	for _, s := range pkg.Symbols() {
+		href, err := out.localHref(f, s.HeaderText)
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasPrefix(href, "#") {
+			continue
+		}
+
+		anchors[s.Key()] = strings.TrimPrefix(href, "#")
+	}
+
+	return anchors, nil
+}
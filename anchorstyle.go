@@ -0,0 +1,144 @@
+package gomarkdoc
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"text/template"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/format/formatcore"
+)
+
+// Styles of anchor generation supported by WithAnchorStyle.
+const (
+	// AnchorsFormat generates anchors the way the renderer the format
+	// targets does. This is the default.
+	AnchorsFormat = "format"
+
+	// AnchorsGitHub generates anchors the way GitHub does, which is also
+	// the default for many other renderers such as Docusaurus and Hugo.
+	AnchorsGitHub = "github"
+
+	// AnchorsAzureDevOps generates anchors the way Azure DevOps does.
+	AnchorsAzureDevOps = "azure-devops"
+
+	// AnchorsExplicit adds an <a id="..."> tag before each header, so that
+	// links work regardless of how the renderer generates anchors.
+	AnchorsExplicit = "explicit"
+)
+
+var anchorStyles = map[string]bool{
+	AnchorsFormat:      true,
+	AnchorsGitHub:      true,
+	AnchorsAzureDevOps: true,
+	AnchorsExplicit:    true,
+}
+
+// AnchorTemplateData is the data passed to templates provided to
+// WithAnchorTemplate.
+type AnchorTemplateData struct {
+	// Text holds the text of the header, which may contain markdown.
+	Text string
+
+	// Plain holds the text of the header with any markdown removed.
+	Plain string
+}
+
+// WithAnchorStyle changes how the anchors that local links point to are
+// generated, for renderers that derive them from the text of headers
+// differently than the renderer the format targets. See the Anchors
+// constants for the supported styles.
+func WithAnchorStyle(style string) RendererOption {
+	return func(renderer *Renderer) error {
+		if !anchorStyles[style] {
+			return fmt.Errorf(`gomarkdoc: invalid anchor style "%s"`, style)
+		}
+
+		renderer.anchorStyle = style
+		return nil
+	}
+}
+
+// WithAnchorTemplate generates the anchors that local links point to using
+// the provided template, which is executed with an AnchorTemplateData and
+// produces the anchor without the leading "#". In addition to the standard
+// functions, templates can use lower, upper, trim, replace (old, new, s) and
+// slug, which generates an anchor the way GitHub does:
+//
+//	{{ .Plain | lower | replace " " "_" }}
+//
+// When combined with the explicit anchor style, the generated anchors are
+// also used for the tags added before each header.
+func WithAnchorTemplate(tmpl string) RendererOption {
+	return func(renderer *Renderer) error {
+		t, err := parseAnchorTemplate(tmpl)
+		if err != nil {
+			return err
+		}
+
+		renderer.anchorTmpl = t
+		return nil
+	}
+}
+
+func parseAnchorTemplate(tmpl string) (*template.Template, error) {
+	t, err := template.New("anchor").Funcs(anchorTemplateFuncs).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: invalid anchor template: %w", err)
+	}
+
+	return t, nil
+}
+
+var anchorTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+	"slug": func(s string) (string, error) {
+		href, err := (&format.GitHubFlavoredMarkdown{}).LocalHref(s)
+		return strings.TrimPrefix(href, "#"), err
+	},
+}
+
+// localHref generates the href of the header with the provided text using
+// the configured anchor style and template, falling back to the LocalHref
+// function of the format.
+func (out *Renderer) localHref(f format.Format, headerText string) (string, error) {
+	if out.anchorTmpl != nil {
+		var b strings.Builder
+		data := AnchorTemplateData{Text: headerText, Plain: formatcore.PlainText(headerText)}
+		if err := out.anchorTmpl.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("gomarkdoc: failed to generate anchor for %q: %w", headerText, err)
+		}
+
+		return "#" + b.String(), nil
+	}
+
+	switch out.anchorStyle {
+	case AnchorsGitHub:
+		return (&format.GitHubFlavoredMarkdown{}).LocalHref(headerText)
+	case AnchorsAzureDevOps:
+		return (&format.AzureDevOpsMarkdown{}).LocalHref(headerText)
+	case AnchorsExplicit:
+		// Formats without anchors of their own get GitHub style anchors
+		href, err := f.LocalHref(headerText)
+		if err != nil || href != "" {
+			return href, err
+		}
+
+		return (&format.GitHubFlavoredMarkdown{}).LocalHref(headerText)
+	default:
+		return f.LocalHref(headerText)
+	}
+}
+
+// explicitAnchor provides the tag to add before a header with the provided
+// href when using the explicit anchor style. The tag is followed by a blank
+// line so that it doesn't swallow the header into an HTML block.
+func explicitAnchor(href string) string {
+	return fmt.Sprintf("<a id=\"%s\"></a>\n\n", html.EscapeString(strings.TrimPrefix(href, "#")))
+}
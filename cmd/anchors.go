@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	// Anchors maps the import path of each documented package to the location
+	// of its documentation. It is written as a JSON sidecar file so that other
+	// tools can link directly to the documentation of a symbol.
+	Anchors map[string]*PackageAnchors
+
+	// PackageAnchors locates the documentation of a single package. File is
+	// relative to the directory containing the anchors file and uses forward
+	// slashes. Symbols maps the key of each symbol (e.g. Type.Method for
+	// methods) to the anchor of its documentation within the file.
+	PackageAnchors struct {
+		File    string            `json:"file"`
+		Symbols map[string]string `json:"symbols"`
+	}
+)
+
+// BuildAnchors collects the anchors of the symbols of each package written to
+// an Output file in the provided Format. Packages written to stdout are
+// omitted. Anchors are only produced for formats that support them.
+func BuildAnchors(specs []*PackageSpec, opts CommandOptions) (Anchors, error) {
+	out, err := resolveRenderer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	anchors := make(Anchors)
+	for _, spec := range specs {
+		if spec.Pkg == nil || spec.OutputFile == "" {
+			continue
+		}
+
+		file, err := manifestKey(opts.Anchors, spec.OutputFile)
+		if err != nil {
+			return nil, err
+		}
+
+		symbols, err := out.SymbolAnchors(spec.Pkg)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to resolve anchors for package %s: %w", spec.Pkg.ImportPath(), err)
+		}
+
+		anchors[spec.Pkg.ImportPath()] = &PackageAnchors{
+			File:    file,
+			Symbols: symbols,
+		}
+	}
+
+	return anchors, nil
+}
+
+// anchorsFormat determines the Format that anchors are resolved for, which is
+// the first markdown Format. The empty string is returned if only data formats
+// are generated.
+func anchorsFormat(opts CommandOptions) string {
+	for _, f := range ResolveFormats(opts) {
+		if !IsDataFormat(f) {
+			return f
+		}
+	}
+
+	return ""
+}
+
+// writeAnchors writes the anchors file for the Output of the Format in opts.
+// It is handled like any other Output file, so it is checked rather than
+// written in Check mode and is recorded in the manifest.
+func writeAnchors(specs []*PackageSpec, opts CommandOptions, manifest, checked Manifest, mismatches *Mismatches, written *[]string) error {
+	anchors, err := BuildAnchors(specs, opts)
+	if err != nil {
+		return err
+	}
+
+	text, err := anchors.JSON()
+	if err != nil {
+		return err
+	}
+
+	if err := emitOutput(opts, manifest, checked, opts.Anchors, text); err != nil && !mismatches.Add(err) {
+		return err
+	}
+
+	if !opts.Check && !opts.Preview {
+		*written = append(*written, opts.Anchors)
+	}
+
+	return nil
+}
+
+// JSON renders the anchors as indented JSON with a trailing newline. Keys are
+// sorted, so the output is stable between runs.
+func (a Anchors) JSON() (string, error) {
+	b, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: failed to render anchors: %w", err)
+	}
+
+	return string(b) + "\n", nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"go/build"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/ag5denis/gomarkdoc/logger"
+)
+
+func TestBuildAnchors(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := build.ImportDir("../testData/lang/function", build.ImportComment)
+	is.NoErr(err)
+
+	pkg, err := lang.NewPackageFromBuild(logger.New(logger.ErrorLevel), buildPkg)
+	is.NoErr(err)
+
+	dir := t.TempDir()
+	specs := []*PackageSpec{
+		{Pkg: pkg, OutputFile: filepath.Join(dir, "function", "README.md")},
+		{Pkg: pkg, OutputFile: ""},
+	}
+
+	anchors, err := BuildAnchors(specs, CommandOptions{
+		Format:  "github",
+		Anchors: filepath.Join(dir, "anchors.json"),
+	})
+	is.NoErr(err)
+
+	is.Equal(len(anchors), 1)
+
+	pkgAnchors := anchors[pkg.ImportPath()]
+	is.Equal(pkgAnchors.File, "function/README.md")
+	is.Equal(pkgAnchors.Symbols["Standalone"], "func-standalone")
+	is.Equal(pkgAnchors.Symbols["Receiver"], "type-receiver")
+	is.Equal(pkgAnchors.Symbols["Receiver.WithReceiver"], "func-receiver-withreceiver")
+}
+
+func TestBuildAnchors_noAnchors(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := build.ImportDir("../testData/lang/function", build.ImportComment)
+	is.NoErr(err)
+
+	pkg, err := lang.NewPackageFromBuild(logger.New(logger.ErrorLevel), buildPkg)
+	is.NoErr(err)
+
+	anchors, err := BuildAnchors([]*PackageSpec{{Pkg: pkg, OutputFile: "README.md"}}, CommandOptions{
+		Format:  "plain",
+		Anchors: "anchors.json",
+	})
+	is.NoErr(err)
+
+	is.Equal(len(anchors[pkg.ImportPath()].Symbols), 0)
+}
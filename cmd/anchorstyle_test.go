@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_anchorStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     CommandOptions
+		contains []string
+	}{
+		{
+			name:     "azure-devops",
+			opts:     CommandOptions{AnchorStyle: "azure-devops"},
+			contains: []string{"(<#type-greeter>)", "(<#func-%28greeter%29-hello>)"},
+		},
+		{
+			name: "explicit",
+			opts: CommandOptions{AnchorStyle: "explicit"},
+			contains: []string{
+				"<a id=\"type-greeter\"></a>\n\n## type Greeter",
+				"<a id=\"func-greeter-hello\"></a>\n\n### func \\(Greeter\\) Hello",
+				"(<#func-greeter-hello>)",
+			},
+		},
+		{
+			name:     "template",
+			opts:     CommandOptions{AnchorTemplate: `{{ .Plain | lower | replace " " "_" }}`},
+			contains: []string{"(<#type_greeter>)", "(<#func_(greeter)_hello>)"},
+		},
+		{
+			name: "explicit template",
+			opts: CommandOptions{AnchorStyle: "explicit", AnchorTemplate: `api-{{ slug .Text }}`},
+			contains: []string{
+				"<a id=\"api-type-greeter\"></a>\n\n## type Greeter",
+				"(<#api-type-greeter>)",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			dir := t.TempDir()
+			writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+			writeConfig(t, filepath.Join(dir, "lib.go"), `// Package lib is a library.
+package lib
+
+// Greeter greets.
+type Greeter struct{}
+
+// Hello says hello.
+func (Greeter) Hello() {}
+`)
+			chdir(t, dir)
+
+			opts := test.opts
+			opts.Output = "README.md"
+			is.NoErr(RunCommand([]string{"."}, opts))
+
+			b, err := os.ReadFile("README.md")
+			is.NoErr(err)
+
+			for _, s := range test.contains {
+				if !strings.Contains(string(b), s) {
+					t.Errorf("expected output to contain %q:\n%s", s, b)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveOverrides_invalidAnchors(t *testing.T) {
+	is := is.New(t)
+
+	_, err := ResolveOverrides(CommandOptions{AnchorStyle: "bogus"})
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), `invalid anchor style "bogus"`))
+
+	_, err = ResolveOverrides(CommandOptions{AnchorTemplate: "{{ .Plain"})
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "invalid anchor template"))
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Modes for the annotations of check failures and lint issues, as selected with
+// the --annotations flag.
+const (
+	// AnnotationsGitHub emits GitHub Actions workflow commands, which show
+	// the problems inline on the diff of a pull request.
+	AnnotationsGitHub = "github"
+
+	// AnnotationsNone disables annotations, even within GitHub Actions.
+	AnnotationsNone = "none"
+)
+
+var annotationModes = []string{AnnotationsGitHub, AnnotationsNone}
+
+// ResolveAnnotations determines the annotations to emit for the run. If no mode
+// is set, GitHub annotations are emitted when running within GitHub Actions.
+func ResolveAnnotations(opts CommandOptions) string {
+	if opts.Annotations != "" {
+		return opts.Annotations
+	}
+
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return AnnotationsGitHub
+	}
+
+	return AnnotationsNone
+}
+
+// ValidateAnnotations checks that the annotations mode is known.
+func ValidateAnnotations(opts CommandOptions) error {
+	if opts.Annotations == "" {
+		return nil
+	}
+
+	for _, mode := range annotationModes {
+		if opts.Annotations == mode {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("gomarkdoc: invalid annotations mode: %s (valid options: %s)", opts.Annotations, strings.Join(annotationModes, ", "))
+}
+
+// writeGitHubAnnotation writes an ::error workflow command for a problem in
+// the file. The line is left out if it is 0.
+func writeGitHubAnnotation(w io.Writer, file string, line int, title, message string) error {
+	props := []string{"file=" + escapeAnnotationProperty(annotationPath(file))}
+	if line > 0 {
+		props = append(props, fmt.Sprintf("line=%d", line))
+	}
+
+	props = append(props, "title="+escapeAnnotationProperty(title))
+
+	_, err := fmt.Fprintf(w, "::error %s::%s\n", strings.Join(props, ","), escapeAnnotationData(message))
+	return err
+}
+
+// writeCheckAnnotations writes a GitHub annotation for each of the mismatches
+// found in Check mode.
+func writeCheckAnnotations(w io.Writer, mismatches Mismatches) error {
+	for _, m := range mismatches.sorted() {
+		if err := writeGitHubAnnotation(w, m.File, 0, "Documentation "+m.Reason, checkMessage(m)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// annotationPath provides the path of the file relative to the working
+// directory, which is the root of the workspace in GitHub Actions, using
+// forward slashes.
+func annotationPath(file string) string {
+	if filepath.IsAbs(file) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, file); err == nil {
+				file = rel
+			}
+		}
+	}
+
+	return filepath.ToSlash(filepath.Clean(file))
+}
+
+var (
+	annotationDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	annotationPropEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeAnnotationData(s string) string {
+	return annotationDataEscaper.Replace(s)
+}
+
+func escapeAnnotationProperty(s string) string {
+	return annotationPropEscaper.Replace(s)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestResolveAnnotations(t *testing.T) {
+	is := is.New(t)
+
+	t.Setenv("GITHUB_ACTIONS", "")
+	is.Equal(ResolveAnnotations(CommandOptions{}), AnnotationsNone)
+	is.Equal(ResolveAnnotations(CommandOptions{Annotations: AnnotationsGitHub}), AnnotationsGitHub)
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	is.Equal(ResolveAnnotations(CommandOptions{}), AnnotationsGitHub)
+	is.Equal(ResolveAnnotations(CommandOptions{Annotations: AnnotationsNone}), AnnotationsNone)
+
+	is.Equal(
+		ValidateAnnotations(CommandOptions{Annotations: "gitlab"}).Error(),
+		"gomarkdoc: invalid annotations mode: gitlab (valid options: github, none)",
+	)
+}
+
+func TestWriteCheckAnnotations(t *testing.T) {
+	is := is.New(t)
+
+	mismatches := Mismatches{
+		{File: filepath.Join("b", "README.md"), Reason: MismatchOutOfDate, Added: 2, Removed: 1},
+		{File: filepath.Join("a", "README.md"), Reason: MismatchMissing},
+	}
+
+	var out bytes.Buffer
+	is.NoErr(writeCheckAnnotations(&out, mismatches))
+	is.Equal(out.String(), ""+
+		"::error file=a/README.md,title=Documentation missing::Documentation is missing: the file doesn't exist. Run gomarkdoc to update it.\n"+
+		"::error file=b/README.md,title=Documentation out of date::Documentation is out of date: regenerating adds 2 line(s) and removes 1 line(s). Run gomarkdoc to update it.\n",
+	)
+}
+
+func TestWriteGitHubAnnotation_escaping(t *testing.T) {
+	is := is.New(t)
+
+	var out bytes.Buffer
+	is.NoErr(writeGitHubAnnotation(&out, "a,b:c.go", 3, "doc-prefix", "100% wrong\nreally"))
+	is.Equal(out.String(), "::error file=a%2Cb%3Ac.go,line=3,title=doc-prefix::100%25 wrong%0Areally\n")
+}
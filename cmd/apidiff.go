@@ -0,0 +1,487 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// APIDiff holds the changes to the exported API of the packages between two
+// revisions.
+type APIDiff struct {
+	// From holds the revision that the changes are relative to.
+	From string
+
+	// To holds the revision that contains the changes.
+	To string
+
+	// Packages holds the changes to each package that changed, sorted by
+	// directory.
+	Packages []*PackageDiff
+}
+
+// PackageDiff holds the changes to the exported API of a single package.
+type PackageDiff struct {
+	// Dir holds the directory of the package, relative to the working
+	// directory.
+	Dir string
+
+	// Added holds the symbols that only exist in the newer revision.
+	Added []*SymbolDiff
+
+	// Removed holds the symbols that only exist in the older revision.
+	Removed []*SymbolDiff
+
+	// Changed holds the symbols whose declaration changed.
+	Changed []*SymbolDiff
+
+	// DocChanged holds the symbols whose doc comment changed. The package
+	// doc comment is reported with the name "package".
+	DocChanged []*SymbolDiff
+}
+
+// Empty reports whether the package has no changes.
+func (d *PackageDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.DocChanged) == 0
+}
+
+// SymbolDiff describes the change to a single exported symbol. Methods are
+// named after their receiver type (e.g. "Type.Method").
+type SymbolDiff struct {
+	Name    string
+	Kind    string
+	OldDecl string
+	NewDecl string
+	OldDoc  string
+	NewDoc  string
+}
+
+// apiSymbol holds the parts of an exported symbol that are compared between
+// revisions.
+type apiSymbol struct {
+	kind string
+	decl string
+	doc  string
+}
+
+// DiffPackageModels compares the exported API of two versions of a package.
+// Either model may be nil if the package only exists in one of the revisions.
+func DiffPackageModels(dir string, old, new *lang.PackageModel) *PackageDiff {
+	d := &PackageDiff{Dir: dir}
+	oldSyms, newSyms := apiSymbols(old), apiSymbols(new)
+
+	if old != nil && new != nil && old.Doc != new.Doc {
+		d.DocChanged = append(d.DocChanged, &SymbolDiff{
+			Name:   "package",
+			Kind:   "package",
+			OldDoc: old.Doc,
+			NewDoc: new.Doc,
+		})
+	}
+
+	for _, name := range sortedSymbolNames(newSyms) {
+		n := newSyms[name]
+		o, ok := oldSyms[name]
+		if !ok {
+			d.Added = append(d.Added, &SymbolDiff{Name: name, Kind: n.kind, NewDecl: n.decl, NewDoc: n.doc})
+			continue
+		}
+
+		if o.decl != n.decl {
+			d.Changed = append(d.Changed, &SymbolDiff{
+				Name:    name,
+				Kind:    n.kind,
+				OldDecl: o.decl,
+				NewDecl: n.decl,
+				OldDoc:  o.doc,
+				NewDoc:  n.doc,
+			})
+		}
+
+		if o.doc != n.doc {
+			d.DocChanged = append(d.DocChanged, &SymbolDiff{
+				Name:    name,
+				Kind:    n.kind,
+				OldDecl: o.decl,
+				NewDecl: n.decl,
+				OldDoc:  o.doc,
+				NewDoc:  n.doc,
+			})
+		}
+	}
+
+	for _, name := range sortedSymbolNames(oldSyms) {
+		if _, ok := newSyms[name]; !ok {
+			o := oldSyms[name]
+			d.Removed = append(d.Removed, &SymbolDiff{Name: name, Kind: o.kind, OldDecl: o.decl, OldDoc: o.doc})
+		}
+	}
+
+	return d
+}
+
+// apiSymbols indexes the exported symbols of the package by name.
+func apiSymbols(m *lang.PackageModel) map[string]apiSymbol {
+	syms := make(map[string]apiSymbol)
+	if m == nil {
+		return syms
+	}
+
+	values := func(kind string, values []*lang.ValueModel) {
+		for _, v := range values {
+			for _, name := range v.Names {
+				syms[name] = apiSymbol{kind, valueSpec(v.Decl, name), v.Doc}
+			}
+		}
+	}
+
+	funcs := func(funcs []*lang.FuncModel) {
+		for _, fn := range funcs {
+			if fn.Receiver == "" {
+				syms[fn.Name] = apiSymbol{"func", fn.Signature, fn.Doc}
+				continue
+			}
+
+			recv := strings.TrimPrefix(fn.Receiver, "*")
+			if i := strings.Index(recv, "["); i >= 0 {
+				recv = recv[:i]
+			}
+
+			syms[recv+"."+fn.Name] = apiSymbol{"method", fn.Signature, fn.Doc}
+		}
+	}
+
+	values("const", m.Consts)
+	values("var", m.Vars)
+	funcs(m.Funcs)
+
+	for _, typ := range m.Types {
+		syms[typ.Name] = apiSymbol{"type", typ.Decl, typ.Doc}
+		values("const", typ.Consts)
+		values("var", typ.Vars)
+		funcs(typ.Funcs)
+		funcs(typ.Methods)
+	}
+
+	return syms
+}
+
+// valueSpec finds the line of a const or var declaration that declares the
+// name, so that adding a name to a block doesn't mark the other names in the
+// block as changed. The whole declaration is returned if there's no such line.
+func valueSpec(decl, name string) string {
+	for _, line := range strings.Split(decl, "\n") {
+		spec := strings.TrimSpace(line)
+		spec = strings.TrimPrefix(spec, "const ")
+		spec = strings.TrimPrefix(spec, "var ")
+
+		names := spec
+		if i := strings.IndexAny(names, "="); i >= 0 {
+			names = names[:i]
+		}
+
+		for _, n := range strings.Split(names, ",") {
+			fields := strings.Fields(n)
+			if len(fields) > 0 && fields[0] == name {
+				return spec
+			}
+		}
+	}
+
+	return decl
+}
+
+func sortedSymbolNames(syms map[string]apiSymbol) []string {
+	names := make([]string, 0, len(syms))
+	for name := range syms {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// WriteAPIDiff writes the changes as a markdown report that is suitable for
+// inclusion in release notes.
+func WriteAPIDiff(w io.Writer, diff *APIDiff) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# API changes from %s to %s\n", diff.From, diff.To)
+
+	if len(diff.Packages) == 0 {
+		b.WriteString("\nNo changes to the exported API.\n")
+	}
+
+	for _, pkg := range diff.Packages {
+		fmt.Fprintf(&b, "\n## %s\n", pkg.Dir)
+
+		writeSymbolList(&b, "Added", pkg.Added, func(s *SymbolDiff) string { return s.NewDecl })
+		writeSymbolList(&b, "Removed", pkg.Removed, func(s *SymbolDiff) string { return s.OldDecl })
+
+		if len(pkg.Changed) > 0 {
+			b.WriteString("\n### Changed\n")
+			for _, s := range pkg.Changed {
+				fmt.Fprintf(&b, "\n#### %s %s\n\n", s.Kind, s.Name)
+				writeDiffBlock(&b, s.OldDecl, s.NewDecl)
+			}
+		}
+
+		if len(pkg.DocChanged) > 0 {
+			b.WriteString("\n### Documentation changed\n")
+			for _, s := range pkg.DocChanged {
+				if s.Kind == "package" {
+					b.WriteString("\n#### package\n\n")
+				} else {
+					fmt.Fprintf(&b, "\n#### %s %s\n\n", s.Kind, s.Name)
+				}
+
+				writeDiffBlock(&b, s.OldDoc, s.NewDoc)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeSymbolList(b *strings.Builder, title string, syms []*SymbolDiff, decl func(*SymbolDiff) string) {
+	if len(syms) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n### %s\n\n", title)
+	for _, s := range syms {
+		// Only the first line of multi-line declarations fits in a list
+		d := strings.SplitN(decl(s), "\n", 2)[0]
+		if (s.Kind == "const" || s.Kind == "var") && !strings.HasPrefix(d, s.Kind+" ") {
+			d = s.Kind + " " + d
+		}
+
+		fmt.Fprintf(b, "- `%s`\n", d)
+	}
+}
+
+func writeDiffBlock(b *strings.Builder, old, new string) {
+	b.WriteString("```diff\n")
+	for _, line := range splitDiffLines(old) {
+		fmt.Fprintf(b, "-%s\n", line)
+	}
+
+	for _, line := range splitDiffLines(new) {
+		fmt.Fprintf(b, "+%s\n", line)
+	}
+
+	b.WriteString("```\n")
+}
+
+func splitDiffLines(text string) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
+
+// loadRevisionModels loads the package models for the paths as they are at
+// the revision of the repository, keyed by the directory of each package
+// relative to the working directory. The revision is extracted into a
+// temporary directory, which the paths are resolved against.
+func loadRevisionModels(repo *git.Repository, rev string, paths []string, opts CommandOptions) (map[string]*lang.PackageModel, error) {
+	for _, p := range paths {
+		if !IsLocalPath(filepath.FromSlash(p)) {
+			return nil, fmt.Errorf("gomarkdoc: only local package paths can be compared: %s", p)
+		}
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: couldn't find the repository root: %w", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := filepath.Rel(wt.Filesystem.Root(), wd)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "gomarkdoc-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractRevision(repo, rev, tmp); err != nil {
+		return nil, err
+	}
+
+	// Loading from the extracted tree keeps the package directories relative,
+	// so that they line up between revisions and with the exclude patterns.
+	base := filepath.Join(tmp, rel)
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return nil, err
+	}
+
+	if err := os.Chdir(base); err != nil {
+		return nil, err
+	}
+	defer os.Chdir(wd)
+
+	var failures Failures
+	specs, err := loadSpecs(paths, opts, &failures)
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: failed to load packages at %s: %w", rev, err)
+	}
+
+	if err := failures.Err(); err != nil {
+		return nil, fmt.Errorf("gomarkdoc: failed to load packages at %s: %w", rev, err)
+	}
+
+	models := make(map[string]*lang.PackageModel)
+	for _, spec := range specs {
+		if spec.Pkg == nil {
+			continue
+		}
+
+		m, err := spec.Pkg.Model()
+		if err != nil {
+			return nil, err
+		}
+
+		dir := filepath.ToSlash(filepath.Clean(spec.Dir))
+		if dir != "." {
+			dir = "./" + dir
+		}
+
+		models[dir] = m
+	}
+
+	return models, nil
+}
+
+// extractRevision writes the files of the repository at the revision into
+// the destination directory. Symlinks and submodules are skipped.
+func extractRevision(repo *git.Repository, rev, dest string) error {
+	tree, err := revisionTree(repo, rev)
+	if err != nil {
+		return err
+	}
+
+	return tree.Files().ForEach(func(f *object.File) error {
+		if f.Mode != filemode.Regular && f.Mode != filemode.Executable {
+			return nil
+		}
+
+		path := filepath.Join(dest, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		r, err := f.Reader()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		out, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		_, err = io.Copy(out, r)
+		return err
+	})
+}
+
+// revisionTree provides the tree of the commit that the revision resolves to.
+func revisionTree(repo *git.Repository, rev string) (*object.Tree, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: invalid revision %s: %w", rev, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: invalid revision %s: %w", rev, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: invalid revision %s: %w", rev, err)
+	}
+
+	return tree, nil
+}
+
+// buildDiffCommand creates the diff command, which reports the changes to the
+// exported API of the packages between two git revisions.
+func buildDiffCommand(resolve ResolveFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <ref1> <ref2> [package ...]",
+		Short: "print a markdown report of the changes to the exported API between two git revisions",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, paths, err := resolve(args[2:])
+			if err != nil {
+				return err
+			}
+
+			repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{
+				DetectDotGit: true,
+			})
+			if err != nil {
+				return fmt.Errorf("gomarkdoc: couldn't open git repository: %w", err)
+			}
+
+			oldModels, err := loadRevisionModels(repo, args[0], paths, opts)
+			if err != nil {
+				return err
+			}
+
+			newModels, err := loadRevisionModels(repo, args[1], paths, opts)
+			if err != nil {
+				return err
+			}
+
+			dirs := make(map[string]bool)
+			for dir := range oldModels {
+				dirs[dir] = true
+			}
+
+			for dir := range newModels {
+				dirs[dir] = true
+			}
+
+			sorted := make([]string, 0, len(dirs))
+			for dir := range dirs {
+				sorted = append(sorted, dir)
+			}
+
+			sort.Strings(sorted)
+
+			diff := &APIDiff{From: args[0], To: args[1]}
+			for _, dir := range sorted {
+				if d := DiffPackageModels(dir, oldModels[dir], newModels[dir]); !d.Empty() {
+					diff.Packages = append(diff.Packages, d)
+				}
+			}
+
+			return WriteAPIDiff(cmd.OutOrStdout(), diff)
+		},
+	}
+}
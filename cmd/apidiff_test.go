@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/matryer/is"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+func TestDiffPackageModels(t *testing.T) {
+	is := is.New(t)
+
+	old := &lang.PackageModel{
+		Doc: "Package a does things.\n",
+		Consts: []*lang.ValueModel{
+			{Names: []string{"Min", "Max"}, Decl: "const (\n\tMin = 1\n\tMax = 10\n)", Doc: "Limits.\n"},
+		},
+		Funcs: []*lang.FuncModel{
+			{Name: "Run", Signature: "func Run()", Doc: "Run runs.\n"},
+			{Name: "Stop", Signature: "func Stop()", Doc: "Stop stops.\n"},
+		},
+		Types: []*lang.TypeModel{
+			{
+				Name: "Thing",
+				Decl: "type Thing struct{}",
+				Doc:  "Thing is a thing.\n",
+				Methods: []*lang.FuncModel{
+					{Name: "Do", Receiver: "*Thing", Signature: "func (t *Thing) Do()", Doc: "Do does.\n"},
+				},
+			},
+		},
+	}
+
+	new := &lang.PackageModel{
+		Doc: "Package a does things.\n",
+		Consts: []*lang.ValueModel{
+			{Names: []string{"Min", "Mid", "Max"}, Decl: "const (\n\tMin = 1\n\tMid = 5\n\tMax = 10\n)", Doc: "Limits.\n"},
+		},
+		Funcs: []*lang.FuncModel{
+			{Name: "Run", Signature: "func Run(ctx context.Context)", Doc: "Run runs.\n"},
+		},
+		Types: []*lang.TypeModel{
+			{
+				Name: "Thing",
+				Decl: "type Thing struct{}",
+				Doc:  "Thing is a thing.\n",
+				Methods: []*lang.FuncModel{
+					{Name: "Do", Receiver: "*Thing", Signature: "func (t *Thing) Do()", Doc: "Do does the thing.\n"},
+				},
+			},
+		},
+	}
+
+	d := DiffPackageModels(".", old, new)
+
+	is.Equal(len(d.Added), 1)
+	is.Equal(d.Added[0].Name, "Mid")
+	is.Equal(d.Added[0].NewDecl, "Mid = 5")
+
+	is.Equal(len(d.Removed), 1)
+	is.Equal(d.Removed[0].Name, "Stop")
+
+	is.Equal(len(d.Changed), 1)
+	is.Equal(d.Changed[0].Name, "Run")
+	is.Equal(d.Changed[0].OldDecl, "func Run()")
+	is.Equal(d.Changed[0].NewDecl, "func Run(ctx context.Context)")
+
+	is.Equal(len(d.DocChanged), 1)
+	is.Equal(d.DocChanged[0].Name, "Thing.Do")
+	is.Equal(d.DocChanged[0].Kind, "method")
+}
+
+func TestDiffPackageModels_addedPackage(t *testing.T) {
+	is := is.New(t)
+
+	d := DiffPackageModels("./b", nil, &lang.PackageModel{
+		Doc:   "Package b is new.\n",
+		Funcs: []*lang.FuncModel{{Name: "New", Signature: "func New()"}},
+	})
+
+	is.Equal(len(d.Added), 1)
+	is.Equal(len(d.DocChanged), 0) // the package doc is only compared between revisions
+	is.True(!d.Empty())
+}
+
+func TestWriteAPIDiff(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	is.NoErr(WriteAPIDiff(&buf, &APIDiff{
+		From: "v1.0.0",
+		To:   "v1.1.0",
+		Packages: []*PackageDiff{
+			{
+				Dir:     "./a",
+				Added:   []*SymbolDiff{{Name: "Mid", Kind: "const", NewDecl: "Mid = 5"}},
+				Removed: []*SymbolDiff{{Name: "Stop", Kind: "func", OldDecl: "func Stop()"}},
+				Changed: []*SymbolDiff{{Name: "Run", Kind: "func", OldDecl: "func Run()", NewDecl: "func Run(ctx context.Context)"}},
+				DocChanged: []*SymbolDiff{
+					{Name: "package", Kind: "package", OldDoc: "Package a.\n", NewDoc: "Package a does things.\n"},
+				},
+			},
+		},
+	}))
+
+	is.Equal(buf.String(), "# API changes from v1.0.0 to v1.1.0\n"+
+		"\n## ./a\n"+
+		"\n### Added\n\n- `const Mid = 5`\n"+
+		"\n### Removed\n\n- `func Stop()`\n"+
+		"\n### Changed\n"+
+		"\n#### func Run\n\n```diff\n-func Run()\n+func Run(ctx context.Context)\n```\n"+
+		"\n### Documentation changed\n"+
+		"\n#### package\n\n```diff\n-Package a.\n+Package a does things.\n```\n")
+}
+
+func TestWriteAPIDiff_noChanges(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	is.NoErr(WriteAPIDiff(&buf, &APIDiff{From: "a", To: "b"}))
+	is.Equal(buf.String(), "# API changes from a to b\n\nNo changes to the exported API.\n")
+}
+
+func TestExtractRevision(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	is.NoErr(err)
+
+	wt, err := repo.Worktree()
+	is.NoErr(err)
+
+	commit := func(content string) {
+		is.NoErr(os.MkdirAll(filepath.Join(dir, "pkg"), 0755))
+		is.NoErr(os.WriteFile(filepath.Join(dir, "pkg", "pkg.go"), []byte(content), 0644))
+		_, err := wt.Add("pkg/pkg.go")
+		is.NoErr(err)
+
+		_, err = wt.Commit("update", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		is.NoErr(err)
+	}
+
+	commit("package pkg\n\nfunc Old() {}\n")
+	commit("package pkg\n\nfunc New() {}\n")
+
+	dest := t.TempDir()
+	is.NoErr(extractRevision(repo, "HEAD~1", dest))
+
+	b, err := os.ReadFile(filepath.Join(dest, "pkg", "pkg.go"))
+	is.NoErr(err)
+	is.Equal(string(b), "package pkg\n\nfunc Old() {}\n")
+
+	is.True(extractRevision(repo, "missing", dest) != nil)
+}
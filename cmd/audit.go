@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AuditIssue describes an accessibility problem found in a rendered Output
+// file.
+type AuditIssue struct {
+	// Line holds the one-indexed line of the file on which the issue occurs.
+	Line int
+
+	// Message describes the issue.
+	Message string
+}
+
+var (
+	auditHeadingRegex   = regexp.MustCompile(`^(#{1,6})(?:[ \t]|$)`)
+	auditEmptyLinkRegex = regexp.MustCompile(`(?:^|[^!\\])\[\s*\]\(`)
+	auditEmptyAnchor    = regexp.MustCompile(`(?i)<a\b[^>]*>\s*</a>`)
+	auditEmptyImage     = regexp.MustCompile(`!\[\s*\]\(`)
+	auditHTMLImage      = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	auditHTMLAlt        = regexp.MustCompile(`(?i)\balt\s*=\s*("[^"]*\S[^"]*"|'[^']*\S[^']*'|[^\s"'>]+)`)
+)
+
+// AuditAccessibility checks the rendered text of an Output file for links
+// without text, headings that skip levels and images without alt text. Code
+// blocks are ignored.
+func AuditAccessibility(text string) []AuditIssue {
+	var (
+		issues    []AuditIssue
+		prevLevel int
+		fence     auditFence
+	)
+
+	for i, line := range strings.Split(text, "\n") {
+		lineNum := i + 1
+
+		// Code blocks run until a fence of the same character that is at
+		// least as long as the one that opened them, or the end of the file
+		if fence.char != 0 {
+			if f, rest, ok := parseAuditFence(line); ok && f.char == fence.char && f.length >= fence.length && strings.TrimSpace(rest) == "" {
+				fence = auditFence{}
+			}
+
+			continue
+		}
+
+		if f, rest, ok := parseAuditFence(line); ok && (f.char != '`' || !strings.Contains(rest, "`")) {
+			fence = f
+			continue
+		}
+
+		if m := auditHeadingRegex.FindStringSubmatch(line); m != nil {
+			level := len(m[1])
+			if prevLevel > 0 && level > prevLevel+1 {
+				issues = append(issues, AuditIssue{
+					Line:    lineNum,
+					Message: fmt.Sprintf("heading level %d skips level %d", level, prevLevel+1),
+				})
+			}
+
+			prevLevel = level
+		}
+
+		if auditEmptyLinkRegex.MatchString(line) || auditEmptyAnchor.MatchString(line) {
+			issues = append(issues, AuditIssue{Line: lineNum, Message: "link has no text"})
+		}
+
+		if auditEmptyImage.MatchString(line) {
+			issues = append(issues, AuditIssue{Line: lineNum, Message: "image has no alt text"})
+		}
+
+		for _, img := range auditHTMLImage.FindAllString(line, -1) {
+			if !auditHTMLAlt.MatchString(img) {
+				issues = append(issues, AuditIssue{Line: lineNum, Message: "image has no alt text"})
+			}
+		}
+	}
+
+	return issues
+}
+
+// auditFence describes the fence that opened a code block.
+type auditFence struct {
+	char   byte
+	length int
+}
+
+// parseAuditFence parses a code fence at the start of the line, which is a run
+// of at least three backticks or tildes indented by up to three spaces, as in
+// CommonMark. The text following the fence is returned along with it.
+func parseAuditFence(line string) (auditFence, string, bool) {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 || s == "" || (s[0] != '`' && s[0] != '~') {
+		return auditFence{}, "", false
+	}
+
+	n := len(s) - len(strings.TrimLeft(s, s[:1]))
+	if n < 3 {
+		return auditFence{}, "", false
+	}
+
+	return auditFence{char: s[0], length: n}, s[n:], true
+}
+
+// auditError reports the accessibility issues found in a single Output file.
+type auditError struct {
+	fileName string
+	issues   []AuditIssue
+}
+
+func (e *auditError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "accessibility audit of %s found %d issue(s):", e.fileName, len(e.issues))
+	for _, issue := range e.issues {
+		fmt.Fprintf(&b, "\n    line %d: %s", issue.Line, issue.Message)
+	}
+
+	return b.String()
+}
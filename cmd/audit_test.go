@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestAuditAccessibility(t *testing.T) {
+	is := is.New(t)
+
+	text := "# pkg\n\n" +
+		"#### Skipped\n\n" +
+		"See [](<#func-a>) and <a href=\"#b\"> </a>.\n\n" +
+		"![](logo.png) <img src=\"logo.png\"> <img src=\"logo.png\" alt=\"Logo\">\n\n" +
+		"```go\n# not a heading [](x)\n```\n\n" +
+		"## Fine [link](<#a>) ![Logo](logo.png)\n"
+
+	is.Equal(AuditAccessibility(text), []AuditIssue{
+		{Line: 3, Message: "heading level 4 skips level 2"},
+		{Line: 5, Message: "link has no text"},
+		{Line: 7, Message: "image has no alt text"},
+		{Line: 7, Message: "image has no alt text"},
+	})
+}
+
+func TestAuditAccessibility_clean(t *testing.T) {
+	is := is.New(t)
+
+	text := "## pkg\n\n### func [A](<#func-a>)\n\n## type B\n"
+	is.Equal(len(AuditAccessibility(text)), 0)
+}
+
+func TestAuditAccessibility_fences(t *testing.T) {
+	tests := map[string]struct {
+		text  string
+		lines []int
+	}{
+		"tildes": {
+			text:  "# pkg\n\n~~~\n### not a heading\n~~~\n\n### Skipped\n",
+			lines: []int{7},
+		},
+		"longer fence": {
+			text:  "# pkg\n\n````md\n```\n### not a heading\n```\n````\n\n### Skipped\n",
+			lines: []int{9},
+		},
+		"backticks within tildes": {
+			text:  "# pkg\n\n~~~\n```\n~~~\n\n### Skipped\n",
+			lines: []int{7},
+		},
+		"tildes within backticks": {
+			text:  "# pkg\n\n```\n~~~\n### not a heading\n```\n\n### Skipped\n",
+			lines: []int{8},
+		},
+		"closing fence with text": {
+			text:  "# pkg\n\n```\n``` go\n### not a heading\n```\n\n### Skipped\n",
+			lines: []int{8},
+		},
+		"indented code": {
+			text:  "# pkg\n\n    ```\n\n### Skipped\n",
+			lines: []int{5},
+		},
+		"inline code": {
+			text:  "# pkg\n\n``` `code` ```\n\n### Skipped\n",
+			lines: []int{5},
+		},
+		"unclosed": {
+			text: "# pkg\n\n~~~~\n~~~\n### not a heading\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			var lines []int
+			for _, issue := range AuditAccessibility(test.text) {
+				lines = append(lines, issue.Line)
+			}
+
+			is.Equal(lines, test.lines)
+		})
+	}
+}
@@ -0,0 +1,568 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// cacheVersion is recorded in the cache file so that caches written by
+// incompatible versions of gomarkdoc are discarded.
+const cacheVersion = "1"
+
+// Cache records the inputs and the contents of each Output file generated by a
+// run, so that later runs can skip the packages whose Output files are
+// unchanged. Paths are stored relative to the directory containing the cache
+// file using forward slashes, like the paths of a Manifest.
+type Cache struct {
+	Version string                 `json:"version"`
+	Files   map[string]*CacheEntry `json:"files"`
+
+	path string
+
+	// inputs holds the hash of the inputs of each Output file computed for the
+	// current run, keyed like Files.
+	inputs map[string]string
+
+	// commit holds the commit checked out for the current run, which
+	// templates can render through the generation function.
+	commit string
+}
+
+// CacheEntry records the generation of a single Output file.
+type CacheEntry struct {
+	// Inputs holds the hash of the package sources and options used to
+	// generate the Output file.
+	Inputs string `json:"inputs"`
+
+	// Outputs holds the hash of the contents of each file written for the
+	// Output file, which includes its examples file when examples are split.
+	Outputs map[string]string `json:"outputs"`
+
+	// Packages maps the directory of each package documented in the Output
+	// file to its import path, which is needed to link to the package without
+	// loading it.
+	Packages map[string]string `json:"packages"`
+
+	// Includes holds the hash of the contents of each file included by the
+	// templates when the Output file was generated.
+	Includes map[string]string `json:"includes,omitempty"`
+}
+
+// ReadCache reads the cache at the provided path. An empty cache is provided if
+// the file doesn't exist or was written by an incompatible version.
+func ReadCache(path string) (*Cache, error) {
+	c := &Cache{
+		Version: cacheVersion,
+		Files:   make(map[string]*CacheEntry),
+		path:    path,
+		inputs:  make(map[string]string),
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: failed to read cache %s: %w", path, err)
+	}
+
+	var stored Cache
+	if err := json.Unmarshal(b, &stored); err != nil {
+		return nil, fmt.Errorf("gomarkdoc: invalid cache %s: %w", path, err)
+	}
+
+	if stored.Version == cacheVersion && stored.Files != nil {
+		c.Files = stored.Files
+	}
+
+	return c, nil
+}
+
+// Write writes the cache to the path it was read from.
+func (c *Cache) Write() error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFile(c.path, string(b)+"\n"); err != nil {
+		return fmt.Errorf("gomarkdoc: failed to write cache %s: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// Record records the contents of the files written for the Output file along
+// with the packages documented in it and the files its templates included.
+// Nothing is recorded if the inputs of the Output file weren't computed for the
+// current run or an included file can't be read.
+func (c *Cache) Record(fileName string, outputs map[string]string, specs []*PackageSpec, included []string) error {
+	key, err := manifestKey(c.path, fileName)
+	if err != nil {
+		return err
+	}
+
+	inputs, ok := c.inputs[key]
+	if !ok {
+		return nil
+	}
+
+	entry := &CacheEntry{
+		Inputs:   inputs,
+		Outputs:  make(map[string]string),
+		Packages: make(map[string]string),
+	}
+
+	for name, text := range outputs {
+		outputKey, err := manifestKey(c.path, name)
+		if err != nil {
+			return err
+		}
+
+		entry.Outputs[outputKey] = HashContents(text)
+	}
+
+	for _, spec := range specs {
+		if spec.OutputFile != fileName || spec.Pkg == nil {
+			continue
+		}
+
+		entry.Packages[filepath.ToSlash(filepath.Clean(spec.Dir))] = spec.Pkg.ImportPath()
+	}
+
+	for _, name := range included {
+		h := sha256.New()
+		if !hashFile(h, name) {
+			delete(c.Files, key)
+			return nil
+		}
+
+		if entry.Includes == nil {
+			entry.Includes = make(map[string]string)
+		}
+
+		includeKey, err := manifestKey(c.path, name)
+		if err != nil {
+			return err
+		}
+
+		entry.Includes[includeKey] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	c.Files[key] = entry
+	return nil
+}
+
+// cachedFile holds the Output file of a format along with the specs documented
+// in it.
+type cachedFile struct {
+	name   string
+	inputs string
+	specs  []*PackageSpec
+}
+
+// cachedOutput holds the current contents of the files written for an Output
+// file whose inputs are unchanged.
+type cachedOutput map[string]string
+
+// prepare computes the inputs of each of the Output files of the run and
+// determines the files that are unchanged since they were recorded. The specs
+// that need to be loaded are provided, along with the contents of the unchanged
+// files for each format. Each package documented in an unchanged file is
+// given the import path recorded for it, so that other files can still link
+// to it.
+func (c *Cache) prepare(groups []*specGroup, specs []*PackageSpec, formats []string, opts CommandOptions) ([]*PackageSpec, []map[string]cachedOutput, error) {
+	c.commit = headCommit()
+
+	dirHashes := make(map[string]string)
+	dirHash := func(spec *PackageSpec) string {
+		if h, ok := dirHashes[spec.Dir]; ok {
+			return h
+		}
+
+		h := hashPackageDir(spec)
+		dirHashes[spec.Dir] = h
+		return h
+	}
+
+	// Wildcard directories without any Go files don't produce a package
+	var documented []*PackageSpec
+	for _, spec := range specs {
+		if spec.IsWildcard && dirHash(spec) == "" {
+			continue
+		}
+
+		documented = append(documented, spec)
+	}
+
+	files := make([][]*cachedFile, len(formats))
+	for i, f := range formats {
+		for _, g := range groups {
+			if err := ResolveOutput(g.specs, g.outputTmpls[i]); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if err := ResolveCaseCollisions(specs, opts.CaseCollisions); err != nil {
+			return nil, nil, err
+		}
+
+		var outputs []string
+		for _, spec := range documented {
+			outputs = append(outputs, fmt.Sprintf("%s\x00%s", spec.Dir, spec.OutputFile))
+		}
+
+		for _, g := range groups {
+			groupOpts := g.opts
+			groupOpts.Format = f
+			groupOpts.Output = g.opts.FormatOutput(f)
+
+			fileSpecs := make(map[string][]*PackageSpec)
+			var names []string
+			for _, spec := range g.specs {
+				if spec.IsWildcard && dirHash(spec) == "" {
+					continue
+				}
+
+				if _, ok := fileSpecs[spec.OutputFile]; !ok {
+					names = append(names, spec.OutputFile)
+				}
+
+				fileSpecs[spec.OutputFile] = append(fileSpecs[spec.OutputFile], spec)
+			}
+
+			for _, name := range names {
+				file := &cachedFile{name: name, specs: fileSpecs[name]}
+				if name != "" {
+					file.inputs = c.hashInputs(groupOpts, file.specs, documented, outputs, dirHash)
+				}
+
+				if file.inputs != "" {
+					key, err := manifestKey(c.path, name)
+					if err != nil {
+						return nil, nil, err
+					}
+
+					c.inputs[key] = file.inputs
+				}
+
+				files[i] = append(files[i], file)
+			}
+		}
+	}
+
+	// Find the files that can be skipped, marking each of the packages in
+	// the others as stale
+	stale := make(map[*PackageSpec]bool)
+	contents := make(map[*cachedFile]cachedOutput)
+	for _, formatFiles := range files {
+		for _, file := range formatFiles {
+			if output, ok := c.fresh(file); ok {
+				contents[file] = output
+				continue
+			}
+
+			for _, spec := range file.specs {
+				stale[spec] = true
+			}
+		}
+	}
+
+	// A file has to be generated again if any of its packages are loaded, as
+	// the file is written with only the packages that were loaded
+	for found := true; found; {
+		found = false
+		for _, formatFiles := range files {
+			for _, file := range formatFiles {
+				if _, ok := contents[file]; !ok || !anyStale(file.specs, stale) {
+					continue
+				}
+
+				delete(contents, file)
+				for _, spec := range file.specs {
+					if !stale[spec] {
+						stale[spec] = true
+						found = true
+					}
+				}
+			}
+		}
+	}
+
+	// Links to the contents of other packages need all of the packages
+	if len(stale) > 0 && (opts.CrossPackageLinks || opts.PackageTable || opts.Subpackages) {
+		for _, spec := range specs {
+			stale[spec] = true
+		}
+
+		contents = make(map[*cachedFile]cachedOutput)
+	}
+
+	cached := make([]map[string]cachedOutput, len(formats))
+	for i, formatFiles := range files {
+		cached[i] = make(map[string]cachedOutput)
+		for _, file := range formatFiles {
+			output, ok := contents[file]
+			if !ok {
+				continue
+			}
+
+			cached[i][file.name] = output
+
+			key, err := manifestKey(c.path, file.name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, spec := range file.specs {
+				spec.importPath = c.Files[key].Packages[filepath.ToSlash(filepath.Clean(spec.Dir))]
+			}
+		}
+	}
+
+	var load []*PackageSpec
+	for _, spec := range specs {
+		if stale[spec] {
+			load = append(load, spec)
+		}
+	}
+
+	return load, cached, nil
+}
+
+// fresh determines whether the file was recorded with the same inputs, the
+// files its templates included are unchanged and its files still have the
+// contents that were recorded, providing those contents.
+func (c *Cache) fresh(file *cachedFile) (cachedOutput, bool) {
+	if file.inputs == "" {
+		return nil, false
+	}
+
+	key, err := manifestKey(c.path, file.name)
+	if err != nil {
+		return nil, false
+	}
+
+	entry, ok := c.Files[key]
+	if !ok || entry.Inputs != file.inputs || len(entry.Packages) != len(file.specs) {
+		return nil, false
+	}
+
+	for _, spec := range file.specs {
+		if _, ok := entry.Packages[filepath.ToSlash(filepath.Clean(spec.Dir))]; !ok {
+			return nil, false
+		}
+	}
+
+	dir := filepath.Dir(c.path)
+	for name, hash := range entry.Includes {
+		h := sha256.New()
+		if !hashFile(h, filepath.Join(dir, filepath.FromSlash(name))) || hex.EncodeToString(h.Sum(nil)) != hash {
+			return nil, false
+		}
+	}
+
+	output := make(cachedOutput)
+	for name, hash := range entry.Outputs {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		b, err := ioutil.ReadFile(path)
+		if err != nil || HashContents(string(b)) != hash {
+			return nil, false
+		}
+
+		output[path] = string(b)
+	}
+
+	return output, true
+}
+
+// hashInputs computes the hash of the inputs of an Output file: the options
+// used to generate it, the configuration files they were loaded from, the
+// contents of the files those options reference, the checked out commit, the
+// sources of its packages and the Output file of each package, which
+// determines the links between packages. Options that link to the contents of
+// other packages include the sources of all of the packages. An empty string
+// is provided if the inputs can't be determined.
+func (c *Cache) hashInputs(opts CommandOptions, fileSpecs, specs []*PackageSpec, outputs []string, dirHash func(*PackageSpec) string) string {
+	// Options that don't affect the contents of the file
+	opts.Check = false
+	opts.Preview = false
+	opts.Verbosity = 0
+	opts.KeepGoing = false
+	opts.LogFormat = ""
+	opts.Hooks = Hooks{}
+	opts.Manifest = ""
+	opts.Anchors = ""
+	opts.Cache = ""
+	opts.Since = ""
+	opts.SinceDeps = false
+	opts.Shard = ""
+	opts.ServeAddr = ""
+	opts.Formats = nil
+	opts.FormatOutputs = nil
+
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", cacheVersion, version, c.commit, b)
+
+	referenced := append([]string{opts.HeaderFile, opts.FooterFile}, opts.configFiles...)
+	for _, name := range sortedKeys(opts.TemplateFileOverrides) {
+		referenced = append(referenced, opts.TemplateFileOverrides[name])
+	}
+
+	for _, name := range referenced {
+		if name == "" {
+			continue
+		}
+
+		if !hashFile(h, name) {
+			return ""
+		}
+	}
+
+	sources := fileSpecs
+	if opts.CrossPackageLinks || opts.PackageTable || opts.Subpackages {
+		sources = specs
+	}
+
+	for _, spec := range sources {
+		dh := dirHash(spec)
+		if dh == "" {
+			return ""
+		}
+
+		fmt.Fprintf(h, "%s\x00%s\x00", spec.Dir, dh)
+	}
+
+	fmt.Fprint(h, strings.Join(outputs, "\x00"))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashPackageDir computes the hash of the Go files in the directory of a local
+// package. An empty string is provided for remote packages and directories
+// without any Go files other than tests.
+func hashPackageDir(spec *PackageSpec) string {
+	if !spec.IsLocal {
+		return ""
+	}
+
+	entries, err := ioutil.ReadDir(spec.Dir)
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.New()
+
+	var found bool
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() || filepath.Ext(entry.Name()) != ".go" {
+			continue
+		}
+
+		if !strings.HasSuffix(entry.Name(), "_test.go") {
+			found = true
+		}
+
+		fmt.Fprintf(h, "%s\x00", entry.Name())
+		if !hashFile(h, filepath.Join(spec.Dir, entry.Name())) {
+			return ""
+		}
+	}
+
+	if !found {
+		return ""
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashFile writes the contents of the file to the hash, reporting whether the
+// file could be read.
+func hashFile(w io.Writer, name string) bool {
+	f, err := os.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err == nil
+}
+
+func anyStale(specs []*PackageSpec, stale map[*PackageSpec]bool) bool {
+	for _, spec := range specs {
+		if stale[spec] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// keepCachedOutputs accounts for the files of the Output files that were
+// skipped because they are unchanged, as if they had been written or checked.
+func keepCachedOutputs(opts CommandOptions, manifest, checked Manifest, mismatches *Mismatches, cached map[string]cachedOutput) error {
+	if manifest == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(cached))
+	for name := range cached {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		output := cached[name]
+		for _, file := range sortedKeys(output) {
+			if opts.Check {
+				if err := emitOutput(opts, manifest, checked, file, output[file]); err != nil && !mismatches.Add(err) {
+					return err
+				}
+
+				continue
+			}
+
+			if err := manifest.Add(opts.Manifest, file, output[file]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// openCache reads the cache for the run. A nil Cache is provided if no cache
+// was requested or it can't be used for the run.
+func openCache(opts CommandOptions) (*Cache, error) {
+	// The anchors file is written from all of the packages, so they all need
+	// to be loaded
+	if opts.Cache == "" || opts.Preview || opts.Anchors != "" {
+		return nil, nil
+	}
+
+	return ReadCache(opts.Cache)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_cache(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "pkg", "pkg.go"), "// Package pkg is a package.\npackage pkg\n\n// Func is a function.\nfunc Func() {}\n")
+
+	readme := filepath.Join(dir, "pkg", "README.md")
+	paths := []string{filepath.Join(dir, "pkg")}
+	opts := CommandOptions{
+		Output: "{{.Dir}}/README.md",
+		Cache:  filepath.Join(dir, ".gomarkdoc-cache.json"),
+	}
+
+	is.NoErr(RunCommand(paths, opts))
+
+	cache, err := ReadCache(opts.Cache)
+	is.NoErr(err)
+	is.Equal(len(cache.Files), 1)
+	is.Equal(cache.Files["pkg/README.md"].Packages, map[string]string{
+		filepath.ToSlash(filepath.Join(dir, "pkg")): "example.com/mod/pkg",
+	})
+
+	// Unchanged files aren't written again
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	is.NoErr(os.Chtimes(readme, old, old))
+	is.NoErr(RunCommand(paths, opts))
+
+	info, err := os.Stat(readme)
+	is.NoErr(err)
+	is.True(info.ModTime().Equal(old))
+
+	checkOpts := opts
+	checkOpts.Check = true
+	is.NoErr(RunCommand(paths, checkOpts))
+
+	// Edits to the file are detected
+	writeConfig(t, readme, "edited\n")
+	is.True(errors.Is(RunCommand(paths, checkOpts), errOutputMismatch))
+
+	// Changes to the sources are picked up
+	writeConfig(t, filepath.Join(dir, "pkg", "pkg.go"), "// Package pkg is a package.\npackage pkg\n\n// Other is another function.\nfunc Other() {}\n")
+	is.NoErr(RunCommand(paths, opts))
+
+	b, err := os.ReadFile(readme)
+	is.NoErr(err)
+	is.True(strings.Contains(string(b), "Other is another function."))
+	is.NoErr(RunCommand(paths, checkOpts))
+
+	// Files that are skipped stay in the manifest
+	manifestOpts := opts
+	manifestOpts.Manifest = filepath.Join(dir, "docs.sha256")
+	is.NoErr(RunCommand(paths, manifestOpts))
+
+	manifest, err := ReadManifest(manifestOpts.Manifest)
+	is.NoErr(err)
+	is.Equal(len(manifest), 1)
+}
+
+func TestReadCache(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache.json")
+
+	c, err := ReadCache(path)
+	is.NoErr(err)
+	is.Equal(len(c.Files), 0)
+
+	writeConfig(t, path, `{"version": "0", "files": {"README.md": {"inputs": "abc"}}}`)
+	c, err = ReadCache(path)
+	is.NoErr(err)
+	is.Equal(len(c.Files), 0)
+
+	writeConfig(t, path, "{")
+	_, err = ReadCache(path)
+	is.True(err != nil)
+}
+
+func TestCache_hashInputs(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "pkg", "pkg.go"), "// Package pkg is a package.\npackage pkg\n")
+	writeConfig(t, filepath.Join(dir, ".gomarkdoc.yml"), "translations:\n  Index: Contents\n")
+	writeConfig(t, filepath.Join(dir, "preset", ".gomarkdoc.yml"), "locale: de\n")
+
+	c, err := ReadCache(filepath.Join(dir, "cache.json"))
+	is.NoErr(err)
+
+	specs := []*PackageSpec{{Dir: filepath.Join(dir, "pkg"), IsLocal: true}}
+	opts := CommandOptions{
+		Output:      "README.md",
+		configFiles: []string{filepath.Join(dir, ".gomarkdoc.yml"), filepath.Join(dir, "preset", ".gomarkdoc.yml")},
+	}
+
+	hash := func() string {
+		return c.hashInputs(opts, specs, specs, nil, hashPackageDir)
+	}
+
+	c.commit = "a"
+	inputs := hash()
+	is.True(inputs != "")
+	is.Equal(hash(), inputs)
+
+	// The checked out commit is available to templates
+	c.commit = "b"
+	is.True(hash() != inputs)
+	c.commit = "a"
+
+	// As are the contents of the configuration files
+	writeConfig(t, filepath.Join(dir, ".gomarkdoc.yml"), "translations:\n  Index: Overview\n")
+	is.True(hash() != inputs)
+	inputs = hash()
+
+	writeConfig(t, filepath.Join(dir, "preset", ".gomarkdoc.yml"), "locale: fr\n")
+	is.True(hash() != inputs)
+
+	// Inputs that can't be read disable the cache for the file
+	is.NoErr(os.Remove(filepath.Join(dir, "preset", ".gomarkdoc.yml")))
+	is.Equal(hash(), "")
+}
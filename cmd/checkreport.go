@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Formats of the report of the Output files that don't match in Check mode,
+// as selected with the --check-format flag.
+const (
+	// CheckFormatText reports the mismatches in the error returned by the
+	// run. This is the default.
+	CheckFormatText = "text"
+
+	// CheckFormatJSON writes a JSON array of CheckFinding values.
+	CheckFormatJSON = "json"
+
+	// CheckFormatSARIF writes a SARIF 2.1.0 log with a result for each
+	// mismatch, for ingestion by code scanning tools.
+	CheckFormatSARIF = "sarif"
+)
+
+var checkFormats = []string{CheckFormatText, CheckFormatJSON, CheckFormatSARIF}
+
+// CheckFinding describes an Output file that doesn't match the generated
+// documentation in the JSON check report.
+type CheckFinding struct {
+	// File holds the path of the Output file, using forward slashes.
+	File string `json:"file"`
+
+	// Reason holds one of MismatchOutOfDate, MismatchMissing or
+	// MismatchNotGenerated.
+	Reason string `json:"reason"`
+
+	// ExpectedHash holds the hash of the generated documentation for the
+	// file, if it is still generated.
+	ExpectedHash string `json:"expectedHash,omitempty"`
+
+	// ActualHash holds the hash of the current contents of the file, if it
+	// exists.
+	ActualHash string `json:"actualHash,omitempty"`
+
+	// LinesAdded and LinesRemoved hold the number of lines that regenerating
+	// the file would add and remove.
+	LinesAdded   int `json:"linesAdded"`
+	LinesRemoved int `json:"linesRemoved"`
+
+	// Summary describes the drift of the file in a sentence.
+	Summary string `json:"summary"`
+}
+
+// WriteCheckReport writes the mismatches found in Check mode to the writer in
+// the provided format, which is one of CheckFormatJSON or CheckFormatSARIF. A
+// report is written even if there are no mismatches, so that tools consuming
+// it can tell a clean run apart from a failed one.
+func WriteCheckReport(w io.Writer, format string, mismatches Mismatches) error {
+	var report interface{}
+	switch format {
+	case CheckFormatJSON:
+		findings := make([]*CheckFinding, 0, len(mismatches))
+		for _, m := range mismatches.sorted() {
+			findings = append(findings, &CheckFinding{
+				File:         filepath.ToSlash(m.File),
+				Reason:       m.Reason,
+				ExpectedHash: m.Expected,
+				ActualHash:   m.Actual,
+				LinesAdded:   m.Added,
+				LinesRemoved: m.Removed,
+				Summary:      m.Summary(),
+			})
+		}
+
+		report = findings
+	case CheckFormatSARIF:
+		report = sarifReport(mismatches)
+	default:
+		return fmt.Errorf("gomarkdoc: invalid check format: %s", format)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+type (
+	sarifLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifResult struct {
+		RuleID     string            `json:"ruleId"`
+		Level      string            `json:"level"`
+		Message    sarifMessage      `json:"message"`
+		Locations  []sarifLocation   `json:"locations"`
+		Properties map[string]string `json:"properties,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+)
+
+// sarifReport builds a SARIF log with a rule for each reason a file may not
+// match and a result for each mismatch.
+func sarifReport(mismatches Mismatches) *sarifLog {
+	var rules []sarifRule
+	for _, reason := range []string{MismatchOutOfDate, MismatchMissing, MismatchNotGenerated} {
+		rules = append(rules, sarifRule{
+			ID:               sarifRuleID(reason),
+			ShortDescription: sarifMessage{Text: "Generated documentation is " + reason},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(mismatches))
+	for _, m := range mismatches.sorted() {
+		props := make(map[string]string)
+		if m.Expected != "" {
+			props["expectedHash"] = m.Expected
+		}
+
+		if m.Actual != "" {
+			props["actualHash"] = m.Actual
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  sarifRuleID(m.Reason),
+			Level:   "error",
+			Message: sarifMessage{Text: checkMessage(m)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(m.File)},
+				},
+			}},
+			Properties: props,
+		})
+	}
+
+	return &sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gomarkdoc",
+				InformationURI: "https://github.com/princjef/gomarkdoc",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// checkMessage describes the mismatch for code scanning tools and annotations.
+func checkMessage(m *Mismatch) string {
+	return fmt.Sprintf("Documentation is %s: %s. Run gomarkdoc to update it.", m.Reason, m.Summary())
+}
+
+// sarifRuleID derives the id of the SARIF rule for the reason of a mismatch,
+// such as out-of-date.
+func sarifRuleID(reason string) string {
+	return strings.ReplaceAll(reason, " ", "-")
+}
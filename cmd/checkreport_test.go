@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestWriteCheckReport_json(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	outOfDate := filepath.Join(dir, "b", "README.md")
+	missing := filepath.Join(dir, "a", "README.md")
+	writeConfig(t, outOfDate, "# b\n\nold line\n")
+
+	var mismatches Mismatches
+	is.True(mismatches.Add(CheckFile(bytes.NewBufferString("# b\n\nnew line\nanother line\n"), outOfDate)))
+	is.True(mismatches.Add(CheckFile(bytes.NewBufferString("# a\n"), missing)))
+
+	var out bytes.Buffer
+	is.NoErr(WriteCheckReport(&out, CheckFormatJSON, mismatches))
+
+	var findings []CheckFinding
+	is.NoErr(json.Unmarshal(out.Bytes(), &findings))
+	is.Equal(findings, []CheckFinding{
+		{
+			File:         filepath.ToSlash(missing),
+			Reason:       MismatchMissing,
+			ExpectedHash: HashContents("# a\n"),
+			LinesAdded:   1,
+			Summary:      "the file doesn't exist",
+		},
+		{
+			File:         filepath.ToSlash(outOfDate),
+			Reason:       MismatchOutOfDate,
+			ExpectedHash: HashContents("# b\n\nnew line\nanother line\n"),
+			ActualHash:   HashContents("# b\n\nold line\n"),
+			LinesAdded:   2,
+			LinesRemoved: 1,
+			Summary:      "regenerating adds 2 line(s) and removes 1 line(s)",
+		},
+	})
+}
+
+func TestWriteCheckReport_sarif(t *testing.T) {
+	is := is.New(t)
+
+	mismatches := Mismatches{{File: "a/README.md", Reason: MismatchNotGenerated, Actual: "abc"}}
+
+	var out bytes.Buffer
+	is.NoErr(WriteCheckReport(&out, CheckFormatSARIF, mismatches))
+
+	var log sarifLog
+	is.NoErr(json.Unmarshal(out.Bytes(), &log))
+	is.Equal(log.Version, "2.1.0")
+	is.Equal(len(log.Runs), 1)
+	is.Equal(len(log.Runs[0].Tool.Driver.Rules), 3)
+
+	results := log.Runs[0].Results
+	is.Equal(len(results), 1)
+	is.Equal(results[0].RuleID, "no-longer-generated")
+	is.Equal(results[0].Level, "error")
+	is.Equal(results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI, "a/README.md")
+	is.Equal(results[0].Properties, map[string]string{"actualHash": "abc"})
+}
+
+func TestWriteCheckReport_clean(t *testing.T) {
+	is := is.New(t)
+
+	var out bytes.Buffer
+	is.NoErr(WriteCheckReport(&out, CheckFormatJSON, nil))
+	is.Equal(out.String(), "[]\n")
+}
+
+func TestValidateCheckFormat(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateCheckFormat(CommandOptions{}))
+	is.NoErr(ValidateCheckFormat(CommandOptions{CheckFormat: CheckFormatSARIF}))
+	is.Equal(
+		ValidateCheckFormat(CommandOptions{CheckFormat: "xml"}).Error(),
+		"gomarkdoc: invalid check format: xml (valid options: text, json, sarif)",
+	)
+}
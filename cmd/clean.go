@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/spf13/cobra"
+)
+
+// generatedMarker is the comment at the top of the files rendered with the
+// default templates, which identifies the files that are safe to remove.
+var generatedMarker = []byte("<!-- Code generated by gomarkdoc. DO NOT EDIT -->")
+
+// StaleOutputs finds the Output files of earlier runs that no longer belong to
+// any package, such as those of packages that were renamed or deleted. The
+// Output template is evaluated for every directory in the package tree, and
+// the files of directories without a package are stale if they were generated
+// by gomarkdoc. When mirroring the tree with OutputDir, the files within the
+// directory are matched against the packages instead. Files listed in the
+// manifest that no package is written to are stale as well. The returned paths
+// are sorted.
+func StaleOutputs(paths []string, opts CommandOptions) ([]string, error) {
+	excludes, err := ParseExcludePatterns(opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := GetSpecsFor(opts, paths...)
+
+	included := make(map[*PackageSpec]bool)
+	for _, spec := range ExcludeSpecs(filterInternal(specs, opts), excludes) {
+		included[spec] = true
+	}
+
+	formats := ResolveFormats(opts)
+	outputTmpls, err := parseOutputTemplates(opts, formats)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := groupSpecs(specs, opts, formats, outputTmpls)
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]bool)
+	candidates := make(map[string]bool)
+	for i := range formats {
+		for _, g := range groups {
+			if err := ResolveOutput(g.specs, g.outputTmpls[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, spec := range specs {
+			if spec.OutputFile == "" {
+				continue
+			}
+
+			files := []string{spec.OutputFile}
+			if opts.SplitExamples {
+				files = append(files, ExamplesFilePath(spec.OutputFile))
+			}
+
+			isLive := !spec.IsLocal
+			if included[spec] && spec.IsLocal {
+				_, err := lang.ListPackage(spec.ImportPath, opts.Tags...)
+				isLive = err == nil
+			}
+
+			for _, f := range files {
+				if isLive {
+					live[filepath.Clean(f)] = true
+				} else {
+					candidates[filepath.Clean(f)] = true
+				}
+			}
+		}
+	}
+
+	if opts.OutputDir != "" {
+		if err := outputDirCandidates(opts, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	var stale []string
+	for f := range candidates {
+		if !live[f] && isGenerated(f) {
+			stale = append(stale, f)
+		}
+	}
+
+	if opts.Manifest != "" {
+		manifest, err := ReadManifest(opts.Manifest)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		manifestDir := filepath.Dir(opts.Manifest)
+		for key := range manifest {
+			f := filepath.Clean(filepath.Join(manifestDir, filepath.FromSlash(key)))
+			if live[f] || candidates[f] {
+				continue
+			}
+
+			if _, err := os.Stat(f); err == nil {
+				stale = append(stale, f)
+			}
+		}
+	}
+
+	sort.Strings(stale)
+
+	return stale, nil
+}
+
+// filterInternal drops the internal packages from the specs if the options
+// exclude them.
+func filterInternal(specs []*PackageSpec, opts CommandOptions) []*PackageSpec {
+	if opts.ExcludeInternal {
+		return ExcludeInternalSpecs(specs)
+	}
+
+	return specs
+}
+
+// outputDirCandidates adds the files within the OutputDir that were written
+// for a package to the candidates, so that they can be matched against the
+// packages that still exist.
+func outputDirCandidates(opts CommandOptions, candidates map[string]bool) error {
+	err := filepath.WalkDir(opts.OutputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && (d.Name() == outputDirFileName || (opts.SplitExamples && d.Name() == ExamplesFilePath(outputDirFileName))) {
+			candidates[filepath.Clean(path)] = true
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// isGenerated indicates whether the file exists and was generated by
+// gomarkdoc.
+func isGenerated(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Contains(b, generatedMarker)
+}
+
+// CleanOutputs removes the stale Output files, along with any directories that
+// are left empty, and removes them from the manifest if one is used.
+func CleanOutputs(stale []string, opts CommandOptions) error {
+	for _, f := range stale {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("gomarkdoc: failed to remove %s: %w", f, err)
+		}
+
+		removeEmptyDirs(filepath.Dir(f))
+	}
+
+	if opts.Manifest == "" {
+		return nil
+	}
+
+	manifest, err := ReadManifest(opts.Manifest)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, f := range stale {
+		key, err := manifestKey(opts.Manifest, f)
+		if err != nil {
+			return err
+		}
+
+		delete(manifest, key)
+	}
+
+	if err := manifest.Write(opts.Manifest); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", opts.Manifest, err)
+	}
+
+	return nil
+}
+
+// removeEmptyDirs removes the directory and its parents for as long as they
+// are empty, without leaving the working directory.
+func removeEmptyDirs(dir string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	for {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return
+		}
+
+		rel, err := filepath.Rel(wd, abs)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, parentPathPrefix) {
+			return
+		}
+
+		// Removing a directory fails if it isn't empty, which stops the
+		// removal at the first directory that still has contents
+		if os.Remove(abs) != nil {
+			return
+		}
+
+		dir = filepath.Dir(abs)
+	}
+}
+
+// buildCleanCommand creates the clean command, which removes the Output files
+// that no longer belong to a package.
+func buildCleanCommand(resolve ResolveFunc) *cobra.Command {
+	var dryRun bool
+
+	clean := &cobra.Command{
+		Use:   "clean [package ...]",
+		Short: "remove generated Output files that no longer belong to a package",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, paths, err := resolve(args)
+			if err != nil {
+				return err
+			}
+
+			stale, err := StaleOutputs(paths, opts)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				for _, f := range stale {
+					fmt.Fprintf(cmd.OutOrStdout(), "would remove %s\n", f)
+				}
+
+				return nil
+			}
+
+			if err := CleanOutputs(stale, opts); err != nil {
+				return err
+			}
+
+			for _, f := range stale {
+				fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", f)
+			}
+
+			return nil
+		},
+	}
+
+	clean.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"Print the files that would be removed without removing them.",
+	)
+
+	return clean
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestStaleOutputs(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "a", "a.go"), "// Package a is a package.\npackage a\n")
+	writeConfig(t, filepath.Join(dir, "b", "b.go"), "// Package b is a package.\npackage b\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{Output: "{{.Dir}}/README.md"}
+	is.NoErr(RunCommand([]string{"./..."}, opts))
+
+	// Hand-written files are never removed
+	writeConfig(t, filepath.Join("notes", "README.md"), "# Notes\n")
+
+	stale, err := StaleOutputs([]string{"./..."}, opts)
+	is.NoErr(err)
+	is.Equal(len(stale), 0)
+
+	// Packages that are deleted leave their generated files behind
+	is.NoErr(os.Remove(filepath.Join("b", "b.go")))
+
+	stale, err = StaleOutputs([]string{"./..."}, opts)
+	is.NoErr(err)
+	is.Equal(stale, []string{filepath.Join("b", "README.md")})
+
+	is.NoErr(CleanOutputs(stale, opts))
+
+	_, err = os.Stat("b")
+	is.True(os.IsNotExist(err))
+
+	_, err = os.Stat(filepath.Join("notes", "README.md"))
+	is.NoErr(err)
+}
+
+func TestStaleOutputs_outputDir(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "a", "a.go"), "// Package a is a package.\npackage a\n")
+	writeConfig(t, filepath.Join(dir, "b", "b.go"), "// Package b is a package.\npackage b\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{OutputDir: "docs"}
+	is.NoErr(RunCommand([]string{"./..."}, opts))
+
+	// Renaming a package moves its documentation
+	is.NoErr(os.Rename("b", "c"))
+	is.NoErr(RunCommand([]string{"./..."}, opts))
+
+	stale, err := StaleOutputs([]string{"./..."}, opts)
+	is.NoErr(err)
+	is.Equal(stale, []string{filepath.Join("docs", "b", "index.md")})
+}
+
+func TestStaleOutputs_manifest(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "a", "a.go"), "// Package a is a package.\npackage a\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{Output: "{{.Dir}}/API.md", Manifest: "docs.sha256"}
+	is.NoErr(RunCommand([]string{"./..."}, opts))
+
+	// Files from the manifest are removed even if the Output template changes
+	opts.Output = "{{.Dir}}/README.md"
+	stale, err := StaleOutputs([]string{"./..."}, opts)
+	is.NoErr(err)
+	is.Equal(stale, []string{filepath.Join("a", "API.md")})
+
+	is.NoErr(CleanOutputs(stale, opts))
+
+	manifest, err := ReadManifest(opts.Manifest)
+	is.NoErr(err)
+	is.Equal(len(manifest), 0)
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Valid values for the CaseCollisions option.
+const (
+	// CaseCollisionsError fails the run when two output paths differ only by
+	// case.
+	CaseCollisionsError = "error"
+
+	// CaseCollisionsRename disambiguates output paths that differ only by case
+	// by adding a numeric suffix to the file name.
+	CaseCollisionsRename = "rename"
+
+	// CaseCollisionsIgnore leaves output paths untouched, allowing them to
+	// overwrite each other on case-insensitive filesystems.
+	CaseCollisionsIgnore = "ignore"
+)
+
+// ResolveCaseCollisions detects output files for the provided specs whose
+// paths differ only by case. These paths refer to the same file on
+// case-insensitive filesystems (such as the defaults on macOS and Windows), so
+// they would silently overwrite each other. Depending on the mode, an error is
+// returned or the paths are disambiguated. Specs with identical output paths
+// are intentionally written to the same file and are not considered
+// collisions.
+func ResolveCaseCollisions(specs []*PackageSpec, mode string) error {
+	switch mode {
+	case "", CaseCollisionsError, CaseCollisionsRename:
+	case CaseCollisionsIgnore:
+		return nil
+	default:
+		return fmt.Errorf("gomarkdoc: invalid case collision mode: %s", mode)
+	}
+
+	// Group the distinct output paths by their case-folded form
+	groups := make(map[string][]string)
+	for _, spec := range specs {
+		if spec.OutputFile == "" {
+			continue
+		}
+
+		folded := strings.ToLower(spec.OutputFile)
+
+		var found bool
+		for _, p := range groups[folded] {
+			if p == spec.OutputFile {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			groups[folded] = append(groups[folded], spec.OutputFile)
+		}
+	}
+
+	// Renamed paths must not collide with any other output either, so every
+	// case-folded path in use is tracked
+	taken := make(map[string]bool, len(groups))
+	keys := make([]string, 0, len(groups))
+	for folded := range groups {
+		taken[folded] = true
+		keys = append(keys, folded)
+	}
+
+	sort.Strings(keys)
+
+	renames := make(map[string]string)
+	for _, folded := range keys {
+		paths := groups[folded]
+		if len(paths) < 2 {
+			continue
+		}
+
+		sort.Strings(paths)
+
+		if mode != CaseCollisionsRename {
+			return fmt.Errorf(
+				"gomarkdoc: Output files %s differ only by case and would overwrite each other on case-insensitive filesystems",
+				strings.Join(paths, ", "),
+			)
+		}
+
+		// Keep the first path as-is so that the output is stable as packages
+		// are added.
+		n := 2
+		for _, p := range paths[1:] {
+			ext := filepath.Ext(p)
+			for {
+				renamed := fmt.Sprintf("%s-%d%s", strings.TrimSuffix(p, ext), n, ext)
+				n++
+
+				if !taken[strings.ToLower(renamed)] {
+					taken[strings.ToLower(renamed)] = true
+					renames[p] = renamed
+					break
+				}
+			}
+		}
+	}
+
+	for _, spec := range specs {
+		if renamed, ok := renames[spec.OutputFile]; ok {
+			spec.OutputFile = renamed
+		}
+	}
+
+	return nil
+}
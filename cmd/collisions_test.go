@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestResolveCaseCollisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		outputs []string
+		want    []string
+	}{
+		{
+			name:    "no collisions",
+			mode:    CaseCollisionsRename,
+			outputs: []string{"a/README.md", "b/README.md"},
+			want:    []string{"a/README.md", "b/README.md"},
+		},
+		{
+			name:    "identical paths",
+			mode:    CaseCollisionsError,
+			outputs: []string{"docs/README.md", "docs/README.md"},
+			want:    []string{"docs/README.md", "docs/README.md"},
+		},
+		{
+			name:    "rename",
+			mode:    CaseCollisionsRename,
+			outputs: []string{"docs/readme.md", "docs/README.md", "docs/Readme.md"},
+			want:    []string{"docs/readme-3.md", "docs/README.md", "docs/Readme-2.md"},
+		},
+		{
+			name:    "rename around existing outputs",
+			mode:    CaseCollisionsRename,
+			outputs: []string{"docs/README.md", "docs/readme.md", "docs/readme-2.md", "docs/README-3.md"},
+			want:    []string{"docs/README.md", "docs/readme-4.md", "docs/readme-2.md", "docs/README-3.md"},
+		},
+		{
+			name:    "ignore",
+			mode:    CaseCollisionsIgnore,
+			outputs: []string{"docs/README.md", "docs/readme.md"},
+			want:    []string{"docs/README.md", "docs/readme.md"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			specs := make([]*PackageSpec, len(test.outputs))
+			for i, output := range test.outputs {
+				specs[i] = &PackageSpec{OutputFile: output}
+			}
+
+			is.NoErr(ResolveCaseCollisions(specs, test.mode))
+
+			got := make([]string, len(specs))
+			for i, spec := range specs {
+				got[i] = spec.OutputFile
+			}
+
+			is.Equal(got, test.want)
+		})
+	}
+}
+
+func TestResolveCaseCollisions_error(t *testing.T) {
+	is := is.New(t)
+
+	specs := []*PackageSpec{{OutputFile: "docs/README.md"}, {OutputFile: "docs/readme.md"}}
+
+	err := ResolveCaseCollisions(specs, CaseCollisionsError)
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "docs/README.md, docs/readme.md differ only by case"))
+
+	err = ResolveCaseCollisions(specs, "other")
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "invalid case collision mode: other"))
+}
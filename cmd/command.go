@@ -3,24 +3,23 @@ package cmd
 import (
 	"bytes"
 	"container/list"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"go/build"
 	"hash/fnv"
 	"html/template"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/ag5denis/gomarkdoc"
-	"github.com/ag5denis/gomarkdoc/format"
 	"github.com/ag5denis/gomarkdoc/lang"
 	"github.com/ag5denis/gomarkdoc/logger"
 )
@@ -30,47 +29,252 @@ var version = ""
 
 const configFilePrefix = ".gomarkdoc"
 
-func BuildCommand() *cobra.Command {
+// BuildCommand builds the gomarkdoc command. The provided options can be used
+// to extend the command with additional subcommands and option hooks, so that
+// wrapper binaries can reuse the core command wiring.
+func BuildCommand(buildOpts ...BuildOption) *cobra.Command {
+	var b commandBuilder
+	for _, opt := range buildOpts {
+		opt(&b)
+	}
+
 	var opts CommandOptions
 	var configFile string
+	var command *cobra.Command
+
+	// Subcommands added with WithSubcommand, whose flags may be set in the
+	// configuration file
+	var plugins []*cobra.Command
 
 	// cobra.OnInitialize(func() { BuildConfig(configFile) })
 
-	var command = &cobra.Command{
+	// Resolves the final options and package paths from the flags, the
+	// configuration file and the option hooks. Subcommands share the same
+	// options, so this is used by all of them.
+	resolve := func(args []string) (CommandOptions, []string, error) {
+		if err := BuildConfig(configFile); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		settings, err := loadLocalConfig(configFile)
+		if err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		opts.configFiles, err = usedConfigFiles(configFile)
+		if err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if err := ValidateConfig(settings, ConfigSchema(command, plugins...)); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		// Load configuration from viper
+		opts.IncludeUnexported = viper.GetBool("IncludeUnexported")
+		opts.ExcludeInternal = viper.GetBool("ExcludeInternal")
+		opts.IncludeMain = viper.GetBool("IncludeMain")
+		opts.Exclude = viper.GetStringSlice("Exclude")
+		opts.SkipDirs = viper.GetStringSlice("SkipDirs")
+		opts.FollowSymlinks = viper.GetBool("FollowSymlinks")
+		opts.Output = viper.GetString("Output")
+		opts.SingleFile = viper.GetString("SingleFile")
+		opts.OutputDir = viper.GetString("OutputDir")
+		opts.FileMode = viper.GetString("FileMode")
+		opts.DirMode = viper.GetString("DirMode")
+		opts.Check = viper.GetBool("Check")
+		opts.CheckFormat = viper.GetString("CheckFormat")
+		opts.Annotations = viper.GetString("Annotations")
+		opts.Embed = viper.GetBool("Embed")
+		opts.HeadingOffset = viper.GetInt("HeadingOffset")
+		opts.AnnotateChanges = viper.GetBool("AnnotateChanges")
+		opts.Formats = viper.GetStringSlice("Format")
+		opts.FormatOutputs = viper.GetStringMapString("FormatOutput")
+		opts.TemplateOverrides = viper.GetStringMapString("template")
+		opts.TemplateFileOverrides = viper.GetStringMapString("templateFile")
+		opts.DebugTemplates = viper.GetBool("DebugTemplates")
+		opts.Header = viper.GetString("Header")
+		opts.HeaderFile = viper.GetString("HeaderFile")
+		opts.Footer = viper.GetString("Footer")
+		opts.FooterFile = viper.GetString("FooterFile")
+		opts.Tags = viper.GetStringSlice("Tags")
+		opts.Platforms = viper.GetStringSlice("Platforms")
+		opts.Manifest = viper.GetString("Manifest")
+		opts.Anchors = viper.GetString("Anchors")
+		opts.CaseCollisions = viper.GetString("CaseCollisions")
+		opts.Shard = viper.GetString("Shard")
+		opts.Since = viper.GetString("Since")
+		opts.SinceDeps = viper.GetBool("SinceDeps")
+		opts.Cache = viper.GetString("Cache")
+		opts.SplitExamples = viper.GetBool("SplitExamples")
+		opts.WikiTOC = viper.GetBool("WikiTOC")
+		opts.AnchorStyle = viper.GetString("AnchorStyle")
+		opts.AnchorTemplate = viper.GetString("AnchorTemplate")
+		opts.Locale = viper.GetString("Locale")
+		opts.Playground = viper.GetBool("Playground")
+		opts.NormalizeWhitespace = viper.GetStringSlice("NormalizeWhitespace")
+		opts.LintProfile = viper.GetString("LintProfile")
+		opts.OutputCompat = viper.GetString("OutputCompat")
+		opts.LogFormat = viper.GetString("LogFormat")
+		opts.Quiet = viper.GetBool("Quiet")
+		opts.ImportPath = viper.GetString("ImportPath")
+		opts.OmitDeprecated = viper.GetBool("OmitDeprecated")
+		opts.Order = viper.GetString("Order")
+		opts.MethodOrder = viper.GetString("MethodOrder")
+		opts.GroupReceivers = viper.GetBool("GroupReceivers")
+		opts.ConstructorPrefixes = viper.GetStringSlice("ConstructorPrefix")
+		opts.ConstructorSuffixes = viper.GetStringSlice("ConstructorSuffix")
+		opts.UngroupConstructors = viper.GetBool("UngroupConstructors")
+		opts.Implements = viper.GetBool("Implements")
+		opts.ImplementsStdlib = viper.GetBool("ImplementsStdlib")
+		opts.ConstValues = viper.GetBool("ConstValues")
+		opts.ExternalLinks = viper.GetBool("ExternalLinks")
+		opts.OpaqueTypes = viper.GetBool("OpaqueTypes")
+		opts.ExternalTests = viper.GetBool("ExternalTests")
+		opts.FuzzTargets = viper.GetBool("FuzzTargets")
+		opts.GenerateDirectives = viper.GetBool("GenerateDirectives")
+		opts.Notes = viper.GetStringSlice("Notes")
+		opts.TaggedExamples = viper.GetBool("TaggedExamples")
+		opts.KeepGoing = viper.GetBool("KeepGoing")
+		opts.FieldTable = viper.GetBool("FieldTable")
+		opts.IncludeSource = viper.GetBool("IncludeSource")
+		opts.ReferenceLinks = viper.GetBool("ReferenceLinks")
+		opts.CrossPackageLinks = viper.GetBool("CrossPackageLinks")
+		opts.PackageTable = viper.GetBool("PackageTable")
+		opts.Subpackages = viper.GetBool("Subpackages")
+		opts.HiddenSections = resolveHiddenSections()
+		opts.Audit = viper.GetBool("Audit")
+		opts.Repository.Remote = viper.GetString("Repository.url")
+		opts.Repository.DefaultBranch = viper.GetString("Repository.defaultBranch")
+		opts.Repository.PathFromRoot = viper.GetString("Repository.path")
+		opts.Repository.LinkRoot = viper.GetString("Repository.linkRoot")
+		opts.Hooks.Pre = viper.GetStringSlice("Hooks.pre")
+		opts.Hooks.Post = viper.GetStringSlice("Hooks.post")
+		opts.Vars = viper.GetStringMap("Vars")
+		opts.Translations = viper.GetStringMapString("Translations")
+
+		opts.flagged = make(map[string]bool)
+		for key, name := range dirConfigFlags {
+			if f := command.Flags().Lookup(name); (f != nil && f.Changed) || envSet(name) {
+				opts.flagged[key] = true
+			}
+		}
+
+		// Opaque types are documented by default since v1.2.0, so output pinned
+		// to an earlier release leaves them out unless they are requested
+		if opts.OutputCompat != "" && !viper.IsSet("OpaqueTypes") {
+			reverted, err := gomarkdoc.OutputChangeReverted(opts.OutputCompat, "opaque-types")
+			if err != nil {
+				return CommandOptions{}, nil, err
+			}
+
+			opts.OpaqueTypes = opts.OpaqueTypes && !reverted
+		}
+
+		for _, hook := range b.optionHooks {
+			if err := hook(&opts); err != nil {
+				return CommandOptions{}, nil, err
+			}
+		}
+
+		if err := ValidateFormats(opts); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if err := ValidateSkipDirs(opts); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if err := ValidateModes(opts); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if err := ValidateOutput(opts); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if err := ValidateCheckFormat(opts); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if err := ValidateAnnotations(opts); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if _, err := ParsePlatforms(opts.Platforms); err != nil {
+			return CommandOptions{}, nil, err
+		}
+
+		if opts.Quiet && opts.Verbosity > 0 {
+			return CommandOptions{}, nil, errors.New("gomarkdoc: --quiet and --verbose cannot be used together")
+		}
+
+		switch logger.Format(opts.LogFormat) {
+		case "", logger.TextFormat, logger.JSONFormat:
+		default:
+			return CommandOptions{}, nil, fmt.Errorf("gomarkdoc: invalid log format: %s", opts.LogFormat)
+		}
+
+		if opts.Check {
+			for _, f := range ResolveFormats(opts) {
+				if opts.FormatOutput(f) == "" {
+					return CommandOptions{}, nil, errors.New("gomarkdoc: Check mode cannot be run without an Output set")
+				}
+			}
+		}
+
+		if opts.Preview {
+			if opts.Check {
+				return CommandOptions{}, nil, errors.New("gomarkdoc: changes cannot be previewed in Check mode")
+			}
+
+			for _, f := range ResolveFormats(opts) {
+				if opts.FormatOutput(f) == "" {
+					return CommandOptions{}, nil, errors.New("gomarkdoc: changes cannot be previewed without an Output set")
+				}
+			}
+		}
+
+		if opts.AnnotateChanges && (!opts.Embed || opts.Check) {
+			return CommandOptions{}, nil, errors.New("gomarkdoc: signature changes can only be annotated when embedding outside of Check mode")
+		}
+
+		if opts.Anchors != "" && anchorsFormat(opts) == "" {
+			return CommandOptions{}, nil, errors.New("gomarkdoc: anchors can only be resolved for markdown formats")
+		}
+
+		if opts.SplitExamples {
+			for _, f := range ResolveFormats(opts) {
+				if opts.FormatOutput(f) == "" && !IsDataFormat(f) {
+					return CommandOptions{}, nil, errors.New("gomarkdoc: examples cannot be split without an Output set")
+				}
+			}
+		}
+
+		if len(args) == 0 {
+			// Default to current directory
+			args = []string{"."}
+		}
+
+		return opts, args, nil
+	}
+
+	command = &cobra.Command{
 		Use:   "gomarkdoc [package ...]",
 		Short: "generate markdown documentation for golang code",
+		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if opts.Version {
-				PrintVersion()
-				return nil
+				return WriteVersion(cmd.OutOrStdout(), opts.VersionFormat)
 			}
 
-			BuildConfig(configFile)
-
-			// Load configuration from viper
-			opts.IncludeUnexported = viper.GetBool("IncludeUnexported")
-			opts.Output = viper.GetString("Output")
-			opts.Check = viper.GetBool("Check")
-			opts.Embed = viper.GetBool("Embed")
-			opts.Format = viper.GetString("Format")
-			opts.TemplateOverrides = viper.GetStringMapString("template")
-			opts.TemplateFileOverrides = viper.GetStringMapString("templateFile")
-			opts.Header = viper.GetString("Header")
-			opts.HeaderFile = viper.GetString("HeaderFile")
-			opts.Footer = viper.GetString("Footer")
-			opts.FooterFile = viper.GetString("FooterFile")
-			opts.Tags = viper.GetStringSlice("Tags")
-			opts.Repository.Remote = viper.GetString("Repository.url")
-			opts.Repository.DefaultBranch = viper.GetString("Repository.defaultBranch")
-			opts.Repository.PathFromRoot = viper.GetString("Repository.path")
-
-			if opts.Check && opts.Output == "" {
-				return errors.New("gomarkdoc: Check mode cannot be run without an Output set")
+			opts, args, err := resolve(args)
+			if err != nil {
+				return err
 			}
 
-			if len(args) == 0 {
-				// Default to current directory
-				args = []string{"."}
+			if opts.ServeAddr != "" {
+				return Serve(opts.ServeAddr, args, opts)
 			}
 
 			return RunCommand(args, opts)
@@ -90,6 +294,36 @@ func BuildCommand() *cobra.Command {
 		false,
 		"Output documentation for unexported symbols, methods and fields in addition to exported ones.",
 	)
+	command.Flags().BoolVar(
+		&opts.ExcludeInternal,
+		"exclude-internal",
+		false,
+		"Skip packages located within an internal directory when expanding recursive paths. Packages requested explicitly are still documented.",
+	)
+	command.Flags().BoolVar(
+		&opts.IncludeMain,
+		"include-main",
+		false,
+		"Document main packages found when expanding recursive paths, which are skipped otherwise. Main packages are documented as commands, with install instructions and the flags and cobra commands declared in their source.",
+	)
+	command.Flags().StringSliceVar(
+		&opts.Exclude,
+		"exclude",
+		nil,
+		"Skip the directories matching a pattern, along with the directories beneath them, when expanding recursive paths. Patterns are globs matched against the path relative to the working directory, where ** matches any number of directories (e.g. **/mocks), or regular expressions prefixed with re:. Packages requested explicitly are still documented. Can be specified multiple times.",
+	)
+	command.Flags().StringSliceVar(
+		&opts.SkipDirs,
+		"skip-dirs",
+		DefaultSkipDirs,
+		"Names of the directories to skip, along with their contents, when expanding package patterns such as ./.... Names are globs matched against each directory's base name (e.g. .* skips hidden directories). Providing this option replaces the defaults. Can be specified multiple times.",
+	)
+	command.Flags().BoolVar(
+		&opts.FollowSymlinks,
+		"follow-symlinks",
+		false,
+		"Follow symlinks to directories when expanding package patterns such as ./.... By default they are skipped. Each directory is only visited once, so links that point back up the tree don't cause a loop.",
+	)
 	command.Flags().StringVarP(
 		&opts.Output,
 		"Output",
@@ -97,6 +331,30 @@ func BuildCommand() *cobra.Command {
 		"",
 		"File or pattern specifying where to write documentation Output. Defaults to printing to stdout.",
 	)
+	command.Flags().StringVar(
+		&opts.SingleFile,
+		"single-file",
+		"",
+		"Write the documentation for all packages to a single file, with a table of contents linking to a section for each package and anchors deduplicated across the packages. Takes the place of --Output.",
+	)
+	command.Flags().StringVar(
+		&opts.OutputDir,
+		"output-dir",
+		"",
+		"Directory to mirror the package tree into, writing the documentation for each package to index.md within the package's path (e.g. docs/lang/index.md for ./lang). Takes the place of --Output.",
+	)
+	command.Flags().StringVar(
+		&opts.FileMode,
+		"file-mode",
+		"",
+		"Octal permission bits to set on the Output files (e.g. 0644), regardless of the umask. Defaults to creating files with 0664 masked by the umask and leaving the permissions of existing files unchanged.",
+	)
+	command.Flags().StringVar(
+		&opts.DirMode,
+		"dir-mode",
+		"",
+		"Octal permission bits to set on the directories created for Output files (e.g. 0755), regardless of the umask. Defaults to 0755 masked by the umask.",
+	)
 	command.Flags().BoolVarP(
 		&opts.Check,
 		"Check",
@@ -104,6 +362,18 @@ func BuildCommand() *cobra.Command {
 		false,
 		"Check the Output to see if it matches the generated documentation. --Output must be specified to use this.",
 	)
+	command.Flags().StringVar(
+		&opts.CheckFormat,
+		"check-format",
+		CheckFormatText,
+		"Format of the report of the files that don't match in Check mode. Valid options: text (listed in the error), json or sarif (written to stdout for code scanning tools and bots).",
+	)
+	command.Flags().StringVar(
+		&opts.Annotations,
+		"annotations",
+		"",
+		"Annotations to write to stdout for the files that don't match in Check mode and for lint issues, so that they show up inline on pull requests. Valid options: github or none. Defaults to github when running within GitHub Actions.",
+	)
 	command.Flags().BoolVarP(
 		&opts.Embed,
 		"Embed",
@@ -111,12 +381,30 @@ func BuildCommand() *cobra.Command {
 		false,
 		"Embed documentation into existing markdown files if available, otherwise append to file.",
 	)
-	command.Flags().StringVarP(
-		&opts.Format,
+	command.Flags().BoolVar(
+		&opts.AnnotateChanges,
+		"annotate-changes",
+		false,
+		"When embedding, mark the symbols whose signatures differ from the previously embedded documentation so that reviewers can focus on API changes. Cannot be used with --Check.",
+	)
+	command.Flags().IntVar(
+		&opts.HeadingOffset,
+		"heading-offset",
+		0,
+		"Increase the level of every heading by the provided amount (0-5), so that the documentation starts at ## or ### when embedded in a file that already has a title.",
+	)
+	command.Flags().StringSliceVarP(
+		&opts.Formats,
 		"Format",
 		"f",
-		"github",
-		"Format to use for writing Output data. Valid options: github (default), azure-devops, plain",
+		[]string{"github"},
+		"Format to use for writing Output data. Valid options: github (default), azure-devops, plain, llms, yaml, docfx. May be repeated or comma-separated to write multiple formats in a single run.",
+	)
+	command.Flags().StringToStringVar(
+		&opts.FormatOutputs,
+		"format-output",
+		map[string]string{},
+		"File or pattern specifying where to write documentation Output for the provided Format instead of the --Output value.",
 	)
 	command.Flags().StringToStringVarP(
 		&opts.TemplateOverrides,
@@ -129,7 +417,13 @@ func BuildCommand() *cobra.Command {
 		&opts.TemplateFileOverrides,
 		"template-file",
 		map[string]string{},
-		"Custom template file to use for the provided template name instead of the default template.",
+		"Custom template file to use for the provided template name instead of the default template. Use - to read from stdin or an http(s) URL to download the template.",
+	)
+	command.Flags().BoolVar(
+		&opts.DebugTemplates,
+		"debug-templates",
+		false,
+		"Annotate the output with the template that produced each part of it and report template errors with the template name, line and data involved.",
 	)
 	command.Flags().StringVar(
 		&opts.Header,
@@ -141,7 +435,7 @@ func BuildCommand() *cobra.Command {
 		&opts.HeaderFile,
 		"Header-file",
 		"",
-		"File containing additional content to inject at the beginning of each Output file.",
+		"File containing additional content to inject at the beginning of each Output file. Use - to read from stdin or an http(s) URL to download the content.",
 	)
 	command.Flags().StringVar(
 		&opts.Footer,
@@ -153,7 +447,7 @@ func BuildCommand() *cobra.Command {
 		&opts.FooterFile,
 		"Footer-file",
 		"",
-		"File containing additional content to inject at the end of each Output file.",
+		"File containing additional content to inject at the end of each Output file. Use - to read from stdin or an http(s) URL to download the content.",
 	)
 	command.Flags().StringSliceVar(
 		&opts.Tags,
@@ -161,12 +455,283 @@ func BuildCommand() *cobra.Command {
 		DefaultTags(),
 		"Set of build Tags to apply when choosing which files to include for documentation generation.",
 	)
+	command.Flags().StringSliceVar(
+		&opts.Platforms,
+		"platforms",
+		nil,
+		"Platforms for which to document the packages, in the form GOOS/GOARCH with optional build tags after a colon separated by plus signs (e.g. linux/amd64:netgo+osusergo). The documentation of all of the platforms is merged, and symbols that are only declared on some of them are annotated with those platforms. Can be specified multiple times.",
+	)
+	command.Flags().StringVar(
+		&opts.Manifest,
+		"manifest",
+		"",
+		"File in which to record the paths and content hashes of the generated Output files. In Check mode, the Output is validated against this file as well as the Output files.",
+	)
+	command.Flags().StringVar(
+		&opts.Anchors,
+		"anchors",
+		"",
+		"File to which a JSON map of the Output file and anchor of each documented symbol is written, for tools that link into the generated documentation. Anchors are resolved for the first markdown Format.",
+	)
+	command.Flags().StringVar(
+		&opts.CaseCollisions,
+		"case-collisions",
+		CaseCollisionsError,
+		"Behavior when Output files differ only by case, which collide on case-insensitive filesystems. Valid options: error (default), rename, ignore",
+	)
+	command.Flags().StringVar(
+		&opts.Shard,
+		"shard",
+		"",
+		"Only process the Nth of M deterministic partitions of the packages, specified as N/M. Useful for splitting large repositories across parallel jobs.",
+	)
+	command.Flags().StringVar(
+		&opts.Since,
+		"since",
+		"",
+		"Only process the local packages with files that changed since the provided git revision, including uncommitted and untracked changes.",
+	)
+	command.Flags().BoolVar(
+		&opts.SinceDeps,
+		"since-deps",
+		false,
+		"With --since, also process the packages that import a changed package, directly or through other packages being documented.",
+	)
+	command.Flags().StringVar(
+		&opts.Cache,
+		"cache",
+		"",
+		"File in which the inputs and contents of each Output file are recorded, so that later runs skip the packages whose Output files are unchanged.",
+	)
+	command.Flags().BoolVar(
+		&opts.SplitExamples,
+		"split-examples",
+		false,
+		"Write examples to a separate file alongside each Output file, named like README.examples.md for README.md, and link to it from the main documentation.",
+	)
+	command.Flags().BoolVar(
+		&opts.WikiTOC,
+		"wiki-toc",
+		false,
+		"Emit the wiki's table of contents macro in place of the generated index for formats that support it (azure-devops).",
+	)
+	command.Flags().StringVar(
+		&opts.AnchorStyle,
+		"anchor-style",
+		"",
+		"How to generate the anchors that links to headers point to. Valid options are: format (match the renderer the Format targets), github, azure-devops and explicit (add an <a id> tag before each header).",
+	)
+	command.Flags().StringVar(
+		&opts.AnchorTemplate,
+		"anchor-template",
+		"",
+		"Template generating the anchor of each header from its text, for renderers that generate anchors in other ways. See the package documentation for details.",
+	)
+	command.Flags().StringVar(
+		&opts.Locale,
+		"locale",
+		"",
+		fmt.Sprintf("Translate the headings and labels of the documentation into the language of the locale. Valid options are: %s. Translations in the configuration file take precedence.", strings.Join(gomarkdoc.Locales(), ", ")),
+	)
+	command.Flags().BoolVar(
+		&opts.Playground,
+		"playground",
+		false,
+		"Add a link to run each standalone example on the Go Playground. The example code is shared publicly when the Output is written, so this should only be used for public modules. Code is never shared in Check mode.",
+	)
+	command.Flags().StringSliceVar(
+		&opts.NormalizeWhitespace,
+		"normalize-whitespace",
+		nil,
+		"Formats for which to strip trailing whitespace, collapse consecutive blank lines and end each Output file with a single newline.",
+	)
+	command.Flags().StringVar(
+		&opts.LintProfile,
+		"lint-profile",
+		"",
+		"Adjust the generated documentation to satisfy the default rules of a markdown linter. Valid options are: markdownlint",
+	)
+	command.Flags().StringVar(
+		&opts.OutputCompat,
+		"output-compat",
+		"",
+		"Revert the changes to the default output introduced after an older gomarkdoc release, such as v1.1.0, so that upgrading changes less of the existing Output files. Only the documented set of changes is reverted.",
+	)
+	command.Flags().StringVar(
+		&opts.ImportPath,
+		"import-path",
+		"",
+		"Import path to use for the current directory when documenting packages that are neither in a Go module nor in the GOPATH. Packages in subdirectories are given import paths beneath it.",
+	)
+	command.Flags().BoolVar(
+		&opts.OmitDeprecated,
+		"omit-deprecated",
+		false,
+		"Leave symbols whose documentation marks them as deprecated out of the documentation.",
+	)
+	command.Flags().StringVar(
+		&opts.Order,
+		"order",
+		string(lang.OrderAlphabetical),
+		"Order in which the declarations of each package are documented. Valid options are: alphabetical, source (funcs and types interleaved as they appear in the source) and kind (grouped by kind, in source order within each kind).",
+	)
+	command.Flags().StringVar(
+		&opts.MethodOrder,
+		"method-order",
+		"",
+		"Order in which the methods of each type are documented. Valid options are: alphabetical, source and exported (exported methods first, each group in source order). Defaults to the order of the declarations set by --order.",
+	)
+	command.Flags().BoolVar(
+		&opts.GroupReceivers,
+		"group-receivers",
+		false,
+		"Document the methods of each type with a value receiver before those with a pointer receiver.",
+	)
+	command.Flags().StringSliceVar(
+		&opts.ConstructorPrefixes,
+		"constructor-prefix",
+		nil,
+		"Name prefix of functions to document with the type they construct, such as Make for MakeWidget. Functions returning the type are grouped with it regardless of their name. Can be specified multiple times.",
+	)
+	command.Flags().StringSliceVar(
+		&opts.ConstructorSuffixes,
+		"constructor-suffix",
+		nil,
+		"Name suffix of functions to document with the type they construct, such as FromFile for WidgetFromFile. Can be specified multiple times.",
+	)
+	command.Flags().BoolVar(
+		&opts.UngroupConstructors,
+		"ungroup-constructors",
+		false,
+		"Document all functions at the top level of the package rather than grouping constructors with the types they return.",
+	)
+	command.Flags().BoolVar(
+		&opts.Implements,
+		"implements",
+		false,
+		"List the interfaces from the package and the rest of its module that each type implements.",
+	)
+	command.Flags().BoolVar(
+		&opts.ImplementsStdlib,
+		"implements-stdlib",
+		false,
+		"Include well-known interfaces from the standard library, such as fmt.Stringer and io.Reader, when listing the interfaces that each type implements. Implies --implements.",
+	)
+	command.Flags().BoolVar(
+		&opts.ConstValues,
+		"const-values",
+		false,
+		"Show the resolved value of each constant whose value isn't evident from its declaration, such as constants defined using iota, and the value of each variable initialized with a literal or a sentinel error.",
+	)
+	command.Flags().BoolVar(
+		&opts.ExternalLinks,
+		"external-links",
+		false,
+		"Link the types from the standard library and other modules that appear in function signatures and type declarations to their documentation on pkg.go.dev.",
+	)
+	command.Flags().BoolVar(
+		&opts.OpaqueTypes,
+		"opaque-types",
+		true,
+		"Document the exported methods of unexported types that appear in the signatures of exported functions, such as opaque handles. Has no effect with --include-unexported. Use --opaque-types=false to disable.",
+	)
+	command.Flags().BoolVar(
+		&opts.ExternalTests,
+		"external-tests",
+		false,
+		"Document the symbols declared in each package's external test package (package foo_test), such as shared fixtures and helpers.",
+	)
+	command.Flags().BoolVar(
+		&opts.FuzzTargets,
+		"fuzz-targets",
+		false,
+		"List the fuzz targets (Fuzz* functions) declared in each package's test files, along with their doc comments, in a Fuzzing section.",
+	)
+	command.Flags().BoolVar(
+		&opts.GenerateDirectives,
+		"generate-directives",
+		false,
+		"List the //go:generate directives in each package's files in a Code Generation section, so readers know which files are generated and how to regenerate them.",
+	)
+	command.Flags().StringSliceVar(
+		&opts.Notes,
+		"notes",
+		lang.DefaultNoteMarkers,
+		"Markers of the notes to document in a Notes section at the end of each package, for comments written in the form MARKER(uid): body, such as BUG(name): or TODO(name):. Pass an empty value to omit notes.",
+	)
+	command.Flags().BoolVar(
+		&opts.TaggedExamples,
+		"tagged-examples",
+		false,
+		"Include examples from test files that require build tags which weren't provided with --Tags, annotated with the tags they require.",
+	)
+	command.Flags().BoolVar(
+		&opts.KeepGoing,
+		"keep-going",
+		false,
+		"Continue past packages that fail to load and Output files that fail to render or write, reporting all of the failures at the end of the run.",
+	)
+	command.Flags().BoolVar(
+		&opts.FieldTable,
+		"field-table",
+		false,
+		"Add a table to each struct type listing the name, type, json/yaml/env struct tags and doc comment of each field, for formats that support tables (github, azure-devops).",
+	)
+	command.Flags().BoolVar(
+		&opts.IncludeSource,
+		"include-source",
+		false,
+		"Include the full source code of each type and func declaration in a collapsible block beneath its documentation.",
+	)
+	command.Flags().BoolVar(
+		&opts.ReferenceLinks,
+		"reference-links",
+		false,
+		"Emit reference-style links with the link definitions collected at the bottom of each Output file instead of inline links.",
+	)
+	command.Flags().BoolVar(
+		&opts.CrossPackageLinks,
+		"cross-package-links",
+		false,
+		"Link to the generated documentation of other packages documented in the same run in place of their documentation on pkg.go.dev.",
+	)
+	command.Flags().BoolVar(
+		&opts.PackageTable,
+		"package-table",
+		false,
+		"Add a table to the documentation of each module root package listing the other packages documented in the same run that are located within the module, with their synopses and links to their documentation.",
+	)
+	command.Flags().BoolVar(
+		&opts.Subpackages,
+		"subpackages",
+		false,
+		"Add a Subpackages section to the documentation of each package listing its immediate child packages documented in the same run, with their synopses and links to their documentation.",
+	)
+	command.Flags().BoolVar(
+		&opts.Audit,
+		"audit",
+		false,
+		"Check each Output file for accessibility issues, such as links without text, headings that skip levels and images without alt text, and fail if any are found.",
+	)
 	command.Flags().CountVarP(
 		&opts.Verbosity,
 		"verbose",
 		"v",
 		"Log additional Output from the execution of the command. Can be chained for additional Verbosity.",
 	)
+	command.Flags().BoolVarP(
+		&opts.Quiet,
+		"quiet",
+		"q",
+		false,
+		"Only log errors, suppressing warnings. Logs are always written to stderr, keeping them separate from documentation printed to stdout.",
+	)
+	command.Flags().StringVar(
+		&opts.LogFormat,
+		"log-format",
+		string(logger.TextFormat),
+		"Format of the log records. Valid options are: text and json (one object per record with its level, message, time and fields such as the package directory).",
+	)
 	command.Flags().StringVar(
 		&opts.Repository.Remote,
 		"Repository.url",
@@ -185,88 +750,463 @@ func BuildCommand() *cobra.Command {
 		"",
 		"Manual override for the path from the root of the git Repository used in place of automatic detection.",
 	)
+	command.Flags().StringVar(
+		&opts.Repository.LinkRoot,
+		"Repository.link-root",
+		"",
+		"Path from the root of the git Repository that source links are relative to, for remotes that only host part of the Repository. Defaults to the root of the Repository.",
+	)
 	command.Flags().BoolVar(
 		&opts.Version,
 		"Version",
 		false,
 		"Print the Version.",
 	)
+	command.Flags().StringVar(
+		&opts.VersionFormat,
+		"version-format",
+		VersionShort,
+		"Format in which to print the Version. Valid options: short (the Version alone), full (with the commit, build date, Go Version and module checksum) or json.",
+	)
 
 	// We ignore the errors here because they only happen if the specified flag doesn't exist
 	_ = viper.BindPFlag("IncludeUnexported", command.Flags().Lookup("include-unexported"))
+	_ = viper.BindPFlag("Exclude", command.Flags().Lookup("exclude"))
+	_ = viper.BindPFlag("ExcludeInternal", command.Flags().Lookup("exclude-internal"))
+	_ = viper.BindPFlag("IncludeMain", command.Flags().Lookup("include-main"))
+	_ = viper.BindPFlag("SkipDirs", command.Flags().Lookup("skip-dirs"))
+	_ = viper.BindPFlag("FollowSymlinks", command.Flags().Lookup("follow-symlinks"))
 	_ = viper.BindPFlag("Output", command.Flags().Lookup("Output"))
+	_ = viper.BindPFlag("SingleFile", command.Flags().Lookup("single-file"))
+	_ = viper.BindPFlag("OutputDir", command.Flags().Lookup("output-dir"))
+	_ = viper.BindPFlag("FileMode", command.Flags().Lookup("file-mode"))
+	_ = viper.BindPFlag("DirMode", command.Flags().Lookup("dir-mode"))
 	_ = viper.BindPFlag("Check", command.Flags().Lookup("Check"))
+	_ = viper.BindPFlag("CheckFormat", command.Flags().Lookup("check-format"))
+	_ = viper.BindPFlag("Annotations", command.Flags().Lookup("annotations"))
 	_ = viper.BindPFlag("Embed", command.Flags().Lookup("Embed"))
+	_ = viper.BindPFlag("HeadingOffset", command.Flags().Lookup("heading-offset"))
+	_ = viper.BindPFlag("AnnotateChanges", command.Flags().Lookup("annotate-changes"))
 	_ = viper.BindPFlag("Format", command.Flags().Lookup("Format"))
+	_ = viper.BindPFlag("FormatOutput", command.Flags().Lookup("format-output"))
 	_ = viper.BindPFlag("template", command.Flags().Lookup("template"))
 	_ = viper.BindPFlag("templateFile", command.Flags().Lookup("template-file"))
+	_ = viper.BindPFlag("DebugTemplates", command.Flags().Lookup("debug-templates"))
 	_ = viper.BindPFlag("Header", command.Flags().Lookup("Header"))
 	_ = viper.BindPFlag("HeaderFile", command.Flags().Lookup("Header-file"))
 	_ = viper.BindPFlag("Footer", command.Flags().Lookup("Footer"))
 	_ = viper.BindPFlag("FooterFile", command.Flags().Lookup("Footer-file"))
 	_ = viper.BindPFlag("Tags", command.Flags().Lookup("Tags"))
+	_ = viper.BindPFlag("Platforms", command.Flags().Lookup("platforms"))
+	_ = viper.BindPFlag("Manifest", command.Flags().Lookup("manifest"))
+	_ = viper.BindPFlag("Anchors", command.Flags().Lookup("anchors"))
+	_ = viper.BindPFlag("CaseCollisions", command.Flags().Lookup("case-collisions"))
+	_ = viper.BindPFlag("Shard", command.Flags().Lookup("shard"))
+	_ = viper.BindPFlag("Since", command.Flags().Lookup("since"))
+	_ = viper.BindPFlag("SinceDeps", command.Flags().Lookup("since-deps"))
+	_ = viper.BindPFlag("Cache", command.Flags().Lookup("cache"))
+	_ = viper.BindPFlag("SplitExamples", command.Flags().Lookup("split-examples"))
+	_ = viper.BindPFlag("WikiTOC", command.Flags().Lookup("wiki-toc"))
+	_ = viper.BindPFlag("AnchorStyle", command.Flags().Lookup("anchor-style"))
+	_ = viper.BindPFlag("AnchorTemplate", command.Flags().Lookup("anchor-template"))
+	_ = viper.BindPFlag("Locale", command.Flags().Lookup("locale"))
+	_ = viper.BindPFlag("Playground", command.Flags().Lookup("playground"))
+	_ = viper.BindPFlag("NormalizeWhitespace", command.Flags().Lookup("normalize-whitespace"))
+	_ = viper.BindPFlag("LintProfile", command.Flags().Lookup("lint-profile"))
+	_ = viper.BindPFlag("LogFormat", command.Flags().Lookup("log-format"))
+	_ = viper.BindPFlag("Quiet", command.Flags().Lookup("quiet"))
+	_ = viper.BindPFlag("OutputCompat", command.Flags().Lookup("output-compat"))
+	_ = viper.BindPFlag("ImportPath", command.Flags().Lookup("import-path"))
+	_ = viper.BindPFlag("OmitDeprecated", command.Flags().Lookup("omit-deprecated"))
+	_ = viper.BindPFlag("Order", command.Flags().Lookup("order"))
+	_ = viper.BindPFlag("MethodOrder", command.Flags().Lookup("method-order"))
+	_ = viper.BindPFlag("GroupReceivers", command.Flags().Lookup("group-receivers"))
+	_ = viper.BindPFlag("ConstructorPrefix", command.Flags().Lookup("constructor-prefix"))
+	_ = viper.BindPFlag("ConstructorSuffix", command.Flags().Lookup("constructor-suffix"))
+	_ = viper.BindPFlag("UngroupConstructors", command.Flags().Lookup("ungroup-constructors"))
+	_ = viper.BindPFlag("Implements", command.Flags().Lookup("implements"))
+	_ = viper.BindPFlag("ImplementsStdlib", command.Flags().Lookup("implements-stdlib"))
+	_ = viper.BindPFlag("ConstValues", command.Flags().Lookup("const-values"))
+	_ = viper.BindPFlag("ExternalLinks", command.Flags().Lookup("external-links"))
+	_ = viper.BindPFlag("OpaqueTypes", command.Flags().Lookup("opaque-types"))
+	_ = viper.BindPFlag("ExternalTests", command.Flags().Lookup("external-tests"))
+	_ = viper.BindPFlag("FuzzTargets", command.Flags().Lookup("fuzz-targets"))
+	_ = viper.BindPFlag("GenerateDirectives", command.Flags().Lookup("generate-directives"))
+	_ = viper.BindPFlag("Notes", command.Flags().Lookup("notes"))
+	_ = viper.BindPFlag("TaggedExamples", command.Flags().Lookup("tagged-examples"))
+	_ = viper.BindPFlag("KeepGoing", command.Flags().Lookup("keep-going"))
+	_ = viper.BindPFlag("FieldTable", command.Flags().Lookup("field-table"))
+	_ = viper.BindPFlag("IncludeSource", command.Flags().Lookup("include-source"))
+	_ = viper.BindPFlag("ReferenceLinks", command.Flags().Lookup("reference-links"))
+	_ = viper.BindPFlag("CrossPackageLinks", command.Flags().Lookup("cross-package-links"))
+	_ = viper.BindPFlag("PackageTable", command.Flags().Lookup("package-table"))
+	_ = viper.BindPFlag("Subpackages", command.Flags().Lookup("subpackages"))
+	addSectionFlags(command.Flags())
+	_ = viper.BindPFlag("Audit", command.Flags().Lookup("audit"))
 	_ = viper.BindPFlag("Repository.url", command.Flags().Lookup("Repository.url"))
 	_ = viper.BindPFlag("Repository.defaultBranch", command.Flags().Lookup("Repository.default-branch"))
 	_ = viper.BindPFlag("Repository.path", command.Flags().Lookup("Repository.path"))
+	_ = viper.BindPFlag("Repository.linkRoot", command.Flags().Lookup("Repository.link-root"))
+
+	bindEnv(command)
+
+	// The preview-diff command accepts the same flags as the root command so
+	// that it renders exactly what a normal run would write.
+	previewDiff := &cobra.Command{
+		Use:   "preview-diff [package ...]",
+		Short: "print a diff of the changes that generating documentation would make to the Output files",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Preview = true
+			return command.RunE(cmd, args)
+		},
+	}
+
+	previewDiff.Flags().AddFlagSet(command.Flags())
+	command.AddCommand(previewDiff)
+
+	var addr string
+	serve := &cobra.Command{
+		Use:   "serve [package ...]",
+		Short: "serve the documentation model of the packages over a JSON query API",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ServeAddr = addr
+			return command.RunE(cmd, args)
+		},
+	}
+
+	serve.Flags().StringVar(
+		&addr,
+		"addr",
+		"localhost:8080",
+		"Address on which to serve documentation queries.",
+	)
+	serve.Flags().AddFlagSet(command.Flags())
+	command.AddCommand(serve)
+
+	// The templates subcommands read the template overrides from the same
+	// flags and configuration as the root command.
+	templates := buildTemplatesCommand(resolve)
+	for _, sub := range templates.Commands() {
+		sub.Flags().AddFlagSet(command.Flags())
+	}
+
+	command.AddCommand(templates)
+
+	lint := buildLintCommand(resolve)
+	lint.Flags().AddFlagSet(command.Flags())
+	command.AddCommand(lint)
+
+	stats := buildStatsCommand(resolve)
+	stats.Flags().AddFlagSet(command.Flags())
+	command.AddCommand(stats)
+
+	diff := buildDiffCommand(resolve)
+	diff.Flags().AddFlagSet(command.Flags())
+	command.AddCommand(diff)
+
+	clean := buildCleanCommand(resolve)
+	clean.Flags().AddFlagSet(command.Flags())
+	command.AddCommand(clean)
+
+	for _, build := range b.subcommands {
+		sub := build(resolve)
+		sub.Flags().AddFlagSet(command.Flags())
+		command.AddCommand(sub)
+		plugins = append(plugins, sub)
+	}
+
+	registerCompletions(command)
+	command.AddCommand(buildCompletionCommand(command))
+
+	return command
+}
+
+func DefaultTags() []string {
+	f, ok := os.LookupEnv("GOFLAGS")
+	if !ok {
+		return nil
+	}
+
+	fs := flag.NewFlagSet("goflags", flag.ContinueOnError)
+	tags := fs.String("tags", "", "")
+
+	if err := fs.Parse(strings.Fields(f)); err != nil {
+		return nil
+	}
+
+	if tags == nil {
+		return nil
+	}
+
+	return strings.Split(*tags, ",")
+}
+
+func BuildConfig(configFile string) error {
+	viper.SetEnvPrefix(envPrefix)
+	viper.AutomaticEnv()
+
+	presetConfigFile = ""
+
+	if configFile == "" {
+		return buildDiscoveredConfig()
+	}
+
+	viper.SetConfigFile(configFile)
+
+	// Discovered configuration is loaded as JSON, and viper keeps that type
+	// until another one is set, so the type is taken from the file instead.
+	viper.SetConfigType(strings.TrimPrefix(filepath.Ext(configFile), "."))
+
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("gomarkdoc: failed to read config file %s: %w", configFile, err)
+	}
+
+	return ApplyPreset(viper.GetViper())
+}
+
+// buildDiscoveredConfig loads the configuration files found in the working
+// directory and its parents, with files closer to the working directory
+// taking precedence.
+func buildDiscoveredConfig() error {
+	files, err := FindConfigFiles(".")
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	local, err := loadConfigFiles(files)
+	if err != nil {
+		return err
+	}
+
+	// Reading the merged settings replaces any configuration loaded by a
+	// previous run rather than merging into it.
+	b, err := json.Marshal(local)
+	if err != nil {
+		return fmt.Errorf("gomarkdoc: failed to load configuration: %w", err)
+	}
+
+	viper.SetConfigType("json")
+	err = viper.ReadConfig(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("gomarkdoc: failed to load configuration: %w", err)
+	}
+
+	return applyPreset(viper.GetViper(), local)
+}
+
+func RunCommand(paths []string, opts CommandOptions) error {
+	formats := ResolveFormats(opts)
+
+	shard, err := ParseShard(opts.Shard)
+	if err != nil {
+		return err
+	}
+
+	excludes, err := ParseExcludePatterns(opts.Exclude)
+	if err != nil {
+		return err
+	}
+
+	// Parse all of the Output templates up front so that we don't load any
+	// packages if one of them is invalid.
+	outputTmpls, err := parseOutputTemplates(opts, formats)
+	if err != nil {
+		return err
+	}
 
-	return command
-}
+	log := NewLogger(opts)
 
-func DefaultTags() []string {
-	f, ok := os.LookupEnv("GOFLAGS")
-	if !ok {
-		return nil
+	if !opts.Check && !opts.Preview {
+		if err := RunHooks(log, opts.Hooks.Pre, nil); err != nil {
+			return err
+		}
 	}
 
-	fs := flag.NewFlagSet("goflags", flag.ContinueOnError)
-	tags := fs.String("Tags", "", "")
+	specs := GetSpecsFor(opts, paths...)
+	if opts.ExcludeInternal {
+		specs = ExcludeInternalSpecs(specs)
+	}
 
-	if err := fs.Parse(strings.Fields(f)); err != nil {
-		return nil
+	specs = ExcludeSpecs(specs, excludes)
+
+	groups, err := groupSpecs(specs, opts, formats, outputTmpls)
+	if err != nil {
+		return err
 	}
 
-	if tags == nil {
-		return nil
+	if opts.Since != "" {
+		specs, err = filterSince(specs, opts)
+		if err != nil {
+			return err
+		}
+
+		groups = filterGroups(groups, specs)
 	}
 
-	return strings.Split(*tags, ",")
-}
+	if shard != nil {
+		// Shards are assigned based on the Output of the first format
+		for _, g := range groups {
+			if err := ResolveOutput(g.specs, g.outputTmpls[0]); err != nil {
+				return err
+			}
+		}
 
-func BuildConfig(configFile string) {
-	if configFile != "" {
-		viper.SetConfigFile(configFile)
-	} else {
-		viper.AddConfigPath(".")
-		viper.SetConfigName(configFilePrefix)
+		specs = shard.Filter(specs)
+		groups = filterGroups(groups, specs)
 	}
 
-	viper.AutomaticEnv()
+	cache, err := openCache(opts)
+	if err != nil {
+		return err
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			// TODO: better handling
-			fmt.Println(err)
+	// Packages whose Output files are unchanged since they were cached aren't
+	// loaded at all
+	load := specs
+	var cached []map[string]cachedOutput
+	if cache != nil {
+		load, cached, err = cache.prepare(groups, specs, formats, opts)
+		if err != nil {
+			return err
 		}
+
+		log.Debugf("skipping %d packages that are unchanged since they were cached", len(specs)-len(load))
 	}
-}
 
-func RunCommand(paths []string, opts CommandOptions) error {
-	outputTmpl, err := template.New("Output").Parse(opts.Output)
+	// Collects the failures that are skipped over when running with KeepGoing
+	var failures Failures
+
+	// Collects the Output files that don't match in Check mode
+	var mismatches Mismatches
+
+	if err := loadPackages(load, opts, &failures); err != nil {
+		return err
+	}
+
+	manifest, err := openManifest(opts)
 	if err != nil {
-		return fmt.Errorf("gomarkdoc: invalid Output template: %w", err)
+		return err
 	}
 
-	specs := GetSpecs(paths...)
+	// Tracks the manifest entries that were verified in check mode
+	checked := make(Manifest)
+
+	// Tracks the Output files written, which are provided to the post hooks
+	var written []string
+
+	for i, f := range formats {
+		for _, g := range groups {
+			if err := ResolveOutput(g.specs, g.outputTmpls[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := ResolveCaseCollisions(specs, opts.CaseCollisions); err != nil {
+			return err
+		}
+
+		for _, g := range groups {
+			groupOpts := g.opts
+			groupOpts.Format = f
+			groupOpts.Output = g.opts.FormatOutput(f)
+
+			if err := writeOutput(specs, g.specs, groupOpts, manifest, checked, &failures, &mismatches, &written, cache); err != nil {
+				return err
+			}
+		}
+
+		if cache != nil {
+			if err := keepCachedOutputs(opts, manifest, checked, &mismatches, cached[i]); err != nil {
+				return err
+			}
+		}
+
+		formatOpts := opts
+		formatOpts.Format = f
+		formatOpts.Output = opts.FormatOutput(f)
+
+		if opts.Anchors != "" && f == anchorsFormat(opts) {
+			if err := writeAnchors(specs, formatOpts, manifest, checked, &mismatches, &written); err != nil {
+				return err
+			}
+		}
+	}
 
-	if err := ResolveOutput(specs, outputTmpl); err != nil {
+	if err := closeManifest(opts, manifest, checked, &mismatches); err != nil {
 		return err
 	}
 
-	if err := LoadPackages(specs, opts); err != nil {
+	if err := reportMismatches(opts, mismatches); err != nil {
 		return err
 	}
 
-	return WriteOutput(specs, opts)
+	if cache != nil {
+		if err := cache.Write(); err != nil {
+			return err
+		}
+	}
+
+	if !opts.Check && !opts.Preview {
+		if err := RunHooks(log, opts.Hooks.Post, written); err != nil {
+			return err
+		}
+	}
+
+	return runErr(failures, mismatches)
+}
+
+// ResolveFormats determines the list of formats that documentation should be
+// generated in. Each entry in Formats may itself be a comma-separated list. If
+// no Formats are provided, the single Format is used instead.
+func ResolveFormats(opts CommandOptions) []string {
+	var formats []string
+	seen := make(map[string]bool)
+	for _, entry := range opts.Formats {
+		for _, f := range strings.Split(entry, ",") {
+			f = strings.TrimSpace(f)
+			if f == "" || seen[f] {
+				continue
+			}
+
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+
+	if len(formats) == 0 {
+		return []string{opts.Format}
+	}
+
+	return formats
+}
+
+// parseOutputTemplates parses the Output template of each of the formats.
+func parseOutputTemplates(opts CommandOptions, formats []string) ([]*template.Template, error) {
+	outputTmpls := make([]*template.Template, len(formats))
+	formatsByOutput := make(map[string]string)
+	for i, f := range formats {
+		output := opts.FormatOutput(f)
+		if prev, ok := formatsByOutput[output]; ok {
+			return nil, fmt.Errorf("gomarkdoc: formats %s and %s cannot be written to the same Output", prev, f)
+		}
+
+		formatsByOutput[output] = f
+
+		outputTmpl, err := template.New("Output").Parse(output)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: invalid Output template for Format %s: %w", f, err)
+		}
+
+		outputTmpls[i] = outputTmpl
+	}
+
+	return outputTmpls, nil
 }
 
 func ResolveOutput(specs []*PackageSpec, outputTmpl *template.Template) error {
@@ -290,11 +1230,30 @@ func ResolveOutput(specs []*PackageSpec, outputTmpl *template.Template) error {
 }
 
 func ResolveOverrides(opts CommandOptions) ([]gomarkdoc.RendererOption, error) {
-	var overrides []gomarkdoc.RendererOption
+	cfg := gomarkdoc.Config{
+		Format:         opts.Format,
+		LintProfile:    opts.LintProfile,
+		WikiTOC:        opts.WikiTOC,
+		Templates:      make(map[string]string),
+		FieldTable:     opts.FieldTable,
+		IncludeSource:  opts.IncludeSource,
+		ReferenceLinks: opts.ReferenceLinks,
+		OutputCompat:   opts.OutputCompat,
+		SingleFile:     opts.SingleFile != "",
+		AnchorStyle:    opts.AnchorStyle,
+		AnchorTemplate: opts.AnchorTemplate,
+		HeadingOffset:  opts.HeadingOffset,
+		HiddenSections: opts.HiddenSections,
+		IncludeRoot:    includeRoot(),
+		Locale:         opts.Locale,
+		Translations:   opts.Translations,
+		Vars:           opts.Vars,
+		DebugTemplates: opts.DebugTemplates,
+	}
 
 	// Content overrides take precedence over file overrides
 	for name, s := range opts.TemplateOverrides {
-		overrides = append(overrides, gomarkdoc.WithTemplateOverride(name, s))
+		cfg.Templates[name] = s
 	}
 
 	for name, f := range opts.TemplateFileOverrides {
@@ -304,29 +1263,38 @@ func ResolveOverrides(opts CommandOptions) ([]gomarkdoc.RendererOption, error) {
 			continue
 		}
 
-		b, err := ioutil.ReadFile(f)
+		b, err := ReadSource(f)
 		if err != nil {
 			return nil, fmt.Errorf("gomarkdoc: couldn't resolve template for %s: %w", name, err)
 		}
 
-		overrides = append(overrides, gomarkdoc.WithTemplateOverride(name, string(b)))
+		cfg.Templates[name] = string(b)
 	}
 
-	var f format.Format
-	switch opts.Format {
-	case "github":
-		f = &format.GitHubFlavoredMarkdown{}
-	case "azure-devops":
-		f = &format.AzureDevOpsMarkdown{}
-	case "plain":
-		f = &format.PlainMarkdown{}
-	default:
-		return nil, fmt.Errorf("gomarkdoc: invalid Format: %s", opts.Format)
+	for _, normalized := range opts.NormalizeWhitespace {
+		if normalized == opts.Format {
+			cfg.NormalizeWhitespace = true
+			break
+		}
+	}
+
+	rendererOpts, err := cfg.RendererOptions()
+	if err != nil {
+		return nil, err
 	}
 
-	overrides = append(overrides, gomarkdoc.WithFormat(f))
+	if opts.Playground {
+		// Examples are only shared when the files are written. Otherwise, the
+		// links are derived from the code of each example.
+		share := gomarkdoc.SharePlayground
+		if opts.Check || opts.Preview {
+			share = gomarkdoc.PlaygroundLink
+		}
+
+		rendererOpts = append(rendererOpts, gomarkdoc.WithPlaygroundLinks(share))
+	}
 
-	return overrides, nil
+	return append(rendererOpts, gomarkdoc.WithGenerationInfo(generationInfo(opts))), nil
 }
 
 func ResolveHeader(opts CommandOptions) (string, error) {
@@ -335,7 +1303,7 @@ func ResolveHeader(opts CommandOptions) (string, error) {
 	}
 
 	if opts.HeaderFile != "" {
-		b, err := ioutil.ReadFile(opts.HeaderFile)
+		b, err := ReadSource(opts.HeaderFile)
 		if err != nil {
 			return "", fmt.Errorf("gomarkdoc: couldn't resolve Header file: %w", err)
 		}
@@ -352,7 +1320,7 @@ func ResolveFooter(opts CommandOptions) (string, error) {
 	}
 
 	if opts.FooterFile != "" {
-		b, err := ioutil.ReadFile(opts.FooterFile)
+		b, err := ReadSource(opts.FooterFile)
 		if err != nil {
 			return "", fmt.Errorf("gomarkdoc: couldn't resolve Footer file: %w", err)
 		}
@@ -364,19 +1332,48 @@ func ResolveFooter(opts CommandOptions) (string, error) {
 }
 
 func LoadPackages(specs []*PackageSpec, opts CommandOptions) error {
-	for _, spec := range specs {
-		log := logger.New(GetLogLevel(opts.Verbosity), logger.WithField("dir", spec.Dir))
+	var failures Failures
+	if err := loadPackages(specs, opts, &failures); err != nil {
+		return err
+	}
 
-		buildPkg, err := GetBuildPackage(spec.ImportPath, opts.Tags)
-		if err != nil {
-			log.Debugf("unable to load package in directory: %s", err)
-			// We don't care if a wildcard path produces nothing
-			if spec.IsWildcard {
-				continue
-			}
+	return failures.Err()
+}
 
-			return err
-		}
+// loadSpecs resolves the specs for the paths, skipping excluded directories,
+// and loads their packages. It is shared by the subcommands that inspect the
+// packages rather than generating documentation.
+func loadSpecs(paths []string, opts CommandOptions, failures *Failures) ([]*PackageSpec, error) {
+	excludes, err := ParseExcludePatterns(opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := GetSpecsFor(opts, paths...)
+	if opts.ExcludeInternal {
+		specs = ExcludeInternalSpecs(specs)
+	}
+
+	specs = ExcludeSpecs(specs, excludes)
+	if err := loadPackages(specs, opts, failures); err != nil {
+		return nil, err
+	}
+
+	return specs, nil
+}
+
+// loadPackages loads the package for each of the specs. Packages that fail to
+// load are recorded in failures when running with KeepGoing, leaving the Pkg
+// of the spec unset.
+func loadPackages(specs []*PackageSpec, opts CommandOptions, failures *Failures) error {
+	platforms, err := ParsePlatforms(opts.Platforms)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range specs {
+		log := NewLogger(opts, logger.WithField("dir", spec.Dir))
+		start := time.Now()
 
 		var pkgOpts []lang.PackageOption
 		pkgOpts = append(pkgOpts, lang.PackageWithRepositoryOverrides(&opts.Repository))
@@ -385,52 +1382,145 @@ func LoadPackages(specs []*PackageSpec, opts CommandOptions) error {
 			pkgOpts = append(pkgOpts, lang.PackageWithUnexportedIncluded())
 		}
 
-		pkg, err := lang.NewPackageFromBuild(log, buildPkg, pkgOpts...)
-		if err != nil {
-			return err
+		if opts.OmitDeprecated {
+			pkgOpts = append(pkgOpts, lang.PackageWithDeprecatedOmitted())
 		}
 
-		spec.Pkg = pkg
-	}
+		if opts.Order != "" {
+			pkgOpts = append(pkgOpts, lang.PackageWithOrder(lang.Order(opts.Order)))
+		}
 
-	return nil
-}
+		if opts.MethodOrder != "" {
+			pkgOpts = append(pkgOpts, lang.PackageWithMethodOrder(lang.MethodOrder(opts.MethodOrder)))
+		}
+
+		if opts.GroupReceivers {
+			pkgOpts = append(pkgOpts, lang.PackageWithMethodsGroupedByReceiver())
+		}
+
+		if opts.UngroupConstructors {
+			pkgOpts = append(pkgOpts, lang.PackageWithConstructorsUngrouped())
+		} else if len(opts.ConstructorPrefixes) > 0 || len(opts.ConstructorSuffixes) > 0 {
+			pkgOpts = append(pkgOpts, lang.PackageWithConstructorPatterns(opts.ConstructorPrefixes, opts.ConstructorSuffixes))
+		}
+
+		if opts.Implements || opts.ImplementsStdlib {
+			pkgOpts = append(pkgOpts, lang.PackageWithImplements(opts.ImplementsStdlib))
+		}
+
+		if opts.ConstValues {
+			pkgOpts = append(pkgOpts, lang.PackageWithConstValues())
+		}
+
+		if opts.ExternalLinks {
+			pkgOpts = append(pkgOpts, lang.PackageWithExternalLinks())
+		}
+
+		if opts.OpaqueTypes {
+			pkgOpts = append(pkgOpts, lang.PackageWithOpaqueTypes())
+		}
+
+		if opts.ExternalTests {
+			pkgOpts = append(pkgOpts, lang.PackageWithExternalTests())
+		}
+
+		if opts.FuzzTargets {
+			pkgOpts = append(pkgOpts, lang.PackageWithFuzzTargets())
+		}
+
+		if opts.GenerateDirectives {
+			pkgOpts = append(pkgOpts, lang.PackageWithGenerateDirectives())
+		}
+
+		if opts.TaggedExamples {
+			pkgOpts = append(pkgOpts, lang.PackageWithTaggedExamples())
+		}
+
+		if len(opts.Notes) > 0 {
+			pkgOpts = append(pkgOpts, lang.PackageWithNotes(opts.Notes...))
+		}
+
+		if opts.ImportPath != "" {
+			pkgOpts = append(pkgOpts, lang.PackageWithImportPathRoot(opts.ImportPath))
+		}
+
+		if len(opts.Tags) > 0 {
+			pkgOpts = append(pkgOpts, lang.PackageWithBuildTags(opts.Tags...))
+		}
+
+		if len(platforms) > 0 {
+			pkgOpts = append(pkgOpts, lang.PackageWithPlatforms(platforms...))
+		}
 
-func GetBuildPackage(path string, tags []string) (*build.Package, error) {
-	ctx := build.Default
-	ctx.BuildTags = tags
+		if opts.IncludeMain {
+			pkgOpts = append(pkgOpts, lang.PackageWithCommandUsage())
+		}
 
-	if IsLocalPath(path) {
-		pkg, err := ctx.ImportDir(path, build.ImportComment)
+		pkg, err := lang.LoadPackage(log, spec.ImportPath, pkgOpts...)
 		if err != nil {
-			return nil, fmt.Errorf("gomarkdoc: invalid package in directory: %s", path)
+			log.Debugf("unable to load package in directory: %s", err)
+			// We don't care if a wildcard path produces nothing
+			if spec.IsWildcard && errors.Is(err, lang.ErrNoPackage) {
+				continue
+			}
+
+			err = &loadError{err}
+			if !opts.KeepGoing {
+				return err
+			}
+
+			log.Errorf("skipping package: %s", err)
+			failures.Add(fmt.Errorf("package %s: %w", spec.ImportPath, err))
+			continue
 		}
 
-		return pkg, nil
-	}
+		// Commands are only documented by wildcard paths when requested
+		if spec.IsWildcard && pkg.Name() == "main" && !opts.IncludeMain {
+			log.Debug("skipping main package")
+			continue
+		}
 
-	wd, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
+		spec.Pkg = pkg
 
-	pkg, err := ctx.Import(path, wd, build.ImportComment)
-	if err != nil {
-		return nil, fmt.Errorf("gomarkdoc: invalid package at import path: %s", path)
+		NewLogger(
+			opts,
+			logger.WithField("dir", spec.Dir),
+			logger.WithField("duration", time.Since(start).String()),
+		).Debug("loaded package")
 	}
 
-	return pkg, nil
+	return nil
 }
 
+// GetSpecs provides the specs of the packages at the provided paths, expanding
+// package patterns. Directories matching DefaultSkipDirs are skipped.
 func GetSpecs(paths ...string) []*PackageSpec {
+	return GetSpecsSkipping(DefaultSkipDirs, paths...)
+}
+
+// GetSpecsSkipping provides the specs of the packages at the provided paths
+// like GetSpecs, skipping the directories that match the provided patterns of
+// directory names when expanding local package patterns.
+func GetSpecsSkipping(skipDirs []string, paths ...string) []*PackageSpec {
+	return getSpecs(skipDirs, false, paths...)
+}
+
+// GetSpecsFor provides the specs of the packages at the provided paths like
+// GetSpecs, expanding local package patterns according to the SkipDirs and
+// FollowSymlinks options.
+func GetSpecsFor(opts CommandOptions, paths ...string) []*PackageSpec {
+	return getSpecs(opts.SkipDirs, opts.FollowSymlinks, paths...)
+}
+
+func getSpecs(skipDirs []string, followSymlinks bool, paths ...string) []*PackageSpec {
 	var expanded []*PackageSpec
 	for _, path := range paths {
 		// Ensure that the path we're working with is normalized for the OS
 		// we're using (i.e. "\" for windows, "/" for everything else)
 		path = filepath.FromSlash(path)
 
-		// Not a recursive path
-		if !strings.HasSuffix(path, fmt.Sprintf("%s...", string(os.PathSeparator))) {
+		// Not a pattern
+		if !IsPackagePattern(path) {
 			isLocal := IsLocalPath(path)
 			var dir string
 			if isLocal {
@@ -447,68 +1537,121 @@ func GetSpecs(paths ...string) []*PackageSpec {
 			continue
 		}
 
+		// Not a file path, so the go tool resolves the pattern against the
+		// standard library and the modules in the build list
+		if !IsLocalPath(path) {
+			expanded = append(expanded, importPatternSpecs(path)...)
+			continue
+		}
+
+		recursiveSuffix := fmt.Sprintf("%s...", string(os.PathSeparator))
+		if !strings.HasSuffix(path, recursiveSuffix) || strings.Count(path, "...") > 1 {
+			expanded = append(expanded, localPatternSpecs(path, skipDirs, followSymlinks)...)
+			continue
+		}
+
 		// Remove the recursive marker so we can work with the path
 		trimmedPath := path[0 : len(path)-3]
 
-		// Not a file path. Add the original path back to the list so as to not
-		// mislead someone into thinking we're processing the recursive path
-		if !IsLocalPath(trimmedPath) {
-			expanded = append(expanded, &PackageSpec{
-				Dir:        ".",
-				ImportPath: path,
-				IsWildcard: false,
-				IsLocal:    false,
-			})
-			continue
+		expanded = append(expanded, walkLocalSpecs(trimmedPath, skipDirs, followSymlinks, func(string) bool { return true })...)
+	}
+
+	return expanded
+}
+
+// walkLocalSpecs provides a spec for the root directory and each of the
+// directories beneath it that match. Directories matching the skip patterns
+// are skipped along with their contents. Symlinks to directories are skipped
+// unless followSymlinks is set, in which case each directory is only visited
+// once so that links pointing back up the tree don't cause a loop.
+func walkLocalSpecs(root string, skipDirs []string, followSymlinks bool, match func(dir string) bool) []*PackageSpec {
+	visited := make(map[string]bool)
+	visit := func(dir string) bool {
+		if !followSymlinks {
+			return true
+		}
+
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return false
+		}
+
+		if abs, err := filepath.Abs(resolved); err == nil {
+			resolved = abs
+		}
+
+		if visited[resolved] {
+			return false
 		}
 
+		visited[resolved] = true
+		return true
+	}
+
+	visit(root)
+
+	var expanded []*PackageSpec
+	if match(root) {
 		expanded = append(expanded, &PackageSpec{
-			Dir:        trimmedPath,
-			ImportPath: trimmedPath,
+			Dir:        root,
+			ImportPath: root,
 			IsWildcard: true,
 			IsLocal:    true,
 		})
+	}
 
-		queue := list.New()
-		queue.PushBack(trimmedPath)
-		for e := queue.Front(); e != nil; e = e.Next() {
-			prev := e.Prev()
-			if prev != nil {
-				queue.Remove(prev)
-			}
+	queue := list.New()
+	queue.PushBack(root)
+	for e := queue.Front(); e != nil; e = e.Next() {
+		prev := e.Prev()
+		if prev != nil {
+			queue.Remove(prev)
+		}
 
-			p := e.Value.(string)
+		p := e.Value.(string)
 
-			files, err := ioutil.ReadDir(p)
-			if err != nil {
-				// If we couldn't read the folder, there are no directories that
-				// we're going to find beneath it
+		files, err := ioutil.ReadDir(p)
+		if err != nil {
+			// If we couldn't read the folder, there are no directories that
+			// we're going to find beneath it
+			continue
+		}
+
+		for _, f := range files {
+			if IsSkippedDir(f.Name(), skipDirs) {
 				continue
 			}
 
-			for _, f := range files {
-				if IsIgnoredDir(f.Name()) {
-					continue
+			isDir := f.IsDir()
+			if f.Mode()&os.ModeSymlink != 0 && followSymlinks {
+				if info, err := os.Stat(filepath.Join(p, f.Name())); err == nil {
+					isDir = info.IsDir()
 				}
+			}
 
-				if f.IsDir() {
-					subPath := filepath.Join(p, f.Name())
+			if isDir {
+				subPath := filepath.Join(p, f.Name())
+				if !visit(subPath) {
+					continue
+				}
 
-					// Some local paths have their prefixes stripped by Join().
-					// If the path is no longer a local path, add the current
-					// working directory.
-					if !IsLocalPath(subPath) {
-						subPath = fmt.Sprintf("%s%s", cwdPathPrefix, subPath)
-					}
+				// Some local paths have their prefixes stripped by Join().
+				// If the path is no longer a local path, add the current
+				// working directory.
+				if !IsLocalPath(subPath) {
+					subPath = fmt.Sprintf("%s%s", cwdPathPrefix, subPath)
+				}
 
+				if match(subPath) {
 					expanded = append(expanded, &PackageSpec{
 						Dir:        subPath,
 						ImportPath: subPath,
 						IsWildcard: true,
 						IsLocal:    true,
 					})
-					queue.PushBack(subPath)
 				}
+
+				queue.PushBack(subPath)
 			}
 		}
 	}
@@ -516,12 +1659,48 @@ func GetSpecs(paths ...string) []*PackageSpec {
 	return expanded
 }
 
-var ignoredDirs = []string{".git"}
+// ExcludeInternalSpecs removes the specs found by expanding recursive paths that
+// are located within an internal directory. Specs for packages that were
+// requested explicitly are kept.
+func ExcludeInternalSpecs(specs []*PackageSpec) []*PackageSpec {
+	var filtered []*PackageSpec
+	for _, spec := range specs {
+		if spec.IsWildcard && isInternalDir(spec.Dir) {
+			continue
+		}
+
+		filtered = append(filtered, spec)
+	}
+
+	return filtered
+}
+
+func isInternalDir(dir string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Clean(dir)), "/") {
+		if part == "internal" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DefaultSkipDirs lists the patterns of the directory names that are skipped
+// when expanding package patterns, unless others are configured: hidden
+// directories, vendored code, test fixtures and JavaScript dependencies.
+var DefaultSkipDirs = []string{".*", "vendor", "testdata", "node_modules"}
 
-// IsIgnoredDir identifies if the dir is one we want to intentionally ignore.
+// IsIgnoredDir identifies if the dir is one we want to intentionally ignore by
+// default, as listed in DefaultSkipDirs.
 func IsIgnoredDir(dirname string) bool {
-	for _, ignored := range ignoredDirs {
-		if ignored == dirname {
+	return IsSkippedDir(dirname, DefaultSkipDirs)
+}
+
+// IsSkippedDir identifies if the dir matches one of the provided patterns of
+// directory names, which use the syntax of filepath.Match.
+func IsSkippedDir(dirname string, skipDirs []string) bool {
+	for _, pattern := range skipDirs {
+		if match, err := filepath.Match(pattern, dirname); err == nil && match {
 			return true
 		}
 	}
@@ -552,6 +1731,26 @@ func Compare(r1, r2 io.Reader) (bool, error) {
 	return bytes.Equal(r1Hash.Sum(nil), r2Hash.Sum(nil)), nil
 }
 
+// NewLogger creates a logger with the level and format selected by the
+// options, along with any additional logger options such as fields.
+func NewLogger(opts CommandOptions, loggerOpts ...logger.Option) logger.Logger {
+	format := logger.TextFormat
+	if opts.LogFormat != "" {
+		format = logger.Format(opts.LogFormat)
+	}
+
+	level := GetLogLevel(opts.Verbosity)
+	if opts.Quiet {
+		level = logger.ErrorLevel
+	}
+
+	// Logs always go to stderr so that they never mix with documentation
+	// printed to stdout
+	base := []logger.Option{logger.WithFormat(format), logger.WithOutput(os.Stderr)}
+
+	return logger.New(level, append(base, loggerOpts...)...)
+}
+
 func GetLogLevel(verbosity int) logger.Level {
 	switch verbosity {
 	case 0:
@@ -566,14 +1765,5 @@ func GetLogLevel(verbosity int) logger.Level {
 }
 
 func PrintVersion() {
-	if version != "" {
-		fmt.Println(version)
-		return
-	}
-
-	if info, ok := debug.ReadBuildInfo(); ok {
-		fmt.Println(info.Main.Version)
-	} else {
-		fmt.Println("<unknown>")
-	}
+	_ = WriteVersion(os.Stdout, VersionShort)
 }
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ag5denis/gomarkdoc"
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/ag5denis/gomarkdoc/logger"
+)
+
+// formatNames lists the valid values of the --Format flag.
+var formatNames = []string{"github", "azure-devops", "plain", "llms", "yaml", "docfx"}
+
+// buildCompletionCommand creates the completion command, which prints the
+// script that sets up shell completion for the root command.
+func buildCompletionCommand(root *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "generate the script that sets up shell completion for gomarkdoc",
+		Long: `Generate the script that sets up shell completion for gomarkdoc in the
+provided shell. For example, to load completions in the current bash session:
+
+	source <(gomarkdoc completion bash)`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.ExactValidArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(w)
+			case "zsh":
+				return root.GenZshCompletion(w)
+			case "fish":
+				return root.GenFishCompletion(w, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(w)
+			default:
+				return fmt.Errorf("gomarkdoc: unsupported shell: %s", args[0])
+			}
+		},
+	}
+}
+
+// registerCompletions sets up the dynamic completion of the package arguments
+// and of the flags that accept a fixed set of values. Subcommands share the
+// flags of the root command, so they share the completions as well.
+func registerCompletions(command *cobra.Command) {
+	command.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+
+	completions := map[string]func(toComplete string) ([]string, cobra.ShellCompDirective){
+		"Format":          completeList(formatNames),
+		"format-output":   completeKeys(formatNames),
+		"template":        completeKeys(gomarkdoc.TemplateNames()),
+		"template-file":   completeKeys(gomarkdoc.TemplateNames()),
+		"case-collisions": completeValues(CaseCollisionsError, CaseCollisionsRename, CaseCollisionsIgnore),
+		"lint-profile":    completeValues("markdownlint"),
+		"locale":          completeValues(gomarkdoc.Locales()...),
+		"order":           completeValues(string(lang.OrderAlphabetical), string(lang.OrderSource), string(lang.OrderKind)),
+		"method-order":    completeValues(string(lang.MethodOrderAlphabetical), string(lang.MethodOrderSource), string(lang.MethodOrderExportedFirst)),
+		"log-format":      completeValues(string(logger.TextFormat), string(logger.JSONFormat)),
+		"config": func(string) ([]string, cobra.ShellCompDirective) {
+			return []string{"yml", "yaml", "json", "toml"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+	}
+
+	for name, complete := range completions {
+		complete := complete
+		_ = command.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return complete(toComplete)
+		})
+	}
+}
+
+// completeValues completes a flag that accepts one of the provided values.
+func completeValues(values ...string) func(toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeList completes a flag that accepts a comma-separated list of the
+// provided values, completing the last value in the list.
+func completeList(values []string) func(toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(toComplete string) ([]string, cobra.ShellCompDirective) {
+		prefix := ""
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix = toComplete[:i+1]
+		}
+
+		completions := make([]string, len(values))
+		for i, v := range values {
+			completions[i] = prefix + v
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeKeys completes a flag that accepts key=value pairs with the provided
+// keys, leaving the cursor after the = so that the value can be entered.
+func completeKeys(keys []string) func(toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(toComplete string) ([]string, cobra.ShellCompDirective) {
+		if strings.Contains(toComplete, "=") {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		completions := make([]string, len(keys))
+		for i, k := range keys {
+			completions[i] = k + "="
+		}
+
+		return completions, cobra.ShellCompDirectiveNoSpace
+	}
+}
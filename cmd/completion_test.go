@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestCompletion(t *testing.T) {
+	is := is.New(t)
+
+	var out bytes.Buffer
+	cmd := BuildCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"completion", "bash"})
+
+	is.NoErr(cmd.Execute())
+	is.True(strings.Contains(out.String(), "bash completion for gomarkdoc"))
+}
+
+func TestCompletion_invalidShell(t *testing.T) {
+	is := is.New(t)
+
+	cmd := BuildCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"completion", "tcsh"})
+
+	is.True(cmd.Execute() != nil)
+}
+
+func TestCompletion_flagValues(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--log-format", ""}, "text\njson\n:4\n"},
+		{[]string{"--Format", "github,"}, "github,github\ngithub,azure-devops\ngithub,plain\ngithub,llms\ngithub,yaml\ngithub,docfx\n:4\n"},
+		{[]string{"--format-output", ""}, "github=\nazure-devops=\nplain=\nllms=\nyaml=\ndocfx=\n:2\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.args[0], func(t *testing.T) {
+			is := is.New(t)
+
+			var out bytes.Buffer
+			cmd := BuildCommand()
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{"__complete"}, test.args...))
+
+			is.NoErr(cmd.Execute())
+			is.Equal(out.String(), test.want)
+		})
+	}
+}
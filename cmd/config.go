@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// rootConfigKey marks a configuration file as the outermost one, which stops
+// the search for configuration files in parent directories.
+const rootConfigKey = "Root"
+
+// FindConfigFiles looks for configuration files in the directory and each of
+// its parents, in the same way as .editorconfig files are discovered. The
+// search stops at the root of the filesystem or at the first configuration
+// file that sets "root: true". The files are ordered from the outermost to the
+// innermost, which is the order in which they should be applied.
+func FindConfigFiles(dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for {
+		if f, ok := configFileInDir(dir); ok {
+			files = append([]string{f}, files...)
+
+			root, err := isRootConfig(f)
+			if err != nil {
+				return nil, err
+			}
+
+			if root {
+				break
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	return files, nil
+}
+
+// configFileInDir finds the configuration file directly within the directory,
+// using any of the extensions supported by viper.
+func configFileInDir(dir string) (string, bool) {
+	for _, ext := range viper.SupportedExts {
+		f := filepath.Join(dir, fmt.Sprintf("%s.%s", configFilePrefix, ext))
+		if info, err := os.Stat(f); err == nil && !info.IsDir() {
+			return f, true
+		}
+	}
+
+	return "", false
+}
+
+func isRootConfig(file string) (bool, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return false, fmt.Errorf("gomarkdoc: failed to read config file %s: %w", file, err)
+	}
+
+	return v.GetBool(rootConfigKey), nil
+}
+
+// usedConfigFiles provides the configuration files loaded by the last call to
+// BuildConfig with the provided configuration file, including the
+// configuration file of the preset bundle they extend.
+func usedConfigFiles(configFile string) ([]string, error) {
+	files := []string{configFile}
+	if configFile == "" {
+		var err error
+		if files, err = FindConfigFiles("."); err != nil {
+			return nil, err
+		}
+	}
+
+	if presetConfigFile != "" {
+		files = append(files, presetConfigFile)
+	}
+
+	return files, nil
+}
+
+// loadConfigFiles merges the settings of the configuration files, with later
+// files taking precedence over earlier ones. File paths in each file are
+// resolved relative to the directory of that file.
+func loadConfigFiles(files []string) (map[string]interface{}, error) {
+	merged := viper.New()
+	for _, f := range files {
+		v := viper.New()
+		v.SetConfigFile(f)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to read config file %s: %w", f, err)
+		}
+
+		settings := v.AllSettings()
+		delete(settings, strings.ToLower(rootConfigKey))
+		resolveSettingsPaths(settings, filepath.Dir(f))
+
+		if err := merged.MergeConfigMap(settings); err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to read config file %s: %w", f, err)
+		}
+	}
+
+	return merged.AllSettings(), nil
+}
+
+// DirConfigFiles finds the configuration files in the directories between the
+// working directory and the directory of the package, including the package
+// directory itself but not the working directory, whose configuration applies
+// to every package. The files are ordered from the outermost to the innermost.
+// Packages outside of the working directory have no such files.
+func DirConfigFiles(spec *PackageSpec) []string {
+	if !spec.IsLocal || filepath.IsAbs(spec.Dir) {
+		return nil
+	}
+
+	dir := filepath.Clean(spec.Dir)
+	if dir == "." || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	var files []string
+	for dir != "." {
+		if f, ok := configFileInDir(dir); ok {
+			files = append([]string{f}, files...)
+		}
+
+		dir = filepath.Dir(dir)
+	}
+
+	return files
+}
+
+// dirConfigFlags maps the configuration keys that nested configuration files
+// can override to the names of their flags.
+var dirConfigFlags = map[string]string{
+	"Output":       "Output",
+	"FormatOutput": "format-output",
+	"template":     "template",
+	"templateFile": "template-file",
+	"Header":       "Header",
+	"HeaderFile":   "Header-file",
+	"Footer":       "Footer",
+	"FooterFile":   "Footer-file",
+}
+
+// specGroup holds the specs that share the same nested configuration files,
+// which are written with the same options.
+type specGroup struct {
+	opts        CommandOptions
+	specs       []*PackageSpec
+	outputTmpls []*template.Template
+}
+
+// groupSpecs groups the specs by their nested configuration files. Specs
+// without any use the options and Output templates of the run.
+func groupSpecs(specs []*PackageSpec, opts CommandOptions, formats []string, outputTmpls []*template.Template) ([]*specGroup, error) {
+	var groups []*specGroup
+	byFiles := make(map[string]*specGroup)
+	for _, spec := range specs {
+		files := DirConfigFiles(spec)
+		key := strings.Join(files, string(filepath.ListSeparator))
+
+		g, ok := byFiles[key]
+		if !ok {
+			g = &specGroup{opts: opts, outputTmpls: outputTmpls}
+			if len(files) > 0 {
+				for _, f := range files {
+					var err error
+					if g.opts, err = ApplyDirConfig(g.opts, f); err != nil {
+						return nil, err
+					}
+				}
+
+				var err error
+				if g.outputTmpls, err = parseOutputTemplates(g.opts, formats); err != nil {
+					return nil, err
+				}
+			}
+
+			byFiles[key] = g
+			groups = append(groups, g)
+		}
+
+		g.specs = append(g.specs, spec)
+	}
+
+	return groups, nil
+}
+
+// filterGroups drops the specs that aren't in the provided list from the
+// groups.
+func filterGroups(groups []*specGroup, specs []*PackageSpec) []*specGroup {
+	keep := make(map[*PackageSpec]bool, len(specs))
+	for _, spec := range specs {
+		keep[spec] = true
+	}
+
+	var filtered []*specGroup
+	for _, g := range groups {
+		var groupSpecs []*PackageSpec
+		for _, spec := range g.specs {
+			if keep[spec] {
+				groupSpecs = append(groupSpecs, spec)
+			}
+		}
+
+		if len(groupSpecs) > 0 {
+			g.specs = groupSpecs
+			filtered = append(filtered, g)
+		}
+	}
+
+	return filtered
+}
+
+// ApplyDirConfig overrides the options that may vary between subtrees with
+// the values of a nested configuration file. These are the Output, the
+// per-format outputs, the template overrides and the header and footer.
+// Options that were set with flags keep their values. File paths are resolved
+// relative to the directory of the configuration file.
+func ApplyDirConfig(opts CommandOptions, file string) (CommandOptions, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return opts, fmt.Errorf("gomarkdoc: failed to read config file %s: %w", file, err)
+	}
+
+	settings := v.AllSettings()
+	resolveSettingsPaths(settings, filepath.Dir(file))
+	if err := v.MergeConfigMap(settings); err != nil {
+		return opts, fmt.Errorf("gomarkdoc: failed to read config file %s: %w", file, err)
+	}
+
+	isSet := func(key string) bool {
+		return v.IsSet(key) && !opts.flagged[key]
+	}
+
+	if isSet("Output") {
+		opts.Output = v.GetString("Output")
+	}
+
+	if isSet("FormatOutput") {
+		opts.FormatOutputs = mergeStringMaps(opts.FormatOutputs, v.GetStringMapString("FormatOutput"))
+	}
+
+	if isSet("template") {
+		opts.TemplateOverrides = mergeStringMaps(opts.TemplateOverrides, v.GetStringMapString("template"))
+	}
+
+	if isSet("templateFile") {
+		files := v.GetStringMapString("templateFile")
+		opts.TemplateFileOverrides = mergeStringMaps(opts.TemplateFileOverrides, files)
+
+		// Content overrides normally take precedence over file overrides, but
+		// a file override from a nested configuration replaces the content
+		// from an outer one.
+		if !opts.flagged["template"] {
+			contents := v.GetStringMapString("template")
+			opts.TemplateOverrides = mergeStringMaps(opts.TemplateOverrides, nil)
+			for name := range files {
+				if _, ok := contents[name]; !ok {
+					delete(opts.TemplateOverrides, name)
+				}
+			}
+		}
+	}
+
+	// Likewise, the content of the header and footer takes precedence over
+	// their files, so setting either one replaces the other.
+	if !opts.flagged["Header"] && !opts.flagged["HeaderFile"] {
+		if v.IsSet("Header") {
+			opts.Header, opts.HeaderFile = v.GetString("Header"), ""
+		} else if v.IsSet("HeaderFile") {
+			opts.Header, opts.HeaderFile = "", v.GetString("HeaderFile")
+		}
+	}
+
+	if !opts.flagged["Footer"] && !opts.flagged["FooterFile"] {
+		if v.IsSet("Footer") {
+			opts.Footer, opts.FooterFile = v.GetString("Footer"), ""
+		} else if v.IsSet("FooterFile") {
+			opts.Footer, opts.FooterFile = "", v.GetString("FooterFile")
+		}
+	}
+
+	return opts, nil
+}
+
+// mergeStringMaps copies the base map and sets the values of the overrides on
+// the copy, leaving both of the original maps untouched.
+func mergeStringMaps(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+
+	for k, v := range overrides {
+		merged[k] = v
+	}
+
+	return merged
+}
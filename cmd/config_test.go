@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFindConfigFiles(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, ".gomarkdoc.yml"), "output: outer.md\n")
+	writeConfig(t, filepath.Join(dir, "a", ".gomarkdoc.yml"), "root: true\noutput: a.md\n")
+	writeConfig(t, filepath.Join(dir, "a", "b", ".gomarkdoc.yaml"), "output: b.md\n")
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "a", "b", "c"), 0755))
+
+	files, err := FindConfigFiles(filepath.Join(dir, "a", "b", "c"))
+	is.NoErr(err)
+
+	// The search stops at the root configuration file in a
+	is.Equal(files, []string{
+		filepath.Join(dir, "a", ".gomarkdoc.yml"),
+		filepath.Join(dir, "a", "b", ".gomarkdoc.yaml"),
+	})
+
+	settings, err := loadConfigFiles(files)
+	is.NoErr(err)
+	is.Equal(settings["output"], "b.md")
+
+	_, ok := settings[rootConfigKey]
+	is.True(!ok)
+}
+
+func TestDirConfigFiles(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, ".gomarkdoc.yml"), "output: README.md\n")
+	writeConfig(t, filepath.Join(dir, "a", ".gomarkdoc.yml"), "output: a.md\n")
+	writeConfig(t, filepath.Join(dir, "a", "b", ".gomarkdoc.yml"), "output: b.md\n")
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "c"), 0755))
+
+	prev, err := os.Getwd()
+	is.NoErr(err)
+	is.NoErr(os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(prev) })
+
+	is.Equal(DirConfigFiles(&PackageSpec{Dir: "./a/b", IsLocal: true}), []string{
+		filepath.Join("a", ".gomarkdoc.yml"),
+		filepath.Join("a", "b", ".gomarkdoc.yml"),
+	})
+	is.Equal(len(DirConfigFiles(&PackageSpec{Dir: "./c", IsLocal: true})), 0)
+	is.Equal(len(DirConfigFiles(&PackageSpec{Dir: ".", IsLocal: true})), 0) // the working directory applies to all packages
+	is.Equal(len(DirConfigFiles(&PackageSpec{Dir: ".", IsLocal: false})), 0)
+}
+
+func TestApplyDirConfig(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sub", ".gomarkdoc.yml")
+	writeConfig(t, file, "output: \"{{.Dir}}/API.md\"\nheaderFile: header.md\ntemplateFile:\n  func: func.gotxt\nincludeUnexported: true\n")
+
+	opts, err := ApplyDirConfig(CommandOptions{
+		Output:            "{{.Dir}}/README.md",
+		Header:            "Outer header",
+		TemplateOverrides: map[string]string{"func": "outer", "type": "outer"},
+	}, file)
+	is.NoErr(err)
+
+	is.Equal(opts.Output, "{{.Dir}}/API.md")
+	is.Equal(opts.Header, "")
+	is.Equal(opts.HeaderFile, filepath.Join(dir, "sub", "header.md"))
+	is.Equal(opts.TemplateFileOverrides, map[string]string{"func": filepath.Join(dir, "sub", "func.gotxt")})
+	is.Equal(opts.TemplateOverrides, map[string]string{"type": "outer"})
+	is.True(!opts.IncludeUnexported) // only the output options vary between subtrees
+}
+
+func TestApplyDirConfig_flagged(t *testing.T) {
+	is := is.New(t)
+
+	file := filepath.Join(t.TempDir(), ".gomarkdoc.yml")
+	writeConfig(t, file, "output: sub.md\nheader: Sub header\n")
+
+	opts, err := ApplyDirConfig(CommandOptions{
+		Output:  "flag.md",
+		flagged: map[string]bool{"Output": true},
+	}, file)
+	is.NoErr(err)
+
+	is.Equal(opts.Output, "flag.md")
+	is.Equal(opts.Header, "Sub header")
+}
+
+func writeConfig(t *testing.T, file, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change in a
+// unified diff.
+const diffContext = 3
+
+// diffOp is a single line of an edit script. The kind is ' ' for an unchanged
+// line, '-' for a removed line and '+' for an added line.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// UnifiedDiff produces a unified diff of the changes needed to turn the old
+// text into the new text, labelling each side with the provided name. The
+// empty string is returned if the texts are identical.
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			k++
+			continue
+		}
+
+		// Changes separated by only a few unchanged lines share a hunk
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+
+			if run == len(ops) || run-end > 2*diffContext {
+				break
+			}
+
+			end = run
+		}
+
+		start := k - diffContext
+		if start < 0 {
+			start = 0
+		}
+
+		stop := end + diffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				oldStart++
+			}
+
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+
+		var oldCount, newCount int
+		for _, op := range ops[start:stop] {
+			if op.kind != '+' {
+				oldCount++
+			}
+
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+
+		// Empty ranges refer to the line before the change
+		if oldCount == 0 {
+			oldStart--
+		}
+
+		if newCount == 0 {
+			newStart--
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, op := range ops[start:stop] {
+			fmt.Fprintf(&b, "%c%s\n", op.kind, op.line)
+		}
+
+		k = stop
+	}
+
+	return b.String()
+}
+
+// splitLines splits the text into lines, ignoring the newline at the end of
+// the final line.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes the edit script between the two sets of lines from their
+// longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
+
+// PreviewDiff provides the unified diff between the current contents of the
+// file and the provided text. Files that don't exist yet are compared against
+// empty content.
+func PreviewDiff(fileName, text string) (string, error) {
+	oldName := fileName
+	data, err := os.ReadFile(fileName)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		oldName = "/dev/null"
+	case err != nil:
+		return "", fmt.Errorf("failed to read file %s for preview: %w", fileName, err)
+	}
+
+	return UnifiedDiff(oldName, fileName, string(data), text), nil
+}
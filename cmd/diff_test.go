@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	is := is.New(t)
+
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newText := "a\nb\nc\nd\nE\nf\ng\nh\ni\nj\nk\n"
+
+	is.Equal(UnifiedDiff("old", "new", oldText, newText), `--- old
++++ new
+@@ -2,9 +2,10 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+ i
+ j
++k
+`)
+}
+
+func TestUnifiedDiff_separateHunks(t *testing.T) {
+	is := is.New(t)
+
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	newText := "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n"
+
+	is.Equal(UnifiedDiff("old", "new", oldText, newText), `--- old
++++ new
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`)
+}
+
+func TestUnifiedDiff_identical(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(UnifiedDiff("old", "new", "same\n", "same\n"), "")
+}
+
+func TestPreviewDiff_newFile(t *testing.T) {
+	is := is.New(t)
+
+	fileName := filepath.Join(t.TempDir(), "README.md")
+
+	diff, err := PreviewDiff(fileName, "# Title\n")
+	is.NoErr(err)
+	is.Equal(diff, "--- /dev/null\n+++ "+fileName+"\n@@ -0,0 +1,1 @@\n+# Title\n")
+
+	_, err = os.Stat(fileName)
+	is.True(os.IsNotExist(err)) // previewing must not write the file
+}
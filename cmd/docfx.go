@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// docFXMime is the header that identifies DocFX ManagedReference metadata.
+const docFXMime = "### YamlMime:ManagedReference\n"
+
+type (
+	docFXFile struct {
+		Items []*docFXItem `yaml:"items"`
+	}
+
+	docFXItem struct {
+		UID       string       `yaml:"uid"`
+		ID        string       `yaml:"id"`
+		Parent    string       `yaml:"parent,omitempty"`
+		Children  []string     `yaml:"children,omitempty"`
+		Langs     []string     `yaml:"langs"`
+		Name      string       `yaml:"name"`
+		FullName  string       `yaml:"fullName"`
+		Type      string       `yaml:"type"`
+		Namespace string       `yaml:"namespace,omitempty"`
+		Summary   string       `yaml:"summary,omitempty"`
+		Remarks   string       `yaml:"remarks,omitempty"`
+		Syntax    *docFXSyntax `yaml:"syntax,omitempty"`
+		Source    *docFXSource `yaml:"source,omitempty"`
+		Examples  []string     `yaml:"example,omitempty"`
+	}
+
+	docFXSyntax struct {
+		Content string `yaml:"content"`
+	}
+
+	docFXSource struct {
+		Path      string `yaml:"path"`
+		StartLine int    `yaml:"startLine"`
+	}
+)
+
+var interfaceDeclRegex = regexp.MustCompile(`^type\s+\w+(?:\[[^\]]*\])?\s+interface\b`)
+
+// RenderDocFX renders the packages in the file as DocFX ManagedReference
+// metadata, which allows Go package documentation to be integrated into DocFX
+// sites alongside API documentation for other languages. Each package is
+// represented as a namespace containing its types, funcs and values.
+func RenderDocFX(file *lang.File) (string, error) {
+	var out docFXFile
+	for _, pkg := range file.Packages {
+		m, err := pkg.Model()
+		if err != nil {
+			return "", fmt.Errorf("gomarkdoc: failed to build model for package %s: %w", pkg.ImportPath(), err)
+		}
+
+		out.Items = append(out.Items, docFXPackageItems(m)...)
+	}
+
+	b, err := yaml.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: failed to render docfx metadata: %w", err)
+	}
+
+	return docFXMime + string(b), nil
+}
+
+func docFXPackageItems(pkg *lang.PackageModel) []*docFXItem {
+	root := &docFXItem{
+		UID:      pkg.ImportPath,
+		ID:       pkg.Name,
+		Langs:    []string{"go"},
+		Name:     pkg.Name,
+		FullName: pkg.ImportPath,
+		Type:     "Namespace",
+		Summary:  pkg.Summary,
+		Remarks:  pkg.Doc,
+	}
+
+	items := []*docFXItem{root}
+	add := func(item *docFXItem) {
+		root.Children = append(root.Children, item.UID)
+		items = append(items, item)
+	}
+
+	for _, v := range pkg.Consts {
+		for _, item := range docFXValueItems(pkg, pkg.ImportPath, v) {
+			add(item)
+		}
+	}
+
+	for _, v := range pkg.Vars {
+		for _, item := range docFXValueItems(pkg, pkg.ImportPath, v) {
+			add(item)
+		}
+	}
+
+	for _, fn := range pkg.Funcs {
+		add(docFXFuncItem(pkg, pkg.ImportPath, fn, "Function"))
+	}
+
+	for _, typ := range pkg.Types {
+		typeItems := docFXTypeItems(pkg, typ)
+		add(typeItems[0])
+		items = append(items, typeItems[1:]...)
+	}
+
+	return items
+}
+
+func docFXTypeItems(pkg *lang.PackageModel, typ *lang.TypeModel) []*docFXItem {
+	kind := "Class"
+	if interfaceDeclRegex.MatchString(typ.Decl) {
+		kind = "Interface"
+	}
+
+	uid := fmt.Sprintf("%s.%s", pkg.ImportPath, typ.Name)
+	item := &docFXItem{
+		UID:       uid,
+		ID:        typ.Name,
+		Parent:    pkg.ImportPath,
+		Langs:     []string{"go"},
+		Name:      typ.Name,
+		FullName:  fmt.Sprintf("%s.%s", pkg.Name, typ.Name),
+		Type:      kind,
+		Namespace: pkg.ImportPath,
+		Summary:   typ.Summary,
+		Remarks:   typ.Doc,
+		Syntax:    &docFXSyntax{typ.Decl},
+		Source:    docFXSourceFor(typ.Position),
+		Examples:  docFXExamples(typ.Examples),
+	}
+
+	items := []*docFXItem{item}
+	add := func(child *docFXItem) {
+		item.Children = append(item.Children, child.UID)
+		items = append(items, child)
+	}
+
+	for _, v := range typ.Consts {
+		for _, child := range docFXValueItems(pkg, uid, v) {
+			add(child)
+		}
+	}
+
+	for _, v := range typ.Vars {
+		for _, child := range docFXValueItems(pkg, uid, v) {
+			add(child)
+		}
+	}
+
+	for _, fn := range typ.Funcs {
+		add(docFXFuncItem(pkg, uid, fn, "Constructor"))
+	}
+
+	for _, fn := range typ.Methods {
+		add(docFXFuncItem(pkg, uid, fn, "Method"))
+	}
+
+	return items
+}
+
+func docFXFuncItem(pkg *lang.PackageModel, parent string, fn *lang.FuncModel, kind string) *docFXItem {
+	uid := fmt.Sprintf("%s.%s", parent, fn.Name)
+	fullName := fmt.Sprintf("%s.%s", pkg.Name, fn.Name)
+
+	// Methods are scoped to their receiver type rather than the package
+	if fn.Receiver != "" {
+		typeName := strings.TrimPrefix(parent, pkg.ImportPath+".")
+		fullName = fmt.Sprintf("%s.%s.%s", pkg.Name, typeName, fn.Name)
+	}
+
+	return &docFXItem{
+		UID:       uid,
+		ID:        fn.Name,
+		Parent:    parent,
+		Langs:     []string{"go"},
+		Name:      fn.Name,
+		FullName:  fullName,
+		Type:      kind,
+		Namespace: pkg.ImportPath,
+		Summary:   fn.Summary,
+		Remarks:   fn.Doc,
+		Syntax:    &docFXSyntax{fn.Signature},
+		Source:    docFXSourceFor(fn.Position),
+		Examples:  docFXExamples(fn.Examples),
+	}
+}
+
+// docFXValueItems creates an item for each name declared by the value, since
+// DocFX has no concept of a declaration block containing multiple fields.
+func docFXValueItems(pkg *lang.PackageModel, parent string, v *lang.ValueModel) []*docFXItem {
+	var items []*docFXItem
+	for _, name := range v.Names {
+		items = append(items, &docFXItem{
+			UID:       fmt.Sprintf("%s.%s", parent, name),
+			ID:        name,
+			Parent:    parent,
+			Langs:     []string{"go"},
+			Name:      name,
+			FullName:  fmt.Sprintf("%s.%s", pkg.Name, name),
+			Type:      "Field",
+			Namespace: pkg.ImportPath,
+			Summary:   v.Summary,
+			Remarks:   v.Doc,
+			Syntax:    &docFXSyntax{v.Decl},
+			Source:    docFXSourceFor(v.Position),
+		})
+	}
+
+	return items
+}
+
+func docFXSourceFor(pos lang.PositionModel) *docFXSource {
+	return &docFXSource{
+		Path:      pos.File,
+		StartLine: pos.StartLine,
+	}
+}
+
+func docFXExamples(examples []*lang.ExampleModel) []string {
+	var result []string
+	for _, ex := range examples {
+		result = append(result, fmt.Sprintf("```go\n%s\n```", ex.Code))
+	}
+
+	return result
+}
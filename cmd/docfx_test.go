@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestCommand_docfx(t *testing.T) {
+	is := is.New(t)
+
+	err := os.Chdir(filepath.Join(wd, "../testData"))
+	is.NoErr(err)
+
+	os.Args = []string{
+		"gomarkdoc", "./docfx",
+		"--Format", "docfx",
+		"-o", "{{.Dir}}/docfx-test.yml",
+		"--Repository.url", "https://github.com/ag5denis/gomarkdoc",
+		"--Repository.default-branch", "master",
+		"--Repository.path", "/testData/",
+	}
+	os.Remove(filepath.Join("docfx", "docfx-test.yml"))
+
+	runMain(t)
+
+	data, err := os.ReadFile(filepath.Join("docfx", "docfx.yml"))
+	is.NoErr(err)
+
+	data2, err := os.ReadFile(filepath.Join("docfx", "docfx-test.yml"))
+	is.NoErr(err)
+
+	is.Equal(string(data), string(data2))
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// envPrefix is the prefix of the environment variables that set the options
+// of the command.
+const envPrefix = "GOMARKDOC"
+
+var envNameReplacer = strings.NewReplacer("-", "_", ".", "_")
+
+// EnvName provides the name of the environment variable that sets the option
+// with the provided flag or configuration key name. Words are separated by
+// underscores (e.g. --include-unexported is set by
+// GOMARKDOC_INCLUDE_UNEXPORTED and hooks.pre by GOMARKDOC_HOOKS_PRE).
+func EnvName(name string) string {
+	return envPrefix + "_" + strings.ToUpper(envNameReplacer.Replace(name))
+}
+
+// envSet reports whether the environment variable for the flag is set.
+func envSet(name string) bool {
+	_, ok := os.LookupEnv(EnvName(name))
+	return ok
+}
+
+// bindEnv binds the environment variable of each option to its configuration
+// key. Values from the environment take precedence over the configuration
+// files, but not over flags.
+func bindEnv(command *cobra.Command) {
+	command.Flags().VisitAll(func(f *pflag.Flag) {
+		if unboundFlags[f.Name] {
+			return
+		}
+
+		_ = viper.BindEnv(configKeyName(f.Name), EnvName(f.Name))
+	})
+
+	for _, key := range configOnlyKeys {
+		if key.name == rootConfigKey {
+			continue
+		}
+
+		_ = viper.BindEnv(key.name, EnvName(key.name))
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/spf13/cobra"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"format":                    "GOMARKDOC_FORMAT",
+		"Output":                    "GOMARKDOC_OUTPUT",
+		"include-unexported":        "GOMARKDOC_INCLUDE_UNEXPORTED",
+		"Repository.default-branch": "GOMARKDOC_REPOSITORY_DEFAULT_BRANCH",
+		"hooks.pre":                 "GOMARKDOC_HOOKS_PRE",
+	}
+
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(EnvName(name), expected)
+		})
+	}
+}
+
+func TestBuildCommand_env(t *testing.T) {
+	t.Setenv("GOMARKDOC_OUTPUT", "env/{{.Dir}}/README.md")
+	t.Setenv("GOMARKDOC_INCLUDE_UNEXPORTED", "true")
+	t.Setenv("GOMARKDOC_FORMAT", "plain")
+
+	tests := map[string]struct {
+		args   []string
+		output string
+	}{
+		"environment": {
+			args:   []string{"resolve", "./pkg"},
+			output: "env/{{.Dir}}/README.md",
+		},
+		"flag": {
+			args:   []string{"resolve", "--Output", "flag.md", "./pkg"},
+			output: "flag.md",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			var resolved CommandOptions
+			command := BuildCommand(WithSubcommand(func(resolve ResolveFunc) *cobra.Command {
+				return &cobra.Command{
+					Use: "resolve [package ...]",
+					RunE: func(cmd *cobra.Command, args []string) error {
+						var err error
+						resolved, _, err = resolve(args)
+						return err
+					},
+				}
+			}))
+
+			command.SetArgs(test.args)
+			is.NoErr(command.Execute())
+
+			is.Equal(resolved.Output, test.output)
+			is.Equal(resolved.Formats, []string{"plain"})
+			is.True(resolved.IncludeUnexported)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// ExamplesFilePath provides the path of the examples file that accompanies the
+// provided Output file when examples are split from the main documentation. It
+// is written alongside the Output file, with .examples added before the
+// extension, so README.md has its examples in README.examples.md.
+func ExamplesFilePath(outputFile string) string {
+	ext := filepath.Ext(outputFile)
+	return strings.TrimSuffix(outputFile, ext) + ".examples" + ext
+}
+
+// ResolveExamplesRenderer determines how the separate examples file is
+// rendered. A nil FileRenderer is returned if examples are not split for the
+// configured Format. The extra options are applied to the template renderer
+// after those derived from opts.
+func ResolveExamplesRenderer(opts CommandOptions, extra ...gomarkdoc.RendererOption) (FileRenderer, error) {
+	if !opts.SplitExamples || IsDataFormat(opts.Format) {
+		return nil, nil
+	}
+
+	out, err := resolveRenderer(opts, extra...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Examples, nil
+}
+
+// hasExamples determines whether any of the packages or their symbols have
+// examples.
+func hasExamples(pkgs []*lang.Package) bool {
+	for _, pkg := range pkgs {
+		if len(pkg.Examples()) > 0 {
+			return true
+		}
+
+		for _, fn := range pkg.Funcs() {
+			if len(fn.Examples()) > 0 {
+				return true
+			}
+		}
+
+		for _, typ := range pkg.Types() {
+			if len(typ.Examples()) > 0 {
+				return true
+			}
+
+			for _, fn := range typ.Funcs() {
+				if len(fn.Examples()) > 0 {
+					return true
+				}
+			}
+
+			for _, fn := range typ.Methods() {
+				if len(fn.Examples()) > 0 {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestExamplesFilePath(t *testing.T) {
+	tests := map[string]string{
+		"README.md":           "README.examples.md",
+		"docs/api.md":         filepath.Join("docs", "api.examples.md"),
+		"pkg/doc.v2.markdown": filepath.Join("pkg", "doc.v2.examples.markdown"),
+		"docs/API":            filepath.Join("docs", "API.examples"),
+	}
+
+	for output, want := range tests {
+		t.Run(output, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(ExamplesFilePath(filepath.FromSlash(output)), want)
+		})
+	}
+}
+
+func TestRunCommand_splitExamples(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n")
+	writeConfig(t, filepath.Join(dir, "lib_test.go"), "package lib\n\nfunc ExampleHello() {\n\tHello()\n}\n")
+	chdir(t, dir)
+
+	for _, output := range []string{"README.md", "api.md"} {
+		is.NoErr(RunCommand([]string{"."}, CommandOptions{Output: output, SplitExamples: true}))
+
+		examplesFile := ExamplesFilePath(output)
+
+		b, err := os.ReadFile(output)
+		is.NoErr(err)
+		is.True(strings.Contains(string(b), "(<"+examplesFile+"#"))
+		is.True(!strings.Contains(string(b), "Hello()\n}"))
+
+		b, err = os.ReadFile(examplesFile)
+		is.NoErr(err)
+		is.True(strings.Contains(string(b), "Hello()"))
+	}
+
+	_, err := os.Stat("EXAMPLES.md")
+	is.True(os.IsNotExist(err))
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// regexpExcludePrefix marks an exclude pattern that is a regular expression
+// rather than a glob.
+const regexpExcludePrefix = "re:"
+
+// ExcludePattern identifies directories that are skipped when expanding
+// recursive paths.
+type ExcludePattern struct {
+	glob []string
+	re   *regexp.Regexp
+}
+
+// ParseExcludePatterns parses the provided exclude patterns. Patterns are
+// matched against the slash-separated path of each directory relative to the
+// working directory, such as pkg/mocks. A pattern is a glob in which each
+// path segment is matched as with path.Match and ** matches any number of
+// segments, such as **/mocks or examples/. A pattern that matches a directory
+// also excludes the directories beneath it. Patterns starting with re: are
+// regular expressions instead, which must match the directory's path itself.
+func ParseExcludePatterns(patterns []string) ([]*ExcludePattern, error) {
+	parsed := make([]*ExcludePattern, len(patterns))
+	for i, p := range patterns {
+		if strings.HasPrefix(p, regexpExcludePrefix) {
+			re, err := regexp.Compile(strings.TrimPrefix(p, regexpExcludePrefix))
+			if err != nil {
+				return nil, fmt.Errorf("gomarkdoc: invalid exclude pattern %s: %w", p, err)
+			}
+
+			parsed[i] = &ExcludePattern{re: re}
+			continue
+		}
+
+		glob := strings.Split(strings.Trim(path.Clean(filepath.ToSlash(p)), "/"), "/")
+		for _, segment := range glob {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("gomarkdoc: invalid exclude pattern %s: %w", p, err)
+			}
+		}
+
+		parsed[i] = &ExcludePattern{glob: glob}
+	}
+
+	return parsed, nil
+}
+
+// Match reports whether the pattern excludes the provided directory.
+func (p *ExcludePattern) Match(dir string) bool {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	if p.re != nil {
+		return p.re.MatchString(dir)
+	}
+
+	segments := strings.Split(dir, "/")
+	for i := range segments {
+		if matchGlob(p.glob, segments[:i+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGlob reports whether the glob segments match all of the path
+// segments.
+func matchGlob(glob, segments []string) bool {
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+
+	if glob[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(glob[1:], segments[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(glob[0], segments[0]); !ok {
+		return false
+	}
+
+	return matchGlob(glob[1:], segments[1:])
+}
+
+// ExcludeSpecs removes the specs found by expanding recursive paths whose
+// directories match any of the exclude patterns. Specs for packages that were
+// requested explicitly are kept.
+func ExcludeSpecs(specs []*PackageSpec, patterns []*ExcludePattern) []*PackageSpec {
+	if len(patterns) == 0 {
+		return specs
+	}
+
+	var filtered []*PackageSpec
+	for _, spec := range specs {
+		if spec.IsWildcard && matchesAny(patterns, spec.Dir) {
+			continue
+		}
+
+		filtered = append(filtered, spec)
+	}
+
+	return filtered
+}
+
+func matchesAny(patterns []*ExcludePattern, dir string) bool {
+	for _, p := range patterns {
+		if p.Match(dir) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestExcludeSpecs(t *testing.T) {
+	is := is.New(t)
+
+	patterns, err := ParseExcludePatterns([]string{"**/mocks", "examples/", "re:_gen$"})
+	is.NoErr(err)
+
+	specs := ExcludeSpecs([]*PackageSpec{
+		{Dir: "./pkg", ImportPath: "./pkg", IsWildcard: true, IsLocal: true},
+		{Dir: "./mocks", ImportPath: "./mocks", IsWildcard: true, IsLocal: true},
+		{Dir: "./pkg/store/mocks", ImportPath: "./pkg/store/mocks", IsWildcard: true, IsLocal: true},
+		{Dir: "./examples", ImportPath: "./examples", IsWildcard: true, IsLocal: true},
+		{Dir: "./examples/basic", ImportPath: "./examples/basic", IsWildcard: true, IsLocal: true},
+		{Dir: "./pkg/examples", ImportPath: "./pkg/examples", IsWildcard: true, IsLocal: true},
+		{Dir: "./pkg/api_gen", ImportPath: "./pkg/api_gen", IsWildcard: true, IsLocal: true},
+		{Dir: "./pkg/mockstore", ImportPath: "./pkg/mockstore", IsWildcard: true, IsLocal: true},
+		{Dir: "./mocks/explicit", ImportPath: "./mocks/explicit", IsWildcard: false, IsLocal: true},
+	}, patterns)
+
+	var dirs []string
+	for _, spec := range specs {
+		dirs = append(dirs, spec.Dir)
+	}
+
+	is.Equal(dirs, []string{"./pkg", "./pkg/examples", "./pkg/mockstore", "./mocks/explicit"})
+}
+
+func TestParseExcludePatterns_invalid(t *testing.T) {
+	is := is.New(t)
+
+	_, err := ParseExcludePatterns([]string{"pkg/[a-"})
+	is.True(err != nil) // invalid glob
+
+	_, err = ParseExcludePatterns([]string{"re:("})
+	is.True(err != nil) // invalid regular expression
+}
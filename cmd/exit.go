@@ -0,0 +1,92 @@
+package cmd
+
+import "errors"
+
+// Exit codes reported by the gomarkdoc command, which allow CI pipelines to
+// tell stale documentation apart from other failures.
+const (
+	// ExitSuccess indicates that the run completed successfully.
+	ExitSuccess = 0
+
+	// ExitError indicates a usage or configuration error, or any other
+	// failure that isn't covered by a more specific exit code.
+	ExitError = 1
+
+	// ExitLoadFailure indicates that one or more packages failed to load.
+	ExitLoadFailure = 2
+
+	// ExitCheckMismatch indicates that the documentation is out of date in
+	// Check mode.
+	ExitCheckMismatch = 3
+
+	// ExitLintViolation indicates that the lint command found documentation
+	// issues.
+	ExitLintViolation = 4
+)
+
+// loadError marks an error encountered while loading a package.
+type loadError struct {
+	err error
+}
+
+func (e *loadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *loadError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode determines the exit code to report for the error returned by a
+// run. When several failures were collected while running with KeepGoing,
+// general errors take precedence over package load failures, which take
+// precedence over Check mismatches.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	var failures failuresError
+	if errors.As(err, &failures) {
+		code := ExitSuccess
+		for _, f := range failures {
+			if c := ExitCode(f); exitPrecedence(c) > exitPrecedence(code) {
+				code = c
+			}
+		}
+
+		return code
+	}
+
+	var (
+		le   *loadError
+		lint *lintError
+	)
+	switch {
+	case errors.Is(err, errOutputMismatch):
+		return ExitCheckMismatch
+	case errors.As(err, &lint):
+		return ExitLintViolation
+	case errors.As(err, &le):
+		return ExitLoadFailure
+	default:
+		return ExitError
+	}
+}
+
+// exitPrecedence ranks the exit codes by the order in which they take
+// precedence.
+func exitPrecedence(code int) int {
+	switch code {
+	case ExitError:
+		return 4
+	case ExitLoadFailure:
+		return 3
+	case ExitCheckMismatch:
+		return 2
+	case ExitLintViolation:
+		return 1
+	default:
+		return 0
+	}
+}
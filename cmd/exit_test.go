@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestExitCode(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(ExitCode(nil), ExitSuccess)
+	is.Equal(ExitCode(errors.New("gomarkdoc: invalid flag")), ExitError)
+	is.Equal(ExitCode(&loadError{errors.New("gomarkdoc: invalid package")}), ExitLoadFailure)
+	is.Equal(ExitCode(errOutputMismatch), ExitCheckMismatch)
+	is.Equal(ExitCode(fmt.Errorf("Output file a.md: %w", errOutputMismatch)), ExitCheckMismatch)
+	is.Equal(ExitCode(&lintError{2}), ExitLintViolation)
+}
+
+func TestExitCode_failures(t *testing.T) {
+	is := is.New(t)
+
+	var failures Failures
+	failures.Add(fmt.Errorf("Output file a.md: %w", errOutputMismatch))
+	is.Equal(ExitCode(failures.Err()), ExitCheckMismatch)
+
+	failures.Add(fmt.Errorf("package ./b: %w", &loadError{errors.New("failed")}))
+	is.Equal(ExitCode(failures.Err()), ExitLoadFailure) // load failures take precedence
+
+	failures.Add(errors.New("Output file c.md: failed"))
+	is.Equal(ExitCode(failures.Err()), ExitError) // general errors take precedence
+}
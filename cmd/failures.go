@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Failures collects the errors encountered while loading individual packages
+// and writing individual Output files when running with KeepGoing, so that
+// they can be reported together once the rest of the run has completed.
+type Failures []error
+
+// failuresError reports all of the failures from a run as a single error.
+type failuresError Failures
+
+// Add records a failure.
+func (f *Failures) Add(err error) {
+	*f = append(*f, err)
+}
+
+// Err provides an error summarizing all of the recorded failures, or nil if
+// there were none.
+func (f Failures) Err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	return failuresError(f)
+}
+
+func (e failuresError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gomarkdoc: encountered %d failure(s):", len(e))
+	for _, err := range e {
+		fmt.Fprintf(&b, "\n  - %s", err)
+	}
+
+	return b.String()
+}
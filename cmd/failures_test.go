@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFailures_Err(t *testing.T) {
+	is := is.New(t)
+
+	var failures Failures
+	is.NoErr(failures.Err())
+
+	failures.Add(errors.New("package ./a: failed"))
+	failures.Add(errors.New("Output file b.md: failed"))
+
+	err := failures.Err()
+	is.True(err != nil)
+	is.Equal(err.Error(), "gomarkdoc: encountered 2 failure(s):\n  - package ./a: failed\n  - Output file b.md: failed")
+}
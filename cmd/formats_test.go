@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/spf13/cobra"
+)
+
+func TestRunCommand_formats(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Formats:       []string{"github,yaml"},
+		Output:        "README.md",
+		FormatOutputs: map[string]string{"yaml": "api.yml"},
+	}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(strings.Contains(string(b), "## func Hello\n"))
+
+	b, err = os.ReadFile("api.yml")
+	is.NoErr(err)
+	is.True(strings.Contains(string(b), "summary: Hello says hello."))
+	is.True(!strings.Contains(string(b), "## func"))
+
+	// Each format is checked against its own Output
+	opts.Check = true
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	writeConfig(t, "api.yml", "outdated\n")
+	err = RunCommand([]string{"."}, opts)
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "api.yml"))
+}
+
+func TestRunCommand_formatsSameOutput(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n")
+	chdir(t, dir)
+
+	err := RunCommand([]string{"."}, CommandOptions{Formats: []string{"github", "plain"}, Output: "README.md"})
+	is.True(err != nil)
+	is.Equal(err.Error(), "gomarkdoc: formats github and plain cannot be written to the same Output")
+
+	_, err = os.Stat("README.md")
+	is.True(os.IsNotExist(err))
+}
+
+func TestBuildCommand_formatsCheck(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		err  string
+	}{
+		"Output per format": {
+			args: []string{"resolve", "-c", "--Format", "github,llms", "-o", "README.md", "--format-output", "llms=llms.txt"},
+		},
+		"missing Output": {
+			args: []string{"resolve", "-c", "--Format", "github,llms", "--format-output", "llms=llms.txt"},
+			err:  "gomarkdoc: Check mode cannot be run without an Output set",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			// Keep the configuration of the repository from providing an Output
+			chdir(t, t.TempDir())
+
+			command := BuildCommand(WithSubcommand(func(resolve ResolveFunc) *cobra.Command {
+				return &cobra.Command{
+					Use: "resolve [package ...]",
+					RunE: func(cmd *cobra.Command, args []string) error {
+						_, _, err := resolve(args)
+						return err
+					},
+				}
+			}))
+
+			var out bytes.Buffer
+			command.SetOut(&out)
+			command.SetErr(&out)
+			command.SetArgs(test.args)
+
+			err := command.Execute()
+			if test.err != "" {
+				is.Equal(err.Error(), test.err)
+				return
+			}
+
+			is.NoErr(err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_frontmatter(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{Output: "README.md", Header: "Header text"}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(string(b), "<!-- Code generated by gomarkdoc. DO NOT EDIT -->\n"))
+
+	opts.Repository = lang.Repo{
+		Remote:        "https://github.com/example/mod",
+		DefaultBranch: "main",
+		PathFromRoot:  "/",
+	}
+	opts.TemplateOverrides = map[string]string{
+		"frontmatter": `---
+{{- with index .Packages 0 }}
+title: {{ .Name }}
+editUrl: {{ .Repo.Remote }}/edit/{{ .Repo.DefaultBranch }}
+{{- end }}
+---
+`,
+	}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err = os.ReadFile("README.md")
+	is.NoErr(err)
+
+	text := string(b)
+	is.True(strings.HasPrefix(text, "---\ntitle: lib\neditUrl: https://github.com/example/mod/edit/main\n---\n<!-- Code generated by gomarkdoc. DO NOT EDIT -->\n"))
+	is.True(strings.Index(text, "---") < strings.Index(text, "Header text"))
+}
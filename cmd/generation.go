@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/go-git/go-git/v5"
+
+	"github.com/ag5denis/gomarkdoc"
+)
+
+// generationInfo describes the run of the command for templates.
+func generationInfo(opts CommandOptions) gomarkdoc.GenerationInfo {
+	return gomarkdoc.GenerationInfo{
+		Version: ReadVersionInfo().Version,
+		Commit:  headCommit(),
+		Options: opts,
+	}
+}
+
+// headCommit provides the SHA of the commit checked out in the git repository
+// containing the working directory, or an empty string outside of a
+// repository.
+func headCommit() string {
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		return ""
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+
+	return head.Hash().String()
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+func TestRunCommand_generation(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Output: "README.md",
+		Format: "plain",
+		Repository: lang.Repo{
+			Remote:        "https://github.com/example/mod",
+			DefaultBranch: "main",
+			PathFromRoot:  "/",
+		},
+		TemplateOverrides: map[string]string{
+			"frontmatter": "{{ with generation }}{{ .Version }} {{ .Options.Format }}{{ end }}{{ with .Repo }} {{ .Remote }} {{ .DefaultBranch }}{{ end }}\n",
+		},
+	}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(string(b), ReadVersionInfo().Version+" plain https://github.com/example/mod main\n"))
+}
@@ -3,6 +3,7 @@ package main
 import (
 	C "github.com/ag5denis/gomarkdoc/cmd"
 	"log"
+	"os"
 )
 
 func main() {
@@ -11,6 +12,7 @@ func main() {
 	buildCmd := C.BuildCommand()
 
 	if err := buildCmd.Execute(); err != nil {
-		log.Fatal(err)
+		log.Print(err)
+		os.Exit(C.ExitCode(err))
 	}
 }
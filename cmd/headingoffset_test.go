@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_headingOffset(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{Output: "README.md", HeadingOffset: 1}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+
+	text := string(b)
+	is.True(strings.Contains(text, "\n## lib\n"))
+	is.True(strings.Contains(text, "\n### Index\n"))
+	is.True(strings.Contains(text, "\n### func Hello\n"))
+	is.True(!strings.Contains(text, "\n# "))
+}
+
+func TestResolveOverrides_invalidHeadingOffset(t *testing.T) {
+	is := is.New(t)
+
+	_, err := ResolveOverrides(CommandOptions{HeadingOffset: 6})
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "invalid heading offset 6"))
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/logger"
+)
+
+// HookFilesEnv is the environment variable through which hooks receive the
+// list of Output files written by the run, separated by newlines. The same
+// list is also provided to each hook on stdin.
+const HookFilesEnv = "GOMARKDOC_FILES"
+
+// Hooks holds the external commands to run around documentation generation.
+// Pre hooks run before any packages are loaded and post hooks run once all of
+// the Output files have been written. Hooks are not run in Check mode.
+type Hooks struct {
+	Pre  []string
+	Post []string
+}
+
+// RunHooks runs each of the provided commands in order using the system shell,
+// stopping at the first command that fails. The files are made available to
+// each command through the HookFilesEnv environment variable and on stdin.
+func RunHooks(log logger.Logger, commands []string, files []string) error {
+	var list string
+	if len(files) > 0 {
+		list = strings.Join(files, "\n") + "\n"
+	}
+
+	for _, command := range commands {
+		log.Debugf("running hook: %s", command)
+
+		cmd := shellCommand(command)
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", HookFilesEnv, strings.TrimSuffix(list, "\n")))
+		cmd.Stdin = strings.NewReader(list)
+
+		// Hook output goes to stderr so that it doesn't mix with documentation
+		// printed to stdout
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("gomarkdoc: hook %q failed: %w", command, err)
+		}
+	}
+
+	return nil
+}
+
+// shellCommand builds the command to run the provided command line with the
+// system shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/ag5denis/gomarkdoc/logger"
+)
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with sh in this test")
+	}
+
+	is := is.New(t)
+
+	dir := t.TempDir()
+	stdinFile := filepath.Join(dir, "stdin.txt")
+	envFile := filepath.Join(dir, "env.txt")
+
+	err := RunHooks(logger.New(logger.ErrorLevel), []string{
+		"cat > " + stdinFile,
+		"printf '%s' \"$" + HookFilesEnv + "\" > " + envFile,
+	}, []string{"a/README.md", "b/README.md"})
+	is.NoErr(err)
+
+	stdin, err := os.ReadFile(stdinFile)
+	is.NoErr(err)
+	is.Equal(string(stdin), "a/README.md\nb/README.md\n")
+
+	env, err := os.ReadFile(envFile)
+	is.NoErr(err)
+	is.Equal(string(env), "a/README.md\nb/README.md")
+}
+
+func TestRunHooks_failure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with sh in this test")
+	}
+
+	is := is.New(t)
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "marker")
+
+	err := RunHooks(logger.New(logger.ErrorLevel), []string{"exit 3", "touch " + marker}, nil)
+	is.True(err != nil)
+
+	_, err = os.Stat(marker)
+	is.True(os.IsNotExist(err))
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import "github.com/go-git/go-git/v5"
+
+// includeRoot provides the directory that templates can include files from,
+// which is the root of the git repository containing the working directory.
+// Outside of a repository, the working directory is used instead.
+func includeRoot() string {
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		return "."
+	}
+
+	t, err := repo.Worktree()
+	if err != nil {
+		return "."
+	}
+
+	return t.Filesystem.Root()
+}
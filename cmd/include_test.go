@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_include(t *testing.T) {
+	is := is.New(t)
+
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "mod")
+	writeConfig(t, filepath.Join(parent, "secret.txt"), "secret\n")
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n")
+	writeConfig(t, filepath.Join(dir, "docs", "badges.md"), "[![Build](https://example.com/badge.svg)](https://example.com)\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Output:            "README.md",
+		TemplateOverrides: map[string]string{"frontmatter": `{{ include "docs/badges.md" }}`},
+	}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(string(b), "[![Build](https://example.com/badge.svg)](https://example.com)\n<!-- Code generated by gomarkdoc. DO NOT EDIT -->\n"))
+
+	opts.TemplateOverrides = map[string]string{"frontmatter": `{{ include "../secret.txt" }}`}
+	err = RunCommand([]string{"."}, opts)
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "file is outside of"))
+}
+
+func TestRunCommand_includeCache(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n")
+	writeConfig(t, filepath.Join(dir, "docs", "badges.md"), "v1 badge\n")
+	chdir(t, dir)
+
+	paths := []string{dir}
+	opts := CommandOptions{
+		Output:            "README.md",
+		Cache:             ".gomarkdoc-cache.json",
+		TemplateOverrides: map[string]string{"frontmatter": `{{ include "docs/badges.md" }}`},
+	}
+	is.NoErr(RunCommand(paths, opts))
+
+	cache, err := ReadCache(opts.Cache)
+	is.NoErr(err)
+	is.Equal(len(cache.Files["README.md"].Includes), 1)
+
+	checkOpts := opts
+	checkOpts.Check = true
+	is.NoErr(RunCommand(paths, checkOpts))
+
+	// Edits to the included file are picked up although the sources and
+	// options are unchanged
+	writeConfig(t, filepath.Join("docs", "badges.md"), "v2 badge\n")
+	is.True(errors.Is(RunCommand(paths, checkOpts), errOutputMismatch))
+
+	is.NoErr(RunCommand(paths, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(string(b), "v2 badge\n"))
+	is.NoErr(RunCommand(paths, checkOpts))
+}
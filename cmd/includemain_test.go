@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_includeMain(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib", "lib.go"), "// Package lib is a library.\npackage lib\n")
+	writeConfig(t, filepath.Join(dir, "cmd", "tool", "main.go"), `// Command tool does things.
+package main
+
+import "flag"
+
+func main() {
+	flag.String("name", "world", "Who to greet.")
+	flag.Parse()
+}
+`)
+	chdir(t, dir)
+
+	opts := CommandOptions{Output: "{{.Dir}}/README.md"}
+	is.NoErr(RunCommand([]string{"./..."}, opts))
+
+	_, err := os.Stat(filepath.Join("lib", "README.md"))
+	is.NoErr(err)
+
+	_, err = os.Stat(filepath.Join("cmd", "tool", "README.md"))
+	is.True(os.IsNotExist(err)) // Main packages are skipped by default
+
+	opts.IncludeMain = true
+	is.NoErr(RunCommand([]string{"./..."}, opts))
+
+	b, err := os.ReadFile(filepath.Join("cmd", "tool", "README.md"))
+	is.NoErr(err)
+
+	text := string(b)
+	is.True(strings.Contains(text, "```sh\ngo install example.com/mod/cmd/tool@latest\n```"))
+	is.True(!strings.Contains(text, "import \"example.com/mod/cmd/tool\""))
+	is.True(strings.Contains(text, "## Flags\n\n- **\\-name** string (default \"world\"): Who to greet.\n"))
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestExcludeInternalSpecs(t *testing.T) {
+	is := is.New(t)
+
+	specs := ExcludeInternalSpecs([]*PackageSpec{
+		{Dir: "./pkg", ImportPath: "./pkg", IsWildcard: true, IsLocal: true},
+		{Dir: "./internal/store", ImportPath: "./internal/store", IsWildcard: true, IsLocal: true},
+		{Dir: "./pkg/internal", ImportPath: "./pkg/internal", IsWildcard: true, IsLocal: true},
+		{Dir: "./internal/explicit", ImportPath: "./internal/explicit", IsWildcard: false, IsLocal: true},
+		{Dir: "./internals", ImportPath: "./internals", IsWildcard: true, IsLocal: true},
+	})
+
+	var dirs []string
+	for _, spec := range specs {
+		dirs = append(dirs, spec.Dir)
+	}
+
+	is.Equal(dirs, []string{"./pkg", "./internal/explicit", "./internals"})
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// LintRule identifies one of the documentation coverage checks performed by
+// the lint command.
+type LintRule string
+
+const (
+	// LintMissingDoc reports exported symbols without a doc comment.
+	LintMissingDoc LintRule = "missing-doc"
+
+	// LintMissingPackageDoc reports packages without a package doc comment.
+	LintMissingPackageDoc LintRule = "missing-package-doc"
+
+	// LintDocPrefix reports doc comments that don't start with the name of
+	// the symbol they document. Doc comments of types may start with an
+	// article (A, An or The) before the name.
+	LintDocPrefix LintRule = "doc-prefix"
+)
+
+// lintRules lists all of the lint rules, which are enabled by default.
+var lintRules = []LintRule{LintMissingDoc, LintMissingPackageDoc, LintDocPrefix}
+
+// LintIssue describes a documentation coverage problem found by the lint
+// command.
+type LintIssue struct {
+	// Rule identifies the rule that found the issue.
+	Rule LintRule
+
+	// File holds the file in which the issue occurs, or the directory of the
+	// package for issues that apply to the package as a whole.
+	File string
+
+	// Line holds the one-indexed line on which the issue occurs, or 0 for
+	// issues that apply to the package as a whole.
+	Line int
+
+	// Message describes the issue.
+	Message string
+}
+
+func (i *LintIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s (%s)", i.File, i.Message, i.Rule)
+	}
+
+	return fmt.Sprintf("%s:%d: %s (%s)", i.File, i.Line, i.Message, i.Rule)
+}
+
+// ResolveLintRules determines the lint rules to check. All rules are checked
+// unless enable lists some of them, in which case only those are checked.
+// Rules listed in disable are never checked.
+func ResolveLintRules(enable, disable []string) (map[LintRule]bool, error) {
+	valid := make(map[LintRule]bool)
+	for _, rule := range lintRules {
+		valid[rule] = true
+	}
+
+	for _, name := range append(append([]string(nil), enable...), disable...) {
+		if !valid[LintRule(name)] {
+			return nil, fmt.Errorf("gomarkdoc: invalid lint rule: %s", name)
+		}
+	}
+
+	rules := make(map[LintRule]bool)
+	if len(enable) > 0 {
+		for _, name := range enable {
+			rules[LintRule(name)] = true
+		}
+	} else {
+		for _, rule := range lintRules {
+			rules[rule] = true
+		}
+	}
+
+	for _, name := range disable {
+		delete(rules, LintRule(name))
+	}
+
+	return rules, nil
+}
+
+// LintPackage checks the documentation of the package against the provided
+// rules. Const and var declarations are checked by block, so a block with a
+// doc comment covers all of the names it declares.
+func LintPackage(pkg *lang.Package, rules map[LintRule]bool) ([]*LintIssue, error) {
+	m, err := pkg.Model()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []*LintIssue
+	if rules[LintMissingPackageDoc] && m.Doc == "" {
+		issues = append(issues, &LintIssue{
+			Rule:    LintMissingPackageDoc,
+			File:    pkg.Dir(),
+			Message: fmt.Sprintf("package %s has no package doc comment", m.Name),
+		})
+	}
+
+	check := func(kind, name, prefix, doc string, pos lang.PositionModel, article bool) {
+		switch {
+		case doc == "":
+			if rules[LintMissingDoc] {
+				issues = append(issues, &LintIssue{
+					Rule:    LintMissingDoc,
+					File:    pos.File,
+					Line:    pos.StartLine,
+					Message: fmt.Sprintf("exported %s %s has no doc comment", kind, name),
+				})
+			}
+		case prefix != "" && !hasDocPrefix(doc, prefix, article):
+			if rules[LintDocPrefix] {
+				issues = append(issues, &LintIssue{
+					Rule:    LintDocPrefix,
+					File:    pos.File,
+					Line:    pos.StartLine,
+					Message: fmt.Sprintf("doc comment of %s %s should start with %s", kind, name, prefix),
+				})
+			}
+		}
+	}
+
+	checkValues := func(kind string, values []*lang.ValueModel) {
+		for _, v := range values {
+			// The doc comment of a block declaring several names can't
+			// start with all of them
+			prefix := ""
+			if len(v.Names) == 1 {
+				prefix = v.Names[0]
+			}
+
+			check(kind, strings.Join(v.Names, ", "), prefix, v.Doc, v.Position, false)
+		}
+	}
+
+	checkFuncs := func(funcs []*lang.FuncModel) {
+		for _, fn := range funcs {
+			if fn.Receiver == "" {
+				check("func", fn.Name, fn.Name, fn.Doc, fn.Position, false)
+			} else {
+				name := strings.TrimPrefix(fn.Receiver, "*") + "." + fn.Name
+				check("method", name, fn.Name, fn.Doc, fn.Position, false)
+			}
+		}
+	}
+
+	checkValues("const", m.Consts)
+	checkValues("var", m.Vars)
+	checkFuncs(m.Funcs)
+
+	for _, typ := range m.Types {
+		check("type", typ.Name, typ.Name, typ.Doc, typ.Position, true)
+		checkValues("const", typ.Consts)
+		checkValues("var", typ.Vars)
+		checkFuncs(typ.Funcs)
+		checkFuncs(typ.Methods)
+	}
+
+	return issues, nil
+}
+
+// hasDocPrefix reports whether the doc comment starts with the name as a whole
+// word, optionally preceded by an article. Doc comments that only mark the
+// symbol as deprecated are accepted as they are.
+func hasDocPrefix(doc, name string, article bool) bool {
+	if strings.HasPrefix(doc, "Deprecated:") {
+		return true
+	}
+
+	candidates := []string{doc}
+	if article {
+		for _, a := range []string{"A ", "An ", "The "} {
+			if strings.HasPrefix(doc, a) {
+				candidates = append(candidates, strings.TrimPrefix(doc, a))
+			}
+		}
+	}
+
+	for _, c := range candidates {
+		if !strings.HasPrefix(c, name) {
+			continue
+		}
+
+		next, _ := utf8.DecodeRuneInString(c[len(name):])
+		if next == utf8.RuneError || !(unicode.IsLetter(next) || unicode.IsDigit(next) || next == '_') {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lintError reports the number of issues found by the lint command, which
+// have already been printed.
+type lintError struct {
+	count int
+}
+
+func (e *lintError) Error() string {
+	return fmt.Sprintf("gomarkdoc: lint found %d issue(s)", e.count)
+}
+
+// buildLintCommand creates the lint command, which reports gaps in the
+// documentation coverage of the packages.
+func buildLintCommand(resolve ResolveFunc) *cobra.Command {
+	var enable, disable []string
+
+	lint := &cobra.Command{
+		Use:   "lint [package ...]",
+		Short: "report exported symbols and packages with missing or malformed doc comments",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, paths, err := resolve(args)
+			if err != nil {
+				return err
+			}
+
+			rules, err := ResolveLintRules(viper.GetStringSlice("Lint.enable"), viper.GetStringSlice("Lint.disable"))
+			if err != nil {
+				return err
+			}
+
+			annotate := ResolveAnnotations(opts) == AnnotationsGitHub
+
+			var failures Failures
+			specs, err := loadSpecs(paths, opts, &failures)
+			if err != nil {
+				return err
+			}
+
+			var count int
+			for _, spec := range specs {
+				if spec.Pkg == nil {
+					continue
+				}
+
+				issues, err := LintPackage(spec.Pkg, rules)
+				if err != nil {
+					return err
+				}
+
+				for _, issue := range issues {
+					if annotate {
+						err = writeGitHubAnnotation(cmd.OutOrStdout(), issue.File, issue.Line, string(issue.Rule), issue.Message)
+					} else {
+						_, err = fmt.Fprintln(cmd.OutOrStdout(), issue)
+					}
+
+					if err != nil {
+						return err
+					}
+				}
+
+				count += len(issues)
+			}
+
+			if err := failures.Err(); err != nil {
+				return err
+			}
+
+			if count > 0 {
+				return &lintError{count}
+			}
+
+			return nil
+		},
+	}
+
+	lint.Flags().StringSliceVar(
+		&enable,
+		"enable",
+		nil,
+		"Only check the provided lint rules. Valid options are: missing-doc, missing-package-doc and doc-prefix. Can be specified multiple times.",
+	)
+	lint.Flags().StringSliceVar(
+		&disable,
+		"disable",
+		nil,
+		"Skip the provided lint rules. Can be specified multiple times.",
+	)
+
+	_ = viper.BindPFlag("Lint.enable", lint.Flags().Lookup("enable"))
+	_ = viper.BindPFlag("Lint.disable", lint.Flags().Lookup("disable"))
+
+	return lint
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"go/build"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/ag5denis/gomarkdoc/logger"
+)
+
+func TestLintPackage(t *testing.T) {
+	is := is.New(t)
+
+	pkg := loadTestPackage(t, "../testData/lint")
+	rules, err := ResolveLintRules(nil, nil)
+	is.NoErr(err)
+
+	issues, err := LintPackage(pkg, rules)
+	is.NoErr(err)
+
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+
+	is.Equal(messages, []string{
+		"package lint has no package doc comment",
+		"doc comment of var Name should start with Name",
+		"exported func Undocumented has no doc comment",
+		"doc comment of func WrongPrefix should start with WrongPrefix",
+		"exported method Thing.UndocumentedMethod has no doc comment",
+	})
+
+	is.Equal(issues[2].Rule, LintMissingDoc)
+	is.Equal(issues[2].Line, 6)
+	is.Equal(issues[2].String(), "../testData/lint/lint.go:6: exported func Undocumented has no doc comment (missing-doc)")
+}
+
+func TestLintPackage_rules(t *testing.T) {
+	is := is.New(t)
+
+	pkg := loadTestPackage(t, "../testData/lint")
+
+	rules, err := ResolveLintRules([]string{"missing-doc", "doc-prefix"}, []string{"doc-prefix"})
+	is.NoErr(err)
+
+	issues, err := LintPackage(pkg, rules)
+	is.NoErr(err)
+
+	is.Equal(len(issues), 2)
+	for _, issue := range issues {
+		is.Equal(issue.Rule, LintMissingDoc)
+	}
+}
+
+func TestResolveLintRules_invalid(t *testing.T) {
+	is := is.New(t)
+
+	_, err := ResolveLintRules(nil, []string{"missing-docs"})
+	is.Equal(err.Error(), "gomarkdoc: invalid lint rule: missing-docs")
+}
+
+func loadTestPackage(t *testing.T, dir string) *lang.Package {
+	t.Helper()
+
+	buildPkg, err := build.ImportDir(dir, build.ImportComment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := lang.NewPackageFromBuild(logger.New(logger.ErrorLevel), buildPkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
+
+func TestLintCommand_annotations(t *testing.T) {
+	is := is.New(t)
+
+	command := BuildCommand()
+
+	var out bytes.Buffer
+	command.SetOut(&out)
+	command.SetErr(&out)
+	command.SetArgs([]string{"lint", "--enable", "missing-doc", "--annotations", "github", "../testData/lint"})
+
+	var lintErr *lintError
+	is.True(errors.As(command.Execute(), &lintErr))
+	is.True(strings.Contains(
+		out.String(),
+		"::error file=../testData/lint/lint.go,line=6,title=missing-doc::exported func Undocumented has no doc comment\n",
+	))
+}
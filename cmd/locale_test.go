@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_locale(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), `// Package lib is a library.
+package lib
+
+// Answer is the answer. See [Greeting].
+const Answer = 42
+
+// Greeting is the greeting.
+var Greeting = "hello"
+
+// Hello says hello.
+func Hello() {}
+`)
+	writeConfig(t, filepath.Join(dir, "lib_test.go"), `package lib
+
+func ExampleHello() {
+	Hello()
+}
+
+func ExampleHello_loud() {
+	Hello()
+}
+`)
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Output:       "README.md",
+		Locale:       "de",
+		Translations: map[string]string{"variables": "Globale Variablen"},
+	}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+
+	text := string(b)
+	is.True(strings.Contains(text, "\n## Index\n"))
+	is.True(strings.Contains(text, "\n## Konstanten\n"))
+	is.True(strings.Contains(text, "[Konstanten](<#konstanten>)"))
+	is.True(strings.Contains(text, "\n## Globale Variablen\n"))
+	is.True(strings.Contains(text, "[Greeting](<#globale-variablen>)"))
+	is.True(strings.Contains(text, "<summary>Beispiel</summary>"))
+	is.True(strings.Contains(text, "<summary>Beispiel (Loud)</summary>"))
+	is.True(!strings.Contains(text, "Constants"))
+}
+
+func TestResolveOverrides_invalidLocale(t *testing.T) {
+	is := is.New(t)
+
+	_, err := ResolveOverrides(CommandOptions{Locale: "xx"})
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), `unsupported locale "xx"`))
+}
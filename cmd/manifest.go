@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Manifest maps each generated output file to the hash of its contents. Paths
+// are stored relative to the directory containing the manifest file using
+// forward slashes, so manifests can be shared across platforms.
+type Manifest map[string]string
+
+// HashContents computes the hash recorded in a Manifest for the provided file
+// contents.
+func HashContents(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// ReadManifest reads the manifest at the provided path. The manifest uses the
+// same format as the output of the sha256sum tool, with one file per line.
+func ReadManifest(path string) (Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest %s: %w", path, err)
+	}
+
+	defer f.Close()
+
+	m := make(Manifest)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		parts := strings.SplitN(text, "  ", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid entry on line %d of manifest %s", line, path)
+		}
+
+		m[parts[1]] = parts[0]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// Write writes the manifest to the provided path, sorted by file path.
+func (m Manifest) Write(path string) error {
+	files := make([]string, 0, len(m))
+	for file := range m {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+
+	var b strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&b, "%s  %s\n", m[file], file)
+	}
+
+	return WriteFile(path, b.String())
+}
+
+// Add records the hash of the provided contents for the output file. The
+// manifestPath is the location of the manifest the entry is being added to.
+func (m Manifest) Add(manifestPath, fileName, text string) error {
+	key, err := manifestKey(manifestPath, fileName)
+	if err != nil {
+		return err
+	}
+
+	m[key] = HashContents(text)
+	return nil
+}
+
+// Check determines whether the manifest entry for the output file matches the
+// provided contents. The manifestPath is the location of the manifest the
+// entry was read from.
+func (m Manifest) Check(manifestPath, fileName, text string) (bool, error) {
+	key, err := manifestKey(manifestPath, fileName)
+	if err != nil {
+		return false, err
+	}
+
+	hash, ok := m[key]
+	return ok && hash == HashContents(text), nil
+}
+
+// Hash provides the hash recorded in the manifest for the output file, or the
+// empty string if the file isn't listed. The manifestPath is the location of
+// the manifest the entry was read from.
+func (m Manifest) Hash(manifestPath, fileName string) string {
+	key, err := manifestKey(manifestPath, fileName)
+	if err != nil {
+		return ""
+	}
+
+	return m[key]
+}
+
+func manifestKey(manifestPath, fileName string) (string, error) {
+	manifestDir, err := filepath.Abs(filepath.Dir(manifestPath))
+	if err != nil {
+		return "", err
+	}
+
+	absFile, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(manifestDir, absFile)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestManifest(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docs", "docs.sha256")
+
+	m := make(Manifest)
+	is.NoErr(m.Add(path, filepath.Join(dir, "b", "README.md"), "b"))
+	is.NoErr(m.Add(path, filepath.Join(dir, "a", "README.md"), "a"))
+
+	// Entries are relative to the directory of the manifest
+	is.Equal(m.Hash(path, filepath.Join(dir, "a", "README.md")), HashContents("a"))
+	is.Equal(m["../a/README.md"], HashContents("a"))
+	is.Equal(m.Hash(path, filepath.Join(dir, "c", "README.md")), "")
+
+	is.NoErr(m.Write(path))
+
+	b, err := os.ReadFile(path)
+	is.NoErr(err)
+	is.Equal(string(b), HashContents("a")+"  ../a/README.md\n"+HashContents("b")+"  ../b/README.md\n")
+
+	read, err := ReadManifest(path)
+	is.NoErr(err)
+	is.Equal(read, m)
+
+	match, err := read.Check(path, filepath.Join(dir, "a", "README.md"), "a")
+	is.NoErr(err)
+	is.True(match)
+
+	match, err = read.Check(path, filepath.Join(dir, "a", "README.md"), "edited")
+	is.NoErr(err)
+	is.True(!match)
+
+	match, err = read.Check(path, filepath.Join(dir, "c", "README.md"), "c")
+	is.NoErr(err)
+	is.True(!match)
+}
+
+func TestReadManifest_invalid(t *testing.T) {
+	is := is.New(t)
+
+	path := filepath.Join(t.TempDir(), "docs.sha256")
+	writeConfig(t, path, HashContents("a")+"  a/README.md\n\nbroken\n")
+
+	_, err := ReadManifest(path)
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "invalid entry on line 3"))
+
+	_, err = ReadManifest(filepath.Join(t.TempDir(), "missing.sha256"))
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "failed to open manifest"))
+}
+
+func TestRunCommand_checkManifestFiles(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "a", "a.go"), "// Package a is a package.\npackage a\n")
+	writeConfig(t, filepath.Join(dir, "b", "b.go"), "// Package b is a package.\npackage b\n")
+	chdir(t, dir)
+
+	paths := []string{"./..."}
+	opts := CommandOptions{Output: "{{.Dir}}/README.md", Manifest: "docs.sha256"}
+	is.NoErr(RunCommand(paths, opts))
+
+	checkOpts := opts
+	checkOpts.Check = true
+	is.NoErr(RunCommand(paths, checkOpts))
+
+	// The manifest still matches the generated documentation, but the files
+	// don't
+	writeConfig(t, filepath.Join("a", "README.md"), "edited\n")
+	is.NoErr(os.Remove(filepath.Join("b", "README.md")))
+
+	err := RunCommand(paths, checkOpts)
+	is.True(errors.Is(err, errOutputMismatch))
+	is.Equal(err.Error(), errOutputMismatch.Error()+"\n"+
+		"  - "+filepath.Join("a", "README.md")+": out of date\n"+
+		"  - "+filepath.Join("b", "README.md")+": missing")
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Reasons for an Output file not matching the generated documentation in
+// Check mode.
+const (
+	// MismatchOutOfDate indicates that the contents of the file differ from
+	// the generated documentation.
+	MismatchOutOfDate = "out of date"
+
+	// MismatchMissing indicates that the file doesn't exist.
+	MismatchMissing = "missing"
+
+	// MismatchNotGenerated indicates that the file is listed in the manifest
+	// but is no longer generated.
+	MismatchNotGenerated = "no longer generated"
+)
+
+var errOutputMismatch = errors.New("Output does not match current files. Did you forget to run gomarkdoc?")
+
+// Mismatch describes an Output file that doesn't match the generated
+// documentation in Check mode.
+type Mismatch struct {
+	// File holds the path of the Output file.
+	File string
+
+	// Reason holds one of MismatchOutOfDate, MismatchMissing or
+	// MismatchNotGenerated.
+	Reason string
+
+	// Expected holds the hash of the generated documentation for the file
+	// (see HashContents), or is empty if it is no longer generated.
+	Expected string
+
+	// Actual holds the hash of the current contents of the file, or the hash
+	// recorded in the manifest when checking against one. It is empty if the
+	// file is missing.
+	Actual string
+
+	// Added and Removed hold the number of lines that regenerating the file
+	// would add and remove. They are only counted when the file is compared
+	// directly rather than through a manifest.
+	Added   int
+	Removed int
+}
+
+// newMismatch describes the mismatch between the expected contents of the
+// file and its actual contents, which are ignored if the file is missing.
+func newMismatch(file, reason, expected string, actual []byte) *Mismatch {
+	m := &Mismatch{
+		File:     file,
+		Reason:   reason,
+		Expected: HashContents(expected),
+	}
+
+	var actualText string
+	if reason != MismatchMissing {
+		actualText = string(actual)
+		m.Actual = HashContents(actualText)
+	}
+
+	m.Added, m.Removed = lineDrift(actualText, expected)
+
+	return m
+}
+
+// lineDrift counts the lines that turning the old text into the new text
+// would add and remove, without regard to their order. Moved lines aren't
+// counted, which keeps this cheap for large files.
+func lineDrift(oldText, newText string) (added, removed int) {
+	counts := make(map[string]int)
+	for _, line := range splitLines(oldText) {
+		counts[line]++
+	}
+
+	for _, line := range splitLines(newText) {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+
+	for _, n := range counts {
+		removed += n
+	}
+
+	return added, removed
+}
+
+// Summary describes how the file drifted from the generated documentation.
+func (m *Mismatch) Summary() string {
+	switch {
+	case m.Reason == MismatchMissing:
+		return "the file doesn't exist"
+	case m.Reason == MismatchNotGenerated:
+		return "the file is listed in the manifest but is no longer generated"
+	case m.Added == 0 && m.Removed == 0:
+		return "the contents differ from the generated documentation"
+	default:
+		return fmt.Sprintf("regenerating adds %d line(s) and removes %d line(s)", m.Added, m.Removed)
+	}
+}
+
+// Error reports the mismatch of a single file. It matches errOutputMismatch
+// with errors.Is.
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("%s: %s", m.File, m.Reason)
+}
+
+// Is indicates that the mismatch is an instance of errOutputMismatch.
+func (m *Mismatch) Is(target error) bool {
+	return target == errOutputMismatch
+}
+
+// Mismatches collects the Output files that don't match the generated
+// documentation in Check mode, so that all of them can be reported at the end
+// of the run instead of stopping at the first.
+type Mismatches []*Mismatch
+
+// mismatchesError reports all of the mismatches from a run as a single error.
+type mismatchesError Mismatches
+
+// Add records the error if it is a mismatch, and indicates whether it was
+// recorded. Other errors are left for the caller to handle.
+func (m *Mismatches) Add(err error) bool {
+	var mismatch *Mismatch
+	if !errors.As(err, &mismatch) {
+		return false
+	}
+
+	*m = append(*m, mismatch)
+	return true
+}
+
+// Err provides an error listing all of the recorded mismatches sorted by file,
+// or nil if there were none. The error matches errOutputMismatch with
+// errors.Is.
+func (m Mismatches) Err() error {
+	if len(m) == 0 {
+		return nil
+	}
+
+	return mismatchesError(m.sorted())
+}
+
+// sorted provides a copy of the mismatches sorted by file.
+func (m Mismatches) sorted() Mismatches {
+	sorted := make(Mismatches, len(m))
+	copy(sorted, m)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].File < sorted[j].File
+	})
+
+	return sorted
+}
+
+func (e mismatchesError) Error() string {
+	var b strings.Builder
+	b.WriteString(errOutputMismatch.Error())
+	for _, m := range e {
+		fmt.Fprintf(&b, "\n  - %s", m)
+	}
+
+	return b.String()
+}
+
+func (e mismatchesError) Is(target error) bool {
+	return target == errOutputMismatch
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_checkAllMismatches(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "a", "a.go"), "// Package a is a package.\npackage a\n")
+	writeConfig(t, filepath.Join(dir, "b", "b.go"), "// Package b is a package.\npackage b\n")
+	writeConfig(t, filepath.Join(dir, "c", "c.go"), "// Package c is a package.\npackage c\n")
+	chdir(t, dir)
+
+	paths := []string{"./..."}
+	opts := CommandOptions{Output: "{{.Dir}}/README.md"}
+	is.NoErr(RunCommand(paths, opts))
+
+	writeConfig(t, filepath.Join("a", "README.md"), "edited\n")
+	is.NoErr(os.Remove(filepath.Join("c", "README.md")))
+
+	checkOpts := opts
+	checkOpts.Check = true
+	err := RunCommand(paths, checkOpts)
+	is.True(errors.Is(err, errOutputMismatch))
+	is.Equal(ExitCode(err), ExitCheckMismatch)
+	is.Equal(err.Error(), errOutputMismatch.Error()+"\n"+
+		"  - "+filepath.Join("a", "README.md")+": out of date\n"+
+		"  - "+filepath.Join("c", "README.md")+": missing")
+}
+
+func TestRunCommand_checkManifestMismatches(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "a", "a.go"), "// Package a is a package.\npackage a\n")
+	writeConfig(t, filepath.Join(dir, "b", "b.go"), "// Package b is a package.\npackage b\n")
+	chdir(t, dir)
+
+	paths := []string{"./..."}
+	opts := CommandOptions{Output: "{{.Dir}}/README.md", Manifest: "docs.sha256"}
+	is.NoErr(RunCommand(paths, opts))
+
+	writeConfig(t, filepath.Join("a", "a.go"), "// Package a is an edited package.\npackage a\n")
+	is.NoErr(os.Remove(filepath.Join("b", "b.go")))
+
+	checkOpts := opts
+	checkOpts.Check = true
+	err := RunCommand(paths, checkOpts)
+	is.True(errors.Is(err, errOutputMismatch))
+	is.Equal(err.Error(), errOutputMismatch.Error()+"\n"+
+		"  - "+filepath.Join("a", "README.md")+": out of date\n"+
+		"  - "+filepath.Join("b", "README.md")+": no longer generated")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ag5denis/gomarkdoc"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// FileRenderer renders the full contents of a single output file.
+type FileRenderer func(file *lang.File) (string, error)
+
+// ResolveFileRenderer determines how output files are rendered for the
+// configured Format. Markdown formats are rendered through templates, while
+// data formats such as yaml and docfx serialize the documentation model
+// directly. The extra options are applied to the template renderer after those
+// derived from opts.
+func ResolveFileRenderer(opts CommandOptions, extra ...gomarkdoc.RendererOption) (FileRenderer, error) {
+	switch opts.Format {
+	case "yaml":
+		return RenderYAML, nil
+	case "docfx":
+		return RenderDocFX, nil
+	}
+
+	out, err := resolveRenderer(opts, extra...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.File, nil
+}
+
+func resolveRenderer(opts CommandOptions, extra ...gomarkdoc.RendererOption) (*gomarkdoc.Renderer, error) {
+	overrides, err := ResolveOverrides(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return gomarkdoc.NewRenderer(append(overrides, extra...)...)
+}
+
+// RenderYAML renders the documentation model of each of the packages in the
+// file as a YAML list. Headers and footers are not included.
+func RenderYAML(file *lang.File) (string, error) {
+	models := make([]*lang.PackageModel, 0, len(file.Packages))
+	for _, pkg := range file.Packages {
+		m, err := pkg.Model()
+		if err != nil {
+			return "", fmt.Errorf("gomarkdoc: failed to build model for package %s: %w", pkg.ImportPath(), err)
+		}
+
+		models = append(models, m)
+	}
+
+	b, err := yaml.Marshal(models)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: failed to render yaml: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// IsDataFormat identifies formats that serialize the documentation model
+// rather than rendering markdown. Data formats can't be embedded into existing
+// files.
+func IsDataFormat(format string) bool {
+	return format == "yaml" || format == "docfx"
+}
@@ -2,12 +2,12 @@ package cmd
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 
 	"github.com/ag5denis/gomarkdoc"
 	"github.com/ag5denis/gomarkdoc/lang"
@@ -16,18 +16,172 @@ import (
 
 // WriteOutput writes the Output of the documentation to the specified files.
 func WriteOutput(specs []*PackageSpec, opts CommandOptions) error {
-	log := logger.New(GetLogLevel(opts.Verbosity))
+	manifest, err := openManifest(opts)
+	if err != nil {
+		return err
+	}
+
+	// Tracks the manifest entries that were verified in check mode
+	checked := make(Manifest)
+
+	var (
+		failures   Failures
+		mismatches Mismatches
+	)
+	if err := writeOutput(specs, specs, opts, manifest, checked, &failures, &mismatches, nil, nil); err != nil {
+		return err
+	}
+
+	if err := closeManifest(opts, manifest, checked, &mismatches); err != nil {
+		return err
+	}
+
+	if err := reportMismatches(opts, mismatches); err != nil {
+		return err
+	}
 
-	overrides, err := ResolveOverrides(opts)
+	return runErr(failures, mismatches)
+}
+
+// openManifest prepares the manifest for the run. In check mode, the existing
+// manifest is loaded for comparison. A nil Manifest is returned if no manifest
+// was requested.
+func openManifest(opts CommandOptions) (Manifest, error) {
+	if opts.Manifest == "" || opts.Preview {
+		return nil, nil
+	}
+
+	if opts.Check {
+		return ReadManifest(opts.Manifest)
+	}
+
+	// Only some of the files are written when running with --since, so the
+	// entries of the others are kept
+	if opts.Since != "" {
+		if _, err := os.Stat(opts.Manifest); err == nil {
+			return ReadManifest(opts.Manifest)
+		}
+	}
+
+	return make(Manifest), nil
+}
+
+// closeManifest completes the manifest handling for the run once all output
+// has been written or checked. In check mode, the files listed in the manifest
+// that are no longer generated are recorded in mismatches.
+func closeManifest(opts CommandOptions, manifest, checked Manifest, mismatches *Mismatches) error {
+	if manifest == nil {
+		return nil
+	}
+
+	if opts.Check {
+		// When only checking changed packages, the remaining entries belong
+		// to other packages
+		if opts.Since != "" {
+			return nil
+		}
+
+		// When sharding, only the entries assigned to this shard are expected
+		// to be generated by the run
+		shard, err := ParseShard(opts.Shard)
+		if err != nil {
+			return err
+		}
+
+		// Files that didn't match are already reported
+		reported := make(map[string]bool)
+		for _, m := range *mismatches {
+			if key, err := manifestKey(opts.Manifest, m.File); err == nil {
+				reported[key] = true
+			}
+		}
+
+		manifestDir := filepath.Dir(opts.Manifest)
+		for _, key := range sortedKeys(manifest) {
+			file := filepath.Join(manifestDir, filepath.FromSlash(key))
+			if _, ok := checked[key]; !ok && !reported[key] && shard.Contains(file) {
+				mismatches.Add(&Mismatch{
+					File:   file,
+					Reason: MismatchNotGenerated,
+					Actual: manifest[key],
+				})
+			}
+		}
+
+		return nil
+	}
+
+	if err := manifest.Write(opts.Manifest); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", opts.Manifest, err)
+	}
+
+	return nil
+}
+
+// writeOutput renders and writes each of the Output files for the specs.
+// Files that fail to render or write are recorded in failures when running
+// with KeepGoing. The paths of the files written are appended to written if it
+// is non-nil, and the files are recorded in the cache if it is non-nil. Links
+// between packages are resolved against all of the specs, which may include
+// specs that are written separately with other options.
+func writeOutput(all, specs []*PackageSpec, opts CommandOptions, manifest, checked Manifest, failures *Failures, mismatches *Mismatches, written *[]string, cache *Cache) error {
+	log := NewLogger(opts)
+
+	// Collects the files included by the templates of the file being
+	// rendered
+	var included []string
+
+	linkOpts := []gomarkdoc.RendererOption{
+		gomarkdoc.WithOutputFiles(OutputFiles(all)),
+		gomarkdoc.WithDegradationHandler(degradationLogger(opts)),
+		gomarkdoc.WithIncludeHandler(func(path string) {
+			included = append(included, path)
+		}),
+	}
+	if opts.CrossPackageLinks {
+		linkOpts = append(linkOpts, gomarkdoc.WithCrossPackageLinks(loadedPackages(all)...))
+	}
+
+	if opts.PackageTable {
+		linkOpts = append(linkOpts, gomarkdoc.WithPackageTable(loadedPackages(all)...))
+	}
+
+	if opts.Subpackages {
+		linkOpts = append(linkOpts, gomarkdoc.WithSubpackages(loadedPackages(all)...))
+	}
+
+	render, err := ResolveFileRenderer(opts, linkOpts...)
 	if err != nil {
 		return err
 	}
 
-	out, err := gomarkdoc.NewRenderer(overrides...)
+	renderExamples, err := ResolveExamplesRenderer(opts, linkOpts...)
 	if err != nil {
 		return err
 	}
 
+	// With split examples, each Output file links to its own examples file,
+	// so a renderer is resolved for each examples file name in use
+	fileRenderers := make(map[string]FileRenderer)
+	fileRenderer := func(fileName string) (FileRenderer, error) {
+		if renderExamples == nil || fileName == "" {
+			return render, nil
+		}
+
+		href := filepath.Base(ExamplesFilePath(fileName))
+		if r, ok := fileRenderers[href]; ok {
+			return r, nil
+		}
+
+		r, err := ResolveFileRenderer(opts, append([]gomarkdoc.RendererOption{gomarkdoc.WithExamplesFile(href)}, linkOpts...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		fileRenderers[href] = r
+		return r, nil
+	}
+
 	header, err := ResolveHeader(opts)
 	if err != nil {
 		return err
@@ -48,30 +202,238 @@ func WriteOutput(specs []*PackageSpec, opts CommandOptions) error {
 		filePkgs[spec.OutputFile] = append(filePkgs[spec.OutputFile], spec.Pkg)
 	}
 
-	for fileName, pkgs := range filePkgs {
+	// Renders and writes a single Output file, along with its examples file
+	// if examples are split
+	emit := func(fileName, text string) error {
+		if err := emitOutput(opts, manifest, checked, fileName, text); err != nil && !mismatches.Add(err) {
+			return err
+		}
+
+		if written != nil && fileName != "" && !opts.Check && !opts.Preview {
+			*written = append(*written, fileName)
+		}
+
+		if opts.Audit && !IsDataFormat(opts.Format) {
+			if issues := AuditAccessibility(text); len(issues) > 0 {
+				name := fileName
+				if name == "" {
+					name = "stdout"
+				}
+
+				return &auditError{name, issues}
+			}
+		}
+
+		return nil
+	}
+
+	writeFile := func(fileName string, pkgs []*lang.Package) error {
 		file := lang.NewFile(header, footer, pkgs)
+		included = nil
 
-		text, err := out.File(file)
+		render, err := fileRenderer(fileName)
 		if err != nil {
 			return err
 		}
 
-		if opts.Embed && fileName != "" {
+		text, err := render(file)
+		if err != nil {
+			return err
+		}
+
+		if opts.Embed && fileName != "" && !IsDataFormat(opts.Format) {
+			if opts.AnnotateChanges {
+				text = AnnotateSignatureChanges(PreviousEmbedded(fileName), text)
+			}
+
 			text = EmbedContents(log, fileName, text)
 		}
 
-		switch {
-		case fileName == "":
-			fmt.Fprint(os.Stdout, text)
-		case opts.Check:
-			var b bytes.Buffer
-			fmt.Fprint(&b, text)
-			if err := CheckFile(&b, fileName); err != nil {
+		if err := emit(fileName, text); err != nil {
+			return err
+		}
+
+		outputs := map[string]string{fileName: text}
+		if renderExamples != nil && fileName != "" && hasExamples(pkgs) {
+			examplesText, err := renderExamples(file)
+			if err != nil {
+				return err
+			}
+
+			examplesFile := ExamplesFilePath(fileName)
+			if err := emit(examplesFile, examplesText); err != nil {
+				return err
+			}
+
+			outputs[examplesFile] = examplesText
+		}
+
+		if cache == nil || fileName == "" {
+			return nil
+		}
+
+		return cache.Record(fileName, outputs, specs, included)
+	}
+
+	for fileName, pkgs := range filePkgs {
+		if err := writeFile(fileName, pkgs); err != nil {
+			if !opts.KeepGoing {
 				return err
 			}
-		default:
-			if err := WriteFile(fileName, text); err != nil {
-				return fmt.Errorf("failed to write Output file %s: %w", fileName, err)
+
+			log.Errorf("skipping Output file %s: %s", fileName, err)
+			failures.Add(fmt.Errorf("Output file %s: %w", fileName, err))
+		}
+	}
+
+	return nil
+}
+
+// reportMismatches writes the report of the mismatches to stdout in Check mode
+// if a structured CheckFormat is requested. Otherwise, the mismatches are
+// written as annotations if they are enabled.
+func reportMismatches(opts CommandOptions, mismatches Mismatches) error {
+	if !opts.Check {
+		return nil
+	}
+
+	if opts.CheckFormat != "" && opts.CheckFormat != CheckFormatText {
+		return WriteCheckReport(os.Stdout, opts.CheckFormat, mismatches)
+	}
+
+	if ResolveAnnotations(opts) == AnnotationsGitHub {
+		return writeCheckAnnotations(os.Stdout, mismatches)
+	}
+
+	return nil
+}
+
+// runErr provides the error reported at the end of a run for the failures and
+// mismatches collected during it. The mismatches are reported as one of the
+// failures if there are any, so that the exit code accounts for both.
+func runErr(failures Failures, mismatches Mismatches) error {
+	err := mismatches.Err()
+	if err == nil {
+		return failures.Err()
+	}
+
+	if len(failures) == 0 {
+		return err
+	}
+
+	failures.Add(err)
+	return failures.Err()
+}
+
+// degradationLogger logs a warning for each construct that the Format can't
+// represent, with the affected package and symbol attached as fields.
+func degradationLogger(opts CommandOptions) gomarkdoc.DegradationHandler {
+	return func(d gomarkdoc.Degradation) {
+		log := NewLogger(
+			opts,
+			logger.WithField("package", d.Package),
+			logger.WithField("symbol", d.Symbol),
+			logger.WithField("feature", d.Feature),
+			logger.WithField("fallback", d.Fallback),
+		)
+
+		log.Warnf("%s can't be fully represented in the %s Format", d.Construct, opts.Format)
+	}
+}
+
+// OutputFiles maps the import path of each loaded package to the Output file
+// that its documentation is written to. Packages written to stdout are
+// omitted.
+func OutputFiles(specs []*PackageSpec) map[string]string {
+	files := make(map[string]string)
+	for _, spec := range specs {
+		importPath := spec.importPath
+		if spec.Pkg != nil {
+			importPath = spec.Pkg.ImportPath()
+		}
+
+		if importPath == "" || spec.OutputFile == "" {
+			continue
+		}
+
+		files[importPath] = spec.OutputFile
+	}
+
+	return files
+}
+
+// loadedPackages lists the packages of the specs that loaded successfully.
+func loadedPackages(specs []*PackageSpec) []*lang.Package {
+	var pkgs []*lang.Package
+	for _, spec := range specs {
+		if spec.Pkg != nil {
+			pkgs = append(pkgs, spec.Pkg)
+		}
+	}
+
+	return pkgs
+}
+
+// emitOutput writes the text of a single Output file to its destination, or
+// checks it against the existing contents in Check mode.
+func emitOutput(opts CommandOptions, manifest, checked Manifest, fileName, text string) error {
+	switch {
+	case fileName == "":
+		fmt.Fprint(os.Stdout, text)
+	case opts.Preview:
+		diff, err := PreviewDiff(fileName, text)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(os.Stdout, diff)
+	case opts.Check && manifest != nil:
+		match, err := manifest.Check(opts.Manifest, fileName, text)
+		if err != nil {
+			return err
+		}
+
+		if !match {
+			return &Mismatch{
+				File:     fileName,
+				Reason:   MismatchOutOfDate,
+				Expected: HashContents(text),
+				Actual:   manifest.Hash(opts.Manifest, fileName),
+			}
+		}
+
+		if err := checked.Add(opts.Manifest, fileName, text); err != nil {
+			return err
+		}
+
+		// The file itself may have been edited or deleted since the manifest
+		// was written, so its hash must match as well
+		actual, err := os.ReadFile(fileName)
+		if os.IsNotExist(err) {
+			return newMismatch(fileName, MismatchMissing, text, nil)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to open file %s for checking: %w", fileName, err)
+		}
+
+		if HashContents(string(actual)) != HashContents(text) {
+			return newMismatch(fileName, MismatchOutOfDate, text, actual)
+		}
+	case opts.Check:
+		var b bytes.Buffer
+		fmt.Fprint(&b, text)
+		if err := CheckFile(&b, fileName); err != nil {
+			return err
+		}
+	default:
+		if err := writeOutputFile(fileName, text, opts); err != nil {
+			return fmt.Errorf("failed to write Output file %s: %w", fileName, err)
+		}
+
+		if manifest != nil {
+			if err := manifest.Add(opts.Manifest, fileName, text); err != nil {
+				return err
 			}
 		}
 	}
@@ -96,13 +458,111 @@ func WriteFile(fileName string, text string) error {
 	return nil
 }
 
+// WriteFileMode writes the specified text to the specified file in the same
+// way as WriteFile, but sets the permissions of the file and of the folders
+// created for it to the provided modes, regardless of the umask. A zero mode
+// leaves the respective permissions as WriteFile would.
+func WriteFileMode(fileName string, text string, fileMode, dirMode os.FileMode) error {
+	folder := filepath.Dir(fileName)
+
+	if folder != "" {
+		if err := mkdirMode(folder, dirMode); err != nil {
+			return fmt.Errorf("failed to create folder %s: %w", folder, err)
+		}
+	}
+
+	perm := fileMode
+	if perm == 0 {
+		perm = 0664
+	}
+
+	if err := ioutil.WriteFile(fileName, []byte(text), perm); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", fileName, err)
+	}
+
+	if fileMode != 0 {
+		if err := os.Chmod(fileName, fileMode); err != nil {
+			return fmt.Errorf("failed to set mode of file %s: %w", fileName, err)
+		}
+	}
+
+	return nil
+}
+
+// mkdirMode creates the folder along with any missing parents. If a mode is
+// provided, it is set on each of the folders that were created.
+func mkdirMode(folder string, mode os.FileMode) error {
+	if mode == 0 {
+		return os.MkdirAll(folder, 0755)
+	}
+
+	var missing []string
+	for dir := folder; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil || !os.IsNotExist(err) {
+			break
+		}
+
+		missing = append(missing, dir)
+
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	if err := os.MkdirAll(folder, mode); err != nil {
+		return err
+	}
+
+	for _, dir := range missing {
+		if err := os.Chmod(dir, mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeOutputFile writes an Output file with the file and directory modes of
+// the options.
+func writeOutputFile(fileName, text string, opts CommandOptions) error {
+	fileMode, err := ParseMode(opts.FileMode)
+	if err != nil {
+		return err
+	}
+
+	dirMode, err := ParseMode(opts.DirMode)
+	if err != nil {
+		return err
+	}
+
+	return WriteFileMode(fileName, text, fileMode, dirMode)
+}
+
+// ParseMode parses octal permission bits such as 0644, as accepted by the
+// --file-mode and --dir-mode flags. The empty string parses to a zero mode.
+func ParseMode(s string) (os.FileMode, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode == 0 || mode > 0777 {
+		return 0, fmt.Errorf("gomarkdoc: invalid mode %s: expected octal permission bits such as 0644", s)
+	}
+
+	return os.FileMode(mode), nil
+}
+
+// CheckFile compares the contents of the file at the path against the buffer.
+// If they differ or the file doesn't exist, a *Mismatch describing the file is
+// returned.
 func CheckFile(b *bytes.Buffer, path string) error {
-	checkErr := errors.New("Output does not match current files. Did you forget to run gomarkdoc?")
+	expected := b.String()
 
 	f, err := os.Open(path)
 	if err != nil {
-		if err == os.ErrNotExist {
-			return checkErr
+		if os.IsNotExist(err) {
+			return newMismatch(path, MismatchMissing, expected, nil)
 		}
 
 		return fmt.Errorf("failed to open file %s for checking: %w", path, err)
@@ -116,21 +576,26 @@ func CheckFile(b *bytes.Buffer, path string) error {
 	}
 
 	if !match {
-		return checkErr
+		actual, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failure while attempting to Check contents of %s: %w", path, err)
+		}
+
+		return newMismatch(path, MismatchOutOfDate, expected, actual)
 	}
 
 	return nil
 }
 
 var (
-	embedStandaloneRegex = regexp.MustCompile(`(?m:^ *)<!--\s*gomarkdoc:Embed\s*-->(?m:\s*?$)`)
+	embedStandaloneRegex = regexp.MustCompile(`(?m:^ *)<!--\s*gomarkdoc:embed\s*-->(?m:\s*?$)`)
 	embedStartRegex      = regexp.MustCompile(
-		`(?m:^ *)<!--\s*gomarkdoc:Embed:start\s*-->(?s:.*?)<!--\s*gomarkdoc:Embed:end\s*-->(?m:\s*?$)`,
+		`(?m:^ *)<!--\s*gomarkdoc:embed:start\s*-->(?s:.*?)<!--\s*gomarkdoc:embed:end\s*-->(?m:\s*?$)`,
 	)
 )
 
 func EmbedContents(log logger.Logger, fileName string, text string) string {
-	embedText := fmt.Sprintf("<!-- gomarkdoc:Embed:start -->\n\n%s\n\n<!-- gomarkdoc:Embed:end -->", text)
+	embedText := fmt.Sprintf("<!-- gomarkdoc:embed:start -->\n\n%s\n\n<!-- gomarkdoc:embed:end -->", text)
 
 	data, err := os.ReadFile(fileName)
 	if err != nil {
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_modes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits aren't supported on windows")
+	}
+
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "pkg", "pkg.go"), "// Package pkg is a package.\npackage pkg\n")
+
+	out := filepath.Join(dir, "docs", "api", "README.md")
+	paths := []string{filepath.Join(dir, "pkg")}
+	is.NoErr(RunCommand(paths, CommandOptions{Output: out, FileMode: "0600", DirMode: "0750"}))
+
+	info, err := os.Stat(out)
+	is.NoErr(err)
+	is.Equal(info.Mode().Perm(), os.FileMode(0600))
+
+	for _, d := range []string{filepath.Join(dir, "docs"), filepath.Join(dir, "docs", "api")} {
+		info, err := os.Stat(d)
+		is.NoErr(err)
+		is.Equal(info.Mode().Perm(), os.FileMode(0750))
+	}
+
+	// Existing files are updated to the configured mode
+	is.NoErr(RunCommand(paths, CommandOptions{Output: out, FileMode: "0644"}))
+
+	info, err = os.Stat(out)
+	is.NoErr(err)
+	is.Equal(info.Mode().Perm(), os.FileMode(0644))
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_outputDir(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "mod.go"), "// Package mod is a package.\npackage mod\n")
+	writeConfig(t, filepath.Join(dir, "sub", "sub.go"), "// Package sub is a package.\npackage sub\n\n// Func is a function.\nfunc Func() {}\n")
+
+	chdir(t, dir)
+
+	is.NoErr(RunCommand([]string{"./..."}, CommandOptions{OutputDir: "docs", Subpackages: true}))
+
+	b, err := os.ReadFile(filepath.Join("docs", "index.md"))
+	is.NoErr(err)
+	is.True(strings.Contains(string(b), "(<sub/index.md>)"))
+
+	_, err = os.Stat(filepath.Join("docs", "sub", "index.md"))
+	is.NoErr(err)
+}
+
+func TestPackageSpec_TreePath(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal((&PackageSpec{Dir: ".", ImportPath: "encoding/json"}).TreePath(), filepath.Join("encoding", "json"))
+	is.Equal((&PackageSpec{Dir: ".", IsLocal: true}).TreePath(), ".")
+	is.Equal((&PackageSpec{Dir: "./lang/function", IsLocal: true}).TreePath(), filepath.Join("lang", "function"))
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// metaPatterns lists the package patterns with special meaning to the go
+// tool, which match packages from the standard library or the build list.
+var metaPatterns = map[string]bool{
+	"all": true,
+	"std": true,
+	"cmd": true,
+}
+
+// IsPackagePattern indicates whether the path is a package pattern as
+// understood by the go tool, which matches multiple packages.
+func IsPackagePattern(path string) bool {
+	return strings.Contains(path, "...") || metaPatterns[path]
+}
+
+// MatchPattern reports whether the path is matched by a pattern containing
+// "..." wildcards, using the same rules as the go tool. A "..." matches any
+// string, including the empty string and strings containing slashes, and a
+// pattern ending in "/..." also matches the path without the suffix, so x/...
+// matches x. Both the pattern and the path use forward slashes.
+func MatchPattern(pattern, path string) bool {
+	return patternRegexp(pattern).MatchString(path)
+}
+
+func patternRegexp(pattern string) *regexp.Regexp {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.ReplaceAll(re, `\.\.\.`, `.*`)
+
+	if strings.HasSuffix(re, `/.*`) {
+		re = strings.TrimSuffix(re, `/.*`) + `(/.*)?`
+	}
+
+	return regexp.MustCompile(`^` + re + `$`)
+}
+
+// localPatternSpecs expands a pattern of local directories that contains a
+// "..." wildcard somewhere other than at the end. The directories beneath the
+// literal prefix of the pattern are matched against it, except for those
+// matching the skip patterns.
+func localPatternSpecs(pattern string, skipDirs []string, followSymlinks bool) []*PackageSpec {
+	i := strings.Index(pattern, "...")
+
+	root := pattern[:i]
+	if j := strings.LastIndex(root, string(os.PathSeparator)); j >= 0 {
+		root = root[:j+1]
+	} else {
+		root = "."
+	}
+
+	re := patternRegexp(filepath.ToSlash(filepath.Clean(pattern)))
+	return walkLocalSpecs(root, skipDirs, followSymlinks, func(dir string) bool {
+		return re.MatchString(filepath.ToSlash(filepath.Clean(dir)))
+	})
+}
+
+// importPatternSpecs expands a pattern of import paths using the go tool,
+// which resolves it against the standard library and the modules in the build
+// list. Packages in directories beneath the current working directory are
+// treated as local packages. If the pattern can't be expanded, it is kept as
+// is so that loading it reports the problem.
+func importPatternSpecs(pattern string) []*PackageSpec {
+	literal := []*PackageSpec{{
+		Dir:        ".",
+		ImportPath: pattern,
+		IsWildcard: false,
+		IsLocal:    false,
+	}}
+
+	pkgs, err := listPackages(filepath.ToSlash(pattern))
+	if err != nil || len(pkgs) == 0 {
+		return literal
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return literal
+	}
+
+	var specs []*PackageSpec
+	for _, pkg := range pkgs {
+		rel, err := filepath.Rel(wd, pkg.dir)
+		if pkg.dir == "" || err != nil || rel == ".." || strings.HasPrefix(rel, parentPathPrefix) {
+			specs = append(specs, &PackageSpec{
+				Dir:        ".",
+				ImportPath: pkg.importPath,
+				IsWildcard: true,
+				IsLocal:    false,
+			})
+			continue
+		}
+
+		dir := "."
+		if rel != "." {
+			dir = cwdPathPrefix + rel
+		}
+
+		specs = append(specs, &PackageSpec{
+			Dir:        dir,
+			ImportPath: dir,
+			IsWildcard: true,
+			IsLocal:    true,
+		})
+	}
+
+	return specs
+}
+
+// listedPackage holds a package matched by a pattern as listed by the go tool.
+type listedPackage struct {
+	importPath string
+	dir        string
+}
+
+// listPackages lists the packages matched by the pattern with the go tool.
+func listPackages(pattern string) ([]listedPackage, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}", pattern)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: go list failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var pkgs []listedPackage
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+
+		pkgs = append(pkgs, listedPackage{importPath: parts[0], dir: parts[1]})
+	}
+
+	return pkgs, nil
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"pkg/...", "pkg", true},
+		{"pkg/...", "pkg/sub/deep", true},
+		{"pkg/...", "pkgs", false},
+		{"pkg...", "pkgs", true},
+		{".../internal", "a/b/internal", true},
+		{".../internal", "a/internal/b", false},
+		{"a/.../b", "a/b", false},
+		{"a/.../b", "a/x/y/b", true},
+		{"net/...", "net/http", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.path, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(MatchPattern(test.pattern, test.path), test.match)
+		})
+	}
+}
+
+func TestGetSpecs_patterns(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"foo", "foobar", "other/foo", filepath.Join("other", "nested", "foo"), ".git"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string][]string{
+		"foo...":        {"foo", "foobar"},
+		".../foo":       {"other/foo", "other/nested/foo"},
+		"other/.../foo": {"other/nested/foo"},
+		"other/...":     {"other", "other/foo", "other/nested", "other/nested/foo"},
+	}
+
+	for pattern, expected := range tests {
+		t.Run(pattern, func(t *testing.T) {
+			is := is.New(t)
+
+			var dirs []string
+			for _, spec := range GetSpecs(filepath.Join(dir, pattern)) {
+				is.True(spec.IsLocal)
+				is.True(spec.IsWildcard)
+
+				rel, err := filepath.Rel(dir, spec.Dir)
+				is.NoErr(err)
+				dirs = append(dirs, filepath.ToSlash(rel))
+			}
+
+			is.Equal(dirs, expected)
+		})
+	}
+}
+
+func TestGetSpecs_importPattern(t *testing.T) {
+	is := is.New(t)
+
+	// Other tests leave GOFLAGS set to values the go tool doesn't accept
+	t.Setenv("GOFLAGS", "")
+
+	specs := GetSpecs("encoding/...")
+
+	var found bool
+	for _, spec := range specs {
+		is.True(!spec.IsLocal)
+		is.Equal(spec.Dir, ".")
+		if spec.ImportPath == "encoding/json" {
+			found = true
+		}
+	}
+
+	is.True(found)
+}
+
+func TestGetSpecsSkipping(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"pkg", "vendor/dep", ".hidden", "node_modules", "testdata", "mocks"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		skipDirs []string
+		expected []string
+	}{
+		"default": {
+			skipDirs: DefaultSkipDirs,
+			expected: []string{".", "mocks", "pkg"},
+		},
+		"custom": {
+			skipDirs: []string{"mock*", "vendor"},
+			expected: []string{".", ".hidden", "node_modules", "pkg", "testdata"},
+		},
+		"none": {
+			skipDirs: nil,
+			expected: []string{".", ".hidden", "mocks", "node_modules", "pkg", "testdata", "vendor", "vendor/dep"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			var dirs []string
+			for _, spec := range GetSpecsSkipping(test.skipDirs, filepath.Join(dir, "...")) {
+				rel, err := filepath.Rel(dir, spec.Dir)
+				is.NoErr(err)
+				dirs = append(dirs, filepath.ToSlash(rel))
+			}
+
+			is.Equal(dirs, test.expected)
+		})
+	}
+}
+
+func TestGetSpecsFor_symlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := t.TempDir()
+	for _, d := range []string{filepath.Join(dir, "pkg"), filepath.Join(target, "linked")} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for link, to := range map[string]string{
+		"outside": target,
+		"loop":    dir,
+		"pkg/up":  "..",
+	} {
+		if err := os.Symlink(to, filepath.Join(dir, filepath.FromSlash(link))); err != nil {
+			t.Skipf("symlinks are not supported: %v", err)
+		}
+	}
+
+	tests := map[string]struct {
+		follow   bool
+		expected []string
+	}{
+		"skipped": {
+			expected: []string{".", "pkg"},
+		},
+		"followed": {
+			follow:   true,
+			expected: []string{".", "outside", "pkg", "outside/linked"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			opts := CommandOptions{SkipDirs: DefaultSkipDirs, FollowSymlinks: test.follow}
+
+			var dirs []string
+			for _, spec := range GetSpecsFor(opts, filepath.Join(dir, "...")) {
+				rel, err := filepath.Rel(dir, spec.Dir)
+				is.NoErr(err)
+				dirs = append(dirs, filepath.ToSlash(rel))
+			}
+
+			is.Equal(dirs, test.expected)
+		})
+	}
+}
+
+func TestValidateSkipDirs(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateSkipDirs(CommandOptions{SkipDirs: DefaultSkipDirs}))
+	is.True(ValidateSkipDirs(CommandOptions{SkipDirs: []string{"[a-"}}) != nil)
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import "github.com/ag5denis/gomarkdoc/lang"
+
+// ParsePlatforms parses the platforms for which to document the packages, as
+// provided with the --platforms flag. See lang.ParsePlatform for the format of
+// each platform.
+func ParsePlatforms(platforms []string) ([]lang.Platform, error) {
+	var parsed []lang.Platform
+	for _, s := range platforms {
+		p, err := lang.ParsePlatform(s)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, p)
+	}
+
+	return parsed, nil
+}
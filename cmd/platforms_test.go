@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_platforms(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "plat", "plat.go"), "// Package plat is a package.\npackage plat\n\n// Common is everywhere.\nfunc Common() {}\n")
+	writeConfig(t, filepath.Join(dir, "plat", "plat_windows.go"), "package plat\n\n// Handle is only on windows.\nfunc Handle() {}\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Output:    "{{.Dir}}/README.md",
+		Platforms: []string{"linux/amd64", "windows/amd64"},
+	}
+	is.NoErr(RunCommand([]string{"./plat"}, opts))
+
+	b, err := os.ReadFile(filepath.Join("plat", "README.md"))
+	is.NoErr(err)
+
+	text := string(b)
+	is.True(strings.Contains(text, "func Common()"))
+	is.True(strings.Contains(text, "**Platforms:** windows/amd64\n\n```go\nfunc Handle()"))
+	is.True(!strings.Contains(text, "Build constraints"))
+	is.Equal(strings.Count(text, "**Platforms:**"), 1)
+}
+
+func TestParsePlatforms(t *testing.T) {
+	is := is.New(t)
+
+	platforms, err := ParsePlatforms([]string{"linux/amd64", "js/wasm:purego"})
+	is.NoErr(err)
+	is.Equal(len(platforms), 2)
+	is.Equal(platforms[1].String(), "js/wasm:purego")
+
+	_, err = ParsePlatforms([]string{"linux"})
+	is.Equal(err.Error(), `gomarkdoc: invalid platform "linux": expected GOOS/GOARCH`)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRunCommand_playgroundCheck(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/lib\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n")
+	writeConfig(t, filepath.Join(dir, "lib_test.go"), "package lib_test\n\nimport \"example.com/lib\"\n\nfunc ExampleHello() {\n\tlib.Hello()\n}\n")
+	chdir(t, dir)
+
+	var requests int
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return nil, errors.New("unexpected request")
+	})
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	// The examples are shared when writing the Output
+	opts := CommandOptions{Output: "README.md", Playground: true}
+	err := RunCommand([]string{"."}, opts)
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "unexpected request"))
+	is.Equal(requests, 1)
+
+	// But not when checking or previewing it
+	writeConfig(t, "README.md", "outdated\n")
+
+	checkOpts := opts
+	checkOpts.Check = true
+	err = RunCommand([]string{"."}, checkOpts)
+	is.True(errors.Is(err, errOutputMismatch))
+
+	previewOpts := opts
+	previewOpts.Preview = true
+	is.NoErr(RunCommand([]string{"."}, previewOpts))
+
+	is.Equal(requests, 1)
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.Equal(string(b), "outdated\n")
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+type (
+	// BuildOption customizes the command built by BuildCommand.
+	BuildOption func(b *commandBuilder)
+
+	// ResolveFunc loads the flags and configuration of the command into its
+	// options, runs the option hooks and validates the result. It provides
+	// the options along with the package paths to document, which default to
+	// the current directory.
+	ResolveFunc func(args []string) (CommandOptions, []string, error)
+
+	// SubcommandFunc builds a custom subcommand. The subcommand accepts the
+	// same flags as the root command, which its RunE can load by calling
+	// resolve before passing the options to RunCommand or using them
+	// directly.
+	SubcommandFunc func(resolve ResolveFunc) *cobra.Command
+
+	// OptionHook is run with the options of the command once they are loaded
+	// from the flags and configuration, before they are validated. It can
+	// adjust the options or reject them by returning an error.
+	OptionHook func(opts *CommandOptions) error
+
+	commandBuilder struct {
+		subcommands []SubcommandFunc
+		optionHooks []OptionHook
+	}
+)
+
+// WithSubcommand adds the subcommand built by the provided function to the
+// command.
+func WithSubcommand(build SubcommandFunc) BuildOption {
+	return func(b *commandBuilder) {
+		b.subcommands = append(b.subcommands, build)
+	}
+}
+
+// WithOptionHook adds a hook that is run with the options of the command and
+// each of its subcommands. Hooks are run in the order they are added.
+func WithOptionHook(hook OptionHook) BuildOption {
+	return func(b *commandBuilder) {
+		b.optionHooks = append(b.optionHooks, hook)
+	}
+}
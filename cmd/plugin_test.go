@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/spf13/cobra"
+)
+
+func TestBuildCommand_subcommand(t *testing.T) {
+	is := is.New(t)
+
+	var resolved CommandOptions
+	var paths []string
+	command := BuildCommand(
+		WithOptionHook(func(opts *CommandOptions) error {
+			opts.FieldTable = true
+			return nil
+		}),
+		WithSubcommand(func(resolve ResolveFunc) *cobra.Command {
+			return &cobra.Command{
+				Use: "publish [package ...]",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					var err error
+					resolved, paths, err = resolve(args)
+					return err
+				},
+			}
+		}),
+	)
+
+	command.SetArgs([]string{"publish", "--include-source", "./pkg"})
+	is.NoErr(command.Execute())
+
+	is.True(resolved.IncludeSource)
+	is.True(resolved.FieldTable)
+	is.Equal(paths, []string{"./pkg"})
+}
+
+func TestBuildCommand_optionHookError(t *testing.T) {
+	is := is.New(t)
+
+	errRejected := errors.New("rejected")
+	command := BuildCommand(WithOptionHook(func(opts *CommandOptions) error {
+		return errRejected
+	}))
+
+	command.SetArgs([]string{"./pkg"})
+	command.SilenceUsage = true
+	command.SilenceErrors = true
+
+	is.Equal(command.Execute(), errRejected)
+}
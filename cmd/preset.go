@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// extendsKey is the configuration key used to reference a preset bundle.
+const extendsKey = "Extends"
+
+// presetPathKeys lists the configuration keys whose values are file paths. When
+// a preset bundle provides these values, they are resolved relative to the
+// bundle's directory rather than the directory gomarkdoc is invoked from.
+var presetPathKeys = []string{"headerfile", "footerfile"}
+
+// presetPathMapKeys lists the configuration keys holding maps whose values are
+// file paths, which are resolved in the same way as presetPathKeys.
+var presetPathMapKeys = []string{"templatefile"}
+
+// presetConfigFile holds the configuration file of the preset bundle applied by
+// the last call to BuildConfig, if any.
+var presetConfigFile string
+
+// ApplyPreset looks for an "extends" key in the loaded configuration and, if
+// one is present, layers the configuration of the referenced preset bundle
+// beneath the local configuration. Values in the local configuration take
+// precedence over the values provided by the preset.
+//
+// The reference may be a local directory (e.g. ./docs/preset) or a versioned
+// module (e.g. github.com/org/docs-presets/v2@v2.1.0), in which case the module
+// is downloaded using the go tool. A major version alone, as in
+// github.com/org/docs-presets@v2, refers to the latest release of that major
+// version. The bundle is expected to contain a .gomarkdoc configuration file
+// at its root, which can't extend another preset.
+func ApplyPreset(v *viper.Viper) error {
+	if v.GetString(extendsKey) == "" {
+		return nil
+	}
+
+	// Read the local configuration again on its own so that it can be
+	// layered on top of the preset without picking up flag defaults.
+	local := make(map[string]interface{})
+	if f := v.ConfigFileUsed(); f != "" {
+		lv := viper.New()
+		lv.SetConfigFile(f)
+		if err := lv.ReadInConfig(); err != nil {
+			return fmt.Errorf("gomarkdoc: failed to read config file %s: %w", f, err)
+		}
+
+		local = lv.AllSettings()
+	}
+
+	return applyPreset(v, local)
+}
+
+// applyPreset layers the configuration of the preset bundle referenced by the
+// "extends" key beneath the local configuration settings.
+func applyPreset(v *viper.Viper, local map[string]interface{}) error {
+	ref := v.GetString(extendsKey)
+	if ref == "" {
+		return nil
+	}
+
+	dir, err := ResolvePresetDir(ref)
+	if err != nil {
+		return err
+	}
+
+	preset, file, err := loadPresetSettings(dir)
+	if err != nil {
+		return fmt.Errorf("gomarkdoc: failed to load preset %s: %w", ref, err)
+	}
+
+	presetConfigFile = file
+
+	if err := v.MergeConfigMap(preset); err != nil {
+		return fmt.Errorf("gomarkdoc: failed to apply preset %s: %w", ref, err)
+	}
+
+	if err := v.MergeConfigMap(local); err != nil {
+		return fmt.Errorf("gomarkdoc: failed to apply preset %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// ResolvePresetDir resolves a preset reference to the directory on the local
+// filesystem that contains the preset bundle. Local paths are returned as-is,
+// while module references are downloaded into the module cache.
+func ResolvePresetDir(ref string) (string, error) {
+	if IsLocalPath(filepath.FromSlash(ref)) {
+		return filepath.Abs(filepath.FromSlash(ref))
+	}
+
+	ref = presetModuleQuery(ref)
+
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("go", "mod", "download", "-json", ref)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	// The go tool reports most failures through the Error field of the JSON
+	// output, so we only fail immediately if there's nothing to parse.
+	runErr := c.Run()
+
+	var result struct {
+		Dir   string
+		Error string
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		if runErr != nil {
+			return "", fmt.Errorf("gomarkdoc: failed to download preset %s: %s", ref, strings.TrimSpace(stderr.String()))
+		}
+
+		return "", fmt.Errorf("gomarkdoc: failed to download preset %s: %w", ref, err)
+	}
+
+	if result.Error != "" {
+		return "", fmt.Errorf("gomarkdoc: failed to download preset %s: %s", ref, result.Error)
+	}
+
+	if result.Dir == "" {
+		return "", fmt.Errorf("gomarkdoc: failed to download preset %s", ref)
+	}
+
+	return result.Dir, nil
+}
+
+// majorVersionRegex matches a version query made up of a major version alone.
+var majorVersionRegex = regexp.MustCompile(`^v([0-9]+)$`)
+
+// presetModuleQuery provides the module query to download for a preset module
+// reference. References without a version refer to the latest release, and a
+// major version of 2 or more alone refers to the latest release of the module
+// path with the matching /vN suffix, as the go tool doesn't add the suffix
+// itself.
+func presetModuleQuery(ref string) string {
+	i := strings.LastIndex(ref, "@")
+	if i < 0 {
+		return fmt.Sprintf("%s@latest", ref)
+	}
+
+	modPath, query := ref[:i], ref[i+1:]
+	m := majorVersionRegex.FindStringSubmatch(query)
+	if m == nil {
+		return ref
+	}
+
+	// The major version of gopkg.in modules is part of their last element
+	if major, _ := strconv.Atoi(m[1]); major >= 2 && path.Base(modPath) != query && !strings.HasPrefix(modPath, "gopkg.in/") {
+		modPath = fmt.Sprintf("%s/%s", modPath, query)
+	}
+
+	return fmt.Sprintf("%s@%s", modPath, query)
+}
+
+func loadPresetSettings(dir string) (map[string]interface{}, string, error) {
+	pv := viper.New()
+	pv.AddConfigPath(dir)
+	pv.SetConfigName(configFilePrefix)
+
+	if err := pv.ReadInConfig(); err != nil {
+		return nil, "", err
+	}
+
+	settings := pv.AllSettings()
+
+	// Nested presets are not supported
+	if ref, ok := settings[strings.ToLower(extendsKey)]; ok {
+		return nil, "", fmt.Errorf("the preset extends %v, but presets can't extend other presets", ref)
+	}
+
+	resolveSettingsPaths(settings, dir)
+
+	return settings, pv.ConfigFileUsed(), nil
+}
+
+// resolveSettingsPaths resolves the file paths in the configuration settings
+// relative to the directory the settings were loaded from.
+func resolveSettingsPaths(settings map[string]interface{}, dir string) {
+	for _, key := range presetPathKeys {
+		if p, ok := settings[key].(string); ok && p != "" {
+			settings[key] = resolvePresetPath(dir, p)
+		}
+	}
+
+	for _, key := range presetPathMapKeys {
+		m, ok := settings[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for name, val := range m {
+			if p, ok := val.(string); ok && p != "" {
+				m[name] = resolvePresetPath(dir, p)
+			}
+		}
+	}
+}
+
+func resolvePresetPath(dir, p string) string {
+	if p == StdinSource || isURLSource(p) {
+		return p
+	}
+
+	p = filepath.FromSlash(p)
+	if filepath.IsAbs(p) {
+		return p
+	}
+
+	return filepath.Join(dir, p)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/spf13/cobra"
+)
+
+func TestPresetModuleQuery(t *testing.T) {
+	tests := map[string]string{
+		"github.com/org/docs-presets":            "github.com/org/docs-presets@latest",
+		"github.com/org/docs-presets@v1":         "github.com/org/docs-presets@v1",
+		"github.com/org/docs-presets@v2":         "github.com/org/docs-presets/v2@v2",
+		"github.com/org/docs-presets/v2@v2":      "github.com/org/docs-presets/v2@v2",
+		"github.com/org/docs-presets/v2@v2.1.0":  "github.com/org/docs-presets/v2@v2.1.0",
+		"github.com/org/docs-presets@v1.4.0":     "github.com/org/docs-presets@v1.4.0",
+		"github.com/org/docs-presets@main":       "github.com/org/docs-presets@main",
+		"gopkg.in/org/docs-presets.v2@v2":        "gopkg.in/org/docs-presets.v2@v2",
+		"github.com/org/docs-presets/v3@latest":  "github.com/org/docs-presets/v3@latest",
+		"github.com/org/docs-presets@v10":        "github.com/org/docs-presets/v10@v10",
+		"github.com/org/docs-presets@v2-preview": "github.com/org/docs-presets@v2-preview",
+	}
+
+	for ref, want := range tests {
+		t.Run(ref, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(presetModuleQuery(ref), want)
+		})
+	}
+}
+
+func TestResolvePresetDir_local(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	presetDir, err := ResolvePresetDir("./docs/preset")
+	is.NoErr(err)
+	is.Equal(presetDir, filepath.Join(dir, "docs", "preset"))
+}
+
+func TestBuildCommand_preset(t *testing.T) {
+	tests := map[string]struct {
+		preset string
+		opts   CommandOptions
+		err    string
+	}{
+		"local preset": {
+			preset: "format: plain\noutput: preset.md\nheaderFile: header.md\n",
+			opts:   CommandOptions{Formats: []string{"plain"}, Output: "local.md", HeaderFile: filepath.Join("preset", "header.md")},
+		},
+		"nested preset": {
+			preset: "extends: ../other\nformat: plain\n",
+			err:    "gomarkdoc: failed to load preset ./preset: the preset extends ../other, but presets can't extend other presets",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			dir := t.TempDir()
+			writeConfig(t, filepath.Join(dir, ".gomarkdoc.yml"), "root: true\nextends: ./preset\noutput: local.md\n")
+			writeConfig(t, filepath.Join(dir, "preset", ".gomarkdoc.yml"), test.preset)
+			chdir(t, dir)
+
+			var opts CommandOptions
+			command := BuildCommand(WithSubcommand(func(resolve ResolveFunc) *cobra.Command {
+				return &cobra.Command{
+					Use: "resolve [package ...]",
+					RunE: func(cmd *cobra.Command, args []string) error {
+						var err error
+						opts, _, err = resolve(args)
+						return err
+					},
+				}
+			}))
+
+			var out bytes.Buffer
+			command.SetOut(&out)
+			command.SetErr(&out)
+			command.SetArgs([]string{"resolve"})
+
+			err := command.Execute()
+			if test.err != "" {
+				is.True(err != nil)
+				is.Equal(err.Error(), test.err)
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(ResolveFormats(opts), test.opts.Formats)
+			is.Equal(opts.Output, test.opts.Output)
+			is.True(strings.HasSuffix(opts.HeaderFile, string(filepath.Separator)+test.opts.HeaderFile))
+
+			// The preset's configuration file is an input of the run
+			is.Equal(opts.configFiles, []string{
+				filepath.Join(dir, ".gomarkdoc.yml"),
+				filepath.Join(dir, "preset", ".gomarkdoc.yml"),
+			})
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/spf13/cobra"
+)
+
+func TestBuildCommand_quiet(t *testing.T) {
+	tests := map[string]struct {
+		args  []string
+		quiet bool
+		err   string
+	}{
+		"default": {
+			args: []string{"resolve", "./pkg"},
+		},
+		"quiet": {
+			args:  []string{"resolve", "-q", "./pkg"},
+			quiet: true,
+		},
+		"verbose": {
+			args: []string{"resolve", "--quiet", "-v", "./pkg"},
+			err:  "gomarkdoc: --quiet and --verbose cannot be used together",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			var resolved CommandOptions
+			command := BuildCommand(WithSubcommand(func(resolve ResolveFunc) *cobra.Command {
+				return &cobra.Command{
+					Use: "resolve [package ...]",
+					RunE: func(cmd *cobra.Command, args []string) error {
+						var err error
+						resolved, _, err = resolve(args)
+						return err
+					},
+				}
+			}))
+
+			var out bytes.Buffer
+			command.SetOut(&out)
+			command.SetErr(&out)
+			command.SetArgs(test.args)
+
+			err := command.Execute()
+			if test.err != "" {
+				is.Equal(err.Error(), test.err)
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(resolved.Quiet, test.quiet)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/ag5denis/gomarkdoc"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// sectionFlags lists the sections of the documentation that can be hidden,
+// each of which has a --no-<section> flag.
+var sectionFlags = []struct {
+	section string
+	usage   string
+}{
+	{gomarkdoc.SectionImport, "Leave out the import statement of each package."},
+	{gomarkdoc.SectionIndex, "Leave out the index of the symbols of each package."},
+	{gomarkdoc.SectionExamples, "Leave out the examples of packages, types and funcs."},
+	{gomarkdoc.SectionConstants, "Leave out the Constants section of each package."},
+	{gomarkdoc.SectionVariables, "Leave out the Variables section of each package."},
+	{gomarkdoc.SectionSourceLinks, "Leave out the links from symbols to their source code."},
+	{gomarkdoc.SectionNotes, "Leave out the Notes section of each package."},
+}
+
+// addSectionFlags defines the --no-<section> flags and binds them to their
+// configuration keys.
+func addSectionFlags(flags *pflag.FlagSet) {
+	for _, f := range sectionFlags {
+		name := "no-" + f.section
+		flags.Bool(name, false, f.usage)
+		_ = viper.BindPFlag(sectionKey(f.section), flags.Lookup(name))
+	}
+}
+
+// sectionKey provides the configuration key of the --no-<section> flag, such
+// as NoIndex for --no-index.
+func sectionKey(section string) string {
+	return configKeyName("No-" + section)
+}
+
+// resolveHiddenSections provides the sections hidden using the --no-<section>
+// flags or their configuration keys.
+func resolveHiddenSections() []string {
+	var hidden []string
+	for _, f := range sectionFlags {
+		if viper.GetBool(sectionKey(f.section)) {
+			hidden = append(hidden, f.section)
+		}
+	}
+
+	return hidden
+}
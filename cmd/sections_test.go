@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_hiddenSections(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), `// Package lib is a library.
+package lib
+
+// Answer is the answer.
+const Answer = 42
+
+// Greeting is the greeting.
+var Greeting = "hello"
+
+// Hello says hello.
+func Hello() {}
+`)
+	writeConfig(t, filepath.Join(dir, "lib_test.go"), `package lib
+
+func ExampleHello() {
+	Hello()
+}
+`)
+	chdir(t, dir)
+
+	opts := CommandOptions{Output: "README.md"}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+
+	text := string(b)
+	is.True(strings.Contains(text, `import "example.com/mod"`))
+	is.True(strings.Contains(text, "## Index"))
+	is.True(strings.Contains(text, "## Constants"))
+	is.True(strings.Contains(text, "## Variables"))
+	is.True(strings.Contains(text, "Example"))
+
+	opts.HiddenSections = []string{"import", "index", "examples", "constants", "variables"}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err = os.ReadFile("README.md")
+	is.NoErr(err)
+
+	text = string(b)
+	is.True(!strings.Contains(text, `import "example.com/mod"`))
+	is.True(!strings.Contains(text, "## Index"))
+	is.True(!strings.Contains(text, "## Constants"))
+	is.True(!strings.Contains(text, "## Variables"))
+	is.True(!strings.Contains(text, "Example"))
+	is.True(strings.Contains(text, "Hello says hello."))
+}
+
+func TestRunCommand_hiddenSourceLinks(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Output: "README.md",
+		Repository: lang.Repo{
+			Remote:        "https://github.com/example/mod",
+			DefaultBranch: "main",
+			PathFromRoot:  "/",
+		},
+	}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(strings.Contains(string(b), "https://github.com/example/mod/blob/main/lib.go#L5"))
+
+	opts.HiddenSections = []string{"source-links"}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err = os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(!strings.Contains(string(b), "https://github.com/example/mod/blob/"))
+	is.True(strings.Contains(string(b), "## func Hello"))
+}
+
+func TestResolveOverrides_invalidSection(t *testing.T) {
+	is := is.New(t)
+
+	_, err := ResolveOverrides(CommandOptions{HiddenSections: []string{"bogus"}})
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), `invalid section name "bogus"`))
+}
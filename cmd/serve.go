@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+type (
+	// PackageSummary identifies a package served by the query API.
+	PackageSummary struct {
+		Name       string `json:"name"`
+		ImportPath string `json:"importPath"`
+		Summary    string `json:"summary,omitempty"`
+	}
+
+	// SymbolResult holds the documentation of a single symbol served by the
+	// query API. Exactly one of Type, Func and Value is set, depending on the
+	// kind of the symbol. Methods are named Type.Method.
+	SymbolResult struct {
+		Package string           `json:"package"`
+		Kind    string           `json:"kind"`
+		Name    string           `json:"name"`
+		Summary string           `json:"summary,omitempty"`
+		Type    *lang.TypeModel  `json:"type,omitempty"`
+		Func    *lang.FuncModel  `json:"func,omitempty"`
+		Value   *lang.ValueModel `json:"value,omitempty"`
+	}
+
+	// queryIndex holds the documentation models of the served packages.
+	queryIndex struct {
+		packages []*lang.PackageModel
+		symbols  []*SymbolResult
+	}
+)
+
+// Serve loads the packages at the provided paths and serves their
+// documentation model over a JSON query API on the provided address until the
+// server fails. See NewQueryHandler for the endpoints that are served.
+func Serve(addr string, paths []string, opts CommandOptions) error {
+	log := NewLogger(opts)
+
+	specs := GetSpecsFor(opts, paths...)
+	if opts.ExcludeInternal {
+		specs = ExcludeInternalSpecs(specs)
+	}
+
+	var failures Failures
+	if err := loadPackages(specs, opts, &failures); err != nil {
+		return err
+	}
+
+	handler, err := NewQueryHandler(loadedPackages(specs))
+	if err != nil {
+		return err
+	}
+
+	log.Infof("serving documentation queries on %s", addr)
+
+	return http.ListenAndServe(addr, handler)
+}
+
+// NewQueryHandler builds an HTTP handler that answers queries about the
+// documentation of the provided packages with JSON responses:
+//
+//	GET /packages                            lists the packages
+//	GET /packages/<import path>              provides a package's full model
+//	GET /symbol?package=<import path>&name=  provides a single symbol
+//	GET /search?q=<text>                     finds symbols by name
+//
+// Searches match any symbol whose name contains the text, ignoring case.
+func NewQueryHandler(pkgs []*lang.Package) (http.Handler, error) {
+	idx, err := newQueryIndex(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/packages", idx.servePackages)
+	mux.HandleFunc("/packages/", idx.servePackage)
+	mux.HandleFunc("/symbol", idx.serveSymbol)
+	mux.HandleFunc("/search", idx.serveSearch)
+
+	return mux, nil
+}
+
+func newQueryIndex(pkgs []*lang.Package) (*queryIndex, error) {
+	var idx queryIndex
+	for _, pkg := range pkgs {
+		m, err := pkg.Model()
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to build model for package %s: %w", pkg.ImportPath(), err)
+		}
+
+		idx.packages = append(idx.packages, m)
+		idx.addValues(m.ImportPath, "const", m.Consts)
+		idx.addValues(m.ImportPath, "var", m.Vars)
+		idx.addFuncs(m.ImportPath, m.Funcs)
+
+		for _, typ := range m.Types {
+			idx.symbols = append(idx.symbols, &SymbolResult{
+				Package: m.ImportPath,
+				Kind:    string(lang.TypeSymbol),
+				Name:    typ.Name,
+				Summary: typ.Summary,
+				Type:    typ,
+			})
+
+			idx.addValues(m.ImportPath, "const", typ.Consts)
+			idx.addValues(m.ImportPath, "var", typ.Vars)
+			idx.addFuncs(m.ImportPath, typ.Funcs)
+			idx.addFuncs(m.ImportPath, typ.Methods)
+		}
+	}
+
+	sort.SliceStable(idx.packages, func(i, j int) bool {
+		return idx.packages[i].ImportPath < idx.packages[j].ImportPath
+	})
+
+	return &idx, nil
+}
+
+func (idx *queryIndex) addValues(importPath, kind string, values []*lang.ValueModel) {
+	for _, v := range values {
+		for _, name := range v.Names {
+			idx.symbols = append(idx.symbols, &SymbolResult{
+				Package: importPath,
+				Kind:    kind,
+				Name:    name,
+				Summary: v.Summary,
+				Value:   v,
+			})
+		}
+	}
+}
+
+func (idx *queryIndex) addFuncs(importPath string, funcs []*lang.FuncModel) {
+	for _, fn := range funcs {
+		kind, name := string(lang.FuncSymbol), fn.Name
+		if fn.Receiver != "" {
+			kind = string(lang.MethodSymbol)
+			recv := strings.TrimPrefix(strings.Split(fn.Receiver, "[")[0], "*")
+			name = fmt.Sprintf("%s.%s", recv, fn.Name)
+		}
+
+		idx.symbols = append(idx.symbols, &SymbolResult{
+			Package: importPath,
+			Kind:    kind,
+			Name:    name,
+			Summary: fn.Summary,
+			Func:    fn,
+		})
+	}
+}
+
+func (idx *queryIndex) servePackages(w http.ResponseWriter, r *http.Request) {
+	summaries := make([]PackageSummary, 0, len(idx.packages))
+	for _, pkg := range idx.packages {
+		summaries = append(summaries, PackageSummary{
+			Name:       pkg.Name,
+			ImportPath: pkg.ImportPath,
+			Summary:    pkg.Summary,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (idx *queryIndex) servePackage(w http.ResponseWriter, r *http.Request) {
+	importPath := strings.TrimPrefix(r.URL.Path, "/packages/")
+	for _, pkg := range idx.packages {
+		if pkg.ImportPath == importPath {
+			writeJSON(w, http.StatusOK, pkg)
+			return
+		}
+	}
+
+	writeJSONError(w, http.StatusNotFound, fmt.Sprintf("package %s not found", importPath))
+}
+
+func (idx *queryIndex) serveSymbol(w http.ResponseWriter, r *http.Request) {
+	importPath := r.URL.Query().Get("package")
+	name := r.URL.Query().Get("name")
+	if importPath == "" || name == "" {
+		writeJSONError(w, http.StatusBadRequest, "the package and name query parameters are required")
+		return
+	}
+
+	for _, s := range idx.symbols {
+		if s.Package == importPath && s.Name == name {
+			writeJSON(w, http.StatusOK, s)
+			return
+		}
+	}
+
+	writeJSONError(w, http.StatusNotFound, fmt.Sprintf("symbol %s not found in package %s", name, importPath))
+}
+
+func (idx *queryIndex) serveSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.ToLower(r.URL.Query().Get("q"))
+	if q == "" {
+		writeJSONError(w, http.StatusBadRequest, "the q query parameter is required")
+		return
+	}
+
+	results := make([]*SymbolResult, 0)
+	for _, s := range idx.symbols {
+		if strings.Contains(strings.ToLower(s.Name), q) {
+			results = append(results, s)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
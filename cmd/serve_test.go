@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"go/build"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/ag5denis/gomarkdoc/logger"
+)
+
+func TestQueryHandler(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := build.ImportDir("../testData/lang/function", build.ImportComment)
+	is.NoErr(err)
+
+	pkg, err := lang.NewPackageFromBuild(logger.New(logger.ErrorLevel), buildPkg)
+	is.NoErr(err)
+
+	handler, err := NewQueryHandler([]*lang.Package{pkg})
+	is.NoErr(err)
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	get := func(path string, v interface{}) int {
+		res, err := http.Get(srv.URL + path)
+		is.NoErr(err)
+		defer res.Body.Close()
+
+		is.NoErr(json.NewDecoder(res.Body).Decode(v))
+		return res.StatusCode
+	}
+
+	var pkgs []PackageSummary
+	is.Equal(get("/packages", &pkgs), http.StatusOK)
+	is.Equal(len(pkgs), 1)
+	is.Equal(pkgs[0].Name, "function")
+
+	var model lang.PackageModel
+	is.Equal(get("/packages/"+pkgs[0].ImportPath, &model), http.StatusOK)
+	is.Equal(model.ImportPath, pkgs[0].ImportPath)
+
+	var method SymbolResult
+	is.Equal(get("/symbol?package="+pkgs[0].ImportPath+"&name=Base.Describe", &method), http.StatusOK)
+	is.Equal(method.Kind, "method")
+	is.Equal(method.Func.Name, "Describe")
+
+	var results []*SymbolResult
+	is.Equal(get("/search?q=weekday", &results), http.StatusOK)
+	is.True(len(results) > 0)
+	is.Equal(results[0].Name, "Weekday")
+
+	var missing map[string]string
+	is.Equal(get("/symbol?package="+pkgs[0].ImportPath+"&name=Missing", &missing), http.StatusNotFound)
+	is.True(missing["error"] != "")
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Shard identifies a single partition of the package specs for a run, which
+// allows documentation generation for a large repository to be split across
+// multiple parallel jobs.
+type Shard struct {
+	// Index holds the one-based index of this shard.
+	Index int
+
+	// Total holds the total number of shards.
+	Total int
+}
+
+// ParseShard parses a shard specifier of the form N/M, where N is the
+// one-based index of the shard and M is the total number of shards. The empty
+// string results in a nil Shard, indicating that no sharding should occur.
+func ParseShard(s string) (*Shard, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("gomarkdoc: invalid shard %s: expected the form N/M", s)
+	}
+
+	index, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: invalid shard %s: %w", s, err)
+	}
+
+	total, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: invalid shard %s: %w", s, err)
+	}
+
+	if total < 1 || index < 1 || index > total {
+		return nil, fmt.Errorf("gomarkdoc: invalid shard %s: index must be between 1 and %d", s, total)
+	}
+
+	return &Shard{Index: index, Total: total}, nil
+}
+
+// Filter returns the subset of the provided specs that belong to the shard.
+// Specs are assigned to shards by hashing the output file they will be
+// written to, so that packages sharing an output file are always processed by
+// the same shard. Specs without an output file are assigned using their import
+// path instead. The assignment is deterministic and remains stable for
+// existing packages as new packages are added.
+func (s *Shard) Filter(specs []*PackageSpec) []*PackageSpec {
+	if s == nil || s.Total == 1 {
+		return specs
+	}
+
+	var filtered []*PackageSpec
+	for _, spec := range specs {
+		key := spec.OutputFile
+		if key == "" {
+			key = spec.ImportPath
+		}
+
+		if s.Contains(key) {
+			filtered = append(filtered, spec)
+		}
+	}
+
+	return filtered
+}
+
+// Contains determines whether the output file with the provided path, or the
+// import path of a package without an output file, is assigned to the shard.
+func (s *Shard) Contains(key string) bool {
+	if s == nil || s.Total == 1 {
+		return true
+	}
+
+	// Normalize the separators so shards match across platforms
+	key = filepath.ToSlash(key)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32()%uint32(s.Total)) == s.Index-1
+}
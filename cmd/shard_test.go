@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseShard(t *testing.T) {
+	tests := map[string]struct {
+		shard *Shard
+		err   string
+	}{
+		"":      {},
+		"1/3":   {shard: &Shard{Index: 1, Total: 3}},
+		" 2/ 2": {shard: &Shard{Index: 2, Total: 2}},
+		"3":     {err: "gomarkdoc: invalid shard 3: expected the form N/M"},
+		"a/3":   {err: `gomarkdoc: invalid shard a/3: strconv.Atoi: parsing "a": invalid syntax`},
+		"0/3":   {err: "gomarkdoc: invalid shard 0/3: index must be between 1 and 3"},
+		"4/3":   {err: "gomarkdoc: invalid shard 4/3: index must be between 1 and 3"},
+	}
+
+	for s, test := range tests {
+		t.Run(s, func(t *testing.T) {
+			is := is.New(t)
+
+			shard, err := ParseShard(s)
+			if test.err != "" {
+				is.Equal(err.Error(), test.err)
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(shard, test.shard)
+		})
+	}
+}
+
+func TestShard_Filter(t *testing.T) {
+	is := is.New(t)
+
+	var specs []*PackageSpec
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		specs = append(specs, &PackageSpec{ImportPath: "example.com/mod/" + name, OutputFile: filepath.Join(name, "README.md")})
+	}
+
+	// Packages that share an Output file are always in the same shard
+	specs = append(specs, &PackageSpec{ImportPath: "example.com/mod/a/sub", OutputFile: filepath.Join("a", "README.md")})
+
+	shardOf := make(map[*PackageSpec]int)
+	for i := 1; i <= 3; i++ {
+		shard := &Shard{Index: i, Total: 3}
+		for _, spec := range shard.Filter(specs) {
+			_, dup := shardOf[spec]
+			is.True(!dup) // each spec is in a single shard
+			is.True(shard.Contains(spec.OutputFile))
+			shardOf[spec] = i
+		}
+	}
+
+	is.Equal(len(shardOf), len(specs))
+	is.Equal(shardOf[specs[0]], shardOf[specs[len(specs)-1]])
+	is.Equal((&Shard{Index: 1, Total: 1}).Filter(specs), specs)
+}
+
+func TestRunCommand_shardManifest(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	for _, name := range names {
+		writeConfig(t, filepath.Join(dir, name, name+".go"), fmt.Sprintf("// Package %s is a package.\npackage %s\n", name, name))
+	}
+
+	chdir(t, dir)
+
+	// Find a package in each of the shards
+	shards := []*Shard{{Index: 1, Total: 2}, {Index: 2, Total: 2}}
+	var own, other string
+	for _, name := range names {
+		if shards[0].Contains(filepath.Join(name, "README.md")) {
+			own = name
+		} else {
+			other = name
+		}
+	}
+
+	is.True(own != "" && other != "")
+
+	paths := []string{"./..."}
+	opts := CommandOptions{Output: "{{.Dir}}/README.md", Manifest: "docs.sha256"}
+	is.NoErr(RunCommand(paths, opts))
+
+	// Entries for the packages of other shards aren't stale
+	checkOpts := opts
+	checkOpts.Check = true
+	checkOpts.Shard = "1/2"
+	is.NoErr(RunCommand(paths, checkOpts))
+
+	is.NoErr(os.Remove(filepath.Join(other, other+".go")))
+	is.NoErr(RunCommand(paths, checkOpts))
+
+	// Entries for the packages of this shard are
+	is.NoErr(os.Remove(filepath.Join(own, own+".go")))
+
+	err := RunCommand(paths, checkOpts)
+	is.True(errors.Is(err, errOutputMismatch))
+	is.Equal(err.Error(), errOutputMismatch.Error()+"\n"+
+		"  - "+filepath.Join(own, "README.md")+": no longer generated")
+
+	checkOpts.Shard = "2/2"
+	err = RunCommand(paths, checkOpts)
+	is.True(errors.Is(err, errOutputMismatch))
+	is.True(strings.Contains(err.Error(), filepath.Join(other, "README.md")+": no longer generated"))
+	is.True(!strings.Contains(err.Error(), filepath.Join(own, "README.md")))
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// SignatureChangedNote is inserted beneath the header of each symbol whose
+// signature differs from the previously embedded documentation when running
+// with AnnotateChanges.
+const SignatureChangedNote = "*Signature changed in this update.*"
+
+var (
+	headerLineRegex   = regexp.MustCompile(`^#{1,6} +(.+)$`)
+	headerLinkRegex   = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	headerEscapeRegex = regexp.MustCompile(`\\(.)`)
+)
+
+// PreviousEmbedded provides the documentation previously embedded within the
+// provided file, or the empty string if the file doesn't exist or has no
+// embedded documentation.
+func PreviousEmbedded(fileName string) string {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return ""
+	}
+
+	return strings.Join(embedStartRegex.FindAllString(string(data), -1), "\n")
+}
+
+// AnnotateSignatureChanges adds the SignatureChangedNote beneath the header of
+// each symbol in the current documentation whose declaration differs from the
+// declaration under the same header in the previous documentation. Symbols
+// that don't appear in the previous documentation are left unannotated.
+func AnnotateSignatureChanges(previous, current string) string {
+	prevSigs := signatures(previous)
+	if len(prevSigs) == 0 {
+		return current
+	}
+
+	currSigs := signatures(current)
+
+	var (
+		b       strings.Builder
+		inFence bool
+	)
+
+	for i, line := range strings.Split(current, "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString(line)
+
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+		}
+
+		key, ok := headerKey(line)
+		if inFence || !ok {
+			continue
+		}
+
+		prev, ok := prevSigs[key]
+		if ok && prev != currSigs[key] {
+			b.WriteString("\n\n")
+			b.WriteString(SignatureChangedNote)
+		}
+	}
+
+	return b.String()
+}
+
+// signatures maps the plain text of each header in the documentation to the
+// declaration in the first go code block beneath it.
+func signatures(text string) map[string]string {
+	sigs := make(map[string]string)
+
+	var (
+		key     string
+		inFence bool
+		capture bool
+		decl    []string
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "```") && !inFence:
+			inFence = true
+			capture = key != "" && strings.HasPrefix(line, "```go")
+		case strings.HasPrefix(line, "```"):
+			inFence = false
+			if capture {
+				sigs[key] = strings.Join(decl, "\n")
+				key, capture, decl = "", false, nil
+			}
+		case inFence:
+			if capture {
+				decl = append(decl, line)
+			}
+		default:
+			if k, ok := headerKey(line); ok {
+				key = k
+			}
+		}
+	}
+
+	return sigs
+}
+
+// headerKey provides the plain text of the provided line if it is a header,
+// with links and escaping removed so that it is unaffected by changes to
+// source locations.
+func headerKey(line string) (string, bool) {
+	m := headerLineRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+
+	key := headerLinkRegex.ReplaceAllString(m[1], "$1")
+	key = headerEscapeRegex.ReplaceAllString(key, "$1")
+
+	return strings.TrimSpace(key), true
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestAnnotateSignatureChanges(t *testing.T) {
+	is := is.New(t)
+
+	previous := "## func [Unchanged](<https://example.com/a.go#L10>)\n\n```go\nfunc Unchanged()\n```\n\n" +
+		"## func [Changed](<https://example.com/a.go#L20>)\n\n```go\nfunc Changed(a int)\n```\n"
+
+	current := "## func [Unchanged](<https://example.com/a.go#L12>)\n\n```go\nfunc Unchanged()\n```\n\n" +
+		"## func [Changed](<https://example.com/a.go#L22>)\n\n```go\nfunc Changed(a, b int)\n```\n\n" +
+		"## func [Added](<https://example.com/a.go#L30>)\n\n```go\nfunc Added()\n```\n"
+
+	is.Equal(AnnotateSignatureChanges(previous, current),
+		"## func [Unchanged](<https://example.com/a.go#L12>)\n\n```go\nfunc Unchanged()\n```\n\n"+
+			"## func [Changed](<https://example.com/a.go#L22>)\n\n"+SignatureChangedNote+"\n\n```go\nfunc Changed(a, b int)\n```\n\n"+
+			"## func [Added](<https://example.com/a.go#L30>)\n\n```go\nfunc Added()\n```\n")
+}
+
+func TestAnnotateSignatureChanges_noPrevious(t *testing.T) {
+	is := is.New(t)
+
+	current := "## func Added\n\n```go\nfunc Added()\n```\n"
+	is.Equal(AnnotateSignatureChanges("", current), current)
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// ChangedFiles lists the files that changed since the provided git revision,
+// much like git diff --name-only. Changes committed since the revision are
+// included along with staged, unstaged and untracked changes in the working
+// tree. The paths are absolute and sorted.
+func ChangedFiles(repo *git.Repository, rev string) ([]string, error) {
+	from, err := revisionTree(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := revisionTree(repo, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: failed to compare %s with HEAD: %w", rev, err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: couldn't open git worktree: %w", err)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: couldn't get git worktree status: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for _, change := range changes {
+		// One of the names is empty for added and deleted files
+		if change.From.Name != "" {
+			names[change.From.Name] = true
+		}
+
+		if change.To.Name != "" {
+			names[change.To.Name] = true
+		}
+	}
+
+	for name, s := range status {
+		if s.Staging != git.Unmodified || s.Worktree != git.Unmodified {
+			names[name] = true
+		}
+	}
+
+	root := wt.Filesystem.Root()
+	files := make([]string, 0, len(names))
+	for name := range names {
+		files = append(files, filepath.Join(root, filepath.FromSlash(name)))
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// FilterChangedSpecs keeps the specs of the local packages whose directories
+// contain one of the changed files. If deps is true, the specs of packages
+// that import one of the changed packages are also kept, whether directly or
+// through other packages in the specs.
+func FilterChangedSpecs(specs []*PackageSpec, files []string, deps bool, tags []string) []*PackageSpec {
+	changedDirs := make(map[string]bool)
+	for _, f := range files {
+		changedDirs[filepath.Dir(f)] = true
+	}
+
+	changed := make(map[*PackageSpec]bool)
+	dirs := make(map[*PackageSpec]string)
+	for _, spec := range specs {
+		if !spec.IsLocal {
+			continue
+		}
+
+		dir, err := filepath.Abs(spec.Dir)
+		if err != nil {
+			continue
+		}
+
+		dirs[spec] = dir
+		changed[spec] = changedDirs[dir]
+	}
+
+	if deps {
+		changedImports := make(map[string]bool)
+		for dir := range changedDirs {
+			if importPath, ok := lang.FindImportPath(dir); ok {
+				changedImports[importPath] = true
+			}
+		}
+
+		imports := make(map[*PackageSpec][]string)
+		for spec := range dirs {
+			if changed[spec] {
+				continue
+			}
+
+			if pkg, err := lang.ListPackage(spec.ImportPath, tags...); err == nil {
+				for importPath := range pkg.Imports {
+					imports[spec] = append(imports[spec], importPath)
+				}
+			}
+		}
+
+		// Keep marking importers of changed packages until there are no more
+		for found := true; found; {
+			found = false
+			for spec, pkgImports := range imports {
+				if changed[spec] || !importsAny(pkgImports, changedImports) {
+					continue
+				}
+
+				changed[spec] = true
+				found = true
+
+				if importPath, ok := lang.FindImportPath(dirs[spec]); ok {
+					changedImports[importPath] = true
+				}
+			}
+		}
+	}
+
+	var filtered []*PackageSpec
+	for _, spec := range specs {
+		if changed[spec] {
+			filtered = append(filtered, spec)
+		}
+	}
+
+	return filtered
+}
+
+func importsAny(imports []string, importPaths map[string]bool) bool {
+	for _, imp := range imports {
+		if importPaths[imp] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterSince keeps the specs of the packages that changed since the revision
+// in the Since option.
+func filterSince(specs []*PackageSpec, opts CommandOptions) ([]*PackageSpec, error) {
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: couldn't open git repository: %w", err)
+	}
+
+	files, err := ChangedFiles(repo, opts.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := FilterChangedSpecs(specs, files, opts.SinceDeps, opts.Tags)
+	NewLogger(opts).Debugf("%d of %d packages changed since %s", len(filtered), len(specs), opts.Since)
+
+	return filtered, nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/matryer/is"
+)
+
+func TestFilterChangedSpecs(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "base", "base.go"), "package base\n")
+	writeConfig(t, filepath.Join(dir, "mid", "mid.go"), "package mid\n\nimport _ \"example.com/mod/base\"\n")
+	writeConfig(t, filepath.Join(dir, "top", "top.go"), "package top\n\nimport _ \"example.com/mod/mid\"\n")
+	writeConfig(t, filepath.Join(dir, "other", "other.go"), "package other\n")
+
+	var specs []*PackageSpec
+	for _, name := range []string{"base", "mid", "top", "other"} {
+		specs = append(specs, &PackageSpec{
+			Dir:        filepath.Join(dir, name),
+			ImportPath: filepath.Join(dir, name),
+			IsLocal:    true,
+		})
+	}
+
+	specs = append(specs, &PackageSpec{Dir: ".", ImportPath: "encoding/json"})
+
+	files := []string{filepath.Join(dir, "base", "base.go")}
+
+	tests := map[string]struct {
+		deps     bool
+		expected []*PackageSpec
+	}{
+		"changed": {
+			deps:     false,
+			expected: specs[:1],
+		},
+		"deps": {
+			deps:     true,
+			expected: specs[:3],
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(FilterChangedSpecs(specs, files, test.deps, nil), test.expected)
+		})
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	is.NoErr(err)
+
+	wt, err := repo.Worktree()
+	is.NoErr(err)
+
+	commit := func(name, content string) {
+		writeConfig(t, filepath.Join(dir, name), content)
+		_, err := wt.Add(name)
+		is.NoErr(err)
+
+		_, err = wt.Commit("update", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		is.NoErr(err)
+	}
+
+	commit("a/a.go", "package a\n")
+	commit("b/b.go", "package b\n")
+	commit("c/c.go", "package c\n")
+
+	// Uncommitted and untracked changes are included too
+	writeConfig(t, filepath.Join(dir, "c", "c.go"), "package c\n\nfunc C() {}\n")
+	writeConfig(t, filepath.Join(dir, "d", "d.go"), "package d\n")
+
+	files, err := ChangedFiles(repo, "HEAD~1")
+	is.NoErr(err)
+	is.Equal(files, []string{
+		filepath.Join(dir, "c", "c.go"),
+		filepath.Join(dir, "d", "d.go"),
+	})
+
+	files, err = ChangedFiles(repo, "HEAD~2")
+	is.NoErr(err)
+	is.Equal(files, []string{
+		filepath.Join(dir, "b", "b.go"),
+		filepath.Join(dir, "c", "c.go"),
+		filepath.Join(dir, "d", "d.go"),
+	})
+
+	_, err = ChangedFiles(repo, "missing")
+	is.True(err != nil)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_singleFile(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "a", "a.go"), "// Package a is a package.\npackage a\n\n// New creates a value. See [New].\nfunc New() {}\n")
+	writeConfig(t, filepath.Join(dir, "b", "b.go"), "// Package b is a package.\npackage b\n\n// New creates a value. See [New].\nfunc New() {}\n")
+
+	out := filepath.Join(dir, "docs", "API.md")
+	paths := []string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}
+	is.NoErr(RunCommand(paths, CommandOptions{SingleFile: out}))
+
+	b, err := os.ReadFile(out)
+	is.NoErr(err)
+	text := string(b)
+
+	// The table of contents links to the section of each package
+	is.True(strings.Contains(text, "- [example.com/mod/a](<#a>)\n- [example.com/mod/b](<#b>)\n"))
+
+	// Links in the second package point to its own headers
+	is.Equal(strings.Count(text, "See [New](<#func-new>)."), 1)
+	is.Equal(strings.Count(text, "See [New](<#func-new-1>)."), 1)
+	is.True(strings.Contains(text, "- [func New()](<#func-new-1>)"))
+}
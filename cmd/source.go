@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StdinSource is the file name that reads a header, footer or template from
+// stdin instead of a file.
+const StdinSource = "-"
+
+// sourceTimeout bounds the time taken to download a source from a URL.
+const sourceTimeout = 30 * time.Second
+
+var (
+	stdinOnce sync.Once
+	stdinData []byte
+	stdinErr  error
+)
+
+// ReadSource reads the contents of a header, footer or template file. The
+// StdinSource name reads from stdin, which is only read once and shared by all
+// of the sources that reference it. Names beginning with http:// or https://
+// are downloaded. Any other name is read from the filesystem.
+func ReadSource(name string) ([]byte, error) {
+	switch {
+	case name == StdinSource:
+		stdinOnce.Do(func() {
+			stdinData, stdinErr = ioutil.ReadAll(os.Stdin)
+		})
+
+		return stdinData, stdinErr
+	case isURLSource(name):
+		return downloadSource(name)
+	default:
+		return ioutil.ReadFile(name)
+	}
+}
+
+// isURLSource determines whether the source name refers to a URL.
+func isURLSource(name string) bool {
+	return strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "http://")
+}
+
+func downloadSource(url string) ([]byte, error) {
+	client := http.Client{Timeout: sourceTimeout}
+	res, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: status %d", url, res.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	return b, nil
+}
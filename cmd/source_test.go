@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestReadSource_file(t *testing.T) {
+	is := is.New(t)
+
+	fileName := filepath.Join(t.TempDir(), "header.md")
+	is.NoErr(os.WriteFile(fileName, []byte("from a file"), 0644))
+
+	b, err := ReadSource(fileName)
+	is.NoErr(err)
+	is.Equal(string(b), "from a file")
+}
+
+func TestReadSource_url(t *testing.T) {
+	is := is.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/header.md" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write([]byte("from a URL"))
+	}))
+	defer srv.Close()
+
+	b, err := ReadSource(srv.URL + "/header.md")
+	is.NoErr(err)
+	is.Equal(string(b), "from a URL")
+
+	_, err = ReadSource(srv.URL + "/missing.md")
+	is.True(err != nil)
+}
+
+func TestResolvePresetPath_sources(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(resolvePresetPath("presets", StdinSource), StdinSource)
+	is.Equal(resolvePresetPath("presets", "https://example.com/header.md"), "https://example.com/header.md")
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// PackageStats holds documentation statistics for a single package.
+type PackageStats struct {
+	// ImportPath holds the import path of the package.
+	ImportPath string `json:"importPath"`
+
+	// Exported holds the number of exported symbols in the package. Every
+	// name declared in a const or var block counts as a symbol.
+	Exported int `json:"exported"`
+
+	// Documented holds the number of exported symbols with a doc comment.
+	// Names declared in a const or var block are documented by the doc
+	// comment of the block.
+	Documented int `json:"documented"`
+
+	// Undocumented holds the number of exported symbols without a doc
+	// comment.
+	Undocumented int `json:"undocumented"`
+
+	// Examplable holds the number of funcs, types and methods, which are the
+	// symbols that examples can be attached to.
+	Examplable int `json:"examplable"`
+
+	// WithExamples holds the number of funcs, types and methods that have at
+	// least one example.
+	WithExamples int `json:"withExamples"`
+
+	// Words holds the total number of words in the doc comments of the
+	// package, including the package doc comment.
+	Words int `json:"words"`
+}
+
+// DocCoverage returns the percentage of exported symbols that are documented.
+// A package without exported symbols is considered fully documented.
+func (s *PackageStats) DocCoverage() float64 {
+	return percent(s.Documented, s.Exported)
+}
+
+// ExampleCoverage returns the percentage of funcs, types and methods that
+// have at least one example.
+func (s *PackageStats) ExampleCoverage() float64 {
+	return percent(s.WithExamples, s.Examplable)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return float64(n) * 100 / float64(total)
+}
+
+// ComputeStats gathers the documentation statistics of the package.
+func ComputeStats(pkg *lang.Package) (*PackageStats, error) {
+	m, err := pkg.Model()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &PackageStats{
+		ImportPath: m.ImportPath,
+		Words:      len(strings.Fields(m.Doc)),
+	}
+
+	doc := func(count int, text string) {
+		s.Exported += count
+		s.Words += len(strings.Fields(text))
+		if text == "" {
+			s.Undocumented += count
+		} else {
+			s.Documented += count
+		}
+	}
+
+	values := func(values []*lang.ValueModel) {
+		for _, v := range values {
+			doc(len(v.Names), v.Doc)
+		}
+	}
+
+	funcs := func(funcs []*lang.FuncModel) {
+		for _, fn := range funcs {
+			doc(1, fn.Doc)
+			s.Examplable++
+			if len(fn.Examples) > 0 {
+				s.WithExamples++
+			}
+		}
+	}
+
+	values(m.Consts)
+	values(m.Vars)
+	funcs(m.Funcs)
+
+	for _, typ := range m.Types {
+		doc(1, typ.Doc)
+		s.Examplable++
+		if len(typ.Examples) > 0 {
+			s.WithExamples++
+		}
+
+		values(typ.Consts)
+		values(typ.Vars)
+		funcs(typ.Funcs)
+		funcs(typ.Methods)
+	}
+
+	return s, nil
+}
+
+// WriteStats writes the statistics as an aligned table with one row per
+// package.
+func WriteStats(w io.Writer, stats []*PackageStats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tEXPORTED\tDOCUMENTED\tUNDOCUMENTED\tCOVERAGE\tEXAMPLES\tWORDS")
+	for _, s := range stats {
+		fmt.Fprintf(
+			tw,
+			"%s\t%d\t%d\t%d\t%.1f%%\t%.1f%%\t%d\n",
+			s.ImportPath,
+			s.Exported,
+			s.Documented,
+			s.Undocumented,
+			s.DocCoverage(),
+			s.ExampleCoverage(),
+			s.Words,
+		)
+	}
+
+	return tw.Flush()
+}
+
+// buildStatsCommand creates the stats command, which prints documentation
+// statistics for the packages.
+func buildStatsCommand(resolve ResolveFunc) *cobra.Command {
+	var asJSON bool
+
+	stats := &cobra.Command{
+		Use:   "stats [package ...]",
+		Short: "print documentation statistics for the packages",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, paths, err := resolve(args)
+			if err != nil {
+				return err
+			}
+
+			var failures Failures
+			specs, err := loadSpecs(paths, opts, &failures)
+			if err != nil {
+				return err
+			}
+
+			var all []*PackageStats
+			for _, spec := range specs {
+				if spec.Pkg == nil {
+					continue
+				}
+
+				s, err := ComputeStats(spec.Pkg)
+				if err != nil {
+					return err
+				}
+
+				all = append(all, s)
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(all); err != nil {
+					return err
+				}
+			} else if err := WriteStats(cmd.OutOrStdout(), all); err != nil {
+				return err
+			}
+
+			return failures.Err()
+		},
+	}
+
+	stats.Flags().BoolVar(
+		&asJSON,
+		"json",
+		false,
+		"Print the statistics as JSON, which is easier to record and compare over time.",
+	)
+
+	return stats
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestComputeStats(t *testing.T) {
+	is := is.New(t)
+
+	s, err := ComputeStats(loadTestPackage(t, "../testData/lint"))
+	is.NoErr(err)
+
+	is.Equal(s, &PackageStats{
+		ImportPath:   "github.com/ag5denis/gomarkdoc/testData/lint",
+		Exported:     10,
+		Documented:   8,
+		Undocumented: 2,
+		Examplable:   7,
+		WithExamples: 0,
+		Words:        30,
+	})
+	is.Equal(s.DocCoverage(), 80.0)
+	is.Equal(s.ExampleCoverage(), 0.0)
+}
+
+func TestComputeStats_examples(t *testing.T) {
+	is := is.New(t)
+
+	s, err := ComputeStats(loadTestPackage(t, "../testData/lang/function"))
+	is.NoErr(err)
+
+	is.Equal(s.Undocumented, 0)
+	is.Equal(s.DocCoverage(), 100.0)
+	is.True(s.WithExamples > 0)
+	is.True(s.WithExamples < s.Examplable)
+}
+
+func TestWriteStats(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	is.NoErr(WriteStats(&buf, []*PackageStats{
+		{ImportPath: "example.com/a", Exported: 4, Documented: 3, Undocumented: 1, Examplable: 2, WithExamples: 1, Words: 12},
+		{ImportPath: "example.com/empty"},
+	}))
+
+	is.Equal(buf.String(), ""+
+		"PACKAGE            EXPORTED  DOCUMENTED  UNDOCUMENTED  COVERAGE  EXAMPLES  WORDS\n"+
+		"example.com/a      4         3           1             75.0%     50.0%     12\n"+
+		"example.com/empty  0         0           0             100.0%    100.0%    0\n")
+}
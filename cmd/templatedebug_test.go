@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc"
+	"github.com/matryer/is"
+)
+
+func TestRunCommand_debugTemplates(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{Output: "README.md", DebugTemplates: true}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+
+	text := string(b)
+	is.True(strings.Contains(text, "<!-- gomarkdoc:template package -->\n"))
+	is.True(strings.Contains(text, "<!-- /gomarkdoc:template package -->\n"))
+	is.True(strings.Contains(text, "<!-- gomarkdoc:template func -->\n"))
+	is.True(strings.Index(text, "<!-- gomarkdoc:template func -->") < strings.Index(text, "Hello says hello."))
+}
+
+func TestRunCommand_debugTemplatesError(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Output:            "README.md",
+		DebugTemplates:    true,
+		TemplateOverrides: map[string]string{"func": "{{ .Name }}\n{{ .Missing }}\n"},
+	}
+	err := RunCommand([]string{"."}, opts)
+	is.True(err != nil)
+
+	var tmplErr *gomarkdoc.TemplateError
+	is.True(errors.As(err, &tmplErr))
+	is.Equal(tmplErr.Template, "func")
+	is.Equal(tmplErr.Line, 2)
+	is.Equal(tmplErr.Data, `*lang.Func "Hello" (lib.go:5)`)
+	is.True(strings.Contains(err.Error(), `template "func" line 2 with data *lang.Func "Hello" (lib.go:5)`))
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ag5denis/gomarkdoc"
+)
+
+// TemplateDiff compares an override template with the default template of the
+// same name shipped with the current version of gomarkdoc.
+type TemplateDiff struct {
+	// Name is the name of the overridden template.
+	Name string
+	// Source describes where the override came from: the file it was read
+	// from, or the --template flag for inline overrides.
+	Source string
+	// Base is the digest of the default template recorded by the override, or
+	// empty if it doesn't record one. See gomarkdoc.TemplateDigest.
+	Base string
+	// Current is the digest of the current default template.
+	Current string
+	// Diff is the unified diff from the current default template to the
+	// override, or empty if they're identical.
+	Diff string
+}
+
+// DiffTemplates compares each of the override templates in opts with the
+// current default template of the same name. Diffs are sorted by template
+// name.
+func DiffTemplates(opts CommandOptions) ([]*TemplateDiff, error) {
+	sources := make(map[string]string)
+	overrides := make(map[string]string)
+
+	for name, f := range opts.TemplateFileOverrides {
+		b, err := ReadSource(f)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: couldn't resolve template for %s: %w", name, err)
+		}
+
+		sources[name] = f
+		overrides[name] = string(b)
+	}
+
+	// Content overrides take precedence over file overrides
+	for name, s := range opts.TemplateOverrides {
+		sources[name] = "--template " + name
+		overrides[name] = s
+	}
+
+	var diffs []*TemplateDiff
+	for name, override := range overrides {
+		def, ok := gomarkdoc.DefaultTemplate(name)
+		if !ok {
+			return nil, fmt.Errorf(`gomarkdoc: invalid template name "%s"`, name)
+		}
+
+		base, _ := gomarkdoc.TemplateBase(override)
+		diffs = append(diffs, &TemplateDiff{
+			Name:    name,
+			Source:  sources[name],
+			Base:    base,
+			Current: gomarkdoc.TemplateDigest(def),
+			Diff:    UnifiedDiff("default/"+name, sources[name], def, gomarkdoc.StripTemplateBase(override)),
+		})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+
+	return diffs, nil
+}
+
+// UpstreamChanged reports whether the default template has changed since the
+// override was based on it. Overrides that don't record their base are never
+// reported as changed.
+func (d *TemplateDiff) UpstreamChanged() bool {
+	return d.Base != "" && d.Base != d.Current
+}
+
+// String renders the status of the override followed by its diff.
+func (d *TemplateDiff) String() string {
+	var b strings.Builder
+	switch {
+	case d.UpstreamChanged():
+		fmt.Fprintf(&b, "template %s (%s): UPSTREAM CHANGED: the default template has changed since this override was based on it (base %s, current %s)\n", d.Name, d.Source, d.Base, d.Current)
+	case d.Base != "":
+		fmt.Fprintf(&b, "template %s (%s): based on the current default template (%s)\n", d.Name, d.Source, d.Current)
+	default:
+		fmt.Fprintf(&b, "template %s (%s): no base recorded; add {{/* gomarkdoc:base %s */}} to the override to detect future changes to the default template\n", d.Name, d.Source, d.Current)
+	}
+
+	if d.Diff == "" {
+		b.WriteString("override is identical to the default template\n")
+	} else {
+		b.WriteString(d.Diff)
+	}
+
+	return b.String()
+}
+
+// buildTemplatesCommand creates the templates command, which holds the
+// subcommands for maintaining template overrides.
+func buildTemplatesCommand(resolve ResolveFunc) *cobra.Command {
+	templates := &cobra.Command{
+		Use:   "templates",
+		Short: "work with the templates used to render documentation",
+	}
+
+	diff := &cobra.Command{
+		Use:   "diff",
+		Short: "compare the override templates with the default templates of the current version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, _, err := resolve(args)
+			if err != nil {
+				return err
+			}
+
+			diffs, err := DiffTemplates(opts)
+			if err != nil {
+				return err
+			}
+
+			if len(diffs) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no template overrides are configured")
+				return nil
+			}
+
+			for i, d := range diffs {
+				if i > 0 {
+					fmt.Fprintln(cmd.OutOrStdout())
+				}
+
+				fmt.Fprint(cmd.OutOrStdout(), d.String())
+			}
+
+			return nil
+		},
+	}
+
+	templates.AddCommand(diff)
+
+	return templates
+}
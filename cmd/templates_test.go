@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/ag5denis/gomarkdoc"
+)
+
+func TestDiffTemplates(t *testing.T) {
+	is := is.New(t)
+
+	def, ok := gomarkdoc.DefaultTemplate("doc")
+	is.True(ok)
+
+	file := filepath.Join(t.TempDir(), "doc.gotxt")
+	override := "{{/* gomarkdoc:base " + gomarkdoc.TemplateDigest(def) + " */}}\n" + def
+	is.NoErr(os.WriteFile(file, []byte(override), 0o644))
+
+	diffs, err := DiffTemplates(CommandOptions{
+		TemplateOverrides:     map[string]string{"func": "custom\n"},
+		TemplateFileOverrides: map[string]string{"doc": file},
+	})
+	is.NoErr(err)
+	is.Equal(len(diffs), 2)
+
+	// The base comment is left out of the comparison
+	is.Equal(diffs[0].Name, "doc")
+	is.Equal(diffs[0].Source, file)
+	is.Equal(diffs[0].Diff, "")
+	is.True(!diffs[0].UpstreamChanged())
+
+	is.Equal(diffs[1].Name, "func")
+	is.Equal(diffs[1].Base, "")
+	is.True(!diffs[1].UpstreamChanged())
+	is.True(strings.HasPrefix(diffs[1].Diff, "--- default/func\n+++ --template func\n"))
+	is.True(strings.Contains(diffs[1].Diff, "\n+custom\n"))
+}
+
+func TestDiffTemplates_upstreamChanged(t *testing.T) {
+	is := is.New(t)
+
+	diffs, err := DiffTemplates(CommandOptions{
+		TemplateOverrides: map[string]string{"func": "{{- /* gomarkdoc:base 000000000000 */ -}}\ncustom\n"},
+	})
+	is.NoErr(err)
+	is.Equal(len(diffs), 1)
+	is.Equal(diffs[0].Base, "000000000000")
+	is.True(diffs[0].UpstreamChanged())
+	is.True(strings.Contains(diffs[0].String(), "UPSTREAM CHANGED"))
+}
+
+func TestDiffTemplates_invalidName(t *testing.T) {
+	is := is.New(t)
+
+	_, err := DiffTemplates(CommandOptions{
+		TemplateOverrides: map[string]string{"unknown": "custom"},
+	})
+	is.True(err != nil)
+}
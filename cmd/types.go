@@ -1,6 +1,12 @@
 package cmd
 
-import "github.com/ag5denis/gomarkdoc/lang"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
 
 // PackageSpec defines the data available to the --Output option's template.
 // Information is recomputed for each package generated.
@@ -18,22 +24,150 @@ type PackageSpec struct {
 	IsLocal    bool
 	OutputFile string
 	Pkg        *lang.Package
+
+	// importPath holds the import path of a package that wasn't loaded because
+	// its Output files are unchanged since they were cached.
+	importPath string
 }
 
 type CommandOptions struct {
 	Repository            lang.Repo
+	Hooks                 Hooks
 	Output                string
+	SingleFile            string
+	OutputDir             string
+	FileMode              string
+	DirMode               string
 	Header                string
 	HeaderFile            string
 	Footer                string
 	FooterFile            string
+	Manifest              string
+	Anchors               string
+	CaseCollisions        string
+	Shard                 string
+	Cache                 string
+	Since                 string
+	LintProfile           string
+	OutputCompat          string
+	LogFormat             string
+	CheckFormat           string
+	Annotations           string
+	ImportPath            string
+	ServeAddr             string
+	Order                 string
+	MethodOrder           string
 	Format                string
+	Formats               []string
+	FormatOutputs         map[string]string
 	Tags                  []string
+	Platforms             []string
+	NormalizeWhitespace   []string
+	Exclude               []string
+	SkipDirs              []string
+	FollowSymlinks        bool
+	ConstructorPrefixes   []string
+	ConstructorSuffixes   []string
+	Notes                 []string
 	TemplateOverrides     map[string]string
 	TemplateFileOverrides map[string]string
+	Vars                  map[string]interface{}
+	Locale                string
+	Translations          map[string]string
+	DebugTemplates        bool
 	Verbosity             int
+	Quiet                 bool
 	IncludeUnexported     bool
+	ExcludeInternal       bool
+	IncludeMain           bool
 	Check                 bool
+	Preview               bool
 	Embed                 bool
+	HeadingOffset         int
+	AnnotateChanges       bool
+	SplitExamples         bool
+	WikiTOC               bool
+	AnchorStyle           string
+	AnchorTemplate        string
+	Playground            bool
+	OmitDeprecated        bool
+	UngroupConstructors   bool
+	GroupReceivers        bool
+	Implements            bool
+	ImplementsStdlib      bool
+	ConstValues           bool
+	ExternalLinks         bool
+	OpaqueTypes           bool
+	ExternalTests         bool
+	FuzzTargets           bool
+	GenerateDirectives    bool
+	TaggedExamples        bool
+	KeepGoing             bool
+	FieldTable            bool
+	IncludeSource         bool
+	ReferenceLinks        bool
+	CrossPackageLinks     bool
+	PackageTable          bool
+	Subpackages           bool
+	HiddenSections        []string
+	SinceDeps             bool
+	Audit                 bool
 	Version               bool
+	VersionFormat         string
+
+	// flagged holds the configuration keys of the options that were set with
+	// flags, which nested configuration files can't override.
+	flagged map[string]bool
+
+	// configFiles holds the configuration files that the options were loaded
+	// from, including the configuration file of a preset bundle.
+	configFiles []string
+}
+
+// FormatOutput provides the Output template to use for the provided format.
+// Per-format values in FormatOutputs take precedence over Output, and
+// SingleFile or OutputDir take the place of Output when set.
+func (opts CommandOptions) FormatOutput(format string) string {
+	if output, ok := opts.FormatOutputs[format]; ok {
+		return output
+	}
+
+	if opts.SingleFile != "" {
+		return opts.SingleFile
+	}
+
+	if opts.OutputDir != "" {
+		return filepath.Join(opts.OutputDir, "{{.TreePath}}", outputDirFileName)
+	}
+
+	return opts.Output
+}
+
+// outputDirFileName is the name of the file each package is documented in
+// when mirroring the package tree with OutputDir.
+const outputDirFileName = "index.md"
+
+// TreePath provides the path of the package within the package tree, which is
+// mirrored into the directory of the --output-dir flag. Local packages use
+// their directory relative to the working directory, or their absolute
+// directory if they are outside of it, and remote packages use their import
+// path.
+func (s *PackageSpec) TreePath() string {
+	if !s.IsLocal {
+		return filepath.FromSlash(s.ImportPath)
+	}
+
+	abs, err := filepath.Abs(s.Dir)
+	if err != nil {
+		return filepath.Clean(s.Dir)
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		rel, err := filepath.Rel(wd, abs)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, parentPathPrefix) {
+			return rel
+		}
+	}
+
+	return strings.TrimPrefix(abs, filepath.VolumeName(abs)+string(os.PathSeparator))
 }
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// configKey describes a key that may appear in a configuration file.
+type configKey struct {
+	// name holds the camel-cased name of the key, which is used in error
+	// messages. Keys are matched case-insensitively, as viper does.
+	name string
+
+	// typ holds the type of the key's value, using the type names of pflag
+	// (e.g. bool, string, stringSlice, stringToString, int), or map for keys
+	// holding arbitrary nested values.
+	typ string
+}
+
+// configOnlyKeys lists the configuration keys that have no flag on the root
+// command.
+var configOnlyKeys = []configKey{
+	{extendsKey, "string"},
+	{rootConfigKey, "bool"},
+	{"Hooks.pre", "stringSlice"},
+	{"Hooks.post", "stringSlice"},
+	{"Lint.enable", "stringSlice"},
+	{"Lint.disable", "stringSlice"},
+	{"Translations", "stringToString"},
+	{"Vars", "map"},
+}
+
+// unboundFlags lists the flags of the root command that can't be set in a
+// configuration file.
+var unboundFlags = map[string]bool{
+	"config":  true,
+	"help":    true,
+	"verbose": true,
+	"Version": true,
+}
+
+var flagWordRegex = regexp.MustCompile(`-([a-zA-Z])`)
+
+// ConfigSchema builds the set of keys that may appear in a configuration file
+// from the flags of the command and the flags of any additional subcommands,
+// keyed by their lower-cased name. The key of each flag is the camel-cased
+// form of its long name (e.g. --include-unexported becomes
+// includeUnexported).
+func ConfigSchema(root *cobra.Command, subcommands ...*cobra.Command) map[string]configKey {
+	schema := make(map[string]configKey)
+	add := func(f *pflag.Flag) {
+		if unboundFlags[f.Name] {
+			return
+		}
+
+		name := configKeyName(f.Name)
+		schema[strings.ToLower(name)] = configKey{name, f.Value.Type()}
+	}
+
+	root.Flags().VisitAll(add)
+	for _, sub := range subcommands {
+		sub.LocalNonPersistentFlags().VisitAll(add)
+	}
+
+	for _, key := range configOnlyKeys {
+		schema[strings.ToLower(key.name)] = key
+	}
+
+	return schema
+}
+
+// configKeyName provides the configuration key of the flag, which is the
+// camel-cased form of its long name.
+func configKeyName(flag string) string {
+	return flagWordRegex.ReplaceAllStringFunc(flag, func(s string) string {
+		return strings.ToUpper(s[1:])
+	})
+}
+
+// ValidateConfig checks the settings loaded from the configuration files
+// against the schema. All of the unknown keys and values of the wrong type
+// are reported together, with suggestions for keys that look like typos.
+func ValidateConfig(settings map[string]interface{}, schema map[string]configKey) error {
+	var problems []string
+	validateConfigMap(settings, "", schema, &problems)
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return &configError{problems}
+}
+
+func validateConfigMap(settings map[string]interface{}, prefix string, schema map[string]configKey, problems *[]string) {
+	for k, v := range settings {
+		key := prefix + strings.ToLower(k)
+
+		if ck, ok := schema[key]; ok {
+			if !validConfigValue(ck.typ, v) {
+				*problems = append(*problems, fmt.Sprintf("%s must be %s", ck.name, configTypeNames[ck.typ]))
+			}
+
+			continue
+		}
+
+		// Dotted keys such as repository.url are nested in the settings
+		if m, ok := v.(map[string]interface{}); ok && hasConfigPrefix(schema, key+".") {
+			validateConfigMap(m, key+".", schema, problems)
+			continue
+		}
+
+		if suggestion := suggestConfigKey(key, schema); suggestion != "" {
+			*problems = append(*problems, fmt.Sprintf("unknown key %s (did you mean %s?)", key, suggestion))
+		} else {
+			*problems = append(*problems, fmt.Sprintf("unknown key %s", key))
+		}
+	}
+}
+
+func hasConfigPrefix(schema map[string]configKey, prefix string) bool {
+	for key := range schema {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// configTypeNames describes the pflag types for error messages.
+var configTypeNames = map[string]string{
+	"bool":           "a boolean",
+	"string":         "a string",
+	"int":            "an integer",
+	"count":          "an integer",
+	"stringSlice":    "a list of strings",
+	"stringToString": "a map of strings",
+	"map":            "a map",
+}
+
+// validConfigValue reports whether the value can be used for a key of the
+// type. Formats such as env and properties files hold every value as a
+// string, so strings are accepted for any scalar type that they parse as.
+func validConfigValue(typ string, v interface{}) bool {
+	switch typ {
+	case "bool":
+		switch val := v.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(val)
+			return err == nil
+		}
+
+		return false
+	case "int", "count":
+		switch val := v.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return val == float64(int64(val))
+		case string:
+			_, err := strconv.Atoi(val)
+			return err == nil
+		}
+
+		return false
+	case "stringSlice":
+		switch val := v.(type) {
+		case string:
+			return true
+		case []interface{}:
+			for _, elem := range val {
+				if !isConfigScalar(elem) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		return false
+	case "stringToString":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		for _, elem := range m {
+			if !isConfigScalar(elem) {
+				return false
+			}
+		}
+
+		return true
+	case "map":
+		_, ok := v.(map[string]interface{})
+		return ok
+	default:
+		return isConfigScalar(v)
+	}
+}
+
+func isConfigScalar(v interface{}) bool {
+	switch v.(type) {
+	case string, bool, int, int64, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// suggestConfigKey finds the known key closest to the unknown one, if any is
+// close enough to be a likely typo.
+func suggestConfigKey(key string, schema map[string]configKey) string {
+	// Typos rarely take more than a couple of edits, such as a swapped pair of
+	// letters
+	var (
+		best     string
+		bestDist = 3
+	)
+
+	for k, ck := range schema {
+		if d := editDistance(key, k); d < bestDist || (d == bestDist && best != "" && ck.name < best) {
+			best, bestDist = ck.name, d
+		}
+	}
+
+	return best
+}
+
+// editDistance computes the Levenshtein distance between the strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(vals ...int) int {
+	m := vals[0]
+	for _, v := range vals[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
+
+// ValidateSkipDirs checks that each of the patterns of directory names to skip
+// is a valid pattern for filepath.Match.
+func ValidateSkipDirs(opts CommandOptions) error {
+	for _, pattern := range opts.SkipDirs {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("gomarkdoc: invalid skip-dirs pattern: %s", pattern)
+		}
+	}
+
+	return nil
+}
+
+// ValidateOutput checks that at most one of the options choosing where the
+// Output is written is set.
+func ValidateOutput(opts CommandOptions) error {
+	var set []string
+	for _, o := range []struct{ flag, value string }{
+		{"--Output", opts.Output},
+		{"--single-file", opts.SingleFile},
+		{"--output-dir", opts.OutputDir},
+	} {
+		if o.value != "" {
+			set = append(set, o.flag)
+		}
+	}
+
+	if len(set) > 1 {
+		return fmt.Errorf("gomarkdoc: %s cannot be used together", strings.Join(set, " and "))
+	}
+
+	return nil
+}
+
+// ValidateCheckFormat checks that the format of the check report is known.
+func ValidateCheckFormat(opts CommandOptions) error {
+	if opts.CheckFormat == "" {
+		return nil
+	}
+
+	for _, f := range checkFormats {
+		if opts.CheckFormat == f {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("gomarkdoc: invalid check format: %s (valid options: %s)", opts.CheckFormat, strings.Join(checkFormats, ", "))
+}
+
+// ValidateModes checks that the file and directory modes of the options are
+// valid permission bits.
+func ValidateModes(opts CommandOptions) error {
+	if _, err := ParseMode(opts.FileMode); err != nil {
+		return err
+	}
+
+	if _, err := ParseMode(opts.DirMode); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateFormats checks that each of the formats requested by the options is
+// a known format, including those used as keys of FormatOutputs and listed in
+// NormalizeWhitespace.
+func ValidateFormats(opts CommandOptions) error {
+	valid := make(map[string]bool)
+	for _, f := range formatNames {
+		valid[f] = true
+	}
+
+	check := func(f string) error {
+		if !valid[f] {
+			return fmt.Errorf("gomarkdoc: invalid Format: %s (valid options: %s)", f, strings.Join(formatNames, ", "))
+		}
+
+		return nil
+	}
+
+	for _, f := range ResolveFormats(opts) {
+		// An empty Format falls back to github
+		if f == "" {
+			continue
+		}
+
+		if err := check(f); err != nil {
+			return err
+		}
+	}
+
+	for f := range opts.FormatOutputs {
+		if err := check(f); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range opts.NormalizeWhitespace {
+		if err := check(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadLocalConfig reads the settings of the configuration files that apply
+// to the working directory on their own, without flags or presets.
+func loadLocalConfig(configFile string) (map[string]interface{}, error) {
+	if configFile == "" {
+		files, err := FindConfigFiles(".")
+		if err != nil {
+			return nil, err
+		}
+
+		return loadConfigFiles(files)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("gomarkdoc: failed to read config file %s: %w", configFile, err)
+	}
+
+	return v.AllSettings(), nil
+}
+
+// configError reports the problems found in the configuration files.
+type configError struct {
+	problems []string
+}
+
+func (e *configError) Error() string {
+	var b strings.Builder
+	b.WriteString("gomarkdoc: invalid configuration:")
+	for _, p := range e.problems {
+		fmt.Fprintf(&b, "\n    %s", p)
+	}
+
+	return b.String()
+}
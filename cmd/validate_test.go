@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/spf13/cobra"
+)
+
+func TestValidateConfig(t *testing.T) {
+	is := is.New(t)
+
+	schema := ConfigSchema(BuildCommand())
+
+	is.NoErr(ValidateConfig(map[string]interface{}{
+		"output":            "{{.Dir}}/README.md",
+		"includeunexported": true,
+		"embed":             "true",
+		"tags":              []interface{}{"a", "b"},
+		"template":          map[string]interface{}{"func": "{{.Name}}"},
+		"repository": map[string]interface{}{
+			"url":           "https://github.com/ag5denis/gomarkdoc",
+			"defaultbranch": "master",
+		},
+		"hooks": map[string]interface{}{"post": []interface{}{"git add -A"}},
+		"vars":  map[string]interface{}{"productname": "Widgets", "links": map[string]interface{}{"docs": "https://example.com"}},
+		"root":  true,
+	}, schema))
+
+	err := ValidateConfig(map[string]interface{}{
+		"includeunexporetd": true,
+		"check":             "sometimes",
+		"tags":              map[string]interface{}{"a": "b"},
+		"repository":        map[string]interface{}{"branch": "master"},
+		"vars":              "Widgets",
+		"nothinglikeit":     1,
+	}, schema)
+	is.True(err != nil)
+	is.Equal(err.Error(), "gomarkdoc: invalid configuration:\n"+
+		"    Check must be a boolean\n"+
+		"    Tags must be a list of strings\n"+
+		"    Vars must be a map\n"+
+		"    unknown key includeunexporetd (did you mean includeUnexported?)\n"+
+		"    unknown key nothinglikeit\n"+
+		"    unknown key repository.branch")
+}
+
+func TestConfigSchema_subcommands(t *testing.T) {
+	is := is.New(t)
+
+	var addr string
+	sub := &cobra.Command{Use: "plugin"}
+	sub.Flags().StringVar(&addr, "listen-addr", "", "")
+
+	schema := ConfigSchema(BuildCommand(), sub)
+	is.Equal(schema["listenaddr"], configKey{"listenAddr", "string"})
+	is.NoErr(ValidateConfig(map[string]interface{}{"listenaddr": ":8080"}, schema))
+}
+
+func TestValidateFormats(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateFormats(CommandOptions{Format: "github", Formats: []string{"plain,yaml"}}))
+	is.NoErr(ValidateFormats(CommandOptions{}))
+
+	err := ValidateFormats(CommandOptions{Formats: []string{"github,markdown"}})
+	is.Equal(err.Error(), "gomarkdoc: invalid Format: markdown (valid options: github, azure-devops, plain, llms, yaml, docfx)")
+
+	err = ValidateFormats(CommandOptions{Format: "github", FormatOutputs: map[string]string{"gitub": "README.md"}})
+	is.Equal(err.Error(), "gomarkdoc: invalid Format: gitub (valid options: github, azure-devops, plain, llms, yaml, docfx)")
+}
+
+func TestValidateModes(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateModes(CommandOptions{}))
+	is.NoErr(ValidateModes(CommandOptions{FileMode: "0600", DirMode: "755"}))
+
+	err := ValidateModes(CommandOptions{FileMode: "0644", DirMode: "rwxr-xr-x"})
+	is.Equal(err.Error(), "gomarkdoc: invalid mode rwxr-xr-x: expected octal permission bits such as 0644")
+
+	err = ValidateModes(CommandOptions{FileMode: "01777"})
+	is.Equal(err.Error(), "gomarkdoc: invalid mode 01777: expected octal permission bits such as 0644")
+}
+
+func TestValidateOutput(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(ValidateOutput(CommandOptions{}))
+	is.NoErr(ValidateOutput(CommandOptions{OutputDir: "docs"}))
+
+	err := ValidateOutput(CommandOptions{Output: "README.md", SingleFile: "docs/API.md", OutputDir: "docs"})
+	is.Equal(err.Error(), "gomarkdoc: --Output and --single-file and --output-dir cannot be used together")
+}
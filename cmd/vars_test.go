@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/spf13/cobra"
+)
+
+func TestBuildCommand_vars(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, ".gomarkdoc.yml"), "vars:\n  productName: Widgets\n  links:\n    docs: https://example.com\n")
+	chdir(t, dir)
+
+	var resolved CommandOptions
+	command := BuildCommand(WithSubcommand(func(resolve ResolveFunc) *cobra.Command {
+		return &cobra.Command{
+			Use: "resolve [package ...]",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var err error
+				resolved, _, err = resolve(args)
+				return err
+			},
+		}
+	}))
+
+	command.SetArgs([]string{"resolve", "."})
+	is.NoErr(command.Execute())
+
+	is.Equal(resolved.Vars["productname"], "Widgets")
+	is.Equal(resolved.Vars["links"], map[string]interface{}{"docs": "https://example.com"})
+}
+
+func TestRunCommand_vars(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "go.mod"), "module example.com/mod\n\ngo 1.19\n")
+	writeConfig(t, filepath.Join(dir, "lib.go"), "// Package lib is a library.\npackage lib\n")
+	chdir(t, dir)
+
+	opts := CommandOptions{
+		Output: "README.md",
+		Vars:   map[string]interface{}{"productname": "Widgets"},
+		TemplateOverrides: map[string]string{
+			"frontmatter": "{{ var \"productName\" }}{{ with var \"supportEmail\" }} {{ . }}{{ end }}\n",
+		},
+	}
+	is.NoErr(RunCommand([]string{"."}, opts))
+
+	b, err := os.ReadFile("README.md")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(string(b), "Widgets\n<!-- Code generated by gomarkdoc. DO NOT EDIT -->\n"))
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+)
+
+// Flags populated by goreleaser
+var (
+	commit = ""
+	date   = ""
+)
+
+// Formats in which the version can be printed, as selected with the
+// --version-format flag.
+const (
+	// VersionShort prints the version alone. This is the default.
+	VersionShort = "short"
+
+	// VersionFull prints the version along with the details of the build.
+	VersionFull = "full"
+
+	// VersionJSON prints the version and the details of the build as a JSON
+	// object, for tooling that records the versions of tools.
+	VersionJSON = "json"
+)
+
+var versionFormats = []string{VersionShort, VersionFull, VersionJSON}
+
+// VersionInfo describes the build of gomarkdoc that is running.
+type VersionInfo struct {
+	// Version holds the released version, or (devel) for builds from
+	// source.
+	Version string `json:"version"`
+
+	// Commit holds the SHA of the commit the binary was built from.
+	Commit string `json:"commit,omitempty"`
+
+	// Date holds the time the binary was built, or the time of the commit if
+	// the build time isn't known.
+	Date string `json:"date,omitempty"`
+
+	// Modified indicates that the binary was built from a working tree with
+	// uncommitted changes.
+	Modified bool `json:"modified,omitempty"`
+
+	// GoVersion holds the version of Go used to build the binary.
+	GoVersion string `json:"goVersion,omitempty"`
+
+	// Sum holds the checksum of the gomarkdoc module, if it was downloaded
+	// from a module proxy.
+	Sum string `json:"sum,omitempty"`
+}
+
+// ReadVersionInfo describes the running build of gomarkdoc from the values
+// provided at link time and the build information embedded in the binary.
+func ReadVersionInfo() VersionInfo {
+	v := VersionInfo{Version: version, Commit: commit, Date: date}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		if v.Version == "" {
+			v.Version = "<unknown>"
+		}
+
+		return v
+	}
+
+	if v.Version == "" {
+		v.Version = info.Main.Version
+	}
+
+	v.GoVersion = info.GoVersion
+	v.Sum = info.Main.Sum
+
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if v.Commit == "" {
+				v.Commit = s.Value
+			}
+		case "vcs.time":
+			if v.Date == "" {
+				v.Date = s.Value
+			}
+		case "vcs.modified":
+			v.Modified = s.Value == "true"
+		}
+	}
+
+	return v
+}
+
+// WriteVersion writes the version of gomarkdoc to the writer in the provided
+// format, which is one of VersionShort, VersionFull or VersionJSON. An empty
+// format is treated as VersionShort.
+func WriteVersion(w io.Writer, format string) error {
+	v := ReadVersionInfo()
+
+	switch format {
+	case "", VersionShort:
+		_, err := fmt.Fprintln(w, v.Version)
+		return err
+	case VersionFull:
+		var b strings.Builder
+		fmt.Fprintf(&b, "gomarkdoc %s\n", v.Version)
+
+		commit := v.Commit
+		if commit != "" && v.Modified {
+			commit += " (modified)"
+		}
+
+		for _, field := range []struct{ name, value string }{
+			{"commit", commit},
+			{"built", v.Date},
+			{"go", v.GoVersion},
+			{"checksum", v.Sum},
+		} {
+			if field.value != "" {
+				fmt.Fprintf(&b, "  %-9s %s\n", field.name+":", field.value)
+			}
+		}
+
+		_, err := io.WriteString(w, b.String())
+		return err
+	case VersionJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	default:
+		return fmt.Errorf("gomarkdoc: invalid version format: %s (valid options: %s)", format, strings.Join(versionFormats, ", "))
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestWriteVersion(t *testing.T) {
+	is := is.New(t)
+
+	setVersion(t, "v1.2.3", "abc123", "2024-01-02T03:04:05Z")
+
+	var out bytes.Buffer
+	is.NoErr(WriteVersion(&out, ""))
+	is.Equal(out.String(), "v1.2.3\n")
+
+	out.Reset()
+	is.NoErr(WriteVersion(&out, VersionFull))
+	is.True(strings.HasPrefix(out.String(), "gomarkdoc v1.2.3\n  commit:   abc123"))
+	is.True(strings.Contains(out.String(), "\n  built:    2024-01-02T03:04:05Z\n"))
+
+	out.Reset()
+	is.NoErr(WriteVersion(&out, VersionJSON))
+
+	var v VersionInfo
+	is.NoErr(json.Unmarshal(out.Bytes(), &v))
+	is.Equal(v.Version, "v1.2.3")
+	is.Equal(v.Commit, "abc123")
+	is.Equal(v.Date, "2024-01-02T03:04:05Z")
+	is.True(strings.HasPrefix(v.GoVersion, "go"))
+
+	is.Equal(
+		WriteVersion(&out, "yaml").Error(),
+		"gomarkdoc: invalid version format: yaml (valid options: short, full, json)",
+	)
+}
+
+func TestBuildCommand_versionFormat(t *testing.T) {
+	is := is.New(t)
+
+	setVersion(t, "v1.2.3", "", "")
+
+	command := BuildCommand()
+
+	var out bytes.Buffer
+	command.SetOut(&out)
+	command.SetArgs([]string{"--Version", "--version-format", "json"})
+	is.NoErr(command.Execute())
+
+	var v VersionInfo
+	is.NoErr(json.Unmarshal(out.Bytes(), &v))
+	is.Equal(v.Version, "v1.2.3")
+}
+
+func setVersion(t *testing.T, v, c, d string) {
+	t.Helper()
+
+	oldVersion, oldCommit, oldDate := version, commit, date
+	version, commit, date = v, c, d
+
+	t.Cleanup(func() {
+		version, commit, date = oldVersion, oldCommit, oldDate
+	})
+}
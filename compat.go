@@ -0,0 +1,203 @@
+package gomarkdoc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// OutputChange describes a change to the default output of gomarkdoc that can
+// be reverted with WithOutputCompat. Name identifies the change, which
+// templates can check with the outputChange function, and Version is the
+// release of gomarkdoc that introduced it.
+type OutputChange struct {
+	Name        string
+	Version     string
+	Description string
+}
+
+// compatRelease is the first release of gomarkdoc that includes the changes
+// to the default output listed below. Pinning the output to any earlier release
+// reverts all of them.
+const compatRelease = "v1.2.0"
+
+// outputChanges lists the changes to the default output that WithOutputCompat
+// can revert, in the order they were introduced.
+var outputChanges = []OutputChange{
+	{
+		Name:        "devops-anchors",
+		Version:     compatRelease,
+		Description: "Azure DevOps anchors are derived from the plain text of linked headers",
+	},
+	{
+		Name:        "internal-annotation",
+		Version:     compatRelease,
+		Description: "internal packages are annotated with the packages that can import them",
+	},
+	{
+		Name:        "notes",
+		Version:     compatRelease,
+		Description: "notes such as BUG(uid) are rendered in a Notes section",
+	},
+	{
+		Name:        "build-constraints",
+		Version:     compatRelease,
+		Description: "symbols are annotated with the build constraints of their files",
+	},
+	{
+		Name:        "opaque-types",
+		Version:     compatRelease,
+		Description: "the command documents the exported methods of unexported types used by exported functions",
+	},
+	{
+		Name:        "constraint-unions",
+		Version:     compatRelease,
+		Description: "the union elements of constraints are listed with links to each term",
+	},
+	{
+		Name:        "declaration-ranges",
+		Version:     compatRelease,
+		Description: "source links of funcs span the full declaration, including the body",
+	},
+	{
+		Name:        "expanded-types",
+		Version:     compatRelease,
+		Description: "struct and interface types written on a single line are expanded in declarations and signatures",
+	},
+}
+
+// OutputChanges lists the changes to the default output that can be reverted
+// with WithOutputCompat, in the order they were introduced.
+func OutputChanges() []OutputChange {
+	return append([]OutputChange(nil), outputChanges...)
+}
+
+// WithOutputCompat reverts the changes to the default output listed by
+// OutputChanges that were introduced after the provided release of gomarkdoc,
+// such as v1.1.0. This allows the gomarkdoc binary to be upgraded without
+// regenerating all of the documentation that was written with the older
+// release at once. Only the listed changes are reverted, so the output isn't
+// guaranteed to match the older release byte for byte, and features that must
+// be enabled explicitly are unaffected.
+func WithOutputCompat(version string) RendererOption {
+	return func(renderer *Renderer) error {
+		reverted, err := revertedOutputChanges(version)
+		if err != nil {
+			return err
+		}
+
+		renderer.revertedChanges = reverted
+		return nil
+	}
+}
+
+// OutputChangeReverted reports whether pinning the output to the provided
+// release with WithOutputCompat reverts the named change. It allows changes
+// that are made outside of the renderer, such as features that the command
+// enables by default, to honor the same version.
+func OutputChangeReverted(version, name string) (bool, error) {
+	reverted, err := revertedOutputChanges(version)
+	if err != nil {
+		return false, err
+	}
+
+	return reverted[name], nil
+}
+
+// revertedOutputChanges finds the names of the changes that were introduced
+// after the provided release.
+func revertedOutputChanges(version string) (map[string]bool, error) {
+	pinned, err := parseReleaseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	reverted := make(map[string]bool)
+	for _, change := range outputChanges {
+		introduced, err := parseReleaseVersion(change.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		if compareReleaseVersions(introduced, pinned) > 0 {
+			reverted[change.Name] = true
+		}
+	}
+
+	return reverted, nil
+}
+
+// outputChange reports whether the named change to the default output is in
+// effect for the renderer. Unknown changes are reported as in effect, so that
+// templates written for newer releases keep working.
+func (out *Renderer) outputChange(name string) bool {
+	return !out.revertedChanges[name]
+}
+
+// funcLocation provides the location that the source links of the function
+// point to, which only spans its signature if the declaration-ranges change is
+// reverted.
+func (out *Renderer) funcLocation(fn *lang.Func) lang.Location {
+	if out.outputChange("declaration-ranges") {
+		return fn.Location()
+	}
+
+	return fn.SignatureLocation()
+}
+
+// compatFormat adjusts the format to revert the changes that are pinned by
+// WithOutputCompat, unwrapping lint profiles to reach the underlying format.
+func (out *Renderer) compatFormat(f format.Format) format.Format {
+	switch f := f.(type) {
+	case *format.AzureDevOpsMarkdown:
+		if out.outputChange("devops-anchors") {
+			return f
+		}
+
+		pinned := *f
+		pinned.RawHeaderAnchors = true
+		return &pinned
+	case *format.MarkdownLint:
+		return &format.MarkdownLint{Format: out.compatFormat(f.Format)}
+	default:
+		return f
+	}
+}
+
+// parseReleaseVersion parses a release version such as v1.2.3 into its major,
+// minor and patch components. The leading v and the patch component are
+// optional.
+func parseReleaseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return parsed, fmt.Errorf("gomarkdoc: invalid output compatibility version: %s", version)
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("gomarkdoc: invalid output compatibility version: %s", version)
+		}
+
+		parsed[i] = n
+	}
+
+	return parsed, nil
+}
+
+// compareReleaseVersions returns a negative number if a precedes b, a positive
+// number if b precedes a and zero if they're the same release.
+func compareReleaseVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+
+	return 0
+}
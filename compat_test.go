@@ -0,0 +1,72 @@
+package gomarkdoc_test
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc"
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/ag5denis/gomarkdoc/logger"
+	"github.com/matryer/is"
+)
+
+func TestWithOutputCompat(t *testing.T) {
+	is := is.New(t)
+
+	pkg := loadFunctionPackage(t)
+
+	renderer, err := gomarkdoc.NewRenderer()
+	is.NoErr(err)
+
+	doc, err := renderer.Package(pkg)
+	is.NoErr(err)
+	is.True(strings.Contains(doc, "/func.go#L14-L16>)"))                               // source links span the declaration
+	is.True(strings.Contains(doc, "func Configure(opts struct {\n    Name string\n}")) // anonymous structs are expanded
+	is.True(strings.Contains(doc, "    Limits struct {\n        Max, Min int\n    }")) // anonymous fields are expanded
+
+	renderer, err = gomarkdoc.NewRenderer(gomarkdoc.WithOutputCompat("v1.1.0"))
+	is.NoErr(err)
+
+	doc, err = renderer.Package(pkg)
+	is.NoErr(err)
+	is.True(strings.Contains(doc, "/func.go#L14>)"))
+	is.True(!strings.Contains(doc, "/func.go#L14-L16>)"))
+	is.True(strings.Contains(doc, "func Configure(opts struct{ Name string }) interface{ Close() error }"))
+	is.True(strings.Contains(doc, "    Limits struct{ Max, Min int }"))
+}
+
+func TestOutputChangeReverted(t *testing.T) {
+	is := is.New(t)
+
+	for _, change := range gomarkdoc.OutputChanges() {
+		reverted, err := gomarkdoc.OutputChangeReverted("v1.1.0", change.Name)
+		is.NoErr(err)
+		is.True(reverted) // changes after the release are reverted
+
+		reverted, err = gomarkdoc.OutputChangeReverted(change.Version, change.Name)
+		is.NoErr(err)
+		is.True(!reverted) // changes in the release itself are kept
+	}
+}
+
+func loadFunctionPackage(t *testing.T) *lang.Package {
+	t.Helper()
+	is := is.New(t)
+
+	buildPkg, err := build.ImportDir("testData/lang/function", build.ImportComment)
+	is.NoErr(err)
+
+	pkg, err := lang.NewPackageFromBuild(
+		logger.New(logger.ErrorLevel),
+		buildPkg,
+		lang.PackageWithRepositoryOverrides(&lang.Repo{
+			Remote:        "https://github.com/ag5denis/gomarkdoc",
+			DefaultBranch: "master",
+			PathFromRoot:  "/",
+		}),
+	)
+	is.NoErr(err)
+
+	return pkg
+}
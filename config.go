@@ -0,0 +1,241 @@
+package gomarkdoc
+
+import (
+	"fmt"
+
+	"github.com/ag5denis/gomarkdoc/format"
+)
+
+// Config holds the configuration of a Renderer as plain data, for programs
+// that load their settings from their own configuration files instead of
+// assembling RendererOption slices. It mirrors the rendering settings of the
+// gomarkdoc command. Settings that depend on loaded packages or custom
+// functions, such as WithCrossPackageLinks and WithPlaygroundLinks, are left
+// to RendererOption values passed to NewRendererFromConfig.
+type Config struct {
+	// Format is the name of the output format: github (the default),
+	// azure-devops, plain or llms.
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+
+	// LintProfile adjusts the output to satisfy a markdown linter. The only
+	// supported profile is markdownlint.
+	LintProfile string `json:"lintProfile,omitempty" yaml:"lintProfile,omitempty"`
+
+	// WikiTOC adds a table of contents to documentation rendered with the
+	// azure-devops format.
+	WikiTOC bool `json:"wikiTOC,omitempty" yaml:"wikiTOC,omitempty"`
+
+	// Templates maps template names to the content overriding them.
+	Templates map[string]string `json:"templates,omitempty" yaml:"templates,omitempty"`
+
+	// ExamplesFile is the href of the file that examples are moved to. See
+	// WithExamplesFile.
+	ExamplesFile string `json:"examplesFile,omitempty" yaml:"examplesFile,omitempty"`
+
+	// FieldTable adds field tables to struct types. See WithFieldTable.
+	FieldTable bool `json:"fieldTable,omitempty" yaml:"fieldTable,omitempty"`
+
+	// IncludeSource includes the source of declarations. See WithSource.
+	IncludeSource bool `json:"includeSource,omitempty" yaml:"includeSource,omitempty"`
+
+	// ReferenceLinks emits reference-style links. See WithReferenceLinks.
+	ReferenceLinks bool `json:"referenceLinks,omitempty" yaml:"referenceLinks,omitempty"`
+
+	// NormalizeWhitespace cleans up the whitespace of rendered files. See
+	// WithWhitespaceNormalization.
+	NormalizeWhitespace bool `json:"normalizeWhitespace,omitempty" yaml:"normalizeWhitespace,omitempty"`
+
+	// SingleFile renders each file as a single combined document. See
+	// WithSingleFile.
+	SingleFile bool `json:"singleFile,omitempty" yaml:"singleFile,omitempty"`
+
+	// OutputCompat reverts the changes to the default output introduced after
+	// an older release of gomarkdoc. See WithOutputCompat.
+	OutputCompat string `json:"outputCompat,omitempty" yaml:"outputCompat,omitempty"`
+
+	// AnchorStyle changes how anchors are generated: format (the default),
+	// github, azure-devops or explicit. See WithAnchorStyle.
+	AnchorStyle string `json:"anchorStyle,omitempty" yaml:"anchorStyle,omitempty"`
+
+	// AnchorTemplate generates anchors using a template. See
+	// WithAnchorTemplate.
+	AnchorTemplate string `json:"anchorTemplate,omitempty" yaml:"anchorTemplate,omitempty"`
+
+	// HeadingOffset increases the level of every heading. See
+	// WithHeadingOffset.
+	HeadingOffset int `json:"headingOffset,omitempty" yaml:"headingOffset,omitempty"`
+
+	// HiddenSections lists the sections left out of the documentation, such
+	// as index or source-links. See WithHiddenSections.
+	HiddenSections []string `json:"hiddenSections,omitempty" yaml:"hiddenSections,omitempty"`
+
+	// Locale translates the headings and labels of the documentation using
+	// built-in translations. See WithLocale.
+	Locale string `json:"locale,omitempty" yaml:"locale,omitempty"`
+
+	// Translations maps the English headings and labels of the documentation
+	// to their translations. See WithTranslations.
+	Translations map[string]string `json:"translations,omitempty" yaml:"translations,omitempty"`
+
+	// IncludeRoot is the directory that templates can include files from
+	// with the include function. See WithIncludeRoot.
+	IncludeRoot string `json:"includeRoot,omitempty" yaml:"includeRoot,omitempty"`
+
+	// Vars holds variables that templates can look up with the var function.
+	// See WithTemplateVars.
+	Vars map[string]interface{} `json:"vars,omitempty" yaml:"vars,omitempty"`
+
+	// DebugTemplates annotates the output with the templates that produced
+	// it. See WithTemplateDebug.
+	DebugTemplates bool `json:"debugTemplates,omitempty" yaml:"debugTemplates,omitempty"`
+}
+
+// NewRendererFromConfig validates the provided configuration and initializes
+// a Renderer from it. Any additional options are applied after the ones
+// derived from the configuration.
+func NewRendererFromConfig(cfg Config, opts ...RendererOption) (*Renderer, error) {
+	cfgOpts, err := cfg.RendererOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRenderer(append(cfgOpts, opts...)...)
+}
+
+// Validate checks that the configuration only refers to known formats, lint
+// profiles, templates, anchor styles, sections and locales.
+func (cfg Config) Validate() error {
+	_, err := cfg.RendererOptions()
+	return err
+}
+
+// RendererOptions converts the configuration into the equivalent set of
+// options for NewRenderer, or returns an error if the configuration is
+// invalid.
+func (cfg Config) RendererOptions() ([]RendererOption, error) {
+	var opts []RendererOption
+
+	for name, tmpl := range cfg.Templates {
+		if _, ok := templates[name]; !ok {
+			return nil, fmt.Errorf(`gomarkdoc: invalid template name "%s"`, name)
+		}
+
+		opts = append(opts, WithTemplateOverride(name, tmpl))
+	}
+
+	var f format.Format
+	switch cfg.Format {
+	case "", "github":
+		f = &format.GitHubFlavoredMarkdown{}
+	case "azure-devops":
+		f = &format.AzureDevOpsMarkdown{WikiTOC: cfg.WikiTOC}
+	case "plain":
+		f = &format.PlainMarkdown{}
+	case "llms":
+		f = &format.LLMText{}
+	default:
+		return nil, fmt.Errorf("gomarkdoc: invalid Format: %s", cfg.Format)
+	}
+
+	switch cfg.LintProfile {
+	case "":
+	case "markdownlint":
+		f = &format.MarkdownLint{Format: f}
+	default:
+		return nil, fmt.Errorf("gomarkdoc: invalid lint profile: %s", cfg.LintProfile)
+	}
+
+	opts = append(opts, WithFormat(f))
+
+	if cfg.ExamplesFile != "" {
+		opts = append(opts, WithExamplesFile(cfg.ExamplesFile))
+	}
+
+	if cfg.FieldTable {
+		opts = append(opts, WithFieldTable())
+	}
+
+	if cfg.IncludeSource {
+		opts = append(opts, WithSource())
+	}
+
+	if cfg.ReferenceLinks {
+		opts = append(opts, WithReferenceLinks())
+	}
+
+	if cfg.NormalizeWhitespace {
+		opts = append(opts, WithWhitespaceNormalization())
+	}
+
+	if cfg.SingleFile {
+		opts = append(opts, WithSingleFile())
+	}
+
+	if cfg.OutputCompat != "" {
+		if _, err := parseReleaseVersion(cfg.OutputCompat); err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, WithOutputCompat(cfg.OutputCompat))
+	}
+
+	if cfg.AnchorStyle != "" {
+		if !anchorStyles[cfg.AnchorStyle] {
+			return nil, fmt.Errorf(`gomarkdoc: invalid anchor style "%s"`, cfg.AnchorStyle)
+		}
+
+		opts = append(opts, WithAnchorStyle(cfg.AnchorStyle))
+	}
+
+	if cfg.AnchorTemplate != "" {
+		if _, err := parseAnchorTemplate(cfg.AnchorTemplate); err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, WithAnchorTemplate(cfg.AnchorTemplate))
+	}
+
+	if cfg.HeadingOffset != 0 {
+		if cfg.HeadingOffset < 0 || cfg.HeadingOffset > 5 {
+			return nil, fmt.Errorf("gomarkdoc: invalid heading offset %d: must be between 0 and 5", cfg.HeadingOffset)
+		}
+
+		opts = append(opts, WithHeadingOffset(cfg.HeadingOffset))
+	}
+
+	if len(cfg.HiddenSections) > 0 {
+		for _, section := range cfg.HiddenSections {
+			if !hideableSections[section] {
+				return nil, fmt.Errorf(`gomarkdoc: invalid section name "%s"`, section)
+			}
+		}
+
+		opts = append(opts, WithHiddenSections(cfg.HiddenSections...))
+	}
+
+	if cfg.Locale != "" {
+		if _, ok := locales[cfg.Locale]; !ok {
+			return nil, fmt.Errorf(`gomarkdoc: unsupported locale "%s"`, cfg.Locale)
+		}
+
+		opts = append(opts, WithLocale(cfg.Locale))
+	}
+
+	if len(cfg.Translations) > 0 {
+		opts = append(opts, WithTranslations(cfg.Translations))
+	}
+
+	if cfg.IncludeRoot != "" {
+		opts = append(opts, WithIncludeRoot(cfg.IncludeRoot))
+	}
+
+	if len(cfg.Vars) > 0 {
+		opts = append(opts, WithTemplateVars(cfg.Vars))
+	}
+
+	if cfg.DebugTemplates {
+		opts = append(opts, WithTemplateDebug())
+	}
+
+	return opts, nil
+}
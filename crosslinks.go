@@ -0,0 +1,87 @@
+package gomarkdoc
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+var pkgGoDevLinkRegex = regexp.MustCompile(`https://pkg\.go\.dev/([^\s#<>()]+)(?:#([A-Za-z_][A-Za-z0-9_.]*))?`)
+
+// WithCrossPackageLinks links to the generated documentation of the provided
+// packages in place of their documentation on pkg.go.dev. Links are only
+// rewritten for packages with an output file provided by WithOutputFiles, and
+// point to the output file relative to the output file being rendered, along
+// with the anchor of the linked symbol.
+func WithCrossPackageLinks(pkgs ...*lang.Package) RendererOption {
+	return func(renderer *Renderer) error {
+		if renderer.linkedPkgs == nil {
+			renderer.linkedPkgs = make(map[string]*lang.Package)
+		}
+
+		for _, pkg := range pkgs {
+			renderer.linkedPkgs[pkg.ImportPath()] = pkg
+		}
+
+		return nil
+	}
+}
+
+// crossPackageLinks rewrites the links to the documentation of other packages
+// on pkg.go.dev within the rendered text of the file to point to the
+// generated documentation of those packages instead.
+func (out *Renderer) crossPackageLinks(file *lang.File, text string) (string, error) {
+	if len(out.linkedPkgs) == 0 || len(file.Packages) == 0 {
+		return text, nil
+	}
+
+	current := file.Packages[0].ImportPath()
+	if _, ok := out.outputFiles[current]; !ok {
+		return text, nil
+	}
+
+	var err error
+	text = pkgGoDevLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		m := pkgGoDevLinkRegex.FindStringSubmatch(link)
+		pkg, ok := out.linkedPkgs[m[1]]
+		if !ok {
+			return link
+		}
+
+		rel, relErr := out.relativeOutputPath(current, m[1])
+		if relErr != nil {
+			err = relErr
+			return link
+		}
+
+		if rel == "" {
+			return link
+		}
+
+		if m[2] == "" {
+			return rel
+		}
+
+		symbol, ok := pkg.Symbol(m[2])
+		if !ok {
+			return rel
+		}
+
+		anchor, hrefErr := out.localHref(format.Chain(out.format), out.translate(symbol.HeaderText))
+		if hrefErr != nil {
+			err = hrefErr
+			return link
+		}
+
+		// Formats without anchors link to the file as a whole
+		if !strings.HasPrefix(anchor, "#") {
+			return rel
+		}
+
+		return rel + anchor
+	})
+
+	return text, err
+}
@@ -0,0 +1,109 @@
+package gomarkdoc
+
+import (
+	"fmt"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+type (
+	// Degradation identifies a construct in the documentation of a package
+	// that the renderer's format can't represent, along with the fallback
+	// used in its place. Symbol holds the key of the symbol the construct
+	// belongs to (see lang.Symbol.Key), or is empty for constructs of the
+	// package itself.
+	Degradation struct {
+		Package   string
+		Symbol    string
+		Construct string
+		Feature   format.Feature
+		Fallback  string
+	}
+
+	// DegradationHandler receives the degradations found when rendering a
+	// file.
+	DegradationHandler func(d Degradation)
+)
+
+// WithDegradationHandler reports each construct that the format can't
+// represent to the provided handler whenever a file is rendered, so that
+// authors can find where the quality of their documentation is degraded. The
+// constructs used by the default templates are considered.
+func WithDegradationHandler(handler DegradationHandler) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.degradationHandler = handler
+		return nil
+	}
+}
+
+// reportDegradations passes the degradations in the documentation of the
+// file's packages to the degradation handler, if there is one.
+func (out *Renderer) reportDegradations(file *lang.File) {
+	if out.degradationHandler == nil {
+		return
+	}
+
+	details := format.Degradation(out.format, format.DetailsFeature)
+	table := format.Degradation(out.format, format.TableFeature)
+
+	for _, pkg := range file.Packages {
+		report := func(symbol, construct string, feature format.Feature, fallback string) {
+			if fallback == "" {
+				return
+			}
+
+			out.degradationHandler(Degradation{
+				Package:   pkg.ImportPath(),
+				Symbol:    symbol,
+				Construct: construct,
+				Feature:   feature,
+				Fallback:  fallback,
+			})
+		}
+
+		reportExamples := func(symbol string, examples []*lang.Example) {
+			if out.examplesFile == "" && len(examples) > 0 {
+				report(symbol, "examples", format.DetailsFeature, details)
+			}
+		}
+
+		reportFunc := func(symbol string, fn *lang.Func) {
+			if out.includeSource {
+				report(symbol, "source", format.DetailsFeature, details)
+			}
+
+			reportExamples(symbol, fn.Examples())
+		}
+
+		reportExamples("", pkg.Examples())
+
+		for _, fn := range pkg.Funcs() {
+			reportFunc(fn.Name(), fn)
+		}
+
+		for _, typ := range pkg.Types() {
+			if out.includeSource {
+				report(typ.Name(), "source", format.DetailsFeature, details)
+			}
+
+			if out.fieldTable && len(typ.Fields()) > 0 {
+				report(typ.Name(), "field table", format.TableFeature, table)
+			}
+
+			reportExamples(typ.Name(), typ.Examples())
+
+			for _, fn := range typ.Funcs() {
+				reportFunc(fn.Name(), fn)
+			}
+
+			for _, m := range typ.Methods() {
+				reportFunc(fmt.Sprintf("%s.%s", typ.Name(), m.Name()), m)
+			}
+		}
+
+		if len(out.packageTablePackages(pkg)) > 0 {
+			report("", "package table", format.TableFeature, table)
+		}
+	}
+}
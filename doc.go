@@ -7,13 +7,13 @@
 // Command Line Usage
 //
 // If you want to use this package as a command-line tool, you can install the
-// command by running the following on go 1.16+:
+// command by running the following on go 1.25+:
 //
 //	go install github.com/princjef/gomarkdoc/cmd/gomarkdoc@latest
 //
-// For older versions of go, you can install using the following method instead:
-//
-//	GO111MODULE=on go get -u github.com/princjef/gomarkdoc/cmd/gomarkdoc
+// Packages are loaded with golang.org/x/tools/go/packages, which has to
+// understand the output of the go tool in use, so gomarkdoc requires a go
+// release that is still supported.
 //
 // The command line tool supports configuration for all of the features of the
 // importable package:
@@ -52,6 +52,14 @@
 //
 //	gomarkdoc --output doc.md .
 //
+// The --version flag prints the version of gomarkdoc alone. Add
+// --version-format full to also print the commit it was built from, the build
+// date, the Go version and the checksum of the module, or --version-format
+// json to print the same details as a JSON object for tooling that records the
+// versions of tools:
+//
+//	gomarkdoc --version --version-format json
+//
 // Package Specifiers
 //
 // The gomarkdoc tool supports generating documentation for both local packages
@@ -83,6 +91,23 @@
 //
 //	gomarkdoc --output '{{.Dir}}/README.md' ./...
 //
+// Package patterns follow the same rules as the go tool. A ... wildcard may
+// appear anywhere in a local path (e.g. ./cmd/... or ./.../internal), and
+// patterns of import paths such as std, all or golang.org/x/tools/... are
+// resolved with the go tool against the standard library and the modules in
+// your build list. Packages matched by an import path pattern are treated as
+// local packages when they live beneath the current directory:
+//
+//	gomarkdoc --output 'docs/{{.ImportPath}}.md' github.com/org/lib/...
+//
+// If the output template produces paths that differ only by case (for example
+// when packages live in directories named Foo and foo), those files would
+// overwrite each other on case-insensitive filesystems. By default gomarkdoc
+// fails in this situation. Setting --case-collisions to rename disambiguates
+// the paths by adding a numeric suffix to the file name instead:
+//
+//	gomarkdoc --case-collisions rename --output '{{.Dir}}/README.md' ./...
+//
 // You can see all of the data available to the output template in the
 // PackageSpec struct in the github.com/princjef/gomarkdoc/cmd/gomarkdoc
 // package.
@@ -127,12 +152,102 @@
 //
 //	- import:  generates the import code used to pull in a package.
 //
+//	- examples: generates the separate examples file for a file containing
+//	            one or more packages when examples are split from the main
+//	            documentation.
+//
+//	- typeparams: generates the list of type parameters and their
+//	              constraints for a generic type or function.
+//
+//	- contents: generates the table of contents at the top of a file
+//	            rendered with --single-file, linking to the section of each
+//	            package.
+//
+//	- frontmatter: generates content at the very top of each file and
+//	               examples file, before the generated code notice and the
+//	               Header. It renders nothing unless it is overridden.
+//
 // Overriding with the -t option uses a key-vaule pair mapping a template name
 // to the file containing the contents of the override template to use.
 // Specified template files must exist:
 //
 //	gomarkdoc -t package=custom-package.gotxt -t doc=custom-doc.gotxt .
 //
+// Override templates can link between the documentation of different
+// packages regardless of how the Output files are laid out. The outputFile
+// template function provides the Output file for a package's import path,
+// and relativeOutputPath provides the path from the Output file of one package
+// to that of another:
+//
+//	{{ relativeOutputPath .ImportPath "github.com/org/repo/other" }}
+//
+// Static site generators usually read metadata such as the title of a page
+// from front matter at the very top of the file. Rather than overriding the
+// whole file template, override the frontmatter template, which is passed the
+// same data as the file template. The repository of each package is available
+// through its Repo method. The template should end with a newline:
+//
+//	gomarkdoc -t frontmatter=frontmatter.gotxt -o '{{.Dir}}/README.md' ./...
+//
+// With frontmatter.gotxt containing:
+//
+//	---
+//	{{- with index .Packages 0 }}
+//	title: {{ .Name }}
+//	{{- with .Repo }}
+//	editUrl: {{ .Remote }}/edit/{{ .DefaultBranch }}
+//	{{- end }}
+//	{{- end }}
+//	---
+//
+// Templates can pull in the contents of other files, such as usage snippets
+// or badges maintained alongside the code, with the include function. Paths
+// are relative to the root of the git repository, or to the working directory
+// outside of one, and files outside of that directory can't be included.
+// The --cache flag records the included files, so Output files are generated
+// again when they change:
+//
+//	{{ include "docs/badges.md" }}
+//
+// Templates can also record how the documentation was generated. The
+// repository of the packages in a file is available through its Repo method,
+// and the generation function provides the version of gomarkdoc, the commit
+// checked out in the repository and the options of the run, such as
+// .Options.Format. As these change without any change to the code, combining
+// them with the --check flag is not recommended:
+//
+//	{{- with generation }}
+//	Generated from {{ .Commit }} by gomarkdoc {{ .Version }}
+//	{{- end }}
+//	{{- with .Repo }} ({{ .Remote }}, branch {{ .DefaultBranch }}){{ end }}
+//
+// Header, footer and template files don't need to exist on disk. Passing - as
+// the file name reads the contents from stdin, and http:// or https:// URLs are
+// downloaded when the command runs. This lets CI pipelines inject generated
+// content such as build numbers without writing temporary files:
+//
+//	echo "Build $BUILD_NUMBER" | gomarkdoc --header-file - -o README.md .
+//
+// Heavily customized templates need to be kept in step with the defaults as
+// gomarkdoc is upgraded. The templates diff subcommand compares each override
+// configured with the -t and --template-file options against the default
+// template shipped with the current version:
+//
+//	gomarkdoc templates diff -t package=custom-package.gotxt
+//
+// An override can record the digest of the default template it was copied
+// from by including a comment such as {{/* gomarkdoc:base 1a2b3c4d5e6f */}}.
+// The digest to use is printed by the subcommand, which highlights the
+// overrides whose default template has changed since.
+//
+// When developing an override, the --debug-templates flag wraps the output of
+// each template in <!-- gomarkdoc:template NAME --> comments so that it is
+// clear which template produced which part of the documentation. Template
+// errors are reported with the name and line of the template that failed along
+// with the symbol it was rendering:
+//
+//	gomarkdoc --debug-templates -t func=custom-func.gotxt -o README.md .
+//
 // Additional Options
 //
 // As with the godoc tool itself, only exported symbols will be shown in
@@ -141,6 +256,238 @@
 //
 //	gomarkdoc -u -o README.md .
 //
+// The rest of the documentation of each package can be expanded or trimmed
+// with the following flags:
+//
+//   - --opaque-types: documents the exported methods of the unexported types
+//     that exported functions accept or return, such as opaque handles,
+//     beneath each function that uses the type. It is on by default; pass
+//     --opaque-types=false to leave them out, as releases before v1.2.0 did.
+//
+//   - --external-tests: documents the other symbols declared in black-box
+//     test files (package foo_test), such as fixtures and helpers that are
+//     shared with consumers. Examples in those files are always included.
+//
+//   - --fuzz-targets: lists the fuzz targets of the package, along with the
+//     doc comments describing what each of them exercises, in a Fuzzing
+//     section.
+//
+//   - --generate-directives: lists the //go:generate directives of the
+//     package in a Code Generation section, so that readers know which files
+//     are generated and how to regenerate them.
+//
+//   - --notes: lists the markers whose comments, written in the form
+//     MARKER(uid): body, are collected into a Notes section at the end of
+//     each package. The default list is BUG, as with godoc, and an empty
+//     value leaves notes out entirely.
+//
+//   - --omit-deprecated: leaves out symbols with a paragraph beginning with
+//     "Deprecated: " in their documentation. Otherwise they are flagged as
+//     deprecated in the index and their deprecation note is highlighted.
+//
+//   - --implements: lists the interfaces that each type implements, either
+//     directly or through a pointer to the type, out of those declared in the
+//     package and in the packages of the same module that it imports.
+//     --implements-stdlib also includes well-known interfaces from the
+//     standard library such as fmt.Stringer and io.Reader.
+//
+//   - --const-values: shows the resolved values of constants defined using
+//     iota or other constant expressions beneath their declarations. Variables
+//     initialized with a literal or a sentinel error, such as
+//     errors.New("not found"), are listed with their values too.
+//
+//   - --external-links: lists the types from the standard library and other
+//     modules that appear as plain text within each function signature and
+//     type declaration, with a link to their documentation on pkg.go.dev.
+//
+//   - --field-table: adds a table to each struct type listing the name, type,
+//     json, yaml and env struct tags, and doc comment of each of its fields,
+//     in the formats that support tables.
+//
+//   - --include-source: includes the full source code of each type and func
+//     declaration in a collapsible block, for documentation that is read
+//     offline, away from the repository that source links point to.
+//
+//   - --no-import, --no-index, --no-examples, --no-constants, --no-variables
+//     and --no-notes: hide the corresponding parts of each package's
+//     documentation without overriding the templates that render them.
+//     --no-source-links keeps symbol names as plain text instead of linking
+//     them to their source code.
+//
+// For example:
+//
+//	gomarkdoc --fuzz-targets --notes BUG,TODO,SECURITY --const-values --no-index -o README.md .
+//
+// Packages are loaded with the go tool, so modules, workspaces, vendored
+// packages, cgo and the --tags in use are resolved in the same way as a
+// regular build. The --implements, --implements-stdlib and --const-values
+// flags also type check the package, reading the types of its imports from the
+// export data compiled for them.
+//
+// Individual declarations are controlled with directives in their doc
+// comments. Any declaration can be left out, even if it is exported, with a
+// //gomarkdoc:ignore directive, which can also be added to the doc comment or
+// line comment of a struct field or interface method to hide just that member.
+// Top-level funcs and types can be documented together under a heading of
+// their own with a //gomarkdoc:category directive. Categories follow the
+// declarations that don't have one, sorted by name, and the funcs and methods
+// documented with a type stay with it:
+//
+//	// Handle is an implementation detail of the package.
+//	//
+//	//gomarkdoc:ignore
+//	type Handle struct{}
+//
+//	// Dial opens a connection to the address.
+//	//
+//	//gomarkdoc:category Networking
+//	func Dial(addr string) (*Conn, error)
+//
+// Ordering and Language
+//
+// As with godoc, declarations are documented in alphabetical order by default.
+// To follow the order of the source files instead, pass --order source, which
+// interleaves funcs and types as they appear in the source, or --order kind,
+// which keeps declarations grouped by kind but in source order within each
+// kind. The methods of each type follow the same order unless --method-order
+// is set to alphabetical, source or exported. The last lists exported methods
+// before unexported ones when --include-unexported is used, each in source
+// order. Pass --group-receivers to list the methods with a value receiver
+// before those with a pointer receiver:
+//
+//	gomarkdoc --order source --method-order source --group-receivers -o README.md .
+//
+// Functions are documented with the type they construct when the type is the
+// only one of the package among their results, as godoc does. Factory
+// functions that follow another convention can be grouped with their types by
+// name using --constructor-prefix and --constructor-suffix, which match
+// functions such as MakeWidget and WidgetFromFile for the type Widget.
+// Pass --ungroup-constructors to list every function at the top level instead:
+//
+//	gomarkdoc --constructor-prefix Make --constructor-prefix Open -o README.md .
+//
+// The headings and labels emitted by the default templates, such as Index,
+// Constants and Example, are in English. The --locale flag translates them
+// using one of the built-in translations (de, es or fr). For other languages,
+// or to adjust individual translations, list them in the translations section
+// of the configuration file, keyed by the English text. Custom templates can
+// translate their own text in the same way with the tr function:
+//
+//	gomarkdoc --locale de -o README.md .
+//
+//	translations:
+//	  Index: Indeks
+//	  Constants: Stałe
+//
+// Selecting Packages
+//
+// Recursive paths never descend into hidden directories or directories named
+// vendor, testdata or node_modules, and they skip symlinks to directories and
+// main packages, which have no API to import. Packages located within an
+// internal directory can only be imported by the code that shares the
+// internal directory's parent, so their documentation is marked as internal.
+// Recursive runs can be adjusted with the following flags:
+//
+//   - --include-main: documents main packages as commands. In place of an
+//     import statement, the documentation of a command shows how to install
+//     it, followed by its package comment and lists of the flags it defines
+//     with the flag or github.com/spf13/pflag packages and the commands it
+//     declares with github.com/spf13/cobra. Only flags and commands declared
+//     with literal names in the main package itself are found.
+//
+//   - --exclude-internal: leaves internal packages out. Internal packages that
+//     are named explicitly are still documented.
+//
+//   - --exclude: leaves out the directories matching a glob, which is matched
+//     against each directory's path relative to the working directory. A **
+//     matches any number of directories, and the directories beneath a match
+//     are excluded as well. Patterns prefixed with re: are regular expressions
+//     instead.
+//
+//   - --skip-dirs: replaces the list of directory names that are never
+//     descended into with globs matched against the name of each directory,
+//     so include the defaults you want to keep.
+//
+//   - --follow-symlinks: documents the packages behind symlinks. Each
+//     directory is visited only once, so a link that points back up the tree
+//     doesn't send the walk into a loop.
+//
+// For example:
+//
+//	gomarkdoc --include-main --exclude '**/mocks' --skip-dirs '.*' --skip-dirs vendor --skip-dirs third_party -o '{{.Dir}}/README.md' ./...
+//
+// If you would like to include files that are part of a build tag, you can
+// specify build tags with the --tags flag. Tags are also supported through
+// GOFLAGS, though command line and configuration file definitions override tags
+// specified through GOFLAGS.
+//
+//	gomarkdoc --tags sometag .
+//
+// Symbols declared in files with build constraints, whether from a //go:build
+// line or from a GOOS or GOARCH suffix such as _linux.go, are annotated with
+// the constraints of their file so that platform-specific APIs are clearly
+// labeled. Examples in test files are subject to the same build tags, so
+// examples that require tags, such as integration examples that need network
+// access, are left out unless their tags are provided. To document them
+// anyway, add the --tagged-examples flag, which annotates each of them with
+// the tags it requires.
+//
+// By default, packages are documented as they build for the platform gomarkdoc
+// runs on, so APIs for other platforms are left out. To document several
+// platforms at once, list them with --platforms in the form GOOS/GOARCH,
+// optionally followed by a colon and build tags separated by plus signs. The
+// package is loaded by the go tool with GOOS and GOARCH set to each platform
+// and the results are merged, and symbols that are only declared on some of
+// the platforms are annotated with those platforms in place of their build
+// constraints:
+//
+//	gomarkdoc --platforms linux/amd64,darwin/arm64,windows/amd64 -o README.md .
+//
+// Directories of Go files that are neither in a Go module nor in the GOPATH,
+// such as scratch code or teaching materials, can also be documented. Since
+// there is no way to determine an import path for them, the --import-path flag
+// provides one for the current directory. Packages in subdirectories are given
+// import paths beneath it:
+//
+//	gomarkdoc --import-path example.com/scratch -o '{{.Dir}}/README.md' ./...
+//
+// Writing Output
+//
+// The documentation of a run can be laid out in other ways than one file per
+// Output path:
+//
+//   - --single-file: collects the documentation of all of the packages into
+//     one document in place of --output. The file starts with a table of
+//     contents linking to the section of each package, and headers that
+//     appear in more than one package get the "-1", "-2", etc. anchors that
+//     GitHub assigns to them so that links within each section point to the
+//     right place.
+//
+//   - --output-dir: keeps the documentation out of the source tree in place
+//     of --output. It mirrors the package tree into the directory, writing
+//     each package to an index.md file at the package's path, such as
+//     docs/index.md for the current directory and docs/lang/index.md for
+//     ./lang. Links between the generated files point to their new locations.
+//
+//   - --split-examples: writes the examples for each Output file to a
+//     separate file in the same directory, named after the Output file with
+//     .examples added before the extension (README.examples.md for
+//     README.md). The main documentation links to the examples for each symbol
+//     instead of including them inline.
+//
+// For example:
+//
+//	gomarkdoc --single-file docs/API.md ./...
+//	gomarkdoc --output-dir docs ./...
+//
+// Output files are created with mode 0664 and their directories with mode
+// 0755, both masked by the umask. To control the permissions exactly, set them
+// in octal with --file-mode and --dir-mode. The file mode is also applied to
+// existing files, while the directory mode only applies to the directories
+// gomarkdoc creates:
+//
+//	gomarkdoc --file-mode 0644 --dir-mode 0755 -o '{{.Dir}}/README.md' ./...
+//
 // If you want to blend the documentation generated by gomarkdoc with your own
 // hand-written markdown, you can use the --embed/-e flag to change the
 // gomarkdoc tool into an append/embed mode. When documentation is generated,
@@ -164,12 +511,159 @@
 //
 // 	<!-- gomarkdoc:embed:end -->
 //
-// If you would like to include files that are part of a build tag, you can
-// specify build tags with the --tags flag. Tags are also supported through
-// GOFLAGS, though command line and configuration file definitions override tags
-// specified through GOFLAGS.
+// Embedded documentation starts with a level 1 heading for the package, which
+// clashes with the title of a README that already has one. The
+// --heading-offset flag pushes every heading down by the provided number of
+// levels, so that the documentation starts at ## with an offset of 1. When
+// refreshing embedded documentation, the --annotate-changes flag marks each
+// symbol whose declaration differs from the previously embedded documentation
+// with a short note beneath its header, so that reviewers of the refresh can
+// focus on the API changes:
+//
+//	gomarkdoc -e --heading-offset 1 --annotate-changes -o README.md .
+//
+// Besides the markdown formats, the documentation can be written for other
+// consumers:
+//
+//   - llms: flattens the documentation into token-efficient text in the style
+//     of llms.txt files, for LLM context windows and embedding pipelines.
+//     Links and markdown escaping are dropped and each package is preceded by
+//     a stable "----" delimiter, so writing all packages to a single file
+//     produces one flattened document.
+//
+//   - yaml: dumps the documentation model for each package, including the
+//     names, signatures, declarations, documentation and source positions of
+//     every symbol.
+//
+//   - docfx: writes DocFX ManagedReference metadata so that Go package
+//     documentation can be integrated into existing DocFX sites. Each package
+//     becomes a namespace containing its types, funcs and values.
+//
+// Documentation can be written in multiple formats in a single run by
+// repeating the --Format flag or providing a comma-separated list. Packages are
+// only loaded once regardless of the number of formats. Each format can be
+// written to a different location with the --format-output flag, falling back
+// to --output for formats without their own entry:
+//
+//	gomarkdoc --Format llms -o llms.txt ./...
+//	gomarkdoc --Format github,yaml --format-output yaml=api.yml -o '{{.Dir}}/README.md' ./...
+//
+// Not every format can represent every construct. For example, plain markdown
+// has no collapsible blocks and no tables. Whenever documentation is rendered
+// using a simpler construct or left out for this reason, a warning is logged
+// with the affected package, symbol and fallback, so that templates can be
+// adjusted where the degraded output isn't acceptable. Azure DevOps wikis
+// render their own table of contents for a page using the [[_TOC_]] macro, so
+// with the azure-devops format, the --wiki-toc flag emits this macro at the
+// top of each file in place of the generated index.
+//
+// If your generated files are checked by a markdown linter, the
+// --normalize-whitespace flag cleans up the whitespace of the output for the
+// listed formats. Trailing whitespace is removed, consecutive blank lines are
+// collapsed and each file ends with a single newline. To satisfy the default
+// ruleset of markdownlint, the --lint-profile markdownlint option goes
+// further. Headers only increase one level at a time, every code block
+// declares a language, collapsible sections are rendered as headers instead of
+// inline HTML and paragraphs are wrapped at 80 characters where possible:
+//
+//	gomarkdoc --normalize-whitespace github --lint-profile markdownlint -o README.md .
+//
+// Upgrading gomarkdoc can change the anchors and section layout of the
+// generated documentation. To upgrade without regenerating and reviewing all of
+// the checked-in documentation at once, the --output-compat flag reverts the
+// changes to the default output that were introduced after an older release:
+//
+//	gomarkdoc --output-compat v1.1.0 -o README.md .
+//
+// Only the following changes from v1.2.0 are reverted, so other differences
+// from the older release remain. Features that are enabled explicitly are
+// unaffected:
+//
+//   - Azure DevOps anchors are derived from the plain text of linked headers
+//   - internal packages are annotated with the packages that can import them
+//   - notes such as BUG(uid) are rendered in a Notes section
+//   - symbols are annotated with the build constraints of their files
+//   - the exported methods of unexported types used by exported functions are
+//     documented
+//   - the union elements of constraints are listed with links to each term
+//   - source links of funcs span the full declaration, including the body
+//   - struct and interface types written on a single line are expanded in
+//     declarations and signatures
+//
+// Links and Anchors
+//
+// Links to headers within a file rely on the anchors that the renderer
+// generates for them, which differ between renderers. By default, anchors are
+// generated the way the renderer targeted by the format does. Documentation
+// rendered elsewhere, such as on a Docusaurus or Hugo site, can use the
+// --anchor-style flag to pick the github or azure-devops style instead, or the
+// explicit style to add an <a id> tag before each header for links to point
+// to, regardless of how the renderer generates anchors. For anything else, the
+// --anchor-template flag generates anchors with a template. The template is
+// passed the .Text of the header and its .Plain text with any markdown
+// removed, and can use the lower, upper, trim, replace and slug functions:
+//
+//	gomarkdoc --anchor-style explicit -o '{{.Dir}}/README.md' ./...
+//	gomarkdoc --anchor-template '{{ .Plain | lower | replace " " "_" }}' -o README.md .
+//
+// The links of the documentation can be adjusted with the following flags:
+//
+//   - --reference-links: emits reference-style links instead of inline ones,
+//     with the link definitions collected at the bottom of each file. This
+//     keeps the raw markdown readable and means that only the definitions
+//     change when a URL does.
+//
+//   - --cross-package-links: links to the documentation generated in the
+//     same run for the other packages of the module, relative to each Output
+//     file, instead of to their documentation on pkg.go.dev.
+//
+//   - --subpackages: links each package to its immediate child packages,
+//     mirroring the directory listing of pkg.go.dev.
+//
+//   - --package-table: adds a Packages section to the documentation of the
+//     module root package with a table of the other packages documented in
+//     the run, their synopses and links to their documentation. Combined with
+//     embed mode, this keeps a package catalog in the root README up to date.
+//
+//   - --playground: adds a link beneath each standalone example that runs it
+//     on the Go Playground, for public modules. The code for each example is
+//     shared with the playground when the documentation is written, so it must
+//     be able to reach https://go.dev. Check mode and --preview never share
+//     code; they derive each link from the code of the example instead.
+//     Examples with an "Output:" comment include the expected output after the
+//     example code either way.
+//
+// For example:
+//
+//	gomarkdoc --cross-package-links --package-table -e -o '{{.Dir}}/README.md' ./...
+//
+// Tools that link into the generated documentation, such as IDE plugins, link
+// checkers and documentation portals, can use the --anchors flag to write a
+// JSON file mapping each documented package to its output file and each of its
+// symbols to the anchor of its documentation, rather than reimplementing the
+// anchor rules of each format. Methods are keyed as Type.Method:
+//
+//	gomarkdoc --anchors docs-anchors.json -o '{{.Dir}}/README.md' ./...
 //
-//	gomarkdoc --tags sometag .
+// Some features of gomarkdoc rely on being able to detect information from the
+// git repository containing the project. Since individual local git
+// repositories may be configured differently from person to person, you may
+// want to manually specify the information for the repository to remove any
+// inconsistencies. This can be achieved with the --repository.url,
+// --repository.default-branch and --repository.path options. For example, this
+// repository would be configured with:
+//
+//	gomarkdoc --repository.url "https://github.com/princjef/gomarkdoc" --repository.default-branch master --repository.path / -o README.md .
+//
+// In a repository containing multiple projects, the remote may only host part
+// of the repository, such as a mirror of the Go project in a mixed-language
+// monorepo. The --repository.link-root option sets the path within the
+// repository that source links are relative to, so that --repository.path can
+// continue to describe the location of the working directory:
+//
+//	gomarkdoc --repository.path /go/service --repository.link-root /go -o README.md .
+//
+// Checking and Continuous Integration
 //
 // You can also run gomarkdoc in a verification mode with the --check/-c flag.
 // This is particularly useful for continuous integration when you want to make
@@ -179,21 +673,159 @@
 //
 //	gomarkdoc -o README.md -c .
 //
+// Every file is checked before the command exits, and each one that is out of
+// date or missing is listed in the error, along with any files in the
+// --manifest that are no longer generated, so that all of them can be fixed in
+// a single pass. To feed the results into code scanning dashboards or bots,
+// use --check-format to write them to stdout as JSON or SARIF instead. Each
+// finding includes the file, the reason it doesn't match, the hash of the
+// expected contents and a summary of the lines that regenerating it would add
+// and remove:
+//
+//	gomarkdoc -o '{{.Dir}}/README.md' -c --check-format sarif ./... > docs.sarif
+//
+// When running within GitHub Actions, the files that don't match in check mode
+// and the issues found by the lint command are also written to stdout as
+// ::error workflow commands, so that they show up inline on the diff of a pull
+// request. Use --annotations github to emit them elsewhere, or --annotations
+// none to turn them off. Annotations aren't written along with a JSON or SARIF
+// --check-format report, as it occupies stdout.
+//
+// The exit code tells the outcome of a run apart: 0 on success, 1 for usage or
+// configuration errors and other failures, 2 if a package failed to load and 3
+// if the documentation is out of date in check mode. The lint command exits
+// with 4 when it finds issues. By default, gomarkdoc stops as soon as any
+// package fails to load or any Output file fails to render. For large runs
+// such as nightly builds of an entire repository, the --keep-going flag skips
+// past these failures instead and reports all of them at the end of the run,
+// using the code of the most severe one, in the order 1, 2, 3.
+//
+// Large repositories can keep their runs fast with the following flags:
+//
+//   - --manifest: records the paths and content hashes of every generated
+//     file. When a manifest is provided in check mode, the generated
+//     documentation is validated against the manifest, and the hash of each
+//     Output file is compared as well to catch files that were edited or
+//     deleted since the manifest was written. Files listed in the manifest
+//     that are no longer generated are reported too.
+//
+//   - --cache: records a hash of the inputs of each Output file along with a
+//     hash of its contents. The inputs are the package sources, the options
+//     and the configuration files they come from, including that of a preset,
+//     the files referenced by those options and the commit checked out in the
+//     repository. Packages whose Output files have the same inputs and
+//     contents as the last run aren't loaded at all, which makes running with
+//     --check in a pre-commit hook nearly instant. The cache is ignored with
+//     --anchors, which needs all of the packages.
+//
+//   - --shard: splits the run across parallel jobs, each of which processes a
+//     deterministic subset of the packages. Packages that share an Output file
+//     are always assigned to the same shard. When combined with --manifest,
+//     each shard should write to its own manifest file.
+//
+//   - --since: restricts the run to the local packages with files that
+//     changed since a git revision, including uncommitted and untracked
+//     changes. Adding --since-deps also processes the packages that import a
+//     changed package, which is useful when documentation links to other
+//     packages. Entries for the other packages are kept when writing a
+//     --manifest.
+//
+// For example:
+//
+//	gomarkdoc --manifest docs.sum -o '{{.Dir}}/README.md' ./...
+//	gomarkdoc --cache .gomarkdoc-cache.json --shard 1/3 -o '{{.Dir}}/README.md' -c ./...
+//	gomarkdoc --since origin/main --since-deps -o '{{.Dir}}/README.md' ./...
+//
+// Publishing platforms that enforce accessibility rules can be accommodated
+// with the --audit flag, which checks each Output file for links without
+// text, headings that skip levels and images without alt text. The run fails
+// with a list of the issues found in each file:
+//
+//	gomarkdoc --audit -o README.md .
+//
+// Subcommands
+//
+// Alongside generating documentation, gomarkdoc provides the following
+// subcommands:
+//
+//   - lint: reports gaps in documentation coverage instead of generating
+//     anything: exported symbols without a doc comment (missing-doc),
+//     packages without a package doc comment (missing-package-doc) and doc
+//     comments that don't start with the name of their symbol (doc-prefix).
+//     Each issue is printed on its own line with its file and line number.
+//
+//   - stats: prints a table with the number of exported symbols in each
+//     package, how many of them are documented, the share of funcs, types and
+//     methods with examples and the total number of words in the doc
+//     comments, to track documentation health over time. Add --json to record
+//     the numbers in a machine-readable form.
+//
+//   - diff: compares the exported API of the packages at two git revisions
+//     and prints a markdown report of the symbols that were added, removed or
+//     changed, along with any doc comments whose text changed, for release
+//     notes. Only local package paths can be compared.
+//
+//   - preview-diff: accepts the same flags as gomarkdoc itself but prints a
+//     unified diff between the generated documentation and the files
+//     currently on disk instead of writing anything.
+//
+//   - clean: removes the generated files that no longer belong to a package
+//     after packages are renamed or deleted, found by evaluating the output
+//     for every directory in the package tree and by consulting the
+//     --manifest if there is one. Only files carrying the "Code generated by
+//     gomarkdoc" comment or listed in the manifest are removed, so
+//     hand-written files are left alone. Because generating documentation
+//     rewrites the manifest, run clean before regenerating when relying on
+//     it. Use --dry-run to see what would be removed.
+//
+//   - serve: loads the packages once and answers JSON queries over HTTP, so
+//     that tools such as internal developer portals can query the
+//     documentation model directly instead of parsing generated markdown. It
+//     lists packages at /packages, provides a package's full model at
+//     /packages/<import path>, a single symbol at
+//     /symbol?package=<import path>&name=<name> and symbols whose names match
+//     a search at /search?q=<text>.
+//
+//   - completion: prints a shell completion script for bash, zsh, fish or
+//     powershell. It completes the values of flags such as --format and the
+//     template names accepted by --template.
+//
+// For example:
+//
+//	gomarkdoc lint ./...
+//	gomarkdoc stats --json ./... > doc-stats.json
+//	gomarkdoc diff v1.0.0 HEAD ./... > API_CHANGES.md
+//	gomarkdoc preview-diff -o '{{.Dir}}/README.md' ./...
+//	gomarkdoc clean --dry-run -o '{{.Dir}}/README.md' ./...
+//	gomarkdoc serve --addr localhost:8080 ./...
+//	source <(gomarkdoc completion bash)
+//
+// All lint rules are checked by default. Use --enable to only check some of
+// them and --disable to skip some, or set the lint.enable and lint.disable
+// lists in the configuration file:
+//
+//	lint:
+//	  disable:
+//	    - doc-prefix
+//
+// Logging
+//
 // If you're experiencing difficulty with gomarkdoc or just want to get more
 // information about how it's executing underneath, you can add -v to show more
 // logs. This can be chained a second time to show even more verbose logs:
 //
 //	gomarkdoc -vv -o README.md .
 //
-// Some features of gomarkdoc rely on being able to detect information from the
-// git repository containing the project. Since individual local git
-// repositories may be configured differently from person to person, you may
-// want to manually specify the information for the repository to remove any
-// inconsistencies. This can be achieved with the --repository.url,
-// --repository.default-branch and --repository.path options. For example, this
-// repository would be configured with:
+// Logs are always written to stderr, so redirecting the documentation printed
+// to stdout never captures warnings into the generated file. To silence the
+// warnings altogether and only log errors, pass --quiet/-q. To aggregate the
+// logs of automated runs, pass --log-format json to emit one JSON object per
+// record with its level, message and time, along with fields such as the
+// directory of the package being processed. At the highest verbosity, the time
+// taken to load each package is recorded as well:
 //
-//	gomarkdoc --repository.url "https://github.com/princjef/gomarkdoc" --repository.default-branch master --repository.path / -o README.md .
+//	gomarkdoc -q ./pkg > doc.md
+//	gomarkdoc --log-format json -vv -o '{{.Dir}}/README.md' ./...
 //
 // Configuring via File
 //
@@ -210,6 +842,86 @@
 // separated by =. Options provided on the command line override those provided
 // in the configuration file if an option is present in both.
 //
+// The configuration is validated before anything is generated. Unknown keys,
+// values of the wrong type and invalid format names are all reported at once
+// rather than being ignored, along with suggestions for likely typos:
+//
+//	gomarkdoc: invalid configuration:
+//	    unknown key includeunexporetd (did you mean includeUnexported?)
+//
+// Like .editorconfig files, configuration files are also discovered in the
+// parent directories of the folder where you invoke gomarkdoc, with files in
+// nearer directories taking precedence. The search stops at the root of the
+// filesystem or at a configuration file that sets root to true. File paths in
+// each file, such as its headerFile, are resolved relative to that file.
+//
+//	root: true
+//	output: "{{.Dir}}/README.md"
+//
+// Configuration files in the subdirectories of the packages being documented
+// override options for the packages in that subtree. Only the options that
+// shape the Output files may be overridden this way: output, formatOutput,
+// template, templateFile, header, headerFile, footer and footerFile. Options
+// set on the command line still take precedence over them:
+//
+//	# internal/api/.gomarkdoc.yml
+//	output: "{{.Dir}}/API.md"
+//	templateFile:
+//	  func: ./templates/func.gotxt
+//
+// Every option can also be set with an environment variable, which is useful
+// in CI systems where a configuration file or a long list of flags would be
+// awkward. The name of the variable is the long name of the option in upper
+// case, with dashes and dots replaced by underscores and a GOMARKDOC_ prefix
+// (e.g. --include-unexported becomes GOMARKDOC_INCLUDE_UNEXPORTED). Lists are
+// separated by spaces and maps are provided as JSON objects. Environment
+// variables override configuration files, including nested ones, but not
+// options provided on the command line:
+//
+//	GOMARKDOC_FORMAT=plain GOMARKDOC_OUTPUT="{{.Dir}}/README.md" gomarkdoc ./...
+//
+// Configuration can also be shared across repositories using preset bundles.
+// A preset bundle is a directory or Go module containing its own .gomarkdoc
+// configuration file, along with any template, header or footer files it
+// references. Adding the extends key to your configuration file layers your
+// local configuration on top of the bundle's configuration:
+//
+//	extends: github.com/org/docs-presets/v2@v2.1.0
+//
+// File paths in the bundle's configuration are resolved relative to the bundle
+// itself. Module bundles are downloaded with the go tool, so they follow the
+// same version query and authentication rules as any other module. A major
+// version alone, as in github.com/org/docs-presets@v2, selects the latest
+// release of that major version. Local bundles are referenced by their path,
+// such as ./docs/preset, and a bundle can't extend another bundle.
+//
+// The configuration file may also list hooks, which are shell commands run
+// before and after the documentation is generated. The paths of the Output
+// files written by the run are provided to each post hook on stdin, one per
+// line, and in the GOMARKDOC_FILES environment variable. Hooks are not run in
+// Check mode.
+//
+//	hooks:
+//	  pre:
+//	    - go generate ./...
+//	  post:
+//	    - xargs prettier --write
+//	    - git add $GOMARKDOC_FILES
+//
+// The vars section of the configuration file holds arbitrary values that are
+// made available to every template through the var function. This lets one set
+// of templates, often shared in a preset bundle, be branded differently in each
+// repository that uses it. Variable names are not case-sensitive, and looking
+// up a variable that isn't set produces no value, so optional variables can be
+// checked with if or with:
+//
+//	vars:
+//	  productName: Widgets
+//	  supportEmail: support@example.com
+//
+//	{{ var "productName" }} is maintained by the platform team.
+//	{{- with var "supportEmail" }} Contact {{ . }} for help.{{ end }}
+//
 // Programmatic Usage
 //
 // While most users will find the command line utility sufficient for their
@@ -223,7 +935,6 @@
 //	package main
 //
 //	import (
-//		"go/build"
 //		"fmt"
 //		"os"
 //
@@ -244,15 +955,10 @@
 //			// handle error
 //		}
 //
-//		buildPkg, err := build.ImportDir(wd, build.ImportComment)
-//		if err != nil {
-//			// handle error
-//		}
-//
-//		// Create a documentation package from the build representation of our
-//		// package.
+//		// Load our package with the go tool and create a documentation
+//		// package from it.
 //		log := logger.New(logger.DebugLevel)
-//		pkg, err := lang.NewPackageFromBuild(log, buildPkg)
+//		pkg, err := lang.LoadPackage(log, wd)
 //		if err != nil {
 //			// handle error
 //		}
@@ -261,6 +967,48 @@
 //		fmt.Println(out.Package(pkg))
 //	}
 //
+// If you only need the examples for a single symbol, such as to show them in
+// search results, SymbolExamples renders just the code of each example:
+//
+//	snippets, err := out.SymbolExamples(pkg, "Type.Method")
+//
+// Programs that keep their settings in their own configuration files can
+// describe the renderer with a Config, which can be unmarshaled from JSON or
+// YAML and is validated when the renderer is created:
+//
+//	var cfg gomarkdoc.Config
+//	if err := json.Unmarshal(data, &cfg); err != nil {
+//		// handle error
+//	}
+//
+//	out, err := gomarkdoc.NewRendererFromConfig(cfg)
+//
+// Organizations that need extra behavior in the command line utility, such as
+// additional publish targets or custom checks, can build their own binary
+// around the command in the cmd package. BuildCommand accepts subcommands,
+// which accept the same flags as the gomarkdoc command, and hooks that adjust
+// the options before they are validated:
+//
+//	command := cmd.BuildCommand(
+//		cmd.WithOptionHook(func(opts *cmd.CommandOptions) error {
+//			opts.LintProfile = "markdownlint"
+//			return nil
+//		}),
+//		cmd.WithSubcommand(func(resolve cmd.ResolveFunc) *cobra.Command {
+//			return &cobra.Command{
+//				Use: "publish [package ...]",
+//				RunE: func(c *cobra.Command, args []string) error {
+//					opts, paths, err := resolve(args)
+//					if err != nil {
+//						return err
+//					}
+//
+//					// generate and publish the documentation
+//				},
+//			}
+//		}),
+//	)
+//
 // Examples
 //
 // This project uses itself to generate the README files in
@@ -0,0 +1,75 @@
+package gomarkdoc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// fieldTableTags lists the struct tag keys given a column in field tables.
+// Columns are only included when at least one field uses the key.
+var fieldTableTags = []string{"json", "yaml", "env"}
+
+// WithFieldTable adds a table to the documentation of each struct type listing
+// the name, type, json/yaml/env struct tags and doc comment of each of its
+// fields. Tables are only rendered for formats that implement
+// format.TableFormat.
+func WithFieldTable() RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.fieldTable = true
+		return nil
+	}
+}
+
+// renderFieldTable renders the table of the provided fields, or the empty
+// string if field tables are disabled or unsupported by the format.
+func (out *Renderer) renderFieldTable(fields []*lang.Field) (string, error) {
+	if !out.fieldTable || len(fields) == 0 {
+		return "", nil
+	}
+
+	t, ok := out.format.(format.TableFormat)
+	if !ok {
+		return "", nil
+	}
+
+	var tags []string
+	for _, tag := range fieldTableTags {
+		for _, field := range fields {
+			if field.Tag(tag) != "" {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+
+	header := []string{"Field", "Type"}
+	for _, tag := range tags {
+		header = append(header, fmt.Sprintf("%s Tag", tag))
+	}
+	header = append(header, "Description")
+
+	rows := make([][]string, len(fields))
+	for i, field := range fields {
+		typ, err := field.Type()
+		if err != nil {
+			return "", err
+		}
+
+		row := []string{t.Escape(field.Name()), t.Escape(typ)}
+		for _, tag := range tags {
+			row = append(row, t.Escape(field.Tag(tag)))
+		}
+
+		rows[i] = append(row, t.Escape(field.Description()))
+	}
+
+	table, err := t.Table(header, rows)
+	if errors.Is(err, format.ErrUnsupported) {
+		return "", nil
+	}
+
+	return table, err
+}
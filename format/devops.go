@@ -15,11 +15,26 @@ import (
 // DevOps's syntax and semantics. See the Azure DevOps documentation for more
 // details about their markdown format:
 // https://docs.microsoft.com/en-us/azure/devops/project/wiki/markdown-guidance?view=azure-devops
-type AzureDevOpsMarkdown struct{}
+type AzureDevOpsMarkdown struct {
+	// WikiTOC emits the Azure DevOps wiki's [[_TOC_]] macro in place of the
+	// generated index, letting the wiki render its own table of contents.
+	WikiTOC bool
+
+	// RawHeaderAnchors derives anchors from the header text as written,
+	// including any markdown such as links, as gomarkdoc did up to v1.1.0.
+	// The resulting anchors don't match the ones generated by Azure DevOps
+	// for linked headers, so this is only intended for keeping the output of
+	// older versions stable.
+	RawHeaderAnchors bool
+}
+
+// devOpsTOCMacro is replaced with a table of contents by Azure DevOps wikis.
+const devOpsTOCMacro = "[[_TOC_]]"
 
-// Bold converts the provided text to bold
+// Bold is rendered the same way as in the PlainMarkdown fallback, so
+// ErrUnsupported is returned.
 func (f *AzureDevOpsMarkdown) Bold(text string) (string, error) {
-	return formatcore.Bold(text), nil
+	return "", ErrUnsupported
 }
 
 // CodeBlock wraps the provided code as a code block and tags it with the
@@ -28,16 +43,16 @@ func (f *AzureDevOpsMarkdown) CodeBlock(language, code string) (string, error) {
 	return formatcore.GFMCodeBlock(language, code), nil
 }
 
-// Header converts the provided text into a header of the provided level. The
-// level is expected to be at least 1.
+// Header is rendered the same way as in the PlainMarkdown fallback, so
+// ErrUnsupported is returned.
 func (f *AzureDevOpsMarkdown) Header(level int, text string) (string, error) {
-	return formatcore.Header(level, formatcore.Escape(text))
+	return "", ErrUnsupported
 }
 
-// RawHeader converts the provided text into a header of the provided level
-// without escaping the header text. The level is expected to be at least 1.
+// RawHeader is rendered the same way as in the PlainMarkdown fallback, so
+// ErrUnsupported is returned.
 func (f *AzureDevOpsMarkdown) RawHeader(level int, text string) (string, error) {
-	return formatcore.Header(level, text)
+	return "", ErrUnsupported
 }
 
 var devOpsWhitespaceRegex = regexp.MustCompile(`\s`)
@@ -47,7 +62,14 @@ var devOpsWhitespaceRegex = regexp.MustCompile(`\s`)
 // generation follows the guidelines here:
 // https://docs.microsoft.com/en-us/azure/devops/project/wiki/markdown-guidance?view=azure-devops#anchor-links
 func (f *AzureDevOpsMarkdown) LocalHref(headerText string) (string, error) {
-	result := strings.ToLower(headerText)
+	// Anchors are derived from the rendered text of the header, so any
+	// markdown such as links must be removed first
+	result := headerText
+	if !f.RawHeaderAnchors {
+		result = formatcore.PlainText(result)
+	}
+
+	result = strings.ToLower(result)
 	result = strings.TrimSpace(result)
 	result = devOpsWhitespaceRegex.ReplaceAllString(result, "-")
 	result = url.PathEscape(result)
@@ -77,12 +99,22 @@ func (f *AzureDevOpsMarkdown) CodeHref(loc lang.Location) (string, error) {
 		relative = loc.Filepath
 	}
 
+	root := string(filepath.Separator)
+	if loc.Repo.LinkRoot != "" {
+		root = loc.Repo.LinkRoot
+	}
+
 	full := filepath.Join(loc.Repo.PathFromRoot, relative)
-	p, err := filepath.Rel(string(filepath.Separator), full)
+	p, err := filepath.Rel(root, full)
 	if err != nil {
 		return "", err
 	}
 
+	// Files outside of the link root can't be linked to
+	if p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+		return "", nil
+	}
+
 	return fmt.Sprintf(
 		"%s?path=%s&version=GB%s&lineStyle=plain&line=%d&lineEnd=%d&lineStartColumn=%d&lineEndColumn=%d",
 		loc.Repo.Remote,
@@ -95,16 +127,16 @@ func (f *AzureDevOpsMarkdown) CodeHref(loc lang.Location) (string, error) {
 	), nil
 }
 
-// Link generates a link with the given text and href values.
+// Link is rendered the same way as in the PlainMarkdown fallback, so
+// ErrUnsupported is returned.
 func (f *AzureDevOpsMarkdown) Link(text, href string) (string, error) {
-	return formatcore.Link(text, href), nil
+	return "", ErrUnsupported
 }
 
-// ListEntry generates an unordered list entry with the provided text at the
-// provided zero-indexed depth. A depth of 0 is considered the topmost level of
-// list.
+// ListEntry is rendered the same way as in the PlainMarkdown fallback, so
+// ErrUnsupported is returned.
 func (f *AzureDevOpsMarkdown) ListEntry(depth int, text string) (string, error) {
-	return formatcore.ListEntry(depth, text), nil
+	return "", ErrUnsupported
 }
 
 // Accordion generates a collapsible content. The accordion's visible title
@@ -131,12 +163,35 @@ func (f *AzureDevOpsMarkdown) AccordionTerminator() (string, error) {
 	return formatcore.GFMAccordionTerminator(), nil
 }
 
-// Paragraph formats a paragraph with the provided text as the contents.
+// Paragraph is rendered the same way as in the PlainMarkdown fallback, so
+// ErrUnsupported is returned.
 func (f *AzureDevOpsMarkdown) Paragraph(text string) (string, error) {
-	return formatcore.Paragraph(text), nil
+	return "", ErrUnsupported
+}
+
+// Table generates a table with the provided header cells and rows of cells.
+func (f *AzureDevOpsMarkdown) Table(header []string, rows [][]string) (string, error) {
+	return formatcore.GFMTable(header, rows), nil
 }
 
 // Escape escapes special markdown characters from the provided text.
 func (f *AzureDevOpsMarkdown) Escape(text string) string {
 	return formatcore.Escape(text)
 }
+
+// TOC generates the wiki's table of contents macro if WikiTOC is enabled.
+// Otherwise, the empty string is returned and the generated index is used.
+func (f *AzureDevOpsMarkdown) TOC() (string, error) {
+	if !f.WikiTOC {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%s\n\n", devOpsTOCMacro), nil
+}
+
+// Fallback provides the format used for the constructs that this format
+// renders no differently, which return ErrUnsupported. Wrap the format with
+// Chain to render them.
+func (f *AzureDevOpsMarkdown) Fallback() Format {
+	return &PlainMarkdown{}
+}
@@ -13,7 +13,7 @@ import (
 func TestBold(t *testing.T) {
 	is := is.New(t)
 
-	var f format.AzureDevOpsMarkdown
+	f := format.Chain(&format.AzureDevOpsMarkdown{})
 	res, err := f.Bold("sample text")
 	is.NoErr(err)
 	is.Equal(res, "**sample text**")
@@ -57,7 +57,7 @@ func TestHeader(t *testing.T) {
 		t.Run(fmt.Sprintf("%s (level %d)", test.text, test.level), func(t *testing.T) {
 			is := is.New(t)
 
-			var f format.AzureDevOpsMarkdown
+			f := format.Chain(&format.AzureDevOpsMarkdown{})
 			res, err := f.Header(test.level, test.text)
 			is.NoErr(err)
 			is.Equal(res, test.result)
@@ -68,7 +68,7 @@ func TestHeader(t *testing.T) {
 func TestHeader_invalidLevel(t *testing.T) {
 	is := is.New(t)
 
-	var f format.AzureDevOpsMarkdown
+	f := format.Chain(&format.AzureDevOpsMarkdown{})
 	_, err := f.Header(-1, "invalid")
 	is.Equal(err.Error(), "format: header level cannot be less than 1")
 }
@@ -87,7 +87,7 @@ func TestRawHeader(t *testing.T) {
 		t.Run(fmt.Sprintf("%s (level %d)", test.text, test.level), func(t *testing.T) {
 			is := is.New(t)
 
-			var f format.AzureDevOpsMarkdown
+			f := format.Chain(&format.AzureDevOpsMarkdown{})
 			res, err := f.RawHeader(test.level, test.text)
 			is.NoErr(err)
 			is.Equal(res, test.result)
@@ -102,6 +102,7 @@ func TestLocalHref(t *testing.T) {
 		"Multiple	 whitespace":   "#multiple--whitespace",
 		"Special(#)%^Characters": "#special%28%23%29%25%5Echaracters",
 		"With:colon":             "#with%3Acolon",
+		"func [Name](https://dev.azure.com/org/project/_git/repo)": "#func-name",
 	}
 
 	for input, output := range tests {
@@ -116,6 +117,29 @@ func TestLocalHref(t *testing.T) {
 	}
 }
 
+func TestLocalHref_rawHeaderAnchors(t *testing.T) {
+	is := is.New(t)
+
+	f := format.AzureDevOpsMarkdown{RawHeaderAnchors: true}
+	res, err := f.LocalHref("func [Name](https://dev.azure.com/org/project/_git/repo)")
+	is.NoErr(err)
+	is.Equal(res, "#func-%5Bname%5D%28https%3A%2F%2Fdev.azure.com%2Forg%2Fproject%2F_git%2Frepo%29")
+}
+
+func TestTOC(t *testing.T) {
+	is := is.New(t)
+
+	var f format.AzureDevOpsMarkdown
+	res, err := f.TOC()
+	is.NoErr(err)
+	is.Equal(res, "")
+
+	f.WikiTOC = true
+	res, err = f.TOC()
+	is.NoErr(err)
+	is.Equal(res, "[[_TOC_]]\n\n")
+}
+
 func TestCodeHref(t *testing.T) {
 	is := is.New(t)
 
@@ -161,7 +185,7 @@ func TestCodeHref_noRepo(t *testing.T) {
 func TestLink(t *testing.T) {
 	is := is.New(t)
 
-	var f format.AzureDevOpsMarkdown
+	f := format.Chain(&format.AzureDevOpsMarkdown{})
 	res, err := f.Link("link text", "https://test.com/a/b/c")
 	is.NoErr(err)
 	is.Equal(res, "[link text](<https://test.com/a/b/c>)")
@@ -170,7 +194,7 @@ func TestLink(t *testing.T) {
 func TestListEntry(t *testing.T) {
 	is := is.New(t)
 
-	var f format.AzureDevOpsMarkdown
+	f := format.Chain(&format.AzureDevOpsMarkdown{})
 	res, err := f.ListEntry(0, "list entry text")
 	is.NoErr(err)
 	is.Equal(res, "- list entry text\n")
@@ -179,7 +203,7 @@ func TestListEntry(t *testing.T) {
 func TestListEntry_nested(t *testing.T) {
 	is := is.New(t)
 
-	var f format.AzureDevOpsMarkdown
+	f := format.Chain(&format.AzureDevOpsMarkdown{})
 	res, err := f.ListEntry(2, "nested text")
 	is.NoErr(err)
 	is.Equal(res, "    - nested text\n")
@@ -188,8 +212,24 @@ func TestListEntry_nested(t *testing.T) {
 func TestListEntry_empty(t *testing.T) {
 	is := is.New(t)
 
-	var f format.AzureDevOpsMarkdown
+	f := format.Chain(&format.AzureDevOpsMarkdown{})
 	res, err := f.ListEntry(0, "")
 	is.NoErr(err)
 	is.Equal(res, "")
 }
+
+func TestAzureDevOpsMarkdown_fallback(t *testing.T) {
+	is := is.New(t)
+
+	// Constructs rendered no differently than in the fallback are left to it
+	var f format.AzureDevOpsMarkdown
+	_, err := f.Bold("sample text")
+	is.Equal(err, format.ErrUnsupported)
+
+	_, err = f.Paragraph("text")
+	is.Equal(err, format.ErrUnsupported)
+
+	res, err := format.Chain(&f).Paragraph("text with * characters")
+	is.NoErr(err)
+	is.Equal(res, "text with \\* characters\n\n")
+}
@@ -5,4 +5,9 @@
 // functions, but not all formats support all of the functions natively. Where
 // possible, a fallback format is provided. See the documentation for the
 // individual formats for more information.
+//
+// Formats may also declare a fallback format by implementing FallbackFormat.
+// Any primitive that returns ErrUnsupported is then rendered using the
+// fallback format instead, which is consulted by wrapping the format with
+// Chain.
 package format
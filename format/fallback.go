@@ -0,0 +1,116 @@
+package format
+
+import (
+	"errors"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// ErrUnsupported is returned by a Format's primitive when the format is
+// unable to represent the requested construct. When a format returns this
+// error and declares a fallback with FallbackFormat, the fallback format is
+// used for the primitive instead.
+var ErrUnsupported = errors.New("format: construct is not supported by this format")
+
+// maxFallbackDepth bounds the length of a fallback chain to protect against
+// formats that fall back to each other.
+const maxFallbackDepth = 16
+
+// FallbackFormat is implemented by formats that defer the primitives they
+// don't support to another format. This allows new formats to implement only
+// the primitives that differ from an existing format, returning
+// ErrUnsupported for the rest.
+type FallbackFormat interface {
+	Format
+
+	// Fallback provides the format to consult for any primitive that returns
+	// ErrUnsupported. A nil return value ends the chain.
+	Fallback() Format
+}
+
+// Chain wraps the provided format so that each primitive consults the
+// format's fallback chain whenever a format in the chain returns
+// ErrUnsupported. If the format does not declare a fallback, the chain simply
+// calls through to the format itself.
+func Chain(f Format) Format {
+	return &chain{f}
+}
+
+type chain struct {
+	format Format
+}
+
+// resolve calls fn for each format in the chain, stopping at the first result
+// that is not ErrUnsupported.
+func (c *chain) resolve(fn func(f Format) (string, error)) (string, error) {
+	f := c.format
+	for i := 0; i < maxFallbackDepth; i++ {
+		res, err := fn(f)
+		if !errors.Is(err, ErrUnsupported) {
+			return res, err
+		}
+
+		fb, ok := f.(FallbackFormat)
+		if !ok || fb.Fallback() == nil {
+			return "", err
+		}
+
+		f = fb.Fallback()
+	}
+
+	return "", errors.New("format: fallback chain is too long")
+}
+
+func (c *chain) Bold(text string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.Bold(text) })
+}
+
+func (c *chain) CodeBlock(language, code string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.CodeBlock(language, code) })
+}
+
+func (c *chain) Header(level int, text string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.Header(level, text) })
+}
+
+func (c *chain) RawHeader(level int, text string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.RawHeader(level, text) })
+}
+
+func (c *chain) LocalHref(headerText string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.LocalHref(headerText) })
+}
+
+func (c *chain) Link(text, href string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.Link(text, href) })
+}
+
+func (c *chain) CodeHref(loc lang.Location) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.CodeHref(loc) })
+}
+
+func (c *chain) ListEntry(depth int, text string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.ListEntry(depth, text) })
+}
+
+func (c *chain) Accordion(title, body string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.Accordion(title, body) })
+}
+
+func (c *chain) AccordionHeader(title string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.AccordionHeader(title) })
+}
+
+func (c *chain) AccordionTerminator() (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.AccordionTerminator() })
+}
+
+func (c *chain) Paragraph(text string) (string, error) {
+	return c.resolve(func(f Format) (string, error) { return f.Paragraph(text) })
+}
+
+// Escape always uses the primary format, as escaping can't report that it is
+// unsupported.
+func (c *chain) Escape(text string) string {
+	return c.format.Escape(text)
+}
@@ -0,0 +1,73 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/matryer/is"
+)
+
+// partialFormat only supports bold text, deferring everything else to its
+// fallback.
+type partialFormat struct {
+	format.PlainMarkdown
+	fallback format.Format
+}
+
+func (f *partialFormat) Bold(text string) (string, error) {
+	return "<b>" + text + "</b>", nil
+}
+
+func (f *partialFormat) Paragraph(text string) (string, error) {
+	return "", format.ErrUnsupported
+}
+
+func (f *partialFormat) Fallback() format.Format {
+	return f.fallback
+}
+
+func TestChain_primary(t *testing.T) {
+	is := is.New(t)
+
+	f := format.Chain(&partialFormat{fallback: &format.GitHubFlavoredMarkdown{}})
+	res, err := f.Bold("text")
+	is.NoErr(err)
+	is.Equal(res, "<b>text</b>")
+}
+
+func TestChain_fallback(t *testing.T) {
+	is := is.New(t)
+
+	f := format.Chain(&partialFormat{fallback: &format.GitHubFlavoredMarkdown{}})
+	res, err := f.Paragraph("some * text")
+	is.NoErr(err)
+	is.Equal(res, "some \\* text\n\n")
+}
+
+func TestChain_noFallback(t *testing.T) {
+	is := is.New(t)
+
+	f := format.Chain(&partialFormat{})
+	_, err := f.Paragraph("text")
+	is.Equal(err, format.ErrUnsupported)
+}
+
+func TestChain_nested(t *testing.T) {
+	is := is.New(t)
+
+	f := format.Chain(&partialFormat{fallback: &partialFormat{fallback: &format.PlainMarkdown{}}})
+	res, err := f.Paragraph("text")
+	is.NoErr(err)
+	is.Equal(res, "text\n\n")
+}
+
+func TestChain_cycle(t *testing.T) {
+	is := is.New(t)
+
+	a := &partialFormat{}
+	b := &partialFormat{fallback: a}
+	a.fallback = b
+
+	_, err := format.Chain(a).Paragraph("text")
+	is.Equal(err.Error(), "format: fallback chain is too long")
+}
@@ -62,3 +62,77 @@ type Format interface {
 	// Escape escapes special markdown characters from the provided text.
 	Escape(text string) string
 }
+
+// TOCFormat is implemented by formats which can defer the table of contents
+// of a document to the platform that renders it. When TOC returns a non-empty
+// value, it is emitted at the top of each file in place of the index that is
+// otherwise generated for each package.
+type TOCFormat interface {
+	Format
+
+	// TOC generates the macro that the rendering platform replaces with a
+	// table of contents, or the empty string if the generated index should be
+	// used instead.
+	TOC() (string, error)
+}
+
+// NormalizingFormat is implemented by formats which adjust each document as a
+// whole once it has been rendered, such as to satisfy the rules of a linter.
+type NormalizingFormat interface {
+	Format
+
+	// Normalize provides the adjusted text of the fully rendered document.
+	Normalize(text string) string
+}
+
+// TableFormat is implemented by formats which support tables. Constructs that
+// can be presented as a table, such as the fields of a struct, are omitted for
+// formats which don't implement it.
+type TableFormat interface {
+	Format
+
+	// Table generates a table with the provided header cells and rows of
+	// cells. Cells are expected to be formatted already.
+	Table(header []string, rows [][]string) (string, error)
+}
+
+// Feature identifies a construct that some formats can't represent, and
+// instead render using a simpler construct or leave out.
+type Feature string
+
+const (
+	// TableFeature identifies tables, such as the field tables of structs.
+	TableFeature Feature = "table"
+
+	// DetailsFeature identifies collapsible blocks, such as those holding
+	// examples and declaration source.
+	DetailsFeature Feature = "details"
+)
+
+// DegradingFormat is implemented by formats which render some features using
+// a simpler construct, so that the places where the documentation is degraded
+// can be reported. Formats which don't implement TableFormat are always
+// considered to leave tables out.
+type DegradingFormat interface {
+	Format
+
+	// Degradation describes the fallback used in place of the feature, or
+	// provides the empty string if the feature is fully supported.
+	Degradation(feature Feature) string
+}
+
+// Degradation describes the fallback the provided format uses in place of the
+// feature, or provides the empty string if the format fully supports it.
+func Degradation(f Format, feature Feature) string {
+	if feature == TableFeature {
+		if _, ok := f.(TableFormat); !ok {
+			return "omitted"
+		}
+	}
+
+	if d, ok := f.(DegradingFormat); ok {
+		return d.Degradation(feature)
+	}
+
+	return ""
+}
@@ -122,6 +122,38 @@ func GFMAccordionTerminator() string {
 	return "</p>\n</details>\n\n"
 }
 
+// GFMTable generates a table with the provided header cells and rows, using
+// the pipe table format from GitHub Flavored Markdown. Cells are expected to
+// be formatted already; pipes within them are escaped and line breaks are
+// replaced with spaces so that each row stays on a single line.
+func GFMTable(header []string, rows [][]string) string {
+	var b strings.Builder
+	writeTableRow(&b, header)
+
+	b.WriteString("|")
+	for range header {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+
+	for _, row := range rows {
+		writeTableRow(&b, row)
+	}
+
+	b.WriteString("\n")
+	return b.String()
+}
+
+func writeTableRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		cell = strings.Join(strings.Fields(cell), " ")
+		fmt.Fprintf(b, " %s |", cell)
+	}
+	b.WriteString("\n")
+}
+
 // Paragraph formats a paragraph with the provided text as the contents.
 func Paragraph(text string) string {
 	return fmt.Sprintf("%s\n\n", Escape(text))
@@ -132,6 +164,235 @@ var (
 	urlRegex              = xurls.Strict() // Require a scheme in URLs
 )
 
+// NormalizeWhitespace cleans up the whitespace of a fully rendered document so
+// that it satisfies common markdown linting rules. Trailing whitespace is
+// removed from each line, consecutive blank lines are collapsed into a single
+// blank line and the document is terminated by exactly one newline. Lines
+// within fenced code blocks are left untouched.
+func NormalizeWhitespace(text string) string {
+	var (
+		builder strings.Builder
+		fence   string
+		blank   bool
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		f, ok := fenceTransition(line, fence)
+		if fence != "" {
+			fence = f
+			builder.WriteString(line)
+			builder.WriteRune('\n')
+			continue
+		}
+
+		if ok {
+			fence = f
+		}
+
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if blank || builder.Len() == 0 {
+				continue
+			}
+
+			blank = true
+		} else {
+			blank = false
+		}
+
+		builder.WriteString(line)
+		builder.WriteRune('\n')
+	}
+
+	return strings.TrimRight(builder.String(), "\n") + "\n"
+}
+
+// inlineLinkRegex matches the links generated by Link. Link text may contain
+// escaped brackets as well as balanced brackets, such as those of type
+// parameter lists.
+var inlineLinkRegex = regexp.MustCompile(`\[((?:[^\[\]\\]|\\.|\[(?:[^\[\]\\]|\\.)*\])*)\]\(<([^<>]*)>\)`)
+
+// ReferenceLinks converts the inline links generated by Link in a fully
+// rendered document into reference-style links, with the link definitions
+// collected at the bottom of the document. Links to the same href share a
+// definition, and definitions are numbered in order of first use. Links within
+// fenced code blocks are left untouched.
+func ReferenceLinks(text string) string {
+	var (
+		builder strings.Builder
+		fence   string
+		hrefs   []string
+	)
+
+	labels := make(map[string]int)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if i > 0 {
+			builder.WriteRune('\n')
+		}
+
+		if f, ok := fenceTransition(line, fence); ok || fence != "" {
+			fence = f
+			builder.WriteString(line)
+			continue
+		}
+
+		builder.WriteString(inlineLinkRegex.ReplaceAllStringFunc(line, func(link string) string {
+			m := inlineLinkRegex.FindStringSubmatch(link)
+			label, ok := labels[m[2]]
+			if !ok {
+				hrefs = append(hrefs, m[2])
+				label = len(hrefs)
+				labels[m[2]] = label
+			}
+
+			return fmt.Sprintf("[%s][%d]", m[1], label)
+		}))
+	}
+
+	if len(hrefs) == 0 {
+		return text
+	}
+
+	result := strings.TrimRight(builder.String(), "\n")
+	builder.Reset()
+	builder.WriteString(result)
+	builder.WriteString("\n\n")
+	for i, href := range hrefs {
+		fmt.Fprintf(&builder, "[%d]: <%s>\n", i+1, href)
+	}
+
+	return builder.String()
+}
+
+// NormalizeHeadings adjusts the levels of the headers in a fully rendered
+// document so that each header is at most one level deeper than the header
+// containing it. Headers are never moved to a deeper level than they started
+// at. Only ATX-style headers outside of fenced code blocks are considered.
+func NormalizeHeadings(text string) string {
+	type heading struct {
+		original, normalized int
+	}
+
+	var (
+		stack []heading
+		fence string
+	)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if f, ok := fenceTransition(line, fence); ok {
+			fence = f
+			continue
+		}
+
+		if fence != "" {
+			continue
+		}
+
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		if level == 0 || level > 6 || !strings.HasPrefix(line[level:], " ") {
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].original >= level {
+			stack = stack[:len(stack)-1]
+		}
+
+		normalized := 1
+		if len(stack) > 0 {
+			normalized = stack[len(stack)-1].normalized + 1
+		}
+
+		if normalized > level {
+			normalized = level
+		}
+
+		stack = append(stack, heading{level, normalized})
+		lines[i] = strings.Repeat("#", normalized) + line[level:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// WrapParagraphs wraps the lines of paragraphs in a fully rendered document so
+// that they are no longer than the provided width where possible. Only plain
+// paragraph lines are wrapped; headers, list entries, tables, quotes, HTML and
+// code are left untouched, as are words that are longer than the width.
+func WrapParagraphs(text string, width int) string {
+	var (
+		builder strings.Builder
+		fence   string
+	)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if i > 0 {
+			builder.WriteRune('\n')
+		}
+
+		if f, ok := fenceTransition(line, fence); ok {
+			fence = f
+			builder.WriteString(line)
+			continue
+		}
+
+		if fence != "" || len(line) <= width || !isParagraphLine(line) {
+			builder.WriteString(line)
+			continue
+		}
+
+		var lineLen int
+		for j, word := range strings.Fields(line) {
+			switch {
+			case j == 0:
+			case lineLen+1+len(word) > width:
+				builder.WriteRune('\n')
+				lineLen = 0
+			default:
+				builder.WriteRune(' ')
+				lineLen++
+			}
+
+			builder.WriteString(word)
+			lineLen += len(word)
+		}
+	}
+
+	return builder.String()
+}
+
+var nonParagraphRegex = regexp.MustCompile(`^(?:\s|[#>|<*+-]|\d+[.)]|\[[^\]]*\]:|$)`)
+
+// isParagraphLine determines whether the provided line is a line of plain
+// paragraph text that can be safely wrapped.
+func isParagraphLine(line string) bool {
+	return !nonParagraphRegex.MatchString(line)
+}
+
+// fenceTransition determines whether the provided line opens or closes a
+// fenced code block given the fence of the code block that is currently open,
+// if any. If so, the fence that is open after the line is returned. A code
+// block is only closed by a run of the same fence character that is at least
+// as long as the one that opened it, so code blocks can contain shorter fences.
+func fenceTransition(line, fence string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if fence != "" {
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			return "", true
+		}
+
+		return fence, false
+	}
+
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		run := len(trimmed) - len(strings.TrimLeft(trimmed, trimmed[:1]))
+		return trimmed[:run], true
+	}
+
+	return "", false
+}
+
 // Escape escapes the special characters in the provided text, but leaves URLs
 // found intact. Note that the URLs included must begin with a scheme to skip
 // the escaping.
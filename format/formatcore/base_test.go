@@ -99,3 +99,149 @@ func TestEscape(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct {
+		name, in, out string
+	}{
+		{
+			name: "trailing whitespace",
+			in:   "# header  \n\ntext\t\n",
+			out:  "# header\n\ntext\n",
+		},
+		{
+			name: "blank lines",
+			in:   "\n\none\n\n\n\ntwo\n\n\n",
+			out:  "one\n\ntwo\n",
+		},
+		{
+			name: "missing final newline",
+			in:   "text",
+			out:  "text\n",
+		},
+		{
+			name: "code block",
+			in:   "```go\nfoo  \n\n\n\nbar\n```\n\n\n\ntext\n",
+			out:  "```go\nfoo  \n\n\n\nbar\n```\n\ntext\n",
+		},
+		{
+			name: "nested code block",
+			in:   "````md\n```go\nfoo  \n```\n\n\nbar  \n````\n\n\ntext\n",
+			out:  "````md\n```go\nfoo  \n```\n\n\nbar  \n````\n\ntext\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(NormalizeWhitespace(test.in), test.out) // Wrong output for NormalizeWhitespace()
+		})
+	}
+}
+
+func TestNormalizeHeadings(t *testing.T) {
+	tests := []struct {
+		name, in, out string
+	}{
+		{
+			name: "increments",
+			in:   "# pkg\n\n## type\n\n#### Output\n\n## func\n",
+			out:  "# pkg\n\n## type\n\n### Output\n\n## func\n",
+		},
+		{
+			name: "nested skip",
+			in:   "# pkg\n\n### a\n\n##### b\n\n### c\n",
+			out:  "# pkg\n\n## a\n\n### b\n\n## c\n",
+		},
+		{
+			name: "code block",
+			in:   "# pkg\n\n```\n### comment\n```\n\n### a\n",
+			out:  "# pkg\n\n```\n### comment\n```\n\n## a\n",
+		},
+		{
+			name: "not a header",
+			in:   "#hashtag\n",
+			out:  "#hashtag\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(NormalizeHeadings(test.in), test.out) // Wrong output for NormalizeHeadings()
+		})
+	}
+}
+
+func TestWrapParagraphs(t *testing.T) {
+	tests := []struct {
+		name, in, out string
+	}{
+		{
+			name: "short",
+			in:   "short line\n",
+			out:  "short line\n",
+		},
+		{
+			name: "wrapped",
+			in:   "aaaa bbbb cccc dddd\n",
+			out:  "aaaa bbbb\ncccc dddd\n",
+		},
+		{
+			name: "long word",
+			in:   "aaaaaaaaaaaa bb\n",
+			out:  "aaaaaaaaaaaa\nbb\n",
+		},
+		{
+			name: "list entry",
+			in:   "- aaaa bbbb cccc dddd\n",
+			out:  "- aaaa bbbb cccc dddd\n",
+		},
+		{
+			name: "code block",
+			in:   "```\naaaa bbbb cccc dddd\n```\n",
+			out:  "```\naaaa bbbb cccc dddd\n```\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(WrapParagraphs(test.in, 10), test.out) // Wrong output for WrapParagraphs()
+		})
+	}
+}
+
+func TestReferenceLinks(t *testing.T) {
+	tests := []struct {
+		name, in, out string
+	}{
+		{
+			name: "no links",
+			in:   "plain text\n",
+			out:  "plain text\n",
+		},
+		{
+			name: "links",
+			in:   "## type [A](<https://a.go#L1>)\n\nSee [B \\[x\\]](<#b>) and [A](<https://a.go#L1>).\n\n",
+			out:  "## type [A][1]\n\nSee [B \\[x\\]][2] and [A][1].\n\n[1]: <https://a.go#L1>\n[2]: <#b>\n",
+		},
+		{
+			name: "type parameters",
+			in:   "- [type Pair[K comparable, V any]](<#type-pair>)\n",
+			out:  "- [type Pair[K comparable, V any]][1]\n\n[1]: <#type-pair>\n",
+		},
+		{
+			name: "code block",
+			in:   "```\n[A](<#a>)\n```\n[B](<#b>)\n",
+			out:  "```\n[A](<#a>)\n```\n[B][1]\n\n[1]: <#b>\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(ReferenceLinks(test.in), test.out)
+		})
+	}
+}
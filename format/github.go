@@ -2,6 +2,7 @@ package format
 
 import (
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -81,28 +82,57 @@ func (f *GitHubFlavoredMarkdown) CodeHref(loc lang.Location) (string, error) {
 		relative = loc.Filepath
 	}
 
+	root := string(filepath.Separator)
+	if loc.Repo.LinkRoot != "" {
+		root = loc.Repo.LinkRoot
+	}
+
 	full := filepath.Join(loc.Repo.PathFromRoot, relative)
-	p, err := filepath.Rel(string(filepath.Separator), full)
+	p, err := filepath.Rel(root, full)
 	if err != nil {
 		return "", err
 	}
 
+	// Files outside of the link root can't be linked to
+	if p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+		return "", nil
+	}
+
+	// GitLab uses a different path for files and omits the L from the end of
+	// line ranges
+	blob, rangeFmt := "blob", "L%d-L%d"
+	if isGitLabRemote(loc.Repo.Remote) {
+		blob, rangeFmt = "-/blob", "L%d-%d"
+	}
+
 	var locStr string
 	if loc.Start.Line == loc.End.Line {
 		locStr = fmt.Sprintf("L%d", loc.Start.Line)
 	} else {
-		locStr = fmt.Sprintf("L%d-L%d", loc.Start.Line, loc.End.Line)
+		locStr = fmt.Sprintf(rangeFmt, loc.Start.Line, loc.End.Line)
 	}
 
 	return fmt.Sprintf(
-		"%s/blob/%s/%s#%s",
+		"%s/%s/%s/%s#%s",
 		loc.Repo.Remote,
+		blob,
 		loc.Repo.DefaultBranch,
 		filepath.ToSlash(p),
 		locStr,
 	), nil
 }
 
+// isGitLabRemote determines whether the remote is hosted by GitLab, based on
+// its host name.
+func isGitLabRemote(remote string) bool {
+	u, err := url.Parse(remote)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(u.Hostname()), "gitlab")
+}
+
 // ListEntry generates an unordered list entry with the provided text at the
 // provided zero-indexed depth. A depth of 0 is considered the topmost level of
 // list.
@@ -139,6 +169,11 @@ func (f *GitHubFlavoredMarkdown) Paragraph(text string) (string, error) {
 	return formatcore.Paragraph(text), nil
 }
 
+// Table generates a table with the provided header cells and rows of cells.
+func (f *GitHubFlavoredMarkdown) Table(header []string, rows [][]string) (string, error) {
+	return formatcore.GFMTable(header, rows), nil
+}
+
 // Escape escapes special markdown characters from the provided text.
 func (f *GitHubFlavoredMarkdown) Escape(text string) string {
 	return formatcore.Escape(text)
@@ -37,6 +37,18 @@ func TestGitHubFlavoredMarkdown_CodeBlock_noLanguage(t *testing.T) {
 	is.Equal(res, "```\nLine 1\nLine 2\n```\n\n")
 }
 
+func TestGitHubFlavoredMarkdown_Table(t *testing.T) {
+	is := is.New(t)
+
+	var f format.GitHubFlavoredMarkdown
+	res, err := f.Table([]string{"Field", "Description"}, [][]string{
+		{"A", "Either a | b"},
+		{"B", "Spans\nlines"},
+	})
+	is.NoErr(err)
+	is.Equal(res, "| Field | Description |\n| --- | --- |\n| A | Either a \\| b |\n| B | Spans lines |\n\n")
+}
+
 func TestGitHubFlavoredMarkdown_Header(t *testing.T) {
 	tests := []struct {
 		text   string
@@ -139,6 +151,29 @@ func TestGitHubFlavoredMarkdown_CodeHref(t *testing.T) {
 	is.Equal(res, "https://dev.azure.com/org/project/_git/repo/blob/master/subdir/file.go#L12-L14")
 }
 
+func TestGitHubFlavoredMarkdown_CodeHref_gitLab(t *testing.T) {
+	is := is.New(t)
+
+	wd, err := filepath.Abs(".")
+	is.NoErr(err)
+	locPath := filepath.Join(wd, "subdir", "file.go")
+
+	var f format.GitHubFlavoredMarkdown
+	res, err := f.CodeHref(lang.Location{
+		Start:    lang.Position{Line: 12, Col: 1},
+		End:      lang.Position{Line: 14, Col: 43},
+		Filepath: locPath,
+		WorkDir:  wd,
+		Repo: &lang.Repo{
+			Remote:        "https://gitlab.com/org/repo",
+			DefaultBranch: "main",
+			PathFromRoot:  "/",
+		},
+	})
+	is.NoErr(err)
+	is.Equal(res, "https://gitlab.com/org/repo/-/blob/main/subdir/file.go#L12-14")
+}
+
 func TestGitHubFlavoredMarkdown_CodeHref_noRepo(t *testing.T) {
 	is := is.New(t)
 
@@ -193,3 +228,35 @@ func TestGitHubFlavoredMarkdown_ListEntry_empty(t *testing.T) {
 	is.NoErr(err)
 	is.Equal(res, "")
 }
+
+func TestGitHubFlavoredMarkdown_CodeHref_linkRoot(t *testing.T) {
+	is := is.New(t)
+
+	wd, err := filepath.Abs(".")
+	is.NoErr(err)
+	locPath := filepath.Join(wd, "subdir", "file.go")
+
+	var f format.GitHubFlavoredMarkdown
+	res, err := f.CodeHref(lang.Location{
+		Start:    lang.Position{Line: 12, Col: 1},
+		End:      lang.Position{Line: 14, Col: 43},
+		Filepath: locPath,
+		WorkDir:  wd,
+		Repo: &lang.Repo{
+			Remote:        "https://github.com/org/go-mirror",
+			DefaultBranch: "main",
+			PathFromRoot:  filepath.FromSlash("/go/service"),
+			LinkRoot:      filepath.FromSlash("/go"),
+		},
+	})
+	is.NoErr(err)
+	is.Equal(res, "https://github.com/org/go-mirror/blob/main/service/subdir/file.go#L12-L14")
+}
+
+func TestGitHubFlavoredMarkdown_Degradation(t *testing.T) {
+	is := is.New(t)
+
+	var f format.GitHubFlavoredMarkdown
+	is.Equal(format.Degradation(&f, format.DetailsFeature), "")
+	is.Equal(format.Degradation(&f, format.TableFeature), "")
+}
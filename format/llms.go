@@ -0,0 +1,130 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/format/formatcore"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// LLMText provides a Format which produces flattened, token-efficient text
+// suitable for feeding into LLM context windows or embedding pipelines, in the
+// style of llms.txt files. Links, anchors and markdown escaping are dropped
+// entirely, and each top-level section is preceded by a stable delimiter line
+// so that the output can be split reliably into chunks.
+type LLMText struct{}
+
+// LLMSectionDelimiter is the line emitted before each top-level section
+// produced by the LLMText format.
+const LLMSectionDelimiter = "----"
+
+// Bold returns the provided text unchanged, as emphasis carries no meaning in
+// flattened text.
+func (f *LLMText) Bold(text string) (string, error) {
+	return text, nil
+}
+
+// CodeBlock wraps the provided code as a fenced code block and tags it with
+// the provided language (or no language if the empty string is provided).
+func (f *LLMText) CodeBlock(language, code string) (string, error) {
+	return formatcore.GFMCodeBlock(language, code), nil
+}
+
+// Header converts the provided text into a header of the provided level. The
+// level is expected to be at least 1. Top-level headers are preceded by the
+// LLMSectionDelimiter.
+func (f *LLMText) Header(level int, text string) (string, error) {
+	h, err := formatcore.Header(level, text)
+	if err != nil {
+		return "", err
+	}
+
+	if level == 1 {
+		return fmt.Sprintf("%s\n\n%s", LLMSectionDelimiter, h), nil
+	}
+
+	return h, nil
+}
+
+// RawHeader converts the provided text into a header of the provided level.
+// Since no escaping is performed by this format, it is equivalent to Header.
+func (f *LLMText) RawHeader(level int, text string) (string, error) {
+	return f.Header(level, text)
+}
+
+// LocalHref always returns the empty string, as links are dropped from
+// flattened text.
+func (f *LLMText) LocalHref(headerText string) (string, error) {
+	return "", nil
+}
+
+// Link returns the text of the link without its href.
+func (f *LLMText) Link(text, href string) (string, error) {
+	return text, nil
+}
+
+// CodeHref always returns the empty string, as links are dropped from
+// flattened text.
+func (f *LLMText) CodeHref(loc lang.Location) (string, error) {
+	return "", nil
+}
+
+// ListEntry is rendered the same way as in the PlainMarkdown fallback, so
+// ErrUnsupported is returned.
+func (f *LLMText) ListEntry(depth int, text string) (string, error) {
+	return "", ErrUnsupported
+}
+
+// Accordion generates the title of the accordion on its own line followed by
+// the body, as collapsible content has no meaning in flattened text.
+func (f *LLMText) Accordion(title, body string) (string, error) {
+	return fmt.Sprintf("%s:\n\n%s\n\n", title, strings.TrimSpace(body)), nil
+}
+
+// Degradation describes the fallbacks used for the features that flattened
+// text can't represent. Collapsible blocks are rendered as a title line
+// followed by their contents.
+func (f *LLMText) Degradation(feature Feature) string {
+	if feature == DetailsFeature {
+		return "title line"
+	}
+
+	return ""
+}
+
+// AccordionHeader generates the title of the accordion on its own line.
+//
+// The AccordionHeader is expected to be used in conjunction with
+// AccordionTerminator() when the demands of the body's rendering requires it to
+// be generated independently. The result looks conceptually like the following:
+//
+//	accordion := format.AccordionHeader("Accordion Title") + "Accordion Body" + format.AccordionTerminator()
+func (f *LLMText) AccordionHeader(title string) (string, error) {
+	return fmt.Sprintf("%s:\n\n", title), nil
+}
+
+// AccordionTerminator generates nothing, as there is no construct to
+// terminate in flattened text. It is expected to be used in conjunction with
+// AccordionHeader(). See AccordionHeader for a full description.
+func (f *LLMText) AccordionTerminator() (string, error) {
+	return "", nil
+}
+
+// Paragraph formats a paragraph with the provided text as the contents.
+func (f *LLMText) Paragraph(text string) (string, error) {
+	return fmt.Sprintf("%s\n\n", text), nil
+}
+
+// Escape returns the provided text unchanged, as flattened text is not
+// interpreted as markdown.
+func (f *LLMText) Escape(text string) string {
+	return text
+}
+
+// Fallback provides the format used for the constructs that this format
+// renders no differently, which return ErrUnsupported. Wrap the format with
+// Chain to render them.
+func (f *LLMText) Fallback() Format {
+	return &PlainMarkdown{}
+}
@@ -0,0 +1,134 @@
+package format_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/matryer/is"
+)
+
+func TestLLMText_Bold(t *testing.T) {
+	is := is.New(t)
+
+	var f format.LLMText
+	res, err := f.Bold("sample text")
+	is.NoErr(err)
+	is.Equal(res, "sample text")
+}
+
+func TestLLMText_CodeBlock(t *testing.T) {
+	is := is.New(t)
+
+	var f format.LLMText
+	res, err := f.CodeBlock("go", "Line 1\nLine 2")
+	is.NoErr(err)
+	is.Equal(res, "```go\nLine 1\nLine 2\n```\n\n")
+}
+
+func TestLLMText_Header(t *testing.T) {
+	tests := []struct {
+		text   string
+		level  int
+		result string
+	}{
+		{"header text", 1, "----\n\n# header text\n\n"},
+		{"level 2", 2, "## level 2\n\n"},
+		{"level 6", 6, "###### level 6\n\n"},
+		{"other level", 12, "###### other level\n\n"},
+		{"with * no escape", 2, "## with * no escape\n\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s (level %d)", test.text, test.level), func(t *testing.T) {
+			is := is.New(t)
+
+			var f format.LLMText
+			res, err := f.Header(test.level, test.text)
+			is.NoErr(err)
+			is.Equal(res, test.result)
+		})
+	}
+}
+
+func TestLLMText_Header_invalidLevel(t *testing.T) {
+	is := is.New(t)
+
+	var f format.LLMText
+	_, err := f.Header(-1, "invalid")
+	is.Equal(err.Error(), "format: header level cannot be less than 1")
+}
+
+func TestLLMText_LocalHref(t *testing.T) {
+	is := is.New(t)
+
+	var f format.LLMText
+	res, err := f.LocalHref("Normal Header")
+	is.NoErr(err)
+	is.Equal(res, "")
+}
+
+func TestLLMText_CodeHref(t *testing.T) {
+	is := is.New(t)
+
+	wd, err := filepath.Abs(".")
+	is.NoErr(err)
+	locPath := filepath.Join(wd, "subdir", "file.go")
+
+	var f format.LLMText
+	res, err := f.CodeHref(lang.Location{
+		Start:    lang.Position{Line: 12, Col: 1},
+		End:      lang.Position{Line: 14, Col: 43},
+		Filepath: locPath,
+		WorkDir:  wd,
+		Repo: &lang.Repo{
+			Remote:        "https://github.com/org/repo",
+			DefaultBranch: "master",
+			PathFromRoot:  "/",
+		},
+	})
+	is.NoErr(err)
+	is.Equal(res, "")
+}
+
+func TestLLMText_Link(t *testing.T) {
+	is := is.New(t)
+
+	var f format.LLMText
+	res, err := f.Link("link text", "https://test.com/a/b/c")
+	is.NoErr(err)
+	is.Equal(res, "link text")
+}
+
+func TestLLMText_Accordion(t *testing.T) {
+	is := is.New(t)
+
+	var f format.LLMText
+	res, err := f.Accordion("Example", "body text\n\n")
+	is.NoErr(err)
+	is.Equal(res, "Example:\n\nbody text\n\n")
+}
+
+func TestLLMText_Paragraph(t *testing.T) {
+	is := is.New(t)
+
+	var f format.LLMText
+	res, err := f.Paragraph("text with * and _ characters")
+	is.NoErr(err)
+	is.Equal(res, "text with * and _ characters\n\n")
+}
+
+func TestLLMText_ListEntry(t *testing.T) {
+	is := is.New(t)
+
+	// List entries are rendered by the fallback
+	var f format.LLMText
+	_, err := f.ListEntry(0, "list entry text")
+	is.Equal(err, format.ErrUnsupported)
+
+	res, err := format.Chain(&f).ListEntry(1, "list entry text")
+	is.NoErr(err)
+	is.Equal(res, "  - list entry text\n")
+}
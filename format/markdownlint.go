@@ -0,0 +1,167 @@
+package format
+
+import (
+	"github.com/ag5denis/gomarkdoc/format/formatcore"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// MarkdownLint wraps another Format, adjusting its output to satisfy the
+// default ruleset of markdownlint (https://github.com/DavidAnson/markdownlint).
+// Code blocks always declare a language, accordions are rendered as headers
+// instead of inline HTML, and the rendered document is normalized with
+// Normalize so that headers only increment by one level at a time,
+// whitespace is cleaned up and paragraphs are wrapped where possible.
+type MarkdownLint struct {
+	Format Format
+}
+
+// MarkdownLintLineLength is the maximum line length enforced by the default
+// markdownlint ruleset.
+const MarkdownLintLineLength = 80
+
+// markdownLintCodeLanguage is used for code blocks with no language, as
+// markdownlint requires that all fenced code blocks declare one.
+const markdownLintCodeLanguage = "text"
+
+// Bold converts the provided text to bold
+func (f *MarkdownLint) Bold(text string) (string, error) {
+	return f.wrapped().Bold(text)
+}
+
+// CodeBlock wraps the provided code as a code block and tags it with the
+// provided language, or a plain text language if the empty string is
+// provided.
+func (f *MarkdownLint) CodeBlock(language, code string) (string, error) {
+	if language == "" {
+		language = markdownLintCodeLanguage
+	}
+
+	return f.wrapped().CodeBlock(language, code)
+}
+
+// Header converts the provided text into a header of the provided level. The
+// level is expected to be at least 1.
+func (f *MarkdownLint) Header(level int, text string) (string, error) {
+	return f.wrapped().Header(level, text)
+}
+
+// RawHeader converts the provided text into a header of the provided level
+// without escaping the header text. The level is expected to be at least 1.
+func (f *MarkdownLint) RawHeader(level int, text string) (string, error) {
+	return f.wrapped().RawHeader(level, text)
+}
+
+// LocalHref generates an href for navigating to a header with the given
+// headerText located within the same document as the href itself.
+func (f *MarkdownLint) LocalHref(headerText string) (string, error) {
+	return f.wrapped().LocalHref(headerText)
+}
+
+// Link generates a link with the given text and href values.
+func (f *MarkdownLint) Link(text, href string) (string, error) {
+	return f.wrapped().Link(text, href)
+}
+
+// CodeHref generates an href to the provided code entry.
+func (f *MarkdownLint) CodeHref(loc lang.Location) (string, error) {
+	return f.wrapped().CodeHref(loc)
+}
+
+// ListEntry generates an unordered list entry with the provided text at the
+// provided zero-indexed depth. A depth of 0 is considered the topmost level of
+// list.
+func (f *MarkdownLint) ListEntry(depth int, text string) (string, error) {
+	return f.wrapped().ListEntry(depth, text)
+}
+
+// Accordion generates a header with the accordion's title followed by the
+// body, as markdownlint disallows the inline HTML used for accordions.
+func (f *MarkdownLint) Accordion(title, body string) (string, error) {
+	h, err := f.AccordionHeader(title)
+	if err != nil {
+		return "", err
+	}
+
+	p, err := f.wrapped().Paragraph(body)
+	if err != nil {
+		return "", err
+	}
+
+	return h + p, nil
+}
+
+// AccordionHeader generates a header with the accordion's title. The header is
+// generated at the deepest level and brought in line with the surrounding
+// headers by Normalize.
+//
+// The AccordionHeader is expected to be used in conjunction with
+// AccordionTerminator() when the demands of the body's rendering requires it to
+// be generated independently. The result looks conceptually like the following:
+//
+//	accordion := format.AccordionHeader("Accordion Title") + "Accordion Body" + format.AccordionTerminator()
+func (f *MarkdownLint) AccordionHeader(title string) (string, error) {
+	return f.wrapped().Header(6, title)
+}
+
+// Degradation describes the fallbacks used for the features that can't be
+// represented without inline HTML or that the wrapped format doesn't support.
+// Collapsible blocks are rendered as a level 6 header followed by their
+// contents.
+func (f *MarkdownLint) Degradation(feature Feature) string {
+	if feature == DetailsFeature {
+		return "level 6 header"
+	}
+
+	return Degradation(f.Format, feature)
+}
+
+// AccordionTerminator generates nothing, as the accordion's header needs no
+// termination. It is expected to be used in conjunction with
+// AccordionHeader(). See AccordionHeader for a full description.
+func (f *MarkdownLint) AccordionTerminator() (string, error) {
+	return "", nil
+}
+
+// Paragraph formats a paragraph with the provided text as the contents.
+func (f *MarkdownLint) Paragraph(text string) (string, error) {
+	return f.wrapped().Paragraph(text)
+}
+
+// Escape escapes special markdown characters from the provided text.
+func (f *MarkdownLint) Escape(text string) string {
+	return f.Format.Escape(text)
+}
+
+// Normalize adjusts a fully rendered document to satisfy markdownlint's rules
+// for header increments, whitespace and line length.
+func (f *MarkdownLint) Normalize(text string) string {
+	text = formatcore.NormalizeHeadings(text)
+	text = formatcore.WrapParagraphs(text, MarkdownLintLineLength)
+	return formatcore.NormalizeWhitespace(text)
+}
+
+// wrapped provides the wrapped format, consulting its fallback chain for any
+// constructs that it does not support.
+func (f *MarkdownLint) wrapped() Format {
+	return Chain(f.Format)
+}
+
+// TOC provides the table of contents macro of the wrapped format, or the
+// empty string if the wrapped format has none.
+func (f *MarkdownLint) TOC() (string, error) {
+	if t, ok := f.Format.(TOCFormat); ok {
+		return t.TOC()
+	}
+
+	return "", nil
+}
+
+// Table provides the table of the wrapped format, or ErrUnsupported if the
+// wrapped format doesn't support tables.
+func (f *MarkdownLint) Table(header []string, rows [][]string) (string, error) {
+	if t, ok := f.Format.(TableFormat); ok {
+		return t.Table(header, rows)
+	}
+
+	return "", ErrUnsupported
+}
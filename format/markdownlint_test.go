@@ -0,0 +1,74 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/matryer/is"
+)
+
+func TestMarkdownLint_CodeBlock(t *testing.T) {
+	is := is.New(t)
+
+	f := format.MarkdownLint{Format: &format.GitHubFlavoredMarkdown{}}
+	res, err := f.CodeBlock("go", "Line 1\nLine 2")
+	is.NoErr(err)
+	is.Equal(res, "```go\nLine 1\nLine 2\n```\n\n")
+}
+
+func TestMarkdownLint_CodeBlock_noLanguage(t *testing.T) {
+	is := is.New(t)
+
+	f := format.MarkdownLint{Format: &format.GitHubFlavoredMarkdown{}}
+	res, err := f.CodeBlock("", "Line 1\nLine 2")
+	is.NoErr(err)
+	is.Equal(res, "```text\nLine 1\nLine 2\n```\n\n")
+}
+
+func TestMarkdownLint_Accordion(t *testing.T) {
+	is := is.New(t)
+
+	f := format.MarkdownLint{Format: &format.GitHubFlavoredMarkdown{}}
+	res, err := f.Accordion("Title", "Body")
+	is.NoErr(err)
+	is.Equal(res, "###### Title\n\nBody\n\n")
+
+	// The fallback of the wrapped format is used for the header and body
+	f = format.MarkdownLint{Format: &format.AzureDevOpsMarkdown{}}
+	res, err = f.Accordion("Title", "Body")
+	is.NoErr(err)
+	is.Equal(res, "###### Title\n\nBody\n\n")
+}
+
+func TestMarkdownLint_Normalize(t *testing.T) {
+	is := is.New(t)
+
+	f := format.MarkdownLint{Format: &format.GitHubFlavoredMarkdown{}}
+	res := f.Normalize("# Title\n\n### Section  \n\n\n\nSome text\n\n")
+	is.Equal(res, "# Title\n\n## Section\n\nSome text\n")
+}
+
+func TestMarkdownLint_TOC(t *testing.T) {
+	is := is.New(t)
+
+	f := format.MarkdownLint{Format: &format.AzureDevOpsMarkdown{WikiTOC: true}}
+	res, err := f.TOC()
+	is.NoErr(err)
+	is.Equal(res, "[[_TOC_]]\n\n")
+
+	f = format.MarkdownLint{Format: &format.GitHubFlavoredMarkdown{}}
+	res, err = f.TOC()
+	is.NoErr(err)
+	is.Equal(res, "")
+}
+
+func TestMarkdownLint_Degradation(t *testing.T) {
+	is := is.New(t)
+
+	f := &format.MarkdownLint{Format: &format.GitHubFlavoredMarkdown{}}
+	is.Equal(format.Degradation(f, format.DetailsFeature), "level 6 header")
+	is.Equal(format.Degradation(f, format.TableFeature), "")
+
+	f = &format.MarkdownLint{Format: &format.PlainMarkdown{}}
+	is.Equal(format.Degradation(f, format.TableFeature), "omitted")
+}
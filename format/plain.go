@@ -69,6 +69,17 @@ func (f *PlainMarkdown) Accordion(title, body string) (string, error) {
 	return fmt.Sprintf("%s%s", h, formatcore.Paragraph(body)), nil
 }
 
+// Degradation describes the fallbacks used for the features that plain
+// markdown can't represent. Collapsible blocks are rendered as a level 6 header
+// followed by their contents.
+func (f *PlainMarkdown) Degradation(feature Feature) string {
+	if feature == DetailsFeature {
+		return "level 6 header"
+	}
+
+	return ""
+}
+
 // AccordionHeader generates the header visible when an accordion is collapsed.
 // Since accordions are not supported in plain markdown, this generates a level
 // 6 header.
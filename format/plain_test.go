@@ -181,3 +181,11 @@ func TestPlainMarkdown_ListEntry_empty(t *testing.T) {
 	is.NoErr(err)
 	is.Equal(res, "")
 }
+
+func TestPlainMarkdown_Degradation(t *testing.T) {
+	is := is.New(t)
+
+	var f format.PlainMarkdown
+	is.Equal(format.Degradation(&f, format.DetailsFeature), "level 6 header")
+	is.Equal(format.Degradation(&f, format.TableFeature), "omitted")
+}
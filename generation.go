@@ -0,0 +1,28 @@
+package gomarkdoc
+
+// GenerationInfo describes the run of gomarkdoc that generates the
+// documentation, so that templates can record how it was generated.
+type GenerationInfo struct {
+	// Version holds the version of gomarkdoc.
+	Version string
+
+	// Commit holds the SHA of the commit checked out in the repository being
+	// documented, if known.
+	Commit string
+
+	// Options holds the options that the documentation is generated with, as
+	// provided by the program generating it. The gomarkdoc command provides
+	// its cmd.CommandOptions.
+	Options interface{}
+}
+
+// WithGenerationInfo makes the provided information about the run available
+// to every template through the generation function:
+//
+//	{{ with generation }}Generated from {{ .Commit }} by gomarkdoc {{ .Version }}{{ end }}
+func WithGenerationInfo(info GenerationInfo) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.generation = info
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+package gomarkdoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IncludeHandler receives the path of each file read by the include function.
+type IncludeHandler func(path string)
+
+// WithIncludeRoot enables the include function in templates, which inserts the
+// contents of another file, such as a usage snippet or a set of badges, when
+// the documentation is rendered:
+//
+//	{{ include "docs/badges.md" }}
+//
+// Paths are relative to the provided root directory, and files outside of it
+// can't be included, even through symbolic links. Without an include root,
+// templates that use the include function fail to render.
+func WithIncludeRoot(root string) RendererOption {
+	return func(renderer *Renderer) error {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("gomarkdoc: invalid include root %s: %w", root, err)
+		}
+
+		renderer.includeRoot = abs
+		return nil
+	}
+}
+
+// WithIncludeHandler reports the path of each file read by the include
+// function to the provided handler, after symbolic links are resolved, so that
+// callers can tell when the rendered documentation is out of date.
+func WithIncludeHandler(handler IncludeHandler) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.includeHandler = handler
+		return nil
+	}
+}
+
+// include reads the file with the provided path relative to the include root.
+func (out *Renderer) include(name string) (string, error) {
+	if out.includeRoot == "" {
+		return "", fmt.Errorf("gomarkdoc: can't include %s: no include root was configured", name)
+	}
+
+	root, err := filepath.EvalSymlinks(out.includeRoot)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: can't include %s: %w", name, err)
+	}
+
+	path := filepath.FromSlash(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	// Symbolic links are resolved first so that they can't point outside of
+	// the root
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: can't include %s: %w", name, err)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("gomarkdoc: can't include %s: file is outside of %s", name, out.includeRoot)
+	}
+
+	b, err := os.ReadFile(resolved)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: can't include %s: %w", name, err)
+	}
+
+	if out.includeHandler != nil {
+		out.includeHandler(resolved)
+	}
+
+	return string(b), nil
+}
@@ -1,5 +1,7 @@
 package lang
 
+import "strings"
+
 type (
 	// Block defines a single block element (e.g. paragraph, code block) in the
 	// documentation for a symbol or package.
@@ -49,3 +51,43 @@ func (b *Block) Kind() BlockKind {
 func (b *Block) Text() string {
 	return b.text
 }
+
+// IsDeprecation indicates whether the block is a paragraph noting that the
+// symbol it documents is deprecated, which is a paragraph beginning with
+// "Deprecated: ".
+func (b *Block) IsDeprecation() bool {
+	return b.kind == ParagraphBlock && isDeprecationParagraph(b.text)
+}
+
+// DeprecationText provides the text of a deprecation paragraph without the
+// leading "Deprecated:" marker. It is empty for blocks which are not a
+// deprecation.
+func (b *Block) DeprecationText() string {
+	if !b.IsDeprecation() {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(b.text, deprecationPrefix))
+}
+
+// Spans splits the text of a block of kind ParagraphBlock into plain text and
+// doc links, such as [Type.Method] or [net/http.Client]. Blocks of other kinds
+// consist of a single span of text.
+func (b *Block) Spans() []*Span {
+	if b.kind != ParagraphBlock {
+		return []*Span{{kind: TextSpan, text: b.text}}
+	}
+
+	return parseSpans(b.cfg, b.text)
+}
+
+// HasLinks indicates whether the text of the block contains any doc links.
+func (b *Block) HasLinks() bool {
+	for _, s := range b.Spans() {
+		if s.kind == LinkSpan {
+			return true
+		}
+	}
+
+	return false
+}
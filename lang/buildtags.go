@@ -0,0 +1,182 @@
+package lang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// knownOS and knownArch list the build tags that are implied by the target
+// platform rather than provided with the -tags flag.
+var (
+	knownOS = []string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos",
+		"ios", "js", "linux", "nacl", "netbsd", "openbsd", "plan9", "solaris",
+		"wasip1", "windows", "zos",
+	}
+	knownArch = []string{
+		"386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be",
+		"loong64", "mips", "mipsle", "mips64", "mips64le", "mips64p32",
+		"mips64p32le", "ppc", "ppc64", "ppc64le", "riscv", "riscv64", "s390",
+		"s390x", "sparc", "sparc64", "wasm",
+	}
+)
+
+// parseTaggedTestFiles parses the test files of the package that are excluded
+// from the build only because they require build tags that weren't provided,
+// mapping each file to the build constraint it declares. Files excluded by the
+// target platform or Go version, or by the ignore tag, are skipped.
+func parseTaggedTestFiles(pkg *sourcePackage, fs *token.FileSet) (map[*ast.File]string, error) {
+	files := make(map[*ast.File]string)
+	for _, name := range pkg.ignoredGoFiles {
+		if !strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		parsed, err := parser.ParseFile(fs, filepath.Join(pkg.dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to parse package file %s: %w", name, err)
+		}
+
+		if parsed.Name.Name != pkg.name && parsed.Name.Name != pkg.name+"_test" {
+			continue
+		}
+
+		expr, ok := fileConstraint(parsed)
+		if !ok || !expr.Eval(satisfiableTag) {
+			continue
+		}
+
+		removeIgnored(parsed)
+
+		files[parsed] = expr.String()
+	}
+
+	return files, nil
+}
+
+// getFileConstraints determines the build constraints of the package's files,
+// keyed by the full path of each file. The constraint of a file combines its
+// //go:build line with the GOOS and GOARCH implied by its name, such as
+// foo_linux.go. Files without any constraints are omitted.
+func getFileConstraints(pkg *sourcePackage) map[string]string {
+	constraints := make(map[string]string)
+	for _, name := range pkg.goFiles {
+		fileName := filepath.Join(pkg.dir, name)
+
+		expr, _ := fileConstraint(pkg.syntax[fileName])
+		if implied, ok := fileNameConstraint(name); ok {
+			if expr == nil {
+				expr = implied
+			} else if expr.String() != implied.String() {
+				expr = &constraint.AndExpr{X: expr, Y: implied}
+			}
+		}
+
+		if expr != nil {
+			constraints[fileName] = expr.String()
+		}
+	}
+
+	return constraints
+}
+
+// fileNameConstraint determines the constraint implied by the GOOS and GOARCH
+// suffixes of the file's name, following the rules of the go command. The name
+// must contain an underscore before the suffixes, so linux.go is unconstrained.
+func fileNameConstraint(name string) (constraint.Expr, bool) {
+	name = strings.TrimSuffix(name, ".go")
+	name = strings.TrimSuffix(name, "_test")
+
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil, false
+	}
+
+	parts := strings.Split(name[i+1:], "_")
+	n := len(parts)
+
+	if n >= 2 && isKnown(knownOS, parts[n-2]) && isKnown(knownArch, parts[n-1]) {
+		return &constraint.AndExpr{
+			X: &constraint.TagExpr{Tag: parts[n-2]},
+			Y: &constraint.TagExpr{Tag: parts[n-1]},
+		}, true
+	}
+
+	if isKnown(knownOS, parts[n-1]) || isKnown(knownArch, parts[n-1]) {
+		return &constraint.TagExpr{Tag: parts[n-1]}, true
+	}
+
+	return nil, false
+}
+
+// isKnown reports whether the tag is in the provided list of known tags.
+func isKnown(known []string, tag string) bool {
+	for _, k := range known {
+		if tag == k {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileConstraint finds the //go:build constraint of the file, if it has one.
+func fileConstraint(f *ast.File) (constraint.Expr, bool) {
+	for _, group := range f.Comments {
+		if group.Pos() >= f.Package {
+			break
+		}
+
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return nil, false
+			}
+
+			return expr, true
+		}
+	}
+
+	return nil, false
+}
+
+// satisfiableTag reports whether the build tag is satisfied for the target
+// platform and Go version, or could be satisfied by providing it with the
+// -tags flag.
+func satisfiableTag(tag string) bool {
+	ctx := build.Default
+
+	switch {
+	case tag == "ignore":
+		return false
+	case tag == ctx.GOOS || tag == ctx.GOARCH || tag == runtime.Compiler:
+		return true
+	case tag == "unix":
+		return ctx.GOOS != "windows" && ctx.GOOS != "plan9" && ctx.GOOS != "js" && ctx.GOOS != "wasip1"
+	case tag == "cgo":
+		return ctx.CgoEnabled
+	case tag == "gc" || tag == "gccgo":
+		return false
+	case strings.HasPrefix(tag, "go1."):
+		for _, release := range ctx.ReleaseTags {
+			if release == tag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return !isKnown(knownOS, tag) && !isKnown(knownArch, tag)
+}
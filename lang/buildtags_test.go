@@ -0,0 +1,52 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFileNameConstraint(t *testing.T) {
+	tests := map[string]struct {
+		name       string
+		constraint string
+	}{
+		"GOOS": {
+			name:       "file_linux.go",
+			constraint: "linux",
+		},
+		"GOARCH": {
+			name:       "file_amd64.go",
+			constraint: "amd64",
+		},
+		"GOOS and GOARCH": {
+			name:       "file_windows_arm64.go",
+			constraint: "windows && arm64",
+		},
+		"test file": {
+			name:       "file_darwin_test.go",
+			constraint: "darwin",
+		},
+		"no suffix": {
+			name: "file.go",
+		},
+		"name only": {
+			name: "linux.go",
+		},
+		"unknown suffix": {
+			name: "file_other.go",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			expr, ok := fileNameConstraint(test.name)
+			is.Equal(ok, test.constraint != "") // Wrong constrained result
+			if ok {
+				is.Equal(expr.String(), test.constraint) // Wrong constraint
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package lang
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// Category holds the top-level funcs and types of a package that are marked
+// with the same category directive, which are documented together under a
+// heading of their own.
+type Category struct {
+	cfg   *Config
+	name  string
+	decls []*Declaration
+}
+
+// categoryDirective assigns a top-level func or type to the category named by
+// the rest of the line. It must appear in the declaration's doc comment.
+const categoryDirective = "//gomarkdoc:category "
+
+// categoryOf finds the name of the category assigned by the first category
+// directive in the comment groups, or empty string if there is none.
+func categoryOf(groups ...*ast.CommentGroup) string {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, categoryDirective) {
+				if name := strings.TrimSpace(strings.TrimPrefix(c.Text, categoryDirective)); name != "" {
+					return name
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// Level provides the default level at which the header for the category
+// should be rendered in the final documentation.
+func (c *Category) Level() int {
+	return c.cfg.Level
+}
+
+// Name provides the name of the category as written in the directive.
+func (c *Category) Name() string {
+	return c.name
+}
+
+// Declarations lists the funcs and types that belong to the category, in the
+// same order as Package.Declarations.
+func (c *Category) Declarations() []*Declaration {
+	return c.decls
+}
+
+// Category provides the name of the category assigned to the func by a
+// //gomarkdoc:category directive in its doc comment, or empty string if it has
+// none. Categories only apply to top-level funcs; those documented with a type
+// follow the type instead.
+func (fn *Func) Category() string {
+	return fn.cfg.Categories[fn.doc.Decl.Pos()]
+}
+
+// Category provides the name of the category assigned to the type by a
+// //gomarkdoc:category directive in its doc comment, or empty string if it has
+// none.
+func (typ *Type) Category() string {
+	return typ.cfg.Categories[typePos(typ.doc)]
+}
+
+// findCategories reads the category directives of the top-level funcs and
+// types in the package's files.
+func findCategories(pkg *ast.Package) map[token.Pos]string {
+	categories := make(map[token.Pos]string)
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if name := categoryOf(decl.Doc); name != "" {
+					categories[decl.Pos()] = name
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range decl.Specs {
+					// The doc comment of the declaration belongs to its only spec
+					groups := []*ast.CommentGroup{spec.(*ast.TypeSpec).Doc}
+					if len(decl.Specs) == 1 {
+						groups = append(groups, decl.Doc)
+					}
+
+					if name := categoryOf(groups...); name != "" {
+						categories[spec.Pos()] = name
+					}
+				}
+			}
+		}
+	}
+
+	return categories
+}
+
+func (d *Declaration) category() string {
+	if d.Func != nil {
+		return d.Func.Category()
+	}
+
+	return d.Type.Category()
+}
+
+// Categories lists the categories assigned to the package's top-level funcs
+// and types using //gomarkdoc:category directives, sorted by name. Declarations
+// that belong to a category are left out of Declarations.
+func (pkg *Package) Categories() []*Category {
+	cfg := pkg.cfg.Inc(1)
+
+	var categories []*Category
+	byName := make(map[string]*Category)
+	for _, decl := range pkg.declarations(pkg.cfg.Inc(2)) {
+		name := decl.category()
+		if name == "" {
+			continue
+		}
+
+		category, ok := byName[name]
+		if !ok {
+			category = &Category{cfg: cfg, name: name}
+			byName[name] = category
+			categories = append(categories, category)
+		}
+
+		category.decls = append(category.decls, decl)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].name < categories[j].name
+	})
+
+	return categories
+}
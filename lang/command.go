@@ -0,0 +1,406 @@
+package lang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	flagImportPath  = "flag"
+	pflagImportPath = "github.com/spf13/pflag"
+	cobraImportPath = "github.com/spf13/cobra"
+)
+
+// flagFuncRegex matches the names of the functions of the flag and pflag
+// packages that define a flag, capturing the type of the flag and whether the
+// flag is bound to a variable and has a shorthand.
+var flagFuncRegex = regexp.MustCompile(`^([A-Z][A-Za-z0-9]*?)(Var)?(P)?$`)
+
+// flagTypes lists the types of flags that are recognized.
+var flagTypes = map[string]bool{
+	"Bool": true, "BoolSlice": true, "Count": true, "Duration": true,
+	"DurationSlice": true, "Float32": true, "Float64": true, "Func": true,
+	"Int": true, "Int8": true, "Int16": true, "Int32": true, "Int64": true,
+	"IntSlice": true, "String": true, "StringArray": true, "StringSlice": true,
+	"StringToString": true, "Uint": true, "Uint8": true, "Uint16": true,
+	"Uint32": true, "Uint64": true, "UintSlice": true,
+}
+
+type (
+	// CommandFlag holds documentation for a command line flag defined by a
+	// main package using the flag or github.com/spf13/pflag packages.
+	CommandFlag struct {
+		cfg       *Config
+		file      string
+		line      int
+		name      string
+		shorthand string
+		typ       string
+		def       string
+		usage     string
+		posix     bool
+	}
+
+	// Subcommand holds documentation for a command declared by a main
+	// package using github.com/spf13/cobra.
+	Subcommand struct {
+		cfg   *Config
+		file  string
+		line  int
+		use   string
+		short string
+	}
+)
+
+// Name provides the name of the flag, without any leading dashes.
+func (f *CommandFlag) Name() string {
+	return f.name
+}
+
+// Shorthand provides the single letter shorthand of the flag, or the empty
+// string if it has none.
+func (f *CommandFlag) Shorthand() string {
+	return f.shorthand
+}
+
+// Syntax provides the flag as it is written on the command line, such as
+// -name for flags from the flag package or --name, -n for flags from pflag.
+func (f *CommandFlag) Syntax() string {
+	if !f.posix {
+		return "-" + f.name
+	}
+
+	if f.shorthand != "" {
+		return fmt.Sprintf("--%s, -%s", f.name, f.shorthand)
+	}
+
+	return "--" + f.name
+}
+
+// Type provides the type of the flag's value, such as string or duration.
+func (f *CommandFlag) Type() string {
+	return f.typ
+}
+
+// Default provides the source code of the flag's default value, or the empty
+// string if the default is the zero value.
+func (f *CommandFlag) Default() string {
+	return f.def
+}
+
+// Usage provides the usage text of the flag.
+func (f *CommandFlag) Usage() string {
+	return f.usage
+}
+
+// Location returns a representation of the location of the flag's definition
+// within a repository.
+func (f *CommandFlag) Location() Location {
+	return commandLocation(f.cfg, f.file, f.line)
+}
+
+// Use provides the one-line usage of the command, starting with its name.
+func (c *Subcommand) Use() string {
+	return c.use
+}
+
+// Short provides the short description of the command, or the empty string
+// if it has none.
+func (c *Subcommand) Short() string {
+	return c.short
+}
+
+// Location returns a representation of the location of the command's
+// declaration within a repository.
+func (c *Subcommand) Location() Location {
+	return commandLocation(c.cfg, c.file, c.line)
+}
+
+func commandLocation(cfg *Config, file string, line int) Location {
+	return Location{
+		Start:    Position{line, 1},
+		End:      Position{line, 1},
+		Filepath: file,
+		WorkDir:  cfg.WorkDir,
+		Repo:     cfg.Repo,
+	}
+}
+
+// findCommandUsage scans the source files of a main package for the flags it
+// defines using the flag or github.com/spf13/pflag packages and the commands
+// it declares using github.com/spf13/cobra. Only definitions with a literal
+// name are found, and flags and commands defined in other packages aren't
+// found at all. Flags are sorted by name and commands are kept in the order
+// they are declared.
+func findCommandUsage(cfg *Config, pkg *sourcePackage) ([]*CommandFlag, []*Subcommand, error) {
+	names := append([]string(nil), pkg.goFiles...)
+	sort.Strings(names)
+
+	fs := token.NewFileSet()
+
+	var (
+		flags    []*CommandFlag
+		commands []*Subcommand
+	)
+	for _, name := range names {
+		fileName := filepath.Join(pkg.dir, name)
+		parsed, err := parser.ParseFile(fs, fileName, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gomarkdoc: failed to parse package file %s: %w", name, err)
+		}
+
+		imports := fileImportNames(parsed)
+
+		ast.Inspect(parsed, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				if f, ok := parseFlagCall(n, imports, fs); ok {
+					f.cfg = cfg
+					f.file = fileName
+					f.line = fs.Position(n.Pos()).Line
+					flags = append(flags, f)
+				}
+			case *ast.CompositeLit:
+				if c, ok := parseCobraCommand(n, imports); ok {
+					c.cfg = cfg
+					c.file = fileName
+					c.line = fs.Position(n.Pos()).Line
+					commands = append(commands, c)
+				}
+			}
+
+			return true
+		})
+	}
+
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].name < flags[j].name
+	})
+
+	return flags, commands, nil
+}
+
+// fileImportNames maps the names under which the file refers to its imports
+// to their import paths.
+func fileImportNames(f *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, imp := range f.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := assumedPackageName(p)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+
+		imports[name] = p
+	}
+
+	return imports
+}
+
+func importsPath(imports map[string]string, importPath string) bool {
+	for _, p := range imports {
+		if p == importPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseFlagCall recognizes a call that defines a flag, such as
+// flag.String("name", "default", "usage") or
+// cmd.Flags().BoolVarP(&v, "name", "n", false, "usage").
+func parseFlagCall(call *ast.CallExpr, imports map[string]string, fs *token.FileSet) (*CommandFlag, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+
+	m := flagFuncRegex.FindStringSubmatch(sel.Sel.Name)
+	if m == nil || !flagTypes[m[1]] {
+		return nil, false
+	}
+
+	kind, isVar, hasShorthand := m[1], m[2] != "", m[3] != ""
+
+	// The flag must be defined on the flag or pflag package itself, on the
+	// flag set of a cobra command or on a FlagSet variable
+	var posix bool
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		switch imports[x.Name] {
+		case flagImportPath:
+		case pflagImportPath:
+			posix = true
+		case "":
+			// A FlagSet variable, which is assumed to come from pflag if the
+			// file uses pflag or cobra rather than the flag package
+			posix = hasShorthand || importsPath(imports, pflagImportPath) ||
+				(importsPath(imports, cobraImportPath) && !importsPath(imports, flagImportPath))
+		default:
+			return nil, false
+		}
+	case *ast.CallExpr:
+		inner, ok := x.Fun.(*ast.SelectorExpr)
+		if !ok || (inner.Sel.Name != "Flags" && inner.Sel.Name != "PersistentFlags") || len(x.Args) != 0 {
+			return nil, false
+		}
+
+		posix = true
+	default:
+		return nil, false
+	}
+
+	if hasShorthand && !posix {
+		return nil, false
+	}
+
+	args := call.Args
+	if isVar {
+		if len(args) == 0 {
+			return nil, false
+		}
+
+		args = args[1:]
+	}
+
+	f := &CommandFlag{posix: posix, typ: lowerFirst(kind)}
+	if f.name, ok = stringLiteral(args, 0); !ok {
+		return nil, false
+	}
+
+	args = args[1:]
+	if hasShorthand {
+		if f.shorthand, ok = stringLiteral(args, 0); !ok {
+			return nil, false
+		}
+
+		args = args[1:]
+	}
+
+	switch kind {
+	case "Count", "Func":
+		// These flags have no default value
+		if len(args) < 1 {
+			return nil, false
+		}
+	default:
+		if len(args) < 2 {
+			return nil, false
+		}
+
+		def, err := printNode(args[0], fs)
+		if err != nil {
+			return nil, false
+		}
+
+		if !isZeroLiteral(def) {
+			f.def = def
+		}
+
+		args = args[1:]
+	}
+
+	f.usage, _ = stringLiteral(args, 0)
+
+	return f, true
+}
+
+// parseCobraCommand recognizes a cobra.Command composite literal, using its
+// Use and Short fields.
+func parseCobraCommand(lit *ast.CompositeLit, imports map[string]string) (*Subcommand, bool) {
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Command" {
+		return nil, false
+	}
+
+	if x, ok := sel.X.(*ast.Ident); !ok || imports[x.Name] != cobraImportPath {
+		return nil, false
+	}
+
+	c := &Subcommand{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "Use":
+			c.use, _ = stringLiteral([]ast.Expr{kv.Value}, 0)
+		case "Short":
+			c.short, _ = stringLiteral([]ast.Expr{kv.Value}, 0)
+		}
+	}
+
+	if c.use == "" {
+		return nil, false
+	}
+
+	return c, true
+}
+
+// stringLiteral provides the value of the argument at the index if it is a
+// string literal or a concatenation of string literals.
+func stringLiteral(args []ast.Expr, i int) (string, bool) {
+	if i >= len(args) {
+		return "", false
+	}
+
+	switch e := args[i].(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+
+		x, ok := stringLiteral([]ast.Expr{e.X}, 0)
+		if !ok {
+			return "", false
+		}
+
+		y, ok := stringLiteral([]ast.Expr{e.Y}, 0)
+		return x + y, ok
+	case *ast.ParenExpr:
+		return stringLiteral([]ast.Expr{e.X}, 0)
+	default:
+		return "", false
+	}
+}
+
+func isZeroLiteral(s string) bool {
+	switch s {
+	case "false", "0", `""`, "``", "nil", "0.0":
+		return true
+	default:
+		return false
+	}
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
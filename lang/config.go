@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/doc"
 	"go/token"
 	"io"
 	"path/filepath"
@@ -23,15 +24,56 @@ type (
 		Repo    *Repo
 		PkgDir  string
 		WorkDir string
-		Log     logger.Logger
+		Symbols map[string]*Symbol
+		Imports map[string]string
+		// Implements holds the interfaces implemented by each type in the
+		// package, keyed by type name. It is only populated if requested.
+		Implements map[string][]*DocLink
+		// ConstValues holds the resolved value of each constant in the
+		// package, keyed by constant name. It is only populated if requested.
+		ConstValues map[string]string
+		// VarValues indicates whether the simple initializers of variables are
+		// listed along with their declarations.
+		VarValues bool
+		// FuncEnds holds the end of each function declaration, including its
+		// body, keyed by the position of the declaration. Bodies are dropped
+		// from the documentation, so their extent is recorded separately.
+		FuncEnds map[token.Pos]token.Pos
+		// Categories holds the category assigned to each top-level func and
+		// type by a //gomarkdoc:category directive, keyed by the position of
+		// the func's declaration or the type's spec.
+		Categories map[token.Pos]string
+		// OpaqueTypes holds the unexported types of the package that have
+		// exported methods, keyed by type name. It is only populated if
+		// requested and unexported symbols are excluded.
+		OpaqueTypes map[string]*doc.Type
+		// ExampleTags holds the build constraint of each example from a test
+		// file that is excluded under the build tags in use. It is only
+		// populated if requested.
+		ExampleTags map[*doc.Example]string
+		// FileConstraints holds the build constraint of each of the package's
+		// files that has one, keyed by the full path of the file.
+		FileConstraints map[string]string
+		// SymbolPlatforms holds the platforms on which each top-level symbol
+		// is declared, keyed by its name (or Type.Method for methods), when
+		// the package is merged from several platforms. Symbols declared on
+		// all of them are omitted. It is nil if the package isn't merged.
+		SymbolPlatforms map[string][]string
+		// ExternalLinks indicates whether types from other packages that
+		// appear in declarations are linked to their documentation.
+		ExternalLinks bool
+		Log           logger.Logger
 	}
 
 	// Repo represents information about a repository relevant to documentation
-	// generation.
+	// generation. PathFromRoot locates the working directory within the
+	// repository, while LinkRoot is the directory that source links are
+	// relative to, which defaults to the root of the repository.
 	Repo struct {
 		Remote        string
 		DefaultBranch string
 		PathFromRoot  string
+		LinkRoot      string
 	}
 
 	// Location holds information for identifying a position within a file and
@@ -108,12 +150,24 @@ func NewConfig(log logger.Logger, workDir string, pkgDir string, opts ...ConfigO
 // Inc copies the Config and increments the level by the provided step.
 func (c *Config) Inc(step int) *Config {
 	return &Config{
-		FileSet: c.FileSet,
-		Level:   c.Level + step,
-		PkgDir:  c.PkgDir,
-		WorkDir: c.WorkDir,
-		Repo:    c.Repo,
-		Log:     c.Log,
+		FileSet:         c.FileSet,
+		Level:           c.Level + step,
+		PkgDir:          c.PkgDir,
+		WorkDir:         c.WorkDir,
+		Repo:            c.Repo,
+		Symbols:         c.Symbols,
+		Imports:         c.Imports,
+		Implements:      c.Implements,
+		ConstValues:     c.ConstValues,
+		VarValues:       c.VarValues,
+		FuncEnds:        c.FuncEnds,
+		Categories:      c.Categories,
+		OpaqueTypes:     c.OpaqueTypes,
+		ExampleTags:     c.ExampleTags,
+		FileConstraints: c.FileConstraints,
+		SymbolPlatforms: c.SymbolPlatforms,
+		ExternalLinks:   c.ExternalLinks,
+		Log:             c.Log,
 	}
 }
 
@@ -136,6 +190,16 @@ func ConfigWithRepoOverrides(overrides *Repo) ConfigOption {
 			overrides.PathFromRoot = unslashed
 		}
 
+		if overrides.LinkRoot != "" {
+			unslashed := filepath.FromSlash(overrides.LinkRoot)
+
+			if len(unslashed) == 0 || unslashed[0] != filepath.Separator {
+				return fmt.Errorf("provided repository link root %s must be absolute", overrides.LinkRoot)
+			}
+
+			overrides.LinkRoot = unslashed
+		}
+
 		c.Repo = overrides
 		return nil
 	}
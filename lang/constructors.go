@@ -0,0 +1,92 @@
+package lang
+
+import (
+	"go/doc"
+	"sort"
+)
+
+// PackageWithConstructorPatterns can be used along with the LoadPackage
+// function to group additional top-level functions with the types they
+// construct based on their names. The standard library groups a function with a
+// type only if the type is the only type of the package among its results, so
+// factory functions that return an interface from another package or several of
+// the package's types are otherwise listed on their own. A function is grouped
+// with a type if its name is one of the prefixes followed by the name of the
+// type (e.g. MakeWidget for the prefix Make) or the name of the type followed
+// by one of the suffixes (e.g. WidgetFromFile for the suffix FromFile). If a
+// function matches several types, the longest type name wins.
+func PackageWithConstructorPatterns(prefixes, suffixes []string) PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.constructorPrefixes = append(opts.constructorPrefixes, prefixes...)
+		opts.constructorSuffixes = append(opts.constructorSuffixes, suffixes...)
+		return nil
+	}
+}
+
+// PackageWithConstructorsUngrouped can be used along with the LoadPackage
+// function to list all functions at the top level of the package rather than
+// grouping constructors with the types they return.
+func PackageWithConstructorsUngrouped() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.ungroupConstructors = true
+		return nil
+	}
+}
+
+// groupConstructors moves the top-level functions whose names match one of
+// the constructor patterns for a type of the package into that type's funcs.
+func groupConstructors(pkg *doc.Package, prefixes, suffixes []string) {
+	var funcs []*doc.Func
+	for _, fn := range pkg.Funcs {
+		typ := constructedType(pkg.Types, fn.Name, prefixes, suffixes)
+		if typ == nil {
+			funcs = append(funcs, fn)
+			continue
+		}
+
+		typ.Funcs = append(typ.Funcs, fn)
+		sort.Slice(typ.Funcs, func(i, j int) bool {
+			return typ.Funcs[i].Name < typ.Funcs[j].Name
+		})
+	}
+
+	pkg.Funcs = funcs
+}
+
+// constructedType finds the type that the function of the provided name
+// constructs according to the patterns, or nil if it matches none of them.
+func constructedType(types []*doc.Type, name string, prefixes, suffixes []string) *doc.Type {
+	var match *doc.Type
+	for _, typ := range types {
+		if match != nil && len(typ.Name) <= len(match.Name) {
+			continue
+		}
+
+		for _, prefix := range prefixes {
+			if name == prefix+typ.Name {
+				match = typ
+			}
+		}
+
+		for _, suffix := range suffixes {
+			if name == typ.Name+suffix {
+				match = typ
+			}
+		}
+	}
+
+	return match
+}
+
+// ungroupConstructors moves the funcs associated with each type of the
+// package to the top level of the package.
+func ungroupConstructors(pkg *doc.Package) {
+	for _, typ := range pkg.Types {
+		pkg.Funcs = append(pkg.Funcs, typ.Funcs...)
+		typ.Funcs = nil
+	}
+
+	sort.Slice(pkg.Funcs, func(i, j int) bool {
+		return pkg.Funcs[i].Name < pkg.Funcs[j].Name
+	})
+}
@@ -0,0 +1,66 @@
+package lang
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// ConstValue holds the resolved value of a single constant, or the initializer
+// of a single variable.
+type ConstValue struct {
+	Name  string
+	Value string
+}
+
+// newConstValues evaluates the value of each of the constants declared in the
+// type checked package, keyed by the name of the constant.
+func newConstValues(tpkg *types.Package) map[string]string {
+	values := make(map[string]string)
+	scope := tpkg.Scope()
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok {
+			values[name] = c.Val().String()
+		}
+	}
+
+	return values
+}
+
+// isLiteral indicates whether the expression is a literal, whose value is
+// already evident from the declaration.
+func isLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		return e.Name == "true" || e.Name == "false"
+	case *ast.ParenExpr:
+		return isLiteral(e.X)
+	case *ast.UnaryExpr:
+		return isLiteral(e.X)
+	default:
+		return false
+	}
+}
+
+// isSimpleInitializer indicates whether the expression initializing a variable
+// is simple enough to be shown along with the variable's name: a literal or a
+// sentinel error created by errors.New with a literal message.
+func isSimpleInitializer(expr ast.Expr) bool {
+	if isLiteral(expr) {
+		return true
+	}
+
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || !isLiteral(call.Args[0]) {
+		return false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "New" {
+		return false
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "errors"
+}
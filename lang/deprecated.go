@@ -0,0 +1,82 @@
+package lang
+
+import (
+	"go/doc"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// deprecationPrefix begins a paragraph of documentation which indicates that
+// a symbol is deprecated, following the convention recognized by the go tool
+// and pkg.go.dev.
+const deprecationPrefix = "Deprecated:"
+
+// isDeprecationParagraph reports whether the provided paragraph of
+// documentation is a deprecation note.
+func isDeprecationParagraph(paragraph string) bool {
+	paragraph = strings.TrimSpace(paragraph)
+	if !strings.HasPrefix(paragraph, deprecationPrefix) {
+		return false
+	}
+
+	r, _ := utf8.DecodeRuneInString(paragraph[len(deprecationPrefix):])
+	return unicode.IsSpace(r)
+}
+
+// isDeprecated reports whether the provided raw documentation text contains a
+// deprecation note.
+func isDeprecated(text string) bool {
+	for _, paragraph := range multilineRegex.Split(normalizeDoc(text), -1) {
+		if isDeprecationParagraph(paragraph) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeDeprecated strips all symbols with a deprecation note from the
+// package, along with any funcs and methods associated with deprecated types.
+func removeDeprecated(pkg *doc.Package) {
+	pkg.Consts = removeDeprecatedValues(pkg.Consts)
+	pkg.Vars = removeDeprecatedValues(pkg.Vars)
+	pkg.Funcs = removeDeprecatedFuncs(pkg.Funcs)
+
+	var types []*doc.Type
+	for _, typ := range pkg.Types {
+		if isDeprecated(typ.Doc) {
+			continue
+		}
+
+		typ.Consts = removeDeprecatedValues(typ.Consts)
+		typ.Vars = removeDeprecatedValues(typ.Vars)
+		typ.Funcs = removeDeprecatedFuncs(typ.Funcs)
+		typ.Methods = removeDeprecatedFuncs(typ.Methods)
+		types = append(types, typ)
+	}
+
+	pkg.Types = types
+}
+
+func removeDeprecatedValues(values []*doc.Value) []*doc.Value {
+	var result []*doc.Value
+	for _, v := range values {
+		if !isDeprecated(v.Doc) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func removeDeprecatedFuncs(funcs []*doc.Func) []*doc.Func {
+	var result []*doc.Func
+	for _, fn := range funcs {
+		if !isDeprecated(fn.Doc) {
+			result = append(result, fn)
+		}
+	}
+
+	return result
+}
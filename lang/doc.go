@@ -71,6 +71,18 @@ func (d *Doc) Blocks() []*Block {
 	return d.blocks
 }
 
+// Deprecated indicates whether the documentation contains a deprecation
+// paragraph, marking the symbol it documents as deprecated.
+func (d *Doc) Deprecated() bool {
+	for _, b := range d.blocks {
+		if b.IsDeprecation() {
+			return true
+		}
+	}
+
+	return false
+}
+
 func parseBlankLine(text []byte) (length int, ok bool) {
 	if l := blankLineRegex.Find(text); l != nil {
 		// Ignore blank lines
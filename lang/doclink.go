@@ -0,0 +1,233 @@
+package lang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Span defines a single inline element within the text of a paragraph
+	// block, such as plain text or a doc link.
+	Span struct {
+		kind SpanKind
+		text string
+		link *DocLink
+	}
+
+	// SpanKind identifies the type of inline element represented by the
+	// corresponding Span.
+	SpanKind string
+
+	// DocLink holds the target of a doc link within a documentation comment,
+	// such as [Renderer.File] or [net/http.Client]. Links to symbols in the
+	// same package are resolved to the Symbol being referenced, while links to
+	// other packages identify the package by its import path.
+	DocLink struct {
+		// ImportPath holds the import path of the package being referenced
+		// if the link refers to a different package.
+		ImportPath string
+
+		// Name holds the name of the symbol being referenced, with methods
+		// represented as Type.Method. The name is empty for links to an
+		// entire package.
+		Name string
+
+		// Symbol holds the symbol being referenced if the link refers to a
+		// symbol within the same package.
+		Symbol *Symbol
+	}
+)
+
+const (
+	// TextSpan defines a span of plain text.
+	TextSpan SpanKind = "text"
+
+	// LinkSpan defines a span that links to the documentation of a package
+	// or symbol.
+	LinkSpan SpanKind = "link"
+)
+
+// pkgGoDevURL is used to link to the documentation of other packages.
+const pkgGoDevURL = "https://pkg.go.dev/"
+
+var docLinkRegex = regexp.MustCompile(`\[(\*?[\p{L}_][\p{L}\p{N}_./-]*)\]([^(\[:]|$)`)
+
+// Kind provides the kind of inline element that this span represents.
+func (s *Span) Kind() SpanKind {
+	return s.kind
+}
+
+// Text provides the raw text of the span. For links, this is the text that is
+// displayed for the link.
+func (s *Span) Text() string {
+	return s.text
+}
+
+// Link provides the target of the span if it is a LinkSpan, or nil otherwise.
+func (s *Span) Link() *DocLink {
+	return s.link
+}
+
+// IsLocal indicates whether the link refers to a symbol within the same
+// package, which is documented in the same output.
+func (l *DocLink) IsLocal() bool {
+	return l.Symbol != nil
+}
+
+// Text provides the name of the link's target as it would be written in Go
+// code, qualified by the package name for symbols in other packages.
+func (l *DocLink) Text() string {
+	if l.IsLocal() || l.ImportPath == "builtin" {
+		return l.Name
+	}
+
+	if l.Name == "" {
+		return l.ImportPath
+	}
+
+	return fmt.Sprintf("%s.%s", assumedPackageName(l.ImportPath), l.Name)
+}
+
+// URL provides the URL of the documentation on pkg.go.dev for links to other
+// packages. The empty string is returned for local links.
+func (l *DocLink) URL() string {
+	if l.IsLocal() {
+		return ""
+	}
+
+	if l.Name == "" {
+		return pkgGoDevURL + l.ImportPath
+	}
+
+	return fmt.Sprintf("%s%s#%s", pkgGoDevURL, l.ImportPath, l.Name)
+}
+
+// parseSpans splits the provided paragraph text into plain text and doc
+// links. Bracketed text that doesn't resolve to a symbol or package is left as
+// plain text.
+func parseSpans(cfg *Config, text string) []*Span {
+	var (
+		spans  []*Span
+		cursor int
+	)
+
+	addText := func(t string) {
+		if t == "" {
+			return
+		}
+
+		if len(spans) > 0 && spans[len(spans)-1].kind == TextSpan {
+			spans[len(spans)-1].text += t
+			return
+		}
+
+		spans = append(spans, &Span{kind: TextSpan, text: t})
+	}
+
+	for _, loc := range docLinkRegex.FindAllStringSubmatchIndex(text, -1) {
+		target := text[loc[2]:loc[3]]
+		link, ok := resolveDocLink(cfg, target)
+		if !ok {
+			continue
+		}
+
+		addText(text[cursor:loc[0]])
+		spans = append(spans, &Span{kind: LinkSpan, text: target, link: link})
+		cursor = loc[3] + 1
+	}
+
+	addText(text[cursor:])
+
+	return spans
+}
+
+// resolveDocLink resolves the target of a doc link using the rules for doc
+// links in Go doc comments. Links may refer to a symbol in the package (Name
+// or Type.Method), a package (path or an imported package's name) or a
+// symbol in another package (pkg.Name or pkg.Type.Method).
+func resolveDocLink(cfg *Config, target string) (*DocLink, bool) {
+	target = strings.TrimPrefix(target, "*")
+
+	var name string
+	if i := strings.LastIndex(target, "/"); i >= 0 {
+		// Full import path, optionally followed by a symbol. The last element
+		// of an import path may contain dots too (e.g. gopkg.in/yaml.v2), so
+		// the longest prefix naming a known package is used.
+		for j := len(target); j > i; j = strings.LastIndex(target[:j], ".") {
+			if knownPackage(cfg, target[:j]) {
+				return &DocLink{ImportPath: target[:j], Name: strings.TrimPrefix(target[j:], ".")}, true
+			}
+		}
+
+		return nil, false
+	}
+
+	if s, ok := cfg.Symbols[target]; ok {
+		return &DocLink{Name: target, Symbol: s}, true
+	}
+
+	parts := strings.SplitN(target, ".", 2)
+	importPath, ok := cfg.Imports[parts[0]]
+	if !ok {
+		return nil, false
+	}
+
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+
+	return &DocLink{ImportPath: importPath, Name: name}, true
+}
+
+// knownPackage determines whether a doc link may refer to the package with the
+// provided import path: a package imported by the documented package or a
+// package of the standard library.
+func knownPackage(cfg *Config, importPath string) bool {
+	if importsPath(cfg.Imports, importPath) {
+		return true
+	}
+
+	pkg, err := build.Default.Import(importPath, "", build.FindOnly)
+	return err == nil && pkg.Goroot
+}
+
+// externalLinks lists links to the exported symbols of imported packages that
+// are referenced within the provided node, without duplicates. Nothing is
+// listed unless the Config enables ExternalLinks.
+func externalLinks(cfg *Config, node ast.Node) []*DocLink {
+	if !cfg.ExternalLinks {
+		return nil
+	}
+
+	var links []*DocLink
+	seen := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || !sel.Sel.IsExported() {
+			return true
+		}
+
+		importPath, ok := cfg.Imports[pkg.Name]
+		if !ok {
+			return true
+		}
+
+		link := &DocLink{ImportPath: importPath, Name: sel.Sel.Name}
+		if url := link.URL(); !seen[url] {
+			seen[url] = true
+			links = append(links, link)
+		}
+
+		return false
+	})
+
+	return links
+}
@@ -0,0 +1,81 @@
+package lang
+
+import "go/ast"
+
+// Embedded holds documentation information for a type that is embedded
+// within a struct or interface type.
+type Embedded struct {
+	cfg  *Config
+	expr ast.Expr
+}
+
+// NewEmbedded creates a new Embedded from the type expression of the embedded
+// field.
+func NewEmbedded(cfg *Config, expr ast.Expr) *Embedded {
+	return &Embedded{cfg, expr}
+}
+
+// Name provides the embedded type as it is written in the declaration, such as
+// *Base or io.Reader.
+func (e *Embedded) Name() (string, error) {
+	return printNode(e.expr, e.cfg.FileSet)
+}
+
+// Link provides a link to the documentation of the embedded type, or nil if
+// the type isn't declared in the package or one of its imports.
+func (e *Embedded) Link() *DocLink {
+	return typeLink(e.cfg, e.expr)
+}
+
+// newEmbedded lists the types embedded within the provided struct or
+// interface type. Other kinds of types have no embedded types.
+func newEmbedded(cfg *Config, typeExpr ast.Expr) []*Embedded {
+	var fields *ast.FieldList
+	switch t := typeExpr.(type) {
+	case *ast.StructType:
+		fields = t.Fields
+	case *ast.InterfaceType:
+		fields = t.Methods
+	default:
+		return nil
+	}
+
+	if fields == nil {
+		return nil
+	}
+
+	var embedded []*Embedded
+	for _, field := range fields.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+
+		// Interfaces may also embed unions and approximation elements, which
+		// don't refer to a single type
+		switch unwrapTypeExpr(field.Type).(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			embedded = append(embedded, NewEmbedded(cfg, field.Type))
+		}
+	}
+
+	return embedded
+}
+
+// unwrapTypeExpr strips pointers, parentheses and type arguments from the
+// provided type expression, leaving the name of the type being referenced.
+func unwrapTypeExpr(expr ast.Expr) ast.Expr {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		default:
+			return expr
+		}
+	}
+}
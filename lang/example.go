@@ -87,3 +87,18 @@ func (ex *Example) Output() string {
 func (ex *Example) HasOutput() bool {
 	return ex.doc.Output != "" || ex.doc.EmptyOutput
 }
+
+// RequiredTags provides the build constraint of the test file declaring the
+// example if the file is excluded under the build tags in use, such as
+// "integration". It is empty for all other examples, and is only populated if
+// the package was created using PackageWithTaggedExamples.
+func (ex *Example) RequiredTags() string {
+	return ex.cfg.ExampleTags[ex.doc]
+}
+
+// Playable indicates whether the example is a complete program that can be
+// run on its own, such as on the Go Playground. If so, Code provides the full
+// program.
+func (ex *Example) Playable() bool {
+	return ex.doc.Play != nil
+}
@@ -0,0 +1,111 @@
+package lang
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// expandPrefix turns a printed declaration into a file that can be parsed.
+const expandPrefix = "package p\n\n"
+
+// expandAnonymousTypes reformats the printed declaration so that each struct
+// or interface type with fields or methods that is written on a single line,
+// such as an anonymous struct parameter, is printed with one field or method
+// per line. Constraints in type parameter lists are left as they are. The
+// declaration is returned unchanged if there is nothing to expand or it can't
+// be parsed.
+func expandAnonymousTypes(decl string) string {
+	fs := token.NewFileSet()
+	src := expandPrefix + decl
+	f, err := parser.ParseFile(fs, "", src, parser.ParseComments)
+	if err != nil || len(f.Decls) != 1 {
+		return decl
+	}
+
+	skip := make(map[ast.Node]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		var params *ast.FieldList
+		switch n := n.(type) {
+		case *ast.FuncType:
+			params = n.TypeParams
+		case *ast.TypeSpec:
+			params = n.TypeParams
+		}
+
+		if params != nil {
+			ast.Inspect(params, func(n ast.Node) bool {
+				skip[n] = true
+				return true
+			})
+		}
+
+		return true
+	})
+
+	file := fs.File(f.Pos())
+	line := func(pos token.Pos) int {
+		return file.Line(pos)
+	}
+
+	// Newlines are inserted after the opening brace, before each subsequent
+	// field and before the closing brace of each type to expand
+	var breaks []int
+	addBreaks := func(fields *ast.FieldList) {
+		if fields == nil || len(fields.List) == 0 || line(fields.Opening) != line(fields.Closing) {
+			return
+		}
+
+		breaks = append(breaks, file.Offset(fields.Opening)+1)
+		for _, field := range fields.List[1:] {
+			breaks = append(breaks, file.Offset(field.Pos()))
+		}
+
+		breaks = append(breaks, file.Offset(fields.Closing))
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if skip[n] {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.StructType:
+			addBreaks(n.Fields)
+		case *ast.InterfaceType:
+			addBreaks(n.Methods)
+		}
+
+		return true
+	})
+
+	if len(breaks) == 0 {
+		return decl
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(breaks)))
+	for _, offset := range breaks {
+		src = src[:offset] + "\n" + src[offset:]
+	}
+
+	fs = token.NewFileSet()
+	f, err = parser.ParseFile(fs, "", src, parser.ParseComments)
+	if err != nil || len(f.Decls) != 1 {
+		return decl
+	}
+
+	cfg := printer.Config{
+		Mode:     printer.UseSpaces,
+		Tabwidth: 4,
+	}
+
+	var out strings.Builder
+	if err := cfg.Fprint(&out, fs, &printer.CommentedNode{Node: f.Decls[0], Comments: f.Comments}); err != nil {
+		return decl
+	}
+
+	return out.String()
+}
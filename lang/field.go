@@ -0,0 +1,96 @@
+package lang
+
+import (
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Field holds documentation information for a single field of a struct type.
+// Fields declared together (e.g. X, Y int) are represented individually.
+type Field struct {
+	cfg   *Config
+	name  string
+	field *ast.Field
+}
+
+// NewField creates a new Field for the field with the provided name from the
+// field list entry that declares it.
+func NewField(cfg *Config, name string, field *ast.Field) *Field {
+	return &Field{cfg, name, field}
+}
+
+// Name provides the name of the field. Embedded fields are named after the
+// type they embed.
+func (f *Field) Name() string {
+	return f.name
+}
+
+// Embedded indicates whether the field is an embedded field.
+func (f *Field) Embedded() bool {
+	return len(f.field.Names) == 0
+}
+
+// Type provides the type of the field as it is written in the declaration.
+func (f *Field) Type() (string, error) {
+	return printNode(f.field.Type, f.cfg.FileSet)
+}
+
+// Tag provides the value associated with the key in the field's struct tag
+// (e.g. "name,omitempty" for the json key), or empty string if the key isn't
+// present.
+func (f *Field) Tag(key string) string {
+	if f.field.Tag == nil {
+		return ""
+	}
+
+	tag, err := strconv.Unquote(f.field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+
+	return reflect.StructTag(tag).Get(key)
+}
+
+// Description provides the text of the field's doc comment, or its line
+// comment if there is no doc comment, collapsed onto a single line.
+func (f *Field) Description() string {
+	text := f.field.Doc.Text()
+	if text == "" {
+		text = f.field.Comment.Text()
+	}
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// newFields lists the fields of the provided type if it is a struct type.
+// Other kinds of types have no fields.
+func newFields(cfg *Config, typeExpr ast.Expr) []*Field {
+	st, ok := typeExpr.(*ast.StructType)
+	if !ok || st.Fields == nil {
+		return nil
+	}
+
+	var fields []*Field
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			name := unwrapTypeExpr(field.Type)
+			if sel, ok := name.(*ast.SelectorExpr); ok {
+				name = sel.Sel
+			}
+
+			if ident, ok := name.(*ast.Ident); ok {
+				fields = append(fields, NewField(cfg, ident.Name, field))
+			}
+
+			continue
+		}
+
+		for _, name := range field.Names {
+			fields = append(fields, NewField(cfg, name.Name, field))
+		}
+	}
+
+	return fields
+}
@@ -16,3 +16,15 @@ func NewFile(header, footer string, packages []*Package) *File {
 		Packages: packages,
 	}
 }
+
+// Repo provides the repository that the packages of the file are located in,
+// or nil if it isn't known for any of them.
+func (f *File) Repo() *Repo {
+	for _, pkg := range f.Packages {
+		if repo := pkg.Repo(); repo != nil {
+			return repo
+		}
+	}
+
+	return nil
+}
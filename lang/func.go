@@ -2,6 +2,7 @@ package lang
 
 import (
 	"fmt"
+	"go/ast"
 	"go/doc"
 	"go/token"
 	"strings"
@@ -49,12 +50,71 @@ func (fn *Func) Receiver() string {
 	return fn.doc.Recv
 }
 
+// Promoted indicates whether the func is a method promoted from a type that
+// is embedded within the receiver type.
+func (fn *Func) Promoted() bool {
+	return fn.doc.Level > 0
+}
+
+// Origin provides the type of the receiver that originally declares the
+// method if it is promoted from an embedded type, or empty string otherwise.
+func (fn *Func) Origin() string {
+	if !fn.Promoted() {
+		return ""
+	}
+
+	return fn.doc.Orig
+}
+
+// OriginSymbol provides the symbol of the original declaration of a method
+// promoted from an embedded type, or nil if the method isn't promoted or the
+// original declaration isn't documented.
+func (fn *Func) OriginSymbol() *Symbol {
+	if !fn.Promoted() {
+		return nil
+	}
+
+	recv := strings.Split(strings.TrimPrefix(fn.doc.Orig, "*"), "[")[0]
+	return fn.cfg.Symbols[fmt.Sprintf("%s.%s", recv, fn.doc.Name)]
+}
+
 // Location returns a representation of the node's location in a file within a
-// repository.
+// repository. The location spans the full declaration, including the body.
 func (fn *Func) Location() Location {
+	loc := NewLocation(fn.cfg, fn.doc.Decl)
+	if end, ok := fn.cfg.FuncEnds[fn.doc.Decl.Pos()]; ok {
+		pos := fn.cfg.FileSet.Position(end)
+		loc.End = Position{pos.Line, pos.Column}
+	}
+
+	return loc
+}
+
+// SignatureLocation returns a representation of the location of the function's
+// signature in a file within a repository, leaving out its body.
+func (fn *Func) SignatureLocation() Location {
 	return NewLocation(fn.cfg, fn.doc.Decl)
 }
 
+// BuildConstraint provides the build constraint of the file declaring the
+// function, including the GOOS and GOARCH implied by the file's name, or the empty
+// string if the file has no constraint. It is always empty when the package is
+// documented for several platforms, in which case Platforms applies instead.
+func (fn *Func) BuildConstraint() string {
+	if fn.cfg.SymbolPlatforms != nil {
+		return ""
+	}
+
+	return fn.cfg.FileConstraints[fn.Location().Filepath]
+}
+
+// Platforms provides the platforms on which the function is declared when the
+// package is documented for several platforms, or nil if it is declared on all
+// of them.
+func (fn *Func) Platforms() []string {
+	return fn.cfg.SymbolPlatforms[fn.platformKey()]
+}
+
 // Summary provides the one-sentence summary of the function's documentation
 // comment
 func (fn *Func) Summary() string {
@@ -74,6 +134,76 @@ func (fn *Func) Signature() (string, error) {
 	return printNode(fn.doc.Decl, token.NewFileSet())
 }
 
+// SignatureBlock provides the function's signature formatted for display as a
+// block of code. Unlike Signature, which is kept to a single line for use in
+// indexes, struct and interface types in the signature are expanded with one
+// field or method per line.
+func (fn *Func) SignatureBlock() (string, error) {
+	sig, err := fn.Signature()
+	if err != nil {
+		return "", err
+	}
+
+	return expandAnonymousTypes(sig), nil
+}
+
+// Source provides the full source code of the function's declaration,
+// including its body.
+func (fn *Func) Source() (string, error) {
+	return declSource(fn.cfg, fn.doc.Decl)
+}
+
+// OpaqueTypes lists the unexported types with exported methods that appear in
+// the function's signature, so that the methods available on the opaque
+// values it accepts or returns can be documented. It is only populated if the
+// package was created using PackageWithOpaqueTypes.
+func (fn *Func) OpaqueTypes() []*Type {
+	if len(fn.cfg.OpaqueTypes) == 0 {
+		return nil
+	}
+
+	var types []*Type
+	seen := make(map[string]bool)
+	ast.Inspect(fn.doc.Decl.Type, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || seen[ident.Name] {
+			return true
+		}
+
+		if t, ok := fn.cfg.OpaqueTypes[ident.Name]; ok && len(t.Methods) > 0 {
+			seen[ident.Name] = true
+			types = append(types, NewType(fn.cfg.Inc(1), t, nil))
+		}
+
+		return true
+	})
+
+	return types
+}
+
+// ExternalLinks provides links to the documentation of the types from other
+// packages that appear in the function's signature, in the order in which
+// they first appear. It is only populated if the package was created using
+// PackageWithExternalLinks.
+func (fn *Func) ExternalLinks() []*DocLink {
+	return externalLinks(fn.cfg, fn.doc.Decl.Type)
+}
+
+// TypeParams lists the type parameters of the function, or nil if the function
+// is not generic. Methods never declare type parameters of their own, so the
+// type parameters of a method's receiver are available from the receiver's
+// Type instead.
+func (fn *Func) TypeParams() []*TypeParam {
+	return newTypeParams(fn.cfg, fn.doc.Decl.Type.TypeParams)
+}
+
+// TypeParamsDecl provides the raw text representation of the function's type
+// parameter list (e.g. [K comparable, V any]), or the empty string if the
+// function is not generic.
+func (fn *Func) TypeParamsDecl() (string, error) {
+	return typeParamsDecl(fn.doc.Decl.Type.TypeParams)
+}
+
 // Examples provides the list of examples from the list given on initialization
 // that pertain to the function.
 func (fn *Func) Examples() (examples []*Example) {
@@ -90,7 +220,7 @@ func (fn *Func) Examples() (examples []*Example) {
 		switch {
 		case example.Name == fullName:
 			name = ""
-		case strings.HasPrefix(example.Name, underscorePrefix):
+		case strings.HasPrefix(example.Name, underscorePrefix) && isExampleSuffix(example.Name[len(underscorePrefix):]):
 			name = example.Name[len(underscorePrefix):]
 		default:
 			// TODO: better filtering
@@ -103,6 +233,21 @@ func (fn *Func) Examples() (examples []*Example) {
 	return
 }
 
+// platformKey provides the key of the func in Config.SymbolPlatforms. Promoted
+// methods use the type that originally declares them.
+func (fn *Func) platformKey() string {
+	if fn.doc.Recv == "" {
+		return fn.doc.Name
+	}
+
+	recv := fn.rawRecv()
+	if fn.doc.Level > 0 {
+		recv = strings.TrimPrefix(strings.Split(fn.doc.Orig, "[")[0], "*")
+	}
+
+	return recv + "." + fn.doc.Name
+}
+
 func (fn *Func) rawRecv() string {
 	// remove type parameters
 	recv := strings.Split(fn.doc.Recv, "[")[0]
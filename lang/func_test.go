@@ -113,11 +113,112 @@ func TestFunc_Location(t *testing.T) {
 	loc := fn.Location()
 	is.Equal(loc.Start.Line, 14)
 	is.Equal(loc.Start.Col, 1)
+	is.Equal(loc.End.Line, 16)
+	is.Equal(loc.End.Col, 2)
+	is.True(strings.HasSuffix(loc.Filepath, "func.go"))
+}
+
+func TestFunc_SignatureLocation(t *testing.T) {
+	is := is.New(t)
+
+	fn, err := loadFunc("../testData/lang/function", "Standalone")
+	is.NoErr(err)
+
+	loc := fn.SignatureLocation()
+	is.Equal(loc.Start.Line, 14)
+	is.Equal(loc.Start.Col, 1)
 	is.Equal(loc.End.Line, 14)
-	is.Equal(loc.End.Col, 48)
 	is.True(strings.HasSuffix(loc.Filepath, "func.go"))
 }
 
+func TestFunc_BuildConstraint(t *testing.T) {
+	is := is.New(t)
+
+	fn, err := loadFunc("../testData/lang/function", "Accelerated")
+	is.NoErr(err)
+
+	is.Equal(fn.BuildConstraint(), "!purego")
+}
+
+func TestFunc_BuildConstraint_unconstrained(t *testing.T) {
+	is := is.New(t)
+
+	fn, err := loadFunc("../testData/lang/function", "Standalone")
+	is.NoErr(err)
+
+	is.Equal(fn.BuildConstraint(), "")
+}
+
+func TestFunc_SignatureBlock(t *testing.T) {
+	is := is.New(t)
+
+	fn, err := loadFunc("../testData/lang/function", "Configure")
+	is.NoErr(err)
+
+	sig, err := fn.Signature()
+	is.NoErr(err)
+	is.Equal(sig, "func Configure(opts struct{ Name string }) interface{ Close() error }")
+
+	block, err := fn.SignatureBlock()
+	is.NoErr(err)
+	is.Equal(block, `func Configure(opts struct {
+    Name string
+}) interface {
+    Close() error
+}`)
+}
+
+func TestFunc_Source(t *testing.T) {
+	is := is.New(t)
+
+	fn, err := loadFunc("../testData/lang/function", "Shift")
+	is.NoErr(err)
+
+	src, err := fn.Source()
+	is.NoErr(err)
+	is.Equal(src, "func (p Point) Shift(dx, dy int) Point {\n\treturn Point{p.X + dx, p.Y + dy}\n}")
+}
+
+func TestFunc_OpaqueTypes(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithOpaqueTypes())
+	is.NoErr(err)
+
+	var open *lang.Func
+	for _, fn := range pkg.Funcs() {
+		if fn.Name() == "Open" {
+			open = fn
+		}
+	}
+
+	is.True(open != nil)
+
+	types := open.OpaqueTypes()
+	is.Equal(len(types), 1)
+	is.Equal(types[0].Name(), "conn")
+
+	methods := types[0].Methods()
+	is.Equal(len(methods), 2) // only exported methods are included
+	is.Equal(methods[0].Name(), "Addr")
+	is.Equal(methods[1].Name(), "Close")
+}
+
+func TestFunc_OpaqueTypes_disabled(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	for _, fn := range pkg.Funcs() {
+		is.Equal(len(fn.OpaqueTypes()), 0)
+	}
+}
+
 func TestFunc_Examples_generic(t *testing.T) {
 	is := is.New(t)
 	fn, err := loadFunc("../testData/lang/function", "WithGenericReceiver")
@@ -130,6 +231,142 @@ func TestFunc_Examples_generic(t *testing.T) {
 	is.Equal(ex.Name(), "")
 }
 
+func TestFunc_TypeParams(t *testing.T) {
+	is := is.New(t)
+	fn, err := loadFunc("../testData/lang/function", "Sum")
+	is.NoErr(err)
+
+	decl, err := fn.TypeParamsDecl()
+	is.NoErr(err)
+	is.Equal(decl, "[K comparable, V Number]")
+
+	params := fn.TypeParams()
+	is.Equal(len(params), 2)
+
+	is.Equal(params[0].Name(), "K")
+	constraint, err := params[0].Constraint()
+	is.NoErr(err)
+	is.Equal(constraint, "comparable")
+	is.True(params[0].ConstraintSymbol() == nil) // builtin constraints have no symbol
+
+	is.Equal(params[1].Name(), "V")
+	constraint, err = params[1].Constraint()
+	is.NoErr(err)
+	is.Equal(constraint, "Number")
+
+	sym := params[1].ConstraintSymbol()
+	is.True(sym != nil) // local constraint should resolve to a symbol
+	is.Equal(sym.Kind, lang.TypeSymbol)
+	is.Equal(sym.Name, "Number")
+	is.True(params[1].Union() == nil) // named constraints aren't unions
+}
+
+func TestFunc_TypeParams_union(t *testing.T) {
+	is := is.New(t)
+	fn, err := loadFunc("../testData/lang/function", "Clamp")
+	is.NoErr(err)
+
+	params := fn.TypeParams()
+	is.Equal(len(params), 1)
+	is.True(params[0].ConstraintSymbol() == nil) // unions have no symbol
+
+	union := params[0].Union()
+	is.True(union != nil)
+
+	terms := union.Terms()
+	is.Equal(len(terms), 2)
+
+	name, err := terms[0].Name()
+	is.NoErr(err)
+	is.Equal(name, "int")
+	is.True(terms[0].Tilde())
+
+	name, err = terms[1].Name()
+	is.NoErr(err)
+	is.Equal(name, "Celsius")
+	is.True(!terms[1].Tilde())
+	is.True(terms[1].Link().IsLocal())
+}
+
+func TestFunc_Doc_links(t *testing.T) {
+	is := is.New(t)
+	fn, err := loadFunc("../testData/lang/function", "Sum")
+	is.NoErr(err)
+
+	blocks := fn.Doc().Blocks()
+	is.Equal(len(blocks), 2)
+	is.True(!blocks[0].HasLinks())
+	is.True(blocks[1].HasLinks())
+
+	spans := blocks[1].Spans()
+	is.Equal(len(spans), 5)
+
+	is.Equal(spans[0].Kind(), lang.TextSpan)
+	is.Equal(spans[0].Text(), "The values may be of any type satisfying ")
+
+	is.Equal(spans[1].Kind(), lang.LinkSpan)
+	is.Equal(spans[1].Text(), "Number")
+	is.True(spans[1].Link().IsLocal())
+	is.Equal(spans[1].Link().Symbol.HeaderText, "type Number")
+
+	is.Equal(spans[2].Text(), ". For arbitrary precision arithmetic, see ")
+
+	is.Equal(spans[3].Kind(), lang.LinkSpan)
+	is.Equal(spans[3].Text(), "math/big.Int")
+	is.True(!spans[3].Link().IsLocal())
+	is.Equal(spans[3].Link().URL(), "https://pkg.go.dev/math/big#Int")
+
+	is.Equal(spans[4].Text(), " instead.")
+}
+
+func TestFunc_Doc_importLinks(t *testing.T) {
+	tests := map[string][]string{
+		"Open": {
+			"git.PlainOpen https://pkg.go.dev/github.com/go-git/go-git/v5#PlainOpen",
+		},
+		"Render": {
+			"md.Run https://pkg.go.dev/github.com/russross/blackfriday/v2#Run",
+			"github.com/russross/blackfriday/v2.Run https://pkg.go.dev/github.com/russross/blackfriday/v2#Run",
+		},
+		"Decode": {
+			"yaml.Unmarshal https://pkg.go.dev/gopkg.in/yaml.v2#Unmarshal",
+			"gopkg.in/yaml.v2.Unmarshal https://pkg.go.dev/gopkg.in/yaml.v2#Unmarshal",
+			"encoding/json.Unmarshal https://pkg.go.dev/encoding/json#Unmarshal",
+		},
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			fn, err := loadFunc("../testData/lang/doclinks", name)
+			is.NoErr(err)
+
+			var links []string
+			for _, block := range fn.Doc().Blocks() {
+				for _, span := range block.Spans() {
+					if span.Kind() == lang.LinkSpan {
+						links = append(links, span.Text()+" "+span.Link().URL())
+					}
+				}
+			}
+
+			is.Equal(links, want)
+		})
+	}
+}
+
+func TestFunc_TypeParams_none(t *testing.T) {
+	is := is.New(t)
+	fn, err := loadFunc("../testData/lang/function", "Standalone")
+	is.NoErr(err)
+
+	decl, err := fn.TypeParamsDecl()
+	is.NoErr(err)
+	is.Equal(decl, "")
+	is.Equal(len(fn.TypeParams()), 0)
+}
+
 func TestFunc_stringsCompare(t *testing.T) {
 	is := is.New(t)
 
@@ -266,3 +503,23 @@ func loadFunc(dir, name string) (*lang.Func, error) {
 
 	return nil, errors.New("func not found")
 }
+
+func TestFunc_Doc_deprecated(t *testing.T) {
+	is := is.New(t)
+
+	fn, err := loadFunc("../testData/lang/function", "Total")
+	is.NoErr(err)
+
+	doc := fn.Doc()
+	is.True(doc.Deprecated())
+
+	blocks := doc.Blocks()
+	is.Equal(len(blocks), 2)
+	is.True(!blocks[0].IsDeprecation())
+	is.True(blocks[1].IsDeprecation())
+	is.Equal(blocks[1].DeprecationText(), "Use Sum instead, which supports any numeric type.")
+
+	fn, err = loadFunc("../testData/lang/function", "Standalone")
+	is.NoErr(err)
+	is.True(!fn.Doc().Deprecated())
+}
@@ -0,0 +1,91 @@
+package lang
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// generatePrefix begins each line holding a go:generate directive.
+const generatePrefix = "//go:generate"
+
+// GenerateDirective holds a single //go:generate directive from one of the
+// package's files.
+type GenerateDirective struct {
+	cfg     *Config
+	file    string
+	line    int
+	command string
+}
+
+// NewGenerateDirective creates a representation of the directive on the
+// provided line of the file, which runs the provided command.
+func NewGenerateDirective(cfg *Config, file string, line int, command string) *GenerateDirective {
+	return &GenerateDirective{cfg, file, line, command}
+}
+
+// File provides the name of the file declaring the directive.
+func (g *GenerateDirective) File() string {
+	return filepath.Base(g.file)
+}
+
+// Line provides the line of the file on which the directive is declared.
+func (g *GenerateDirective) Line() int {
+	return g.line
+}
+
+// Command provides the command run by go generate for the directive, as
+// written in the directive.
+func (g *GenerateDirective) Command() string {
+	return g.command
+}
+
+// Location returns a representation of the directive's location in a file
+// within a repository.
+func (g *GenerateDirective) Location() Location {
+	return Location{
+		Start:    Position{g.line, 1},
+		End:      Position{g.line, len(generatePrefix) + len(g.command) + 2},
+		Filepath: g.file,
+		WorkDir:  g.cfg.WorkDir,
+		Repo:     g.cfg.Repo,
+	}
+}
+
+// findGenerateDirectives scans the package's files for go:generate directives
+// the way go generate does, including the directives in test files.
+// Directives are sorted by file and then by line.
+func findGenerateDirectives(cfg *Config, pkg *sourcePackage) ([]*GenerateDirective, error) {
+	names := pkg.allGoFiles()
+	sort.Strings(names)
+
+	var directives []*GenerateDirective
+	for _, name := range names {
+		fileName := filepath.Join(pkg.dir, name)
+		b, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to read package file %s: %w", name, err)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		for line := 1; scanner.Scan(); line++ {
+			text := scanner.Text()
+			if !strings.HasPrefix(text, generatePrefix+" ") && !strings.HasPrefix(text, generatePrefix+"\t") {
+				continue
+			}
+
+			command := strings.TrimSpace(text[len(generatePrefix):])
+			directives = append(directives, NewGenerateDirective(cfg, fileName, line, command))
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to read package file %s: %w", name, err)
+		}
+	}
+
+	return directives, nil
+}
@@ -0,0 +1,119 @@
+package lang
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// ignoreDirective marks a declaration, or a field or method of a struct or
+// interface type, that should be left out of the documentation even if it is
+// exported. It can appear in the declaration's doc comment or in the comment
+// at the end of its line.
+const ignoreDirective = "//gomarkdoc:ignore"
+
+// isIgnored reports whether any of the comment groups contains the ignore
+// directive on a line of its own.
+func isIgnored(groups ...*ast.CommentGroup) bool {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+
+		for _, c := range group.List {
+			if strings.TrimSpace(c.Text) == ignoreDirective {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// removeIgnored strips the declarations marked with the ignore directive from
+// the file, along with the fields and methods marked with it in the struct
+// and interface types that remain. Declaration groups left empty are removed
+// entirely.
+func removeIgnored(f *ast.File) {
+	var decls []ast.Decl
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if isIgnored(decl.Doc) {
+				continue
+			}
+		case *ast.GenDecl:
+			if isIgnored(decl.Doc) {
+				continue
+			}
+
+			if len(decl.Specs) > 0 {
+				decl.Specs = removeIgnoredSpecs(decl.Specs)
+				if len(decl.Specs) == 0 {
+					continue
+				}
+			}
+		}
+
+		decls = append(decls, decl)
+	}
+
+	f.Decls = decls
+}
+
+func removeIgnoredSpecs(specs []ast.Spec) []ast.Spec {
+	var result []ast.Spec
+	for _, spec := range specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			if isIgnored(spec.Doc, spec.Comment) {
+				continue
+			}
+
+			removeIgnoredFields(spec.Type)
+		case *ast.ValueSpec:
+			if isIgnored(spec.Doc, spec.Comment) {
+				continue
+			}
+		}
+
+		result = append(result, spec)
+	}
+
+	return result
+}
+
+// removeIgnoredFields strips the ignored fields and methods from the struct
+// and interface types within the provided type expression. Types that lose
+// fields are marked as incomplete, as they are when unexported fields are
+// filtered out.
+func removeIgnoredFields(typ ast.Expr) {
+	ast.Inspect(typ, func(n ast.Node) bool {
+		var fields *ast.FieldList
+		var incomplete *bool
+		switch n := n.(type) {
+		case *ast.StructType:
+			fields, incomplete = n.Fields, &n.Incomplete
+		case *ast.InterfaceType:
+			fields, incomplete = n.Methods, &n.Incomplete
+		default:
+			return true
+		}
+
+		if fields == nil {
+			return true
+		}
+
+		var list []*ast.Field
+		for _, field := range fields.List {
+			if isIgnored(field.Doc, field.Comment) {
+				*incomplete = true
+				continue
+			}
+
+			list = append(list, field)
+		}
+
+		fields.List = list
+		return true
+	})
+}
@@ -0,0 +1,162 @@
+package lang
+
+import (
+	"go/types"
+	"strings"
+)
+
+// implementsCandidate holds an interface that types in a package may
+// implement, along with the link to its documentation.
+type implementsCandidate struct {
+	link  *DocLink
+	iface *types.Interface
+}
+
+// stdlibInterfaces lists the well-known interfaces from the standard library
+// that are checked when standard library interfaces are included in the
+// listing of implemented interfaces.
+var stdlibInterfaces = []string{
+	"builtin.error",
+	"context.Context",
+	"database/sql.Scanner",
+	"database/sql/driver.Valuer",
+	"encoding.BinaryMarshaler",
+	"encoding.BinaryUnmarshaler",
+	"encoding.TextMarshaler",
+	"encoding.TextUnmarshaler",
+	"encoding/json.Marshaler",
+	"encoding/json.Unmarshaler",
+	"flag.Value",
+	"fmt.Formatter",
+	"fmt.GoStringer",
+	"fmt.Stringer",
+	"hash.Hash",
+	"io.ByteReader",
+	"io.ByteWriter",
+	"io.Closer",
+	"io.ReadCloser",
+	"io.ReadWriteCloser",
+	"io.ReadWriter",
+	"io.Reader",
+	"io.ReaderAt",
+	"io.ReaderFrom",
+	"io.RuneReader",
+	"io.Seeker",
+	"io.StringWriter",
+	"io.WriteCloser",
+	"io.Writer",
+	"io.WriterAt",
+	"io.WriterTo",
+	"net/http.Handler",
+	"sort.Interface",
+}
+
+// newImplements determines the exported interfaces implemented by each of the
+// exported types in the type checked package. Interfaces declared in the
+// package itself and in the packages of the same module that it imports are
+// considered, as well as well-known interfaces from the standard library if
+// requested. The result is keyed by the name of the implementing type.
+func newImplements(cfg *Config, dir string, tpkg *types.Package, imp types.Importer, includeStdlib bool) map[string][]*DocLink {
+	var candidates []implementsCandidate
+	addCandidates := func(p *types.Package, local bool) {
+		scope := p.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() {
+				continue
+			}
+
+			iface, ok := implementableInterface(obj)
+			if !ok {
+				continue
+			}
+
+			link := &DocLink{ImportPath: p.Path(), Name: name}
+			if local {
+				link = &DocLink{Name: name, Symbol: cfg.Symbols[name]}
+				if link.Symbol == nil {
+					link.ImportPath = p.Path()
+				}
+			}
+
+			candidates = append(candidates, implementsCandidate{link, iface})
+		}
+	}
+
+	addCandidates(tpkg, true)
+
+	if modPath, _, ok := findModule(dir); ok {
+		for _, dep := range tpkg.Imports() {
+			if dep.Path() == modPath || strings.HasPrefix(dep.Path(), modPath+"/") {
+				addCandidates(dep, false)
+			}
+		}
+	}
+
+	if includeStdlib {
+		for _, target := range stdlibInterfaces {
+			i := strings.LastIndex(target, ".")
+			depPath, name := target[:i], target[i+1:]
+
+			var obj types.Object
+			if depPath == "builtin" {
+				obj = types.Universe.Lookup(name)
+			} else if dep, err := imp.Import(depPath); err == nil {
+				obj = dep.Scope().Lookup(name)
+			} else {
+				cfg.Log.Debugf("unable to import %s for implemented interfaces: %s", depPath, err)
+				continue
+			}
+
+			typeName, ok := obj.(*types.TypeName)
+			if !ok {
+				continue
+			}
+
+			if iface, ok := implementableInterface(typeName); ok {
+				candidates = append(candidates, implementsCandidate{&DocLink{ImportPath: depPath, Name: name}, iface})
+			}
+		}
+	}
+
+	implements := make(map[string][]*DocLink)
+	scope := tpkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || obj.IsAlias() {
+			continue
+		}
+
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+			continue
+		}
+
+		ptr := types.NewPointer(named)
+		for _, c := range candidates {
+			if types.Implements(named, c.iface) || types.Implements(ptr, c.iface) {
+				implements[name] = append(implements[name], c.link)
+			}
+		}
+	}
+
+	return implements
+}
+
+// implementableInterface provides the interface underlying the provided type
+// if it is one that can be listed as implemented. Generic interfaces and
+// interfaces without methods (such as any) are excluded, as are constraint
+// interfaces which can't be implemented by ordinary types.
+func implementableInterface(obj *types.TypeName) (*types.Interface, bool) {
+	named, ok := obj.Type().(*types.Named)
+	if ok && named.TypeParams().Len() > 0 {
+		return nil, false
+	}
+
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 || !iface.IsMethodSet() {
+		return nil, false
+	}
+
+	return iface, true
+}
@@ -0,0 +1,408 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/logger"
+	"golang.org/x/tools/go/packages"
+)
+
+// loadMode is the information loaded for a documented package. The syntax of
+// its files is the basis of its documentation.
+const loadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedImports |
+	packages.NeedSyntax
+
+// typesLoadMode additionally type checks the package, which is needed for
+// information that can't be determined from the syntax alone, such as the
+// interfaces implemented by types and the values of constants. The compiled Go
+// files include the files generated by cgo, so packages that use it are type
+// checked the same way as the compiler sees them.
+const typesLoadMode = loadMode |
+	packages.NeedCompiledGoFiles |
+	packages.NeedTypes |
+	packages.NeedTypesInfo
+
+// listMode is the information listed for a package that isn't documented.
+const listMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedImports
+
+// ErrNoPackage is matched by the error returned when there is no package at the
+// path provided to LoadPackage or ListPackage, such as a directory without any
+// Go files for the target platform.
+var ErrNoPackage = errors.New("gomarkdoc: no package")
+
+// noPackageError reports that there is no package at a path.
+type noPackageError struct {
+	path string
+}
+
+func (e *noPackageError) Error() string {
+	if isLocalPath(e.path) {
+		return fmt.Sprintf("gomarkdoc: invalid package in directory: %s", e.path)
+	}
+
+	return fmt.Sprintf("gomarkdoc: invalid package at import path: %s", e.path)
+}
+
+func (e *noPackageError) Is(target error) bool {
+	return target == ErrNoPackage
+}
+
+// sourcePackage holds the files of a package as they are listed by the go
+// tool, along with their syntax and, if requested, the package's types. Files
+// are identified by their names within the package's directory.
+type sourcePackage struct {
+	dir            string
+	name           string
+	importPath     string
+	goFiles        []string
+	testGoFiles    []string
+	xtestGoFiles   []string
+	ignoredGoFiles []string
+	fset           *token.FileSet
+	syntax         map[string]*ast.File
+	types          *types.Package
+	importer       types.Importer
+}
+
+// ListPackage lists the package at the provided path with the go tool, without
+// parsing its files. The path is either a directory, given as an absolute path
+// or a relative path starting with . or .., or an import path, which is
+// resolved from the working directory. The error matches ErrNoPackage if
+// there is no package at the path.
+func ListPackage(path string, tags ...string) (*packages.Package, error) {
+	conf, pattern, err := newLoadConfig(path, listMode, tags, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(conf.Dir); err != nil || !info.IsDir() {
+		return nil, &noPackageError{path}
+	}
+
+	pkgs, err := packages.Load(conf, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: failed to list package %s: %w", path, err)
+	}
+
+	pkg, _, _ := findTestVariants(pkgs)
+	if pkg == nil || len(pkg.GoFiles) == 0 {
+		return nil, &noPackageError{path}
+	}
+
+	return pkg, nil
+}
+
+// newLoadConfig provides the configuration to load the package at the path
+// with the go tool for the platform, or the platform the go tool targets by
+// default if it is nil, along with the pattern to load. Directories outside of
+// any module are loaded in GOPATH mode, as the go tool refuses to load them
+// otherwise.
+func newLoadConfig(path string, mode packages.LoadMode, tags []string, platform *Platform) (*packages.Config, string, error) {
+	conf := &packages.Config{Mode: mode, Env: os.Environ()}
+
+	pattern := path
+	if isLocalPath(path) {
+		dir, err := filepath.Abs(path)
+		if err != nil {
+			return nil, "", err
+		}
+
+		conf.Dir = dir
+		pattern = "."
+	} else {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, "", err
+		}
+
+		conf.Dir = wd
+	}
+
+	if _, _, ok := findModule(conf.Dir); !ok {
+		conf.Env = append(conf.Env, "GO111MODULE=off")
+	}
+
+	if platform != nil {
+		conf.Env = append(conf.Env, "GOOS="+platform.GOOS, "GOARCH="+platform.GOARCH)
+		tags = append(append([]string(nil), tags...), platform.Tags...)
+	}
+
+	if len(tags) > 0 {
+		conf.BuildFlags = []string{"-tags", strings.Join(tags, ",")}
+	}
+
+	return conf, pattern, nil
+}
+
+// loadSourcePackage loads the package at the path with the go tool for the
+// platform, or the platform the go tool targets by default if it is nil. The
+// package's test files are loaded along with it so that examples can be drawn
+// from them. The package is only type checked if requested, in which case
+// type errors are tolerated so that partially resolved packages still provide
+// information for the declarations that could be checked. The syntax is
+// recorded in the provided FileSet. If the package has no files for the
+// platform, the sourcePackage has no goFiles.
+func loadSourcePackage(log logger.Logger, fset *token.FileSet, path string, tags []string, platform *Platform, typed bool) (*sourcePackage, error) {
+	mode := loadMode
+	if typed {
+		mode = typesLoadMode
+	}
+
+	conf, pattern, err := newLoadConfig(path, mode, tags, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	conf.Tests = true
+	conf.Fset = fset
+	conf.Logf = log.Debugf
+
+	// The files of a directory given by a relative path are named relative to
+	// the working directory as well, so that locations are reported the way
+	// the directory was given
+	dir := conf.Dir
+	if isLocalPath(path) {
+		dir = filepath.Clean(path)
+		conf.ParseFile = func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			if filepath.Dir(filename) == conf.Dir {
+				filename = filepath.Join(dir, filepath.Base(filename))
+			}
+
+			return parser.ParseFile(fset, filename, src, parser.AllErrors|parser.ParseComments)
+		}
+	}
+
+	if info, err := os.Stat(conf.Dir); err != nil || !info.IsDir() {
+		return nil, &noPackageError{path}
+	}
+
+	pkgs, err := packages.Load(conf, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("gomarkdoc: failed to load package %s: %w", path, err)
+	}
+
+	pkg, testPkg, xtestPkg := findTestVariants(pkgs)
+	if pkg == nil {
+		return nil, &noPackageError{path}
+	}
+
+	if !isLocalPath(path) {
+		dir = pkg.Dir
+	}
+
+	src := &sourcePackage{
+		dir:        dir,
+		name:       pkg.Name,
+		importPath: pkg.PkgPath,
+		fset:       fset,
+		syntax:     make(map[string]*ast.File),
+	}
+
+	// Packages outside of both a Go Module and the GOPATH are given a
+	// placeholder import path by the go tool
+	if strings.HasPrefix(src.importPath, "_/") {
+		src.importPath = "."
+	}
+
+	for _, file := range pkg.IgnoredFiles {
+		if strings.HasSuffix(file, ".go") {
+			src.ignoredGoFiles = append(src.ignoredGoFiles, filepath.Base(file))
+		}
+	}
+
+	if len(pkg.GoFiles) == 0 {
+		return src, nil
+	}
+
+	if src.dir == "" {
+		src.dir = filepath.Dir(pkg.GoFiles[0])
+	}
+
+	for _, p := range []*packages.Package{pkg, testPkg, xtestPkg} {
+		if p == nil {
+			continue
+		}
+
+		for _, err := range p.Errors {
+			if err.Kind == packages.ParseError {
+				return nil, fmt.Errorf("gomarkdoc: failed to parse package: %w", err)
+			}
+
+			log.Debugf("loading package: %s", err)
+		}
+
+		for _, f := range p.Syntax {
+			src.syntax[fset.File(f.Package).Name()] = f
+		}
+	}
+
+	src.goFiles = baseNames(pkg.GoFiles)
+	if testPkg != nil {
+		src.testGoFiles = withoutNames(baseNames(testPkg.GoFiles), src.goFiles)
+	}
+
+	if xtestPkg != nil {
+		src.xtestGoFiles = baseNames(xtestPkg.GoFiles)
+	}
+
+	// The syntax of files preprocessed by cgo is that of the generated code,
+	// so the files as they are written are parsed instead
+	for _, name := range src.allGoFiles() {
+		fileName := filepath.Join(src.dir, name)
+		if _, ok := src.syntax[fileName]; ok {
+			continue
+		}
+
+		parsed, err := parser.ParseFile(fset, fileName, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to parse package file %s: %w", name, err)
+		}
+
+		src.syntax[fileName] = parsed
+	}
+
+	if typed && pkg.Types != nil {
+		src.types = pkg.Types
+		src.importer = newPackagesImporter(conf, pkg)
+	}
+
+	return src, nil
+}
+
+// findTestVariants finds the package among those loaded by the go tool along
+// with the variant of it that includes its internal test files and its
+// external test package. The test variants are nil if the package has no
+// test files of the corresponding kind.
+func findTestVariants(pkgs []*packages.Package) (pkg, testPkg, xtestPkg *packages.Package) {
+	for _, p := range pkgs {
+		if p.ID == p.PkgPath && !strings.HasSuffix(p.PkgPath, ".test") {
+			pkg = p
+			break
+		}
+	}
+
+	if pkg == nil {
+		return nil, nil, nil
+	}
+
+	testID := fmt.Sprintf("%s [%s.test]", pkg.PkgPath, pkg.PkgPath)
+	for _, p := range pkgs {
+		switch {
+		case p.ID == testID:
+			testPkg = p
+		case p.PkgPath == pkg.PkgPath+"_test":
+			xtestPkg = p
+		}
+	}
+
+	return pkg, testPkg, xtestPkg
+}
+
+// allGoFiles lists all of the Go files of the package, including its test
+// files.
+func (src *sourcePackage) allGoFiles() []string {
+	var names []string
+	names = append(names, src.goFiles...)
+	names = append(names, src.testGoFiles...)
+	names = append(names, src.xtestGoFiles...)
+	return names
+}
+
+// files provides the syntax of the named files of the package.
+func (src *sourcePackage) files(names []string) []*ast.File {
+	files := make([]*ast.File, 0, len(names))
+	for _, name := range names {
+		files = append(files, src.syntax[filepath.Join(src.dir, name)])
+	}
+
+	return files
+}
+
+// findImportComment finds the import comment following the package clause of
+// one of the package's files, such as // import "example.com/pkg", or the
+// empty string if there isn't one.
+func findImportComment(fset *token.FileSet, files []*ast.File) string {
+	for _, f := range files {
+		line := fset.Position(f.Name.End()).Line
+		for _, group := range f.Comments {
+			c := group.List[0]
+			if c.Pos() < f.Name.End() {
+				continue
+			}
+
+			if fset.Position(c.Pos()).Line != line {
+				break
+			}
+
+			text := strings.TrimPrefix(c.Text, "//")
+			if strings.HasPrefix(c.Text, "/*") {
+				text = strings.TrimSuffix(strings.TrimPrefix(c.Text, "/*"), "*/")
+			}
+
+			text = strings.TrimSpace(text)
+			if !strings.HasPrefix(text, "import ") {
+				break
+			}
+
+			if importPath, err := strconv.Unquote(strings.TrimSpace(text[len("import "):])); err == nil {
+				return importPath
+			}
+
+			break
+		}
+	}
+
+	return ""
+}
+
+// isLocalPath determines whether the path refers to a directory rather than an
+// import path, following the rules of the go tool.
+func isLocalPath(path string) bool {
+	return path == "." || path == ".." ||
+		strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") ||
+		strings.HasPrefix(path, "."+string(filepath.Separator)) ||
+		strings.HasPrefix(path, ".."+string(filepath.Separator)) ||
+		filepath.IsAbs(path)
+}
+
+// baseNames provides the names of the files within their directories.
+func baseNames(files []string) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// withoutNames filters the names to those that aren't excluded.
+func withoutNames(names, excluded []string) []string {
+	skip := make(map[string]bool)
+	for _, name := range excluded {
+		skip[name] = true
+	}
+
+	var result []string
+	for _, name := range names {
+		if !skip[name] {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package lang
+
+import (
+	"fmt"
+	"go/doc"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// MethodOrder determines the order in which the methods of each type are
+// documented.
+type MethodOrder string
+
+const (
+	// MethodOrderAlphabetical documents the methods of each type sorted by
+	// name, as godoc does.
+	MethodOrderAlphabetical MethodOrder = "alphabetical"
+
+	// MethodOrderSource documents the methods of each type in the order they
+	// appear in the package's files.
+	MethodOrderSource MethodOrder = "source"
+
+	// MethodOrderExportedFirst documents the exported methods of each type
+	// before the unexported ones, each in the order they appear in the
+	// package's files. It only differs from MethodOrderSource if unexported
+	// symbols are included.
+	MethodOrderExportedFirst MethodOrder = "exported"
+)
+
+// PackageWithMethodOrder can be used along with the LoadPackage function to
+// change the order in which the methods of each type are documented. By
+// default, methods follow the order provided to PackageWithOrder: they are
+// sorted by name for OrderAlphabetical and follow the source for OrderSource
+// and OrderKind.
+func PackageWithMethodOrder(order MethodOrder) PackageOption {
+	return func(opts *PackageOptions) error {
+		switch order {
+		case MethodOrderAlphabetical, MethodOrderSource, MethodOrderExportedFirst:
+			opts.methodOrder = order
+			return nil
+		default:
+			return fmt.Errorf("gomarkdoc: invalid method order: %s", order)
+		}
+	}
+}
+
+// PackageWithMethodsGroupedByReceiver can be used along with the LoadPackage
+// function to document the methods of each type with a value receiver before
+// the methods with a pointer receiver. The methods keep their order within each
+// group.
+func PackageWithMethodsGroupedByReceiver() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.groupReceivers = true
+		return nil
+	}
+}
+
+// PointerReceiver indicates whether the func is a method with a pointer
+// receiver.
+func (fn *Func) PointerReceiver() bool {
+	return strings.HasPrefix(fn.doc.Recv, "*")
+}
+
+// sortMethods sorts the methods of each type of the package according to the
+// method order, then moves the methods with a value receiver ahead of those
+// with a pointer receiver if requested.
+func sortMethods(fs *token.FileSet, pkg *doc.Package, order MethodOrder, groupReceivers bool) {
+	for _, typ := range pkg.Types {
+		methods := typ.Methods
+
+		switch order {
+		case MethodOrderAlphabetical:
+			sort.SliceStable(methods, func(i, j int) bool {
+				return methods[i].Name < methods[j].Name
+			})
+		case MethodOrderSource:
+			sortFuncsBySource(fs, methods)
+		case MethodOrderExportedFirst:
+			sortFuncsBySource(fs, methods)
+			sort.SliceStable(methods, func(i, j int) bool {
+				return token.IsExported(methods[i].Name) && !token.IsExported(methods[j].Name)
+			})
+		}
+
+		if groupReceivers {
+			sort.SliceStable(methods, func(i, j int) bool {
+				return !strings.HasPrefix(methods[i].Recv, "*") && strings.HasPrefix(methods[j].Recv, "*")
+			})
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package lang
+
+import (
+	"path/filepath"
+)
+
+type (
+	// PackageModel holds a plain data representation of a package's
+	// documentation that is suitable for serialization to formats such as
+	// YAML or JSON. It is intended for tools that want the structured
+	// documentation metadata rather than rendered prose.
+	PackageModel struct {
+		Name         string          `json:"name" yaml:"name"`
+		ImportPath   string          `json:"importPath" yaml:"importPath"`
+		Summary      string          `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Doc          string          `json:"doc,omitempty" yaml:"doc,omitempty"`
+		Consts       []*ValueModel   `json:"consts,omitempty" yaml:"consts,omitempty"`
+		Vars         []*ValueModel   `json:"vars,omitempty" yaml:"vars,omitempty"`
+		Funcs        []*FuncModel    `json:"funcs,omitempty" yaml:"funcs,omitempty"`
+		Types        []*TypeModel    `json:"types,omitempty" yaml:"types,omitempty"`
+		Examples     []*ExampleModel `json:"examples,omitempty" yaml:"examples,omitempty"`
+		ExternalTest *PackageModel   `json:"externalTest,omitempty" yaml:"externalTest,omitempty"`
+	}
+
+	// TypeModel holds a plain data representation of a type's documentation.
+	TypeModel struct {
+		Name       string          `json:"name" yaml:"name"`
+		Decl       string          `json:"decl" yaml:"decl"`
+		Summary    string          `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Doc        string          `json:"doc,omitempty" yaml:"doc,omitempty"`
+		Deprecated bool            `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+		AliasOf    string          `json:"aliasOf,omitempty" yaml:"aliasOf,omitempty"`
+		Constraint string          `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+		Platforms  []string        `json:"platforms,omitempty" yaml:"platforms,omitempty"`
+		Position   PositionModel   `json:"position" yaml:"position"`
+		Consts     []*ValueModel   `json:"consts,omitempty" yaml:"consts,omitempty"`
+		Vars       []*ValueModel   `json:"vars,omitempty" yaml:"vars,omitempty"`
+		Funcs      []*FuncModel    `json:"funcs,omitempty" yaml:"funcs,omitempty"`
+		Methods    []*FuncModel    `json:"methods,omitempty" yaml:"methods,omitempty"`
+		Examples   []*ExampleModel `json:"examples,omitempty" yaml:"examples,omitempty"`
+	}
+
+	// FuncModel holds a plain data representation of a func's documentation.
+	FuncModel struct {
+		Name       string          `json:"name" yaml:"name"`
+		Receiver   string          `json:"receiver,omitempty" yaml:"receiver,omitempty"`
+		Signature  string          `json:"signature" yaml:"signature"`
+		Summary    string          `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Doc        string          `json:"doc,omitempty" yaml:"doc,omitempty"`
+		Deprecated bool            `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+		Constraint string          `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+		Platforms  []string        `json:"platforms,omitempty" yaml:"platforms,omitempty"`
+		Position   PositionModel   `json:"position" yaml:"position"`
+		Examples   []*ExampleModel `json:"examples,omitempty" yaml:"examples,omitempty"`
+	}
+
+	// ValueModel holds a plain data representation of the documentation for
+	// a const or var declaration block.
+	ValueModel struct {
+		Names      []string      `json:"names" yaml:"names"`
+		Decl       string        `json:"decl" yaml:"decl"`
+		Summary    string        `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Doc        string        `json:"doc,omitempty" yaml:"doc,omitempty"`
+		Deprecated bool          `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+		Constraint string        `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+		Platforms  []string      `json:"platforms,omitempty" yaml:"platforms,omitempty"`
+		Position   PositionModel `json:"position" yaml:"position"`
+	}
+
+	// ExampleModel holds a plain data representation of an example.
+	ExampleModel struct {
+		Name     string        `json:"name,omitempty" yaml:"name,omitempty"`
+		Title    string        `json:"title" yaml:"title"`
+		Summary  string        `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Doc      string        `json:"doc,omitempty" yaml:"doc,omitempty"`
+		Code     string        `json:"code" yaml:"code"`
+		Output   string        `json:"output,omitempty" yaml:"output,omitempty"`
+		Tags     string        `json:"tags,omitempty" yaml:"tags,omitempty"`
+		Position PositionModel `json:"position" yaml:"position"`
+	}
+
+	// PositionModel holds the position of a declaration within the source.
+	// The file is relative to the working directory where possible.
+	PositionModel struct {
+		File      string `json:"file" yaml:"file"`
+		StartLine int    `json:"startLine" yaml:"startLine"`
+		StartCol  int    `json:"startCol" yaml:"startCol"`
+		EndLine   int    `json:"endLine" yaml:"endLine"`
+		EndCol    int    `json:"endCol" yaml:"endCol"`
+	}
+)
+
+// Model builds a plain data representation of the package's documentation,
+// including all of the symbols and examples it contains.
+func (pkg *Package) Model() (*PackageModel, error) {
+	m := &PackageModel{
+		Name:       pkg.Name(),
+		ImportPath: pkg.ImportPath(),
+		Summary:    pkg.Summary(),
+		Doc:        normalizeDoc(pkg.doc.Doc),
+	}
+
+	var err error
+	if m.Consts, err = valueModels(pkg.Consts()); err != nil {
+		return nil, err
+	}
+
+	if m.Vars, err = valueModels(pkg.Vars()); err != nil {
+		return nil, err
+	}
+
+	if m.Funcs, err = funcModels(pkg.Funcs()); err != nil {
+		return nil, err
+	}
+
+	for _, typ := range pkg.Types() {
+		tm, err := typ.Model()
+		if err != nil {
+			return nil, err
+		}
+
+		m.Types = append(m.Types, tm)
+	}
+
+	if m.Examples, err = exampleModels(pkg.Examples()); err != nil {
+		return nil, err
+	}
+
+	if pkg.externalTest != nil {
+		if m.ExternalTest, err = pkg.externalTest.Model(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Model builds a plain data representation of the type's documentation,
+// including its associated values, funcs, methods and examples.
+func (typ *Type) Model() (*TypeModel, error) {
+	decl, err := typ.Decl()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &TypeModel{
+		Name:       typ.Name(),
+		Decl:       decl,
+		Summary:    typ.Summary(),
+		Doc:        normalizeDoc(typ.doc.Doc),
+		Deprecated: isDeprecated(typ.doc.Doc),
+		Constraint: typ.BuildConstraint(),
+		Platforms:  typ.Platforms(),
+		Position:   newPositionModel(typ.Location()),
+	}
+
+	if m.AliasOf, err = typ.AliasTarget(); err != nil {
+		return nil, err
+	}
+
+	if m.Consts, err = valueModels(typ.Consts()); err != nil {
+		return nil, err
+	}
+
+	if m.Vars, err = valueModels(typ.Vars()); err != nil {
+		return nil, err
+	}
+
+	if m.Funcs, err = funcModels(typ.Funcs()); err != nil {
+		return nil, err
+	}
+
+	if m.Methods, err = funcModels(typ.Methods()); err != nil {
+		return nil, err
+	}
+
+	if m.Examples, err = exampleModels(typ.Examples()); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Model builds a plain data representation of the func's documentation.
+func (fn *Func) Model() (*FuncModel, error) {
+	sig, err := fn.Signature()
+	if err != nil {
+		return nil, err
+	}
+
+	examples, err := exampleModels(fn.Examples())
+	if err != nil {
+		return nil, err
+	}
+
+	return &FuncModel{
+		Name:       fn.Name(),
+		Receiver:   fn.Receiver(),
+		Signature:  sig,
+		Summary:    fn.Summary(),
+		Doc:        normalizeDoc(fn.doc.Doc),
+		Deprecated: isDeprecated(fn.doc.Doc),
+		Constraint: fn.BuildConstraint(),
+		Platforms:  fn.Platforms(),
+		Position:   newPositionModel(fn.Location()),
+		Examples:   examples,
+	}, nil
+}
+
+// Model builds a plain data representation of the value's documentation.
+func (v *Value) Model() (*ValueModel, error) {
+	decl, err := v.Decl()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ValueModel{
+		Names:      v.doc.Names,
+		Decl:       decl,
+		Summary:    v.Summary(),
+		Doc:        normalizeDoc(v.doc.Doc),
+		Deprecated: isDeprecated(v.doc.Doc),
+		Constraint: v.BuildConstraint(),
+		Platforms:  v.Platforms(),
+		Position:   newPositionModel(v.Location()),
+	}, nil
+}
+
+// Model builds a plain data representation of the example.
+func (ex *Example) Model() (*ExampleModel, error) {
+	code, err := ex.Code()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExampleModel{
+		Name:     ex.Name(),
+		Title:    ex.Title(),
+		Summary:  ex.Summary(),
+		Doc:      normalizeDoc(ex.doc.Doc),
+		Code:     code,
+		Output:   ex.Output(),
+		Tags:     ex.RequiredTags(),
+		Position: newPositionModel(ex.Location()),
+	}, nil
+}
+
+func newPositionModel(loc Location) PositionModel {
+	file := loc.Filepath
+	if filepath.IsAbs(file) && loc.WorkDir != "" {
+		if rel, err := filepath.Rel(loc.WorkDir, file); err == nil {
+			file = rel
+		}
+	}
+
+	return PositionModel{
+		File:      filepath.ToSlash(file),
+		StartLine: loc.Start.Line,
+		StartCol:  loc.Start.Col,
+		EndLine:   loc.End.Line,
+		EndCol:    loc.End.Col,
+	}
+}
+
+func valueModels(values []*Value) ([]*ValueModel, error) {
+	var models []*ValueModel
+	for _, v := range values {
+		m, err := v.Model()
+		if err != nil {
+			return nil, err
+		}
+
+		models = append(models, m)
+	}
+
+	return models, nil
+}
+
+func funcModels(funcs []*Func) ([]*FuncModel, error) {
+	var models []*FuncModel
+	for _, fn := range funcs {
+		m, err := fn.Model()
+		if err != nil {
+			return nil, err
+		}
+
+		models = append(models, m)
+	}
+
+	return models, nil
+}
+
+func exampleModels(examples []*Example) ([]*ExampleModel, error) {
+	var models []*ExampleModel
+	for _, ex := range examples {
+		m, err := ex.Model()
+		if err != nil {
+			return nil, err
+		}
+
+		models = append(models, m)
+	}
+
+	return models, nil
+}
@@ -0,0 +1,62 @@
+package lang_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestPackage_Model(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	m, err := pkg.Model()
+	is.NoErr(err)
+
+	is.Equal(m.Name, "function")
+	is.Equal(len(m.Consts), 1)
+	is.Equal(m.Consts[0].Names, []string{"ConstA", "ConstB"})
+	is.Equal(len(m.Vars), 1)
+	is.Equal(m.Vars[0].Decl, "var Variable = 5")
+	is.Equal(m.Vars[0].Summary, "Variable is a package-level variable.")
+	is.True(strings.HasSuffix(m.Vars[0].Position.File, "value.go"))
+	is.Equal(m.Vars[0].Position.StartLine, 4)
+}
+
+func TestFunc_Model(t *testing.T) {
+	is := is.New(t)
+
+	fn, err := loadFunc("../testData/lang/function", "Standalone")
+	is.NoErr(err)
+
+	m, err := fn.Model()
+	is.NoErr(err)
+
+	is.Equal(m.Name, "Standalone")
+	is.Equal(m.Receiver, "")
+	is.Equal(m.Signature, "func Standalone(p1 int, p2 string) (int, error)")
+	is.Equal(m.Summary, "Standalone provides a function that is not part of a type.")
+	is.True(strings.HasPrefix(m.Doc, "Standalone provides a function"))
+	is.Equal(m.Position.StartLine, 14)
+	is.Equal(len(m.Examples), 2)
+	is.Equal(m.Examples[0].Output, "2\n")
+}
+
+func TestType_Model(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Receiver")
+	is.NoErr(err)
+
+	m, err := typ.Model()
+	is.NoErr(err)
+
+	is.Equal(m.Name, "Receiver")
+	is.Equal(m.Decl, "type Receiver struct{}")
+	is.Equal(len(m.Funcs), 1)
+	is.Equal(m.Funcs[0].Name, "New")
+	is.Equal(len(m.Methods), 2)
+}
@@ -0,0 +1,97 @@
+package lang
+
+import (
+	"go/doc"
+	"go/token"
+	"strings"
+)
+
+type (
+	// NoteGroup holds the notes of a package that share a marker, such as
+	// BUG or TODO.
+	NoteGroup struct {
+		cfg    *Config
+		marker string
+		notes  []*doc.Note
+	}
+
+	// Note holds a single note from a package's comments, written in the form
+	// MARKER(uid): body, as recognized by godoc.
+	Note struct {
+		cfg *Config
+		doc *doc.Note
+	}
+
+	// noteRange locates a note's comment so that it can be passed to
+	// NewLocation.
+	noteRange struct {
+		pos, end token.Pos
+	}
+)
+
+// DefaultNoteMarkers lists the note markers that are documented when no
+// markers are provided to PackageWithNotes. As with godoc, only bugs are
+// documented by default.
+var DefaultNoteMarkers = []string{"BUG"}
+
+// NewNoteGroup creates a representation of the notes for a marker from the
+// raw notes provided by the standard library.
+func NewNoteGroup(cfg *Config, marker string, notes []*doc.Note) *NoteGroup {
+	return &NoteGroup{cfg, marker, notes}
+}
+
+// Level provides the default level that headers for the group should be
+// rendered.
+func (g *NoteGroup) Level() int {
+	return g.cfg.Level
+}
+
+// Marker provides the marker shared by the notes in the group, such as BUG.
+func (g *NoteGroup) Marker() string {
+	return g.marker
+}
+
+// Title provides the title of the group. Bugs are titled as they are by godoc,
+// while other markers are titled using the marker itself.
+func (g *NoteGroup) Title() string {
+	if g.marker == "BUG" {
+		return "Bugs"
+	}
+
+	return g.marker
+}
+
+// Notes lists the notes in the group in the order they appear in the package's
+// files.
+func (g *NoteGroup) Notes() (notes []*Note) {
+	for _, n := range g.notes {
+		notes = append(notes, &Note{g.cfg, n})
+	}
+
+	return
+}
+
+// UID provides the identifier in parentheses after the marker, which is
+// typically the name of the person responsible for the note.
+func (n *Note) UID() string {
+	return n.doc.UID
+}
+
+// Text provides the body of the note joined into a single line.
+func (n *Note) Text() string {
+	return strings.Join(strings.Fields(n.doc.Body), " ")
+}
+
+// Location returns a representation of the note's location in a file within a
+// repository.
+func (n *Note) Location() Location {
+	return NewLocation(n.cfg, noteRange{n.doc.Pos, n.doc.End})
+}
+
+func (r noteRange) Pos() token.Pos {
+	return r.pos
+}
+
+func (r noteRange) End() token.Pos {
+	return r.end
+}
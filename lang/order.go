@@ -0,0 +1,161 @@
+package lang
+
+import (
+	"fmt"
+	"go/doc"
+	"go/token"
+	"sort"
+)
+
+type (
+	// Order determines the order in which the declarations of a package are
+	// documented.
+	Order string
+
+	// Declaration holds one of the top-level funcs or types of a package, as
+	// listed by Package.Declarations. Exactly one of Func and Type is set.
+	Declaration struct {
+		Func *Func
+		Type *Type
+	}
+)
+
+const (
+	// OrderAlphabetical documents the declarations of each kind sorted by
+	// name, as godoc does. This is the default.
+	OrderAlphabetical Order = "alphabetical"
+
+	// OrderSource documents the declarations in the order they appear in the
+	// package's files, with funcs and types interleaved. Constants and
+	// variables keep their own sections, also in source order.
+	OrderSource Order = "source"
+
+	// OrderKind documents the declarations grouped by kind, as with
+	// OrderAlphabetical, but in the order they appear in the package's files
+	// within each kind.
+	OrderKind Order = "kind"
+)
+
+// PackageWithOrder can be used along with the LoadPackage function to change
+// the order in which the package's declarations are documented. The default is
+// OrderAlphabetical.
+func PackageWithOrder(order Order) PackageOption {
+	return func(opts *PackageOptions) error {
+		switch order {
+		case OrderAlphabetical, OrderSource, OrderKind:
+			opts.order = order
+			return nil
+		default:
+			return fmt.Errorf("gomarkdoc: invalid declaration order: %s", order)
+		}
+	}
+}
+
+// Order provides the order in which the package's declarations are
+// documented.
+func (pkg *Package) Order() Order {
+	if pkg.order == "" {
+		return OrderAlphabetical
+	}
+
+	return pkg.order
+}
+
+// Declarations lists the top-level funcs and types of the package that don't
+// belong to a category, as listed by Categories. They are interleaved in the
+// order they appear in the package's files if the package was created using
+// PackageWithOrder(OrderSource). Otherwise, the funcs are listed before the
+// types.
+func (pkg *Package) Declarations() []*Declaration {
+	var decls []*Declaration
+	for _, decl := range pkg.declarations(pkg.cfg.Inc(1)) {
+		if decl.category() == "" {
+			decls = append(decls, decl)
+		}
+	}
+
+	return decls
+}
+
+// declarations lists all of the top-level funcs and types of the package in
+// the order they are documented, using the provided configuration.
+func (pkg *Package) declarations(cfg *Config) []*Declaration {
+	var decls []*Declaration
+	for _, fn := range pkg.doc.Funcs {
+		decls = append(decls, &Declaration{Func: NewFunc(cfg, fn, pkg.examples)})
+	}
+
+	for _, typ := range pkg.doc.Types {
+		decls = append(decls, &Declaration{Type: NewType(cfg, typ, pkg.examples)})
+	}
+
+	if pkg.Order() == OrderSource {
+		sort.SliceStable(decls, func(i, j int) bool {
+			return sourceBefore(pkg.cfg.FileSet, decls[i].pos(), decls[j].pos())
+		})
+	}
+
+	return decls
+}
+
+func (d *Declaration) pos() token.Pos {
+	if d.Func != nil {
+		return d.Func.doc.Decl.Pos()
+	}
+
+	return typePos(d.Type.doc)
+}
+
+// typePos locates the spec of the type. Types declared in a group share the
+// position of the group's declaration, so the spec is used instead.
+func typePos(typ *doc.Type) token.Pos {
+	if len(typ.Decl.Specs) > 0 {
+		return typ.Decl.Specs[0].Pos()
+	}
+
+	return typ.Decl.Pos()
+}
+
+// sourceBefore reports whether the position a comes before b in the source.
+// Positions are compared by file name and offset rather than directly, as
+// some declarations are parsed more than once into the same FileSet.
+func sourceBefore(fs *token.FileSet, a, b token.Pos) bool {
+	pa, pb := fs.Position(a), fs.Position(b)
+	if pa.Filename != pb.Filename {
+		return pa.Filename < pb.Filename
+	}
+
+	return pa.Offset < pb.Offset
+}
+
+// sortBySource sorts the declarations of the package, along with the values,
+// funcs and methods associated with each type, in the order they appear in
+// the package's files.
+func sortBySource(fs *token.FileSet, pkg *doc.Package) {
+	sortValuesBySource(fs, pkg.Consts)
+	sortValuesBySource(fs, pkg.Vars)
+	sortFuncsBySource(fs, pkg.Funcs)
+
+	sort.SliceStable(pkg.Types, func(i, j int) bool {
+		return sourceBefore(fs, typePos(pkg.Types[i]), typePos(pkg.Types[j]))
+	})
+
+	for _, typ := range pkg.Types {
+		sortValuesBySource(fs, typ.Consts)
+		sortValuesBySource(fs, typ.Vars)
+		sortFuncsBySource(fs, typ.Funcs)
+		sortFuncsBySource(fs, typ.Methods)
+	}
+}
+
+func sortValuesBySource(fs *token.FileSet, values []*doc.Value) {
+	sort.SliceStable(values, func(i, j int) bool {
+		return sourceBefore(fs, values[i].Decl.Pos(), values[j].Decl.Pos())
+	})
+}
+
+func sortFuncsBySource(fs *token.FileSet, funcs []*doc.Func) {
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return sourceBefore(fs, funcs[i].Decl.Pos(), funcs[j].Decl.Pos())
+	})
+}
@@ -12,7 +12,11 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ag5denis/gomarkdoc/logger"
 )
@@ -21,9 +25,17 @@ type (
 	// Package holds documentation information for a package and all of the
 	// symbols contained within it.
 	Package struct {
-		cfg      *Config
-		doc      *doc.Package
-		examples []*doc.Example
+		cfg          *Config
+		doc          *doc.Package
+		examples     []*doc.Example
+		fuzzTargets  []*doc.Func
+		noteMarkers  []string
+		externalTest *Package
+		generate     []*GenerateDirective
+		command      bool
+		flags        []*CommandFlag
+		subcommands  []*Subcommand
+		order        Order
 	}
 
 	// PackageOptions holds options related to the configuration of the package
@@ -31,24 +43,71 @@ type (
 	PackageOptions struct {
 		includeUnexported   bool
 		repositoryOverrides *Repo
+		importPathRoot      string
+		omitDeprecated      bool
+		implements          bool
+		implementsStdlib    bool
+		constValues         bool
+		externalLinks       bool
+		opaqueTypes         bool
+		externalTests       bool
+		fuzzTargets         bool
+		noteMarkers         []string
+		taggedExamples      bool
+		generateDirectives  bool
+		order               Order
+		constructorPrefixes []string
+		constructorSuffixes []string
+		ungroupConstructors bool
+		methodOrder         MethodOrder
+		groupReceivers      bool
+		buildTags           []string
+		platforms           []Platform
+		commandUsage        bool
 	}
 
 	// PackageOption configures one or more options for the package.
 	PackageOption func(opts *PackageOptions) error
 )
 
-// NewPackage creates a representation of a package's documentation from the
-// raw documentation constructs provided by the standard library. This is only
+// NewPackage creates a representation of a package's documentation from the raw
+// documentation constructs provided by the standard library. This is only
 // recommended for advanced scenarios. Most consumers will find it easier to use
-// NewPackageFromBuild instead.
+// LoadPackage instead.
 func NewPackage(cfg *Config, doc *doc.Package, examples []*doc.Example) *Package {
-	return &Package{cfg, doc, examples}
+	cfg.Symbols = newSymbols(cfg, doc)
+	if cfg.Imports == nil {
+		cfg.Imports = newImports(doc)
+	}
+
+	return &Package{cfg: cfg, doc: doc, examples: examples}
 }
 
 // NewPackageFromBuild creates a representation of a package's documentation
-// from the build metadata for that package. It can be configured using the
-// provided options.
+// for the package in the directory described by the build metadata. The
+// package is loaded with the go tool in the same way as LoadPackage, so only
+// the directory is taken from the build metadata.
+//
+// Deprecated: Use LoadPackage, which doesn't require the package to be
+// imported with the go/build package beforehand.
 func NewPackageFromBuild(log logger.Logger, pkg *build.Package, opts ...PackageOption) (*Package, error) {
+	// Relative directories are given to the go tool the way it expects them
+	dir := pkg.Dir
+	if !isLocalPath(dir) {
+		dir = "." + string(filepath.Separator) + dir
+	}
+
+	return LoadPackage(log, dir, opts...)
+}
+
+// LoadPackage loads the package at the provided path with the go tool and
+// creates a representation of its documentation. The path is either a
+// directory, given as an absolute path or a relative path starting with . or
+// .., or an import path, which is resolved from the working directory. Modules,
+// workspaces, vendored packages and build tags are resolved in the same way
+// as a regular build. It can be configured using the provided options. The
+// error matches ErrNoPackage if there is no package at the path.
+func LoadPackage(log logger.Logger, path string, opts ...PackageOption) (*Package, error) {
 	var options PackageOptions
 	for _, opt := range opts {
 		if err := opt(&options); err != nil {
@@ -56,34 +115,185 @@ func NewPackageFromBuild(log logger.Logger, pkg *build.Package, opts ...PackageO
 		}
 	}
 
-	wd, err := os.Getwd()
+	fset := token.NewFileSet()
+	typed := options.implements || options.constValues
+
+	var (
+		pkg             *sourcePackage
+		symbolPlatforms map[string][]string
+		err             error
+	)
+	if len(options.platforms) > 0 {
+		pkg, symbolPlatforms, err = mergePlatforms(log, fset, path, options.platforms, options.buildTags, typed)
+	} else {
+		pkg, err = loadSourcePackage(log, fset, path, options.buildTags, nil, typed)
+		if err == nil && len(pkg.goFiles) == 0 {
+			err = &noPackageError{path}
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	cfg, err := NewConfig(log, wd, pkg.Dir, ConfigWithRepoOverrides(options.repositoryOverrides))
+	return newPackageFromSource(log, pkg, symbolPlatforms, options)
+}
+
+// newPackageFromSource creates a representation of the documentation of the
+// loaded package.
+func newPackageFromSource(log logger.Logger, pkg *sourcePackage, symbolPlatforms map[string][]string, options PackageOptions) (*Package, error) {
+	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
 
-	docPkg, err := getDocPkg(pkg, cfg.FileSet, options.includeUnexported)
+	cfg, err := NewConfig(log, wd, pkg.dir, ConfigWithRepoOverrides(options.repositoryOverrides))
 	if err != nil {
 		return nil, err
 	}
 
-	files, err := parsePkgFiles(pkg, cfg.FileSet)
+	cfg.FileSet = pkg.fset
+	cfg.SymbolPlatforms = symbolPlatforms
+
+	// The synthetic import path is only used if the package turns out to be
+	// outside of both a Go Module and the GOPATH
+	var fallbackImportPath string
+	if options.importPathRoot != "" {
+		fallbackImportPath, err = syntheticImportPath(options.importPathRoot, wd, pkg.dir)
+		if err != nil {
+			log.Debugf("unable to synthesize import path: %s", err)
+		}
+	}
+
+	// The constraints are read from the comments of the files, which are
+	// dropped when the documentation is built
+	cfg.FileConstraints = getFileConstraints(pkg)
+
+	docPkg, err := getDocPkg(pkg, cfg, options.includeUnexported, fallbackImportPath)
 	if err != nil {
 		return nil, err
 	}
 
+	if options.opaqueTypes && !options.includeUnexported {
+		if cfg.OpaqueTypes, err = getOpaqueTypes(pkg, cfg.FileSet); err != nil {
+			return nil, err
+		}
+
+		// Constructors of unexported types are dropped along with the type,
+		// so restore them as standalone funcs
+		for _, t := range cfg.OpaqueTypes {
+			docPkg.Funcs = append(docPkg.Funcs, t.Funcs...)
+		}
+
+		sort.Slice(docPkg.Funcs, func(i, j int) bool {
+			return docPkg.Funcs[i].Name < docPkg.Funcs[j].Name
+		})
+	}
+
+	if options.ungroupConstructors {
+		ungroupConstructors(docPkg)
+	} else if len(options.constructorPrefixes) > 0 || len(options.constructorSuffixes) > 0 {
+		groupConstructors(docPkg, options.constructorPrefixes, options.constructorSuffixes)
+	}
+
+	if options.omitDeprecated {
+		removeDeprecated(docPkg)
+	}
+
+	if options.order == OrderSource || options.order == OrderKind {
+		sortBySource(cfg.FileSet, docPkg)
+	}
+
+	if options.methodOrder != "" || options.groupReceivers {
+		sortMethods(cfg.FileSet, docPkg, options.methodOrder, options.groupReceivers)
+	}
+
+	files := testFiles(pkg)
 	examples := doc.Examples(files...)
 
-	return NewPackage(cfg, docPkg, examples), nil
+	if options.taggedExamples {
+		tagged, err := parseTaggedTestFiles(pkg, cfg.FileSet)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.ExampleTags = make(map[*doc.Example]string)
+		for f, tags := range tagged {
+			for _, ex := range doc.Examples(f) {
+				cfg.ExampleTags[ex] = tags
+				examples = append(examples, ex)
+			}
+		}
+
+		sort.SliceStable(examples, func(i, j int) bool {
+			return examples[i].Name < examples[j].Name
+		})
+	}
+
+	cfg.ExternalLinks = options.externalLinks
+	cfg.VarValues = options.constValues
+
+	p := NewPackage(cfg, docPkg, examples)
+	if options.fuzzTargets {
+		p.fuzzTargets = getFuzzTargets(files, cfg.FuncEnds)
+	}
+
+	p.noteMarkers = options.noteMarkers
+	p.order = options.order
+
+	if options.generateDirectives {
+		directives, err := findGenerateDirectives(cfg.Inc(2), pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		p.generate = directives
+	}
+
+	if options.commandUsage && pkg.name == "main" {
+		flags, subcommands, err := findCommandUsage(cfg.Inc(2), pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		p.command = true
+		p.flags = flags
+		p.subcommands = subcommands
+	}
+
+	if options.externalTests && len(pkg.xtestGoFiles) > 0 {
+		xtestPkg, err := getExternalTestDocPkg(pkg, cfg.FileSet, options.includeUnexported, docPkg.ImportPath)
+		if err != nil {
+			return nil, err
+		}
+
+		// The external test package's symbols are nested beneath the package
+		// and resolved separately from it
+		xcfg := cfg.Inc(1)
+		xcfg.Imports = nil
+		xcfg.Implements = nil
+		xcfg.ConstValues = nil
+		xcfg.OpaqueTypes = nil
+
+		p.externalTest = NewPackage(xcfg, xtestPkg, nil)
+	}
+
+	if pkg.types != nil {
+		if options.implements {
+			cfg.Implements = newImplements(cfg, pkg.dir, pkg.types, pkg.importer, options.implementsStdlib)
+		}
+
+		if options.constValues {
+			cfg.ConstValues = newConstValues(pkg.types)
+		}
+	}
+
+	return p, nil
 }
 
-// PackageWithUnexportedIncluded can be used along with the NewPackageFromBuild
-// function to specify that all symbols, including unexported ones, should be
-// included in the documentation for the package.
+// PackageWithUnexportedIncluded can be used along with the LoadPackage function
+// to specify that all symbols, including unexported ones, should be included in
+// the documentation for the package.
 func PackageWithUnexportedIncluded() PackageOption {
 	return func(opts *PackageOptions) error {
 		opts.includeUnexported = true
@@ -91,7 +301,7 @@ func PackageWithUnexportedIncluded() PackageOption {
 	}
 }
 
-// PackageWithRepositoryOverrides can be used along with the NewPackageFromBuild
+// PackageWithRepositoryOverrides can be used along with the LoadPackage
 // function to define manual overrides to the automatic repository detection
 // logic.
 func PackageWithRepositoryOverrides(repo *Repo) PackageOption {
@@ -101,6 +311,170 @@ func PackageWithRepositoryOverrides(repo *Repo) PackageOption {
 	}
 }
 
+// PackageWithDeprecatedOmitted can be used along with the LoadPackage function
+// to specify that symbols whose documentation marks them as deprecated should
+// be left out of the documentation for the package.
+func PackageWithDeprecatedOmitted() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.omitDeprecated = true
+		return nil
+	}
+}
+
+// PackageWithImplements can be used along with the LoadPackage function to list
+// the interfaces implemented by each of the package's types. Interfaces
+// declared in the package and in packages of the same module that it imports
+// are considered. If includeStdlib is true, well-known interfaces from the
+// standard library such as fmt.Stringer and io.Reader are also considered.
+func PackageWithImplements(includeStdlib bool) PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.implements = true
+		opts.implementsStdlib = includeStdlib
+		return nil
+	}
+}
+
+// PackageWithConstValues can be used along with the LoadPackage function to
+// resolve the values of the package's constants, including those defined using
+// iota or other constant expressions, so that they can be shown alongside their
+// declarations. The simple initializers of the package's variables, such as
+// literals and sentinel errors, are shown as well.
+func PackageWithConstValues() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.constValues = true
+		return nil
+	}
+}
+
+// PackageWithExternalLinks can be used along with the LoadPackage function to
+// link the types from the standard library and other modules that appear in the
+// package's function signatures and type declarations to their documentation on
+// pkg.go.dev.
+func PackageWithExternalLinks() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.externalLinks = true
+		return nil
+	}
+}
+
+// PackageWithOpaqueTypes can be used along with the LoadPackage function to
+// document the exported methods of unexported types that appear in the
+// signatures of exported functions, such as opaque handles returned by a
+// constructor. Exported constructors of those types, which would otherwise be
+// omitted along with the type, are documented as standalone functions. It has
+// no effect when unexported symbols are included, as those types are then
+// documented in full.
+func PackageWithOpaqueTypes() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.opaqueTypes = true
+		return nil
+	}
+}
+
+// PackageWithImportPathRoot can be used along with the LoadPackage function to
+// synthesize an import path for packages that are neither in a Go Module nor in
+// the GOPATH. The root is used as the import path of the current working
+// directory, and packages in directories beneath it are given import paths
+// beneath the root.
+func PackageWithImportPathRoot(root string) PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.importPathRoot = root
+		return nil
+	}
+}
+
+// PackageWithExternalTests can be used along with the LoadPackage function to
+// document the symbols declared in the package's external test package (package
+// foo_test), such as shared fixtures and helpers. The test, benchmark, fuzz and
+// example functions run by go test are left out.
+func PackageWithExternalTests() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.externalTests = true
+		return nil
+	}
+}
+
+// PackageWithFuzzTargets can be used along with the LoadPackage function to
+// document the fuzz targets (Fuzz* functions) declared in the package's test
+// files, so that the entry points of the package's fuzz corpus can be listed.
+func PackageWithFuzzTargets() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.fuzzTargets = true
+		return nil
+	}
+}
+
+// PackageWithGenerateDirectives can be used along with the LoadPackage function
+// to list the //go:generate directives in the package's files, so that readers
+// know which files are generated and how to regenerate them.
+func PackageWithGenerateDirectives() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.generateDirectives = true
+		return nil
+	}
+}
+
+// PackageWithTaggedExamples can be used along with the LoadPackage function to
+// include the examples from test files that are excluded only because they
+// require build tags that weren't provided, such as integration examples that
+// need network access. Each of these examples reports the build constraint it
+// requires with Example.RequiredTags.
+func PackageWithTaggedExamples() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.taggedExamples = true
+		return nil
+	}
+}
+
+// PackageWithNotes can be used along with the LoadPackage function to document
+// the notes in the package's comments that use the provided markers, written in
+// the form MARKER(uid): body as recognized by godoc. If no markers are
+// provided, DefaultNoteMarkers is used.
+func PackageWithNotes(markers ...string) PackageOption {
+	return func(opts *PackageOptions) error {
+		if len(markers) == 0 {
+			markers = DefaultNoteMarkers
+		}
+
+		opts.noteMarkers = markers
+		return nil
+	}
+}
+
+// PackageWithBuildTags can be used along with the LoadPackage function to
+// provide the build tags that the package was loaded with. The tags are used
+// when the package is type checked, so they should match the tags of the
+// build.Context that produced the build.Package.
+func PackageWithBuildTags(tags ...string) PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.buildTags = tags
+		return nil
+	}
+}
+
+// PackageWithCommandUsage can be used along with the LoadPackage function to
+// document main packages as commands, listing the command line flags and cobra
+// commands declared in their source. It has no effect on other packages.
+func PackageWithCommandUsage() PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.commandUsage = true
+		return nil
+	}
+}
+
+// PackageWithPlatforms can be used along with the LoadPackage function to
+// document the package as it is built for each of the provided platforms rather
+// than only for the platform of the build.Package. The files of all of the
+// platforms are merged, and symbols that are only declared on some of them
+// report those platforms from their Platforms method in place of a build
+// constraint. The build tags from PackageWithBuildTags apply to every platform.
+func PackageWithPlatforms(platforms ...Platform) PackageOption {
+	return func(opts *PackageOptions) error {
+		opts.platforms = platforms
+		return nil
+	}
+}
+
 // Level provides the default level that headers for the package's root
 // documentation should be rendered.
 func (pkg *Package) Level() int {
@@ -118,6 +492,12 @@ func (pkg *Package) Dirname() string {
 	return filepath.Base(pkg.cfg.PkgDir)
 }
 
+// Repo provides the repository that the package is located in, or nil if it
+// isn't known.
+func (pkg *Package) Repo() *Repo {
+	return pkg.cfg.Repo
+}
+
 // Name provides the name of the package as it would be seen from another
 // package importing it.
 func (pkg *Package) Name() string {
@@ -139,6 +519,45 @@ func (pkg *Package) ImportPath() string {
 	return pkg.doc.ImportPath
 }
 
+// IsInternal indicates whether the package is an internal package, which can
+// only be imported by packages rooted at the parent of its internal directory.
+func (pkg *Package) IsInternal() bool {
+	for _, part := range strings.Split(pkg.ImportPath(), "/") {
+		if part == "internal" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InternalRoot provides the import path of the parent of the package's
+// innermost internal directory, whose packages are the only ones permitted to
+// import the package. The empty string is returned for packages that aren't
+// internal or are internal to the standard library.
+func (pkg *Package) InternalRoot() string {
+	parts := strings.Split(pkg.ImportPath(), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i] == "internal" {
+			return strings.Join(parts[:i], "/")
+		}
+	}
+
+	return ""
+}
+
+// IsModuleRoot indicates whether the package is located in the root directory
+// of a Go Module.
+func (pkg *Package) IsModuleRoot() bool {
+	dir, err := filepath.Abs(pkg.cfg.PkgDir)
+	if err != nil {
+		return false
+	}
+
+	_, modDir, ok := findModule(dir)
+	return ok && modDir == dir
+}
+
 // Summary provides the one-sentence summary of the package's documentation
 // comment.
 func (pkg *Package) Summary() string {
@@ -188,6 +607,66 @@ func (pkg *Package) Types() (types []*Type) {
 	return
 }
 
+// FuzzTargets lists the fuzz targets declared in the package's test files. It
+// is only populated if the package was created using PackageWithFuzzTargets.
+func (pkg *Package) FuzzTargets() (funcs []*Func) {
+	for _, fn := range pkg.fuzzTargets {
+		funcs = append(funcs, NewFunc(pkg.cfg.Inc(2), fn, nil))
+	}
+
+	return
+}
+
+// IsCommand indicates whether the package is a main package documented as a
+// command. This is only the case if the package was created using
+// PackageWithCommandUsage.
+func (pkg *Package) IsCommand() bool {
+	return pkg.command
+}
+
+// CommandFlags lists the command line flags defined by the package with the
+// flag or github.com/spf13/pflag packages, sorted by name. Only flags with a
+// literal name that are defined within the package itself are found. It is
+// only populated for commands (see IsCommand).
+func (pkg *Package) CommandFlags() []*CommandFlag {
+	return pkg.flags
+}
+
+// Subcommands lists the commands declared by the package as
+// github.com/spf13/cobra Command literals, in the order they are declared. It
+// is only populated for commands (see IsCommand).
+func (pkg *Package) Subcommands() []*Subcommand {
+	return pkg.subcommands
+}
+
+// GenerateDirectives lists the //go:generate directives in the package's files,
+// including its test files, ordered by file name and then by line. It is only
+// populated if the package was created using PackageWithGenerateDirectives.
+func (pkg *Package) GenerateDirectives() []*GenerateDirective {
+	return pkg.generate
+}
+
+// Notes provides the notes in the package's comments for each of the markers
+// requested using PackageWithNotes, grouped by marker in the order the markers
+// were requested. Markers without any notes are omitted.
+func (pkg *Package) Notes() (groups []*NoteGroup) {
+	for _, marker := range pkg.noteMarkers {
+		if notes := pkg.doc.Notes[marker]; len(notes) > 0 {
+			groups = append(groups, NewNoteGroup(pkg.cfg.Inc(2), marker, notes))
+		}
+	}
+
+	return
+}
+
+// ExternalTest provides the documentation of the package's external test
+// package, or nil if it has no external test files or they weren't requested
+// using PackageWithExternalTests. Examples from the external test package are
+// always provided with the package itself.
+func (pkg *Package) ExternalTest() *Package {
+	return pkg.externalTest
+}
+
 // Examples provides the package-level examples that have been defined. This
 // does not include examples that are associated with symbols contained within
 // the package.
@@ -197,7 +676,7 @@ func (pkg *Package) Examples() (examples []*Example) {
 		switch {
 		case example.Name == "":
 			name = ""
-		case strings.HasPrefix(example.Name, "_"):
+		case strings.HasPrefix(example.Name, "_") && isExampleSuffix(example.Name[1:]):
 			name = example.Name[1:]
 		default:
 			// TODO: better filtering
@@ -210,42 +689,134 @@ func (pkg *Package) Examples() (examples []*Example) {
 	return
 }
 
+// Symbol looks up the symbol of the provided name declared in the package.
+// Symbols are named as they would be in a doc link, such as "Func", "Type" or
+// "Type.Method".
+func (pkg *Package) Symbol(name string) (*Symbol, bool) {
+	s, ok := pkg.cfg.Symbols[name]
+	return s, ok
+}
+
+// Symbols lists all of the symbols declared in the package, sorted by
+// Symbol.Key().
+func (pkg *Package) Symbols() []*Symbol {
+	symbols := make([]*Symbol, 0, len(pkg.cfg.Symbols))
+	for _, s := range pkg.cfg.Symbols {
+		symbols = append(symbols, s)
+	}
+
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].Key() < symbols[j].Key()
+	})
+
+	return symbols
+}
+
+// SymbolExamples provides the examples for the symbol of the provided name
+// within the package. The symbol is named as it would be in a doc link, such
+// as "Func", "Type" or "Type.Method", and the empty string refers to the
+// package itself. The second return value is false if the package has no such
+// symbol.
+func (pkg *Package) SymbolExamples(symbol string) ([]*Example, bool) {
+	if symbol == "" {
+		return pkg.Examples(), true
+	}
+
+	recv, name := "", symbol
+	if i := strings.Index(symbol, "."); i >= 0 {
+		recv, name = symbol[:i], symbol[i+1:]
+	}
+
+	if recv == "" {
+		for _, fn := range pkg.Funcs() {
+			if fn.Name() == name {
+				return fn.Examples(), true
+			}
+		}
+	}
+
+	for _, typ := range pkg.Types() {
+		if recv == "" {
+			if typ.Name() == name {
+				return typ.Examples(), true
+			}
+
+			for _, fn := range typ.Funcs() {
+				if fn.Name() == name {
+					return fn.Examples(), true
+				}
+			}
+
+			continue
+		}
+
+		if typ.Name() != recv {
+			continue
+		}
+
+		for _, fn := range typ.Methods() {
+			if fn.Name() == name {
+				return fn.Examples(), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
 var goModRegex = regexp.MustCompile(`^\s*module ([^\s]+)`)
 
-// findImportPath attempts to find an import path for the contents of the
+// FindImportPath attempts to find an import path for the contents of the
 // provided dir by walking up to the nearest go.mod file and constructing an
 // import path from it. If the directory is not in a Go Module, the second
 // return value will be false.
-func findImportPath(dir string) (string, bool) {
+func FindImportPath(dir string) (string, bool) {
 	absDir, err := filepath.Abs(dir)
 	if err != nil {
 		return "", false
 	}
 
-	f, ok := findFileInParent(absDir, "go.mod", false)
+	modPath, modDir, ok := findModule(absDir)
 	if !ok {
 		return "", false
 	}
-	defer f.Close()
 
-	b, err := ioutil.ReadAll(f)
+	relative, err := filepath.Rel(modDir, absDir)
 	if err != nil {
 		return "", false
 	}
 
-	m := goModRegex.FindSubmatch(b)
-	if m == nil {
-		return "", false
+	relative = filepath.ToSlash(relative)
+
+	return path.Join(modPath, relative), true
+}
+
+// findModule finds the Go Module containing the provided dir by walking up to
+// the nearest go.mod file, providing the module's path and root directory. If
+// the directory is not in a Go Module, the third return value will be false.
+func findModule(dir string) (string, string, bool) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", false
 	}
 
-	relative, err := filepath.Rel(filepath.Dir(f.Name()), absDir)
+	f, ok := findFileInParent(absDir, "go.mod", false)
+	if !ok {
+		return "", "", false
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
 	if err != nil {
-		return "", false
+		return "", "", false
 	}
 
-	relative = filepath.ToSlash(relative)
+	m := goModRegex.FindSubmatch(b)
+	if m == nil {
+		return "", "", false
+	}
 
-	return path.Join(string(m[1]), relative), true
+	return string(m[1]), filepath.Dir(f.Name()), true
 }
 
 // findFileInParent looks for a file or directory of the given name within the
@@ -277,86 +848,299 @@ func findFileInParent(dir, filename string, fileIsDir bool) (*os.File, bool) {
 	return nil, false
 }
 
-func getDocPkg(pkg *build.Package, fs *token.FileSet, includeUnexported bool) (*doc.Package, error) {
-	pkgs, err := parser.ParseDir(
-		fs,
-		pkg.Dir,
-		func(info os.FileInfo) bool {
-			for _, name := range pkg.GoFiles {
-				if name == info.Name() {
-					return true
-				}
-			}
+// syntheticImportPath constructs an import path for the provided dir from the
+// import path of the root dir.
+func syntheticImportPath(root, rootDir, dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
 
-			for _, name := range pkg.CgoFiles {
-				if name == info.Name() {
-					return true
-				}
-			}
+	relative, err := filepath.Rel(rootDir, absDir)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: couldn't synthesize import path for %s: %w", dir, err)
+	}
 
-			return false
-		},
-		parser.ParseComments,
-	)
+	relative = filepath.ToSlash(relative)
+	if relative == ".." || strings.HasPrefix(relative, "../") {
+		return "", fmt.Errorf("gomarkdoc: couldn't synthesize import path for %s outside of %s", dir, rootDir)
+	}
+
+	return path.Join(root, relative), nil
+}
 
+// getDocPkg builds the documentation for the package. The extent of each
+// function and the category of each declaration are recorded in the config, as
+// they can't be recovered once the documentation is built.
+func getDocPkg(pkg *sourcePackage, cfg *Config, includeUnexported bool, fallbackImportPath string) (*doc.Package, error) {
+	astPkg, err := newASTPackage(pkg)
 	if err != nil {
-		return nil, fmt.Errorf("gomarkdoc: failed to parse package: %w", err)
+		return nil, err
 	}
 
-	if len(pkgs) == 0 {
-		return nil, fmt.Errorf("gomarkdoc: no source-code package in directory %s", pkg.Dir)
+	importPath := pkg.importPath
+	if importComment := findImportComment(pkg.fset, pkg.files(pkg.goFiles)); importComment != "" {
+		importPath = importComment
 	}
 
-	if len(pkgs) > 1 {
-		return nil, fmt.Errorf("gomarkdoc: multiple packages in directory %s", pkg.Dir)
+	if !includeUnexported {
+		packageExports(astPkg)
+	}
+
+	if importPath == "." {
+		if modPath, ok := FindImportPath(pkg.dir); ok {
+			importPath = modPath
+		} else if fallbackImportPath != "" {
+			importPath = fallbackImportPath
+		}
 	}
 
-	astPkg := pkgs[pkg.Name]
+	// Record the extent of each function before its body is dropped
+	cfg.FuncEnds = make(map[token.Pos]token.Pos)
+	for _, f := range astPkg.Files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				cfg.FuncEnds[fn.Pos()] = fn.End()
+			}
+		}
+	}
+
+	// The doc comments of declarations are consumed when the documentation is
+	// built, so the directives within them are read beforehand
+	cfg.Categories = findCategories(astPkg)
+
+	// Imported packages are referenced by the names given in the import
+	// declarations, which the documentation doesn't keep
+	cfg.Imports = packageImportNames(astPkg)
+
+	// Include methods promoted from exported embedded types so that they can
+	// be listed under the embedding type
+	docPkg := doc.New(astPkg, importPath, doc.AllDecls|doc.AllMethods)
+
+	// Filtering to exports drops the import declarations, so restore the
+	// imports from the parsed files to allow references to be resolved
+	docPkg.Imports = fileImports(astPkg)
+
+	return docPkg, nil
+}
+
+// getExternalTestDocPkg builds the documentation for the external test package
+// declared in the package's black-box test files. Functions run by go test are
+// removed before the documentation is built. The files are parsed again so
+// that the examples drawn from them are unaffected.
+func getExternalTestDocPkg(pkg *sourcePackage, fs *token.FileSet, includeUnexported bool, importPath string) (*doc.Package, error) {
+	files := make(map[string]*ast.File)
+	for _, name := range pkg.xtestGoFiles {
+		fileName := filepath.Join(pkg.dir, name)
+		parsed, err := parser.ParseFile(fs, fileName, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to parse package file %s: %w", name, err)
+		}
+
+		var decls []ast.Decl
+		for _, decl := range parsed.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && isTestFunc(fn.Name.Name) {
+				continue
+			}
+
+			decls = append(decls, decl)
+		}
 
+		parsed.Decls = decls
+		removeIgnored(parsed)
+		files[fileName] = parsed
+	}
+
+	astPkg := &ast.Package{Name: pkg.name + "_test", Files: files}
 	if !includeUnexported {
-		ast.PackageExports(astPkg)
+		packageExports(astPkg)
 	}
 
-	importPath := pkg.ImportPath
-	if pkg.ImportComment != "" {
-		importPath = pkg.ImportComment
+	docPkg := doc.New(astPkg, importPath+"_test", doc.AllDecls|doc.AllMethods)
+	docPkg.Imports = fileImports(astPkg)
+
+	return docPkg, nil
+}
+
+// getFuzzTargets collects the fuzz targets declared in the provided test files,
+// sorted by name. The extent of each target is recorded in funcEnds before its
+// body is dropped from the documented declaration.
+func getFuzzTargets(files []*ast.File, funcEnds map[token.Pos]token.Pos) []*doc.Func {
+	var targets []*doc.Func
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Body == nil || !isFuzzFunc(fn.Name.Name) {
+				continue
+			}
+
+			funcEnds[fn.Pos()] = fn.End()
+
+			// Copy the declaration so the examples sharing the file keep
+			// their bodies and comments
+			sig := *fn
+			sig.Doc = nil
+			sig.Body = nil
+
+			targets = append(targets, &doc.Func{
+				Doc:  fn.Doc.Text(),
+				Name: fn.Name.Name,
+				Decl: &sig,
+			})
+		}
 	}
 
-	if importPath == "." {
-		if modPath, ok := findImportPath(pkg.Dir); ok {
-			importPath = modPath
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	return targets
+}
+
+// testFuncPrefixes lists the prefixes of the functions run by go test.
+var testFuncPrefixes = []string{"Test", "Benchmark", "Fuzz", "Example"}
+
+// isTestFunc identifies the names of functions run by go test.
+func isTestFunc(name string) bool {
+	for _, prefix := range testFuncPrefixes {
+		if hasTestPrefix(name, prefix) {
+			return true
 		}
 	}
 
-	return doc.New(astPkg, importPath, doc.AllDecls), nil
+	return false
 }
 
-func parsePkgFiles(pkg *build.Package, fs *token.FileSet) ([]*ast.File, error) {
-	rawFiles, err := ioutil.ReadDir(pkg.Dir)
-	if err != nil {
-		return nil, fmt.Errorf("gomarkdoc: error reading package dir: %w", err)
+// isFuzzFunc identifies the names of fuzz targets.
+func isFuzzFunc(name string) bool {
+	return hasTestPrefix(name, "Fuzz")
+}
+
+// hasTestPrefix follows the same rules as go test for identifying test
+// functions by prefix: the prefix must not be followed by a lower case letter.
+func hasTestPrefix(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
 	}
 
-	var files []*ast.File
-	for _, f := range rawFiles {
-		if !strings.HasSuffix(f.Name(), ".go") && !strings.HasSuffix(f.Name(), ".cgo") {
-			continue
+	if len(name) == len(prefix) {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// newASTPackage collects the syntax of the Go and cgo files of the package,
+// leaving out the declarations that are marked to be ignored.
+func newASTPackage(pkg *sourcePackage) (*ast.Package, error) {
+	astPkg := &ast.Package{Name: pkg.name, Files: make(map[string]*ast.File)}
+	for _, name := range pkg.goFiles {
+		fileName := filepath.Join(pkg.dir, name)
+
+		f := pkg.syntax[fileName]
+		if f == nil {
+			return nil, fmt.Errorf("gomarkdoc: no source-code package in directory %s", pkg.dir)
 		}
 
-		p := path.Join(pkg.Dir, f.Name())
+		if f.Name.Name != pkg.name {
+			return nil, fmt.Errorf("gomarkdoc: multiple packages in directory %s", pkg.dir)
+		}
+
+		removeIgnored(f)
+		astPkg.Files[fileName] = f
+	}
+
+	return astPkg, nil
+}
+
+// getOpaqueTypes finds the unexported types of the package that have exported
+// methods or constructors, which are otherwise dropped along with the type
+// when unexported symbols are excluded. Only the exported methods and
+// constructors of each type are retained. The package's files are parsed again,
+// as the documentation of the package itself is built from syntax that only
+// retains its exported declarations.
+func getOpaqueTypes(pkg *sourcePackage, fs *token.FileSet) (map[string]*doc.Type, error) {
+	astPkg := &ast.Package{Name: pkg.name, Files: make(map[string]*ast.File)}
+	for _, name := range pkg.goFiles {
+		fileName := filepath.Join(pkg.dir, name)
+		parsed, err := parser.ParseFile(fs, fileName, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("gomarkdoc: failed to parse package file %s: %w", name, err)
+		}
+
+		removeIgnored(parsed)
+		astPkg.Files[fileName] = parsed
+	}
+
+	docPkg := doc.New(astPkg, pkg.importPath, doc.AllDecls)
 
-		fi, err := os.Stat(p)
-		if err != nil || !fi.Mode().IsRegular() {
+	types := make(map[string]*doc.Type)
+	for _, t := range docPkg.Types {
+		if token.IsExported(t.Name) {
 			continue
 		}
 
-		parsed, err := parser.ParseFile(fs, p, nil, parser.ParseComments)
-		if err != nil {
-			return nil, fmt.Errorf("gomarkdoc: failed to parse package file %s", f.Name())
+		t.Funcs = exportedFuncs(t.Funcs)
+		t.Methods = exportedFuncs(t.Methods)
+		if len(t.Funcs) == 0 && len(t.Methods) == 0 {
+			continue
+		}
+
+		types[t.Name] = t
+	}
+
+	return types, nil
+}
+
+// exportedFuncs filters the funcs to those with exported names.
+func exportedFuncs(funcs []*doc.Func) []*doc.Func {
+	var exported []*doc.Func
+	for _, fn := range funcs {
+		if token.IsExported(fn.Name) {
+			exported = append(exported, fn)
+		}
+	}
+
+	return exported
+}
+
+// fileImports returns the sorted, unique import paths of the package's files.
+func fileImports(pkg *ast.Package) []string {
+	seen := make(map[string]struct{})
+	var imports []string
+	for _, file := range pkg.Files {
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			if _, ok := seen[importPath]; ok {
+				continue
+			}
+
+			seen[importPath] = struct{}{}
+			imports = append(imports, importPath)
 		}
+	}
+
+	sort.Strings(imports)
+	return imports
+}
 
-		files = append(files, parsed)
+// testFiles provides the syntax of the test files for the package which are
+// eligible under the package's build constraints. Examples are drawn from both
+// the internal and external test files, as they are with godoc.
+func testFiles(pkg *sourcePackage) []*ast.File {
+	var names []string
+	names = append(names, pkg.testGoFiles...)
+	names = append(names, pkg.xtestGoFiles...)
+
+	files := pkg.files(names)
+	for _, f := range files {
+		removeIgnored(f)
 	}
 
-	return files, nil
+	return files
 }
@@ -43,6 +43,477 @@ func TestPackage_Vars(t *testing.T) {
 	is.Equal(decl, `var Variable = 5`)
 }
 
+func TestPackage_Consts_valuesBuildTags(t *testing.T) {
+	tests := map[string]struct {
+		tags  []string
+		value string
+	}{
+		"default": {value: "4"},
+		"tagged":  {tags: []string{"large"}, value: "1024"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+
+			wd, err := os.Getwd()
+			is.NoErr(err)
+
+			ctx := build.Default
+			ctx.BuildTags = test.tags
+			buildPkg, err := ctx.Import("../testData/lang/tagged", wd, build.ImportComment)
+			is.NoErr(err)
+
+			log := logger.New(logger.ErrorLevel)
+			pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithConstValues(), lang.PackageWithBuildTags(test.tags...))
+			is.NoErr(err)
+
+			consts := pkg.Consts()
+			is.Equal(len(consts), 1)
+			is.Equal(consts[0].ConstValues(), []*lang.ConstValue{{Name: "Size", Value: test.value}})
+		})
+	}
+}
+
+func TestPackage_Vars_values(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/values", lang.PackageWithConstValues())
+	is.NoErr(err)
+
+	vars := pkg.Vars()
+	is.Equal(len(vars), 3)
+
+	var values [][]lang.ConstValue
+	for _, v := range vars {
+		vals, err := v.VarValues()
+		is.NoErr(err)
+
+		var deref []lang.ConstValue
+		for _, val := range vals {
+			deref = append(deref, *val)
+		}
+
+		values = append(values, deref)
+	}
+
+	is.Equal(values, [][]lang.ConstValue{
+		{
+			{Name: "ErrNotFound", Value: `errors.New("not found")`},
+			{Name: "ErrClosed", Value: `errors.New("closed")`},
+		},
+		{
+			{Name: "DefaultRetries", Value: "3"},
+			{Name: "DefaultName", Value: `"values"`},
+			{Name: "Separator", Value: `"/"`},
+			{Name: "Verbose", Value: "false"},
+		},
+		nil,
+	})
+}
+
+func TestPackage_Vars_valuesDisabled(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/values")
+	is.NoErr(err)
+
+	for _, v := range pkg.Vars() {
+		values, err := v.VarValues()
+		is.NoErr(err)
+		is.Equal(len(values), 0)
+	}
+}
+
+func TestPackage_Examples(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	examples := pkg.Examples()
+	is.Equal(len(examples), 1)
+	is.Equal(examples[0].Name(), "Package Level")
+	is.Equal(examples[0].Output(), "5\n")
+}
+
+func TestPackage_ExternalTest(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithExternalTests())
+	is.NoErr(err)
+
+	xtest := pkg.ExternalTest()
+	is.True(xtest != nil)
+	is.Equal(xtest.Name(), "function_test")
+	is.Equal(xtest.Level(), pkg.Level()+1)
+
+	// Test entry points such as examples are left out
+	funcs := xtest.Funcs()
+	is.Equal(len(funcs), 1)
+	is.Equal(funcs[0].Name(), "NewTestReceiver")
+
+	// Examples remain with the package itself
+	is.Equal(len(pkg.Examples()), 1)
+}
+
+func TestPackage_FuzzTargets(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithFuzzTargets())
+	is.NoErr(err)
+
+	targets := pkg.FuzzTargets()
+	is.Equal(len(targets), 1)
+	is.Equal(targets[0].Name(), "FuzzStandalone")
+	is.Equal(targets[0].Level(), pkg.Level()+2)
+	is.Equal(targets[0].Summary(), "FuzzStandalone checks that Standalone accepts arbitrary input.")
+
+	sig, err := targets[0].Signature()
+	is.NoErr(err)
+	is.Equal(sig, "func FuzzStandalone(f *testing.F)")
+}
+
+func TestPackage_FuzzTargets_disabled(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	is.Equal(len(pkg.FuzzTargets()), 0)
+}
+
+func TestPackage_taggedExamples(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithTaggedExamples())
+	is.NoErr(err)
+
+	var examples []*lang.Example
+	for _, fn := range pkg.Funcs() {
+		if fn.Name() == "Standalone" {
+			examples = fn.Examples()
+		}
+	}
+
+	is.Equal(len(examples), 3)
+
+	is.Equal(examples[0].Name(), "")
+	is.Equal(examples[0].RequiredTags(), "")
+
+	is.Equal(examples[1].Name(), "Integration")
+	is.Equal(examples[1].RequiredTags(), "integration")
+	is.Equal(examples[1].Output(), "3\n")
+
+	is.Equal(examples[2].Name(), "Zero")
+	is.Equal(examples[2].RequiredTags(), "")
+}
+
+func TestPackage_taggedExamples_disabled(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	for _, fn := range pkg.Funcs() {
+		if fn.Name() == "Standalone" {
+			is.Equal(len(fn.Examples()), 2)
+		}
+	}
+}
+
+func TestPackage_Notes(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithNotes("TODO", "SECURITY", "BUG"))
+	is.NoErr(err)
+
+	groups := pkg.Notes()
+	is.Equal(len(groups), 2)
+
+	is.Equal(groups[0].Marker(), "TODO")
+	is.Equal(groups[0].Title(), "TODO")
+	is.Equal(groups[0].Level(), pkg.Level()+2)
+
+	is.Equal(groups[1].Marker(), "BUG")
+	is.Equal(groups[1].Title(), "Bugs")
+
+	notes := groups[1].Notes()
+	is.Equal(len(notes), 1)
+	is.Equal(notes[0].UID(), "ag5denis")
+	is.Equal(notes[0].Text(), "Standalone ignores its second parameter.")
+	is.Equal(notes[0].Location().Start.Line, 3)
+}
+
+func TestPackage_Notes_default(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithNotes())
+	is.NoErr(err)
+
+	groups := pkg.Notes()
+	is.Equal(len(groups), 1)
+	is.Equal(groups[0].Marker(), "BUG")
+}
+
+func TestPackage_Notes_disabled(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	is.Equal(len(pkg.Notes()), 0)
+}
+
+func TestPackage_GenerateDirectives(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithGenerateDirectives())
+	is.NoErr(err)
+
+	directives := pkg.GenerateDirectives()
+	is.Equal(len(directives), 1)
+	is.Equal(directives[0].File(), "generate.go")
+	is.Equal(directives[0].Line(), 3)
+	is.Equal(directives[0].Command(), `echo "regenerating fixtures"`)
+	is.Equal(directives[0].Location().Start.Line, 3)
+}
+
+func TestPackage_GenerateDirectives_disabled(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	is.Equal(len(pkg.GenerateDirectives()), 0)
+}
+
+func TestPackage_ignoreDirective(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	for _, fn := range pkg.Funcs() {
+		is.True(fn.Name() != "IgnoredFunc") // ignored func should be removed
+	}
+
+	var visible *lang.Type
+	for _, typ := range pkg.Types() {
+		is.True(typ.Name() != "Ignored") // ignored type should be removed
+
+		if typ.Name() == "Visible" {
+			visible = typ
+		}
+	}
+
+	is.True(visible != nil)
+
+	decl, err := visible.Decl()
+	is.NoErr(err)
+	is.Equal(decl, `type Visible struct {
+    Shown string
+    // contains filtered or unexported fields
+}`)
+}
+
+func TestPackage_Order_source(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithOrder(lang.OrderSource))
+	is.NoErr(err)
+	is.Equal(pkg.Order(), lang.OrderSource)
+
+	var locs []lang.Location
+	var sawFunc, sawType bool
+	for _, d := range pkg.Declarations() {
+		if d.Func != nil {
+			sawFunc = true
+			locs = append(locs, d.Func.Location())
+		} else {
+			sawType = true
+			locs = append(locs, d.Type.Location())
+		}
+	}
+
+	is.True(sawFunc && sawType)
+	for i := 1; i < len(locs); i++ {
+		is.True(!locationBefore(locs[i], locs[i-1])) // declarations should follow the source
+	}
+}
+
+func TestPackage_Order_kind(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithOrder(lang.OrderKind))
+	is.NoErr(err)
+
+	funcs := pkg.Funcs()
+	for i := 1; i < len(funcs); i++ {
+		is.True(!locationBefore(funcs[i].Location(), funcs[i-1].Location())) // funcs should follow the source
+	}
+
+	categorized := 0
+	for _, category := range pkg.Categories() {
+		categorized += len(category.Declarations())
+	}
+
+	decls := pkg.Declarations()
+	is.Equal(len(decls)+categorized, len(funcs)+len(pkg.Types()))
+	is.True(decls[0].Func != nil)            // funcs should come first
+	is.True(decls[len(decls)-1].Type != nil) // types should come last
+}
+
+func TestPackage_Order_alphabetical(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+	is.Equal(pkg.Order(), lang.OrderAlphabetical)
+
+	funcs := pkg.Funcs()
+	for i := 1; i < len(funcs); i++ {
+		is.True(funcs[i-1].Name() < funcs[i].Name()) // funcs should be sorted by name
+	}
+}
+
+func TestPackage_Order_invalid(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	_, err = lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithOrder("random"))
+	is.True(err != nil)
+}
+
+func locationBefore(a, b lang.Location) bool {
+	if a.Filepath != b.Filepath {
+		return a.Filepath < b.Filepath
+	}
+
+	return a.Start.Line < b.Start.Line
+}
+
+func TestPackage_constructorPatterns(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Widget", lang.PackageWithConstructorPatterns([]string{"Make"}, nil))
+	is.NoErr(err)
+
+	funcs := typ.Funcs()
+	is.Equal(len(funcs), 1)
+	is.Equal(funcs[0].Name(), "MakeWidget")
+}
+
+func TestPackage_constructorPatterns_default(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Widget")
+	is.NoErr(err)
+	is.Equal(len(typ.Funcs()), 0)
+}
+
+func TestPackage_constructorsUngrouped(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithConstructorsUngrouped())
+	is.NoErr(err)
+
+	for _, typ := range pkg.Types() {
+		is.Equal(len(typ.Funcs()), 0) // no funcs should be grouped with types
+	}
+
+	var found bool
+	for _, fn := range pkg.Funcs() {
+		if fn.Name() == "New" {
+			found = true
+		}
+	}
+
+	is.True(found) // constructor should be listed at the top level
+}
+
+func TestPackage_Categories(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	categories := pkg.Categories()
+	is.Equal(len(categories), 2)
+
+	is.Equal(categories[0].Name(), "Encoding")
+	is.Equal(categories[0].Level(), 2)
+	decls := categories[0].Declarations()
+	is.Equal(len(decls), 1)
+	is.Equal(decls[0].Func.Name(), "Checksum")
+	is.Equal(decls[0].Func.Level(), 3)
+
+	is.Equal(categories[1].Name(), "Networking")
+	decls = categories[1].Declarations()
+	is.Equal(len(decls), 2)
+	is.Equal(decls[0].Func.Name(), "Dial")
+	is.Equal(decls[0].Func.Summary(), "Dial opens a connection to the address.")
+	is.Equal(decls[1].Type.Name(), "Listener")
+	is.Equal(decls[1].Type.Category(), "Networking")
+
+	for _, decl := range pkg.Declarations() {
+		if decl.Func != nil {
+			is.True(decl.Func.Name() != "Dial") // categorized funcs are listed separately
+			is.True(decl.Func.Name() != "Checksum")
+		} else {
+			is.True(decl.Type.Name() != "Listener") // categorized types are listed separately
+		}
+	}
+}
+
+func TestPackage_ExternalTest_disabled(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	is.True(pkg.ExternalTest() == nil)
+}
+
 func TestPackage_dotImport(t *testing.T) {
 	is := is.New(t)
 
@@ -165,17 +636,283 @@ func getBuildPackage(path string) (*build.Package, error) {
 	return build.Import(path, wd, build.ImportComment)
 }
 
-func loadPackage(dir string) (*lang.Package, error) {
+func loadPackage(dir string, opts ...lang.PackageOption) (*lang.Package, error) {
 	buildPkg, err := getBuildPackage(dir)
 	if err != nil {
 		return nil, err
 	}
 
 	log := logger.New(logger.ErrorLevel)
-	pkg, err := lang.NewPackageFromBuild(log, buildPkg)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return pkg, nil
 }
+
+func TestPackage_importPathRoot(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "sub", "sub.go"), []byte("// Package sub is scratch code.\npackage sub\n"), 0644))
+
+	wd, err := os.Getwd()
+	is.NoErr(err)
+
+	is.NoErr(os.Chdir(dir))
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	buildPkg, err := build.ImportDir("./sub", build.ImportComment)
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithImportPathRoot("example.com/scratch"))
+	is.NoErr(err)
+
+	is.Equal(pkg.Import(), `import "example.com/scratch/sub"`)
+	is.Equal(pkg.ImportPath(), "example.com/scratch/sub")
+}
+
+func TestPackage_IsModuleRoot(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/scratch\n"), 0644))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "root.go"), []byte("// Package scratch is scratch code.\npackage scratch\n"), 0644))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "sub", "sub.go"), []byte("// Package sub is scratch code.\npackage sub\n"), 0644))
+
+	log := logger.New(logger.ErrorLevel)
+
+	rootBuildPkg, err := build.ImportDir(dir, build.ImportComment)
+	is.NoErr(err)
+
+	root, err := lang.NewPackageFromBuild(log, rootBuildPkg)
+	is.NoErr(err)
+	is.True(root.IsModuleRoot())
+
+	subBuildPkg, err := build.ImportDir(filepath.Join(dir, "sub"), build.ImportComment)
+	is.NoErr(err)
+
+	sub, err := lang.NewPackageFromBuild(log, subBuildPkg)
+	is.NoErr(err)
+	is.True(!sub.IsModuleRoot())
+}
+
+func TestPackage_IsInternal(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "pkg", "internal", "store"), 0755))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "pkg", "internal", "store", "store.go"), []byte("// Package store is scratch code.\npackage store\n"), 0644))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/scratch\n"), 0644))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "pkg", "pkg.go"), []byte("// Package pkg is scratch code.\npackage pkg\n"), 0644))
+
+	log := logger.New(logger.ErrorLevel)
+
+	storeBuildPkg, err := build.ImportDir(filepath.Join(dir, "pkg", "internal", "store"), build.ImportComment)
+	is.NoErr(err)
+
+	store, err := lang.NewPackageFromBuild(log, storeBuildPkg)
+	is.NoErr(err)
+	is.True(store.IsInternal())
+	is.Equal(store.InternalRoot(), "example.com/scratch/pkg")
+
+	pkgBuildPkg, err := build.ImportDir(filepath.Join(dir, "pkg"), build.ImportComment)
+	is.NoErr(err)
+
+	pkg, err := lang.NewPackageFromBuild(log, pkgBuildPkg)
+	is.NoErr(err)
+	is.True(!pkg.IsInternal())
+	is.Equal(pkg.InternalRoot(), "")
+}
+
+func TestPackage_deprecatedOmitted(t *testing.T) {
+	is := is.New(t)
+
+	buildPkg, err := getBuildPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithDeprecatedOmitted())
+	is.NoErr(err)
+
+	for _, fn := range pkg.Funcs() {
+		is.True(fn.Name() != "Total") // deprecated func should be omitted
+	}
+
+	is.True(len(pkg.Funcs()) > 0) // other funcs should remain
+}
+
+func TestPackage_Symbol(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	s, ok := pkg.Symbol("Receiver.WithReceiver")
+	is.True(ok)
+	is.Equal(s.Kind, lang.MethodSymbol)
+	is.Equal(s.HeaderText, "func (Receiver) WithReceiver")
+
+	_, ok = pkg.Symbol("Missing")
+	is.True(!ok)
+}
+
+func TestPackage_SymbolExamples(t *testing.T) {
+	is := is.New(t)
+
+	pkg, err := loadPackage("../testData/lang/function")
+	is.NoErr(err)
+
+	examples, ok := pkg.SymbolExamples("Standalone")
+	is.True(ok)
+	is.Equal(len(examples), 2)
+
+	examples, ok = pkg.SymbolExamples("Receiver")
+	is.True(ok)
+	is.Equal(len(examples), 2)
+
+	examples, ok = pkg.SymbolExamples("Generic.WithGenericReceiver")
+	is.True(ok)
+	is.Equal(len(examples), 1)
+
+	examples, ok = pkg.SymbolExamples("")
+	is.True(ok)
+	is.Equal(len(examples), 1)
+
+	_, ok = pkg.SymbolExamples("Missing")
+	is.True(!ok)
+}
+
+func TestPackage_platforms(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"common.go":  "// Package plat is scratch code.\npackage plat\n\n// File is a file.\ntype File struct{}\n\n// Open opens a file.\nfunc Open() *File { return nil }\n",
+		"linux.go":   "//go:build linux\n\npackage plat\n\n// Fd provides the descriptor.\nfunc (f *File) Fd() int { return 0 }\n\n// Epoll is only on linux.\nconst Epoll = 1\n",
+		"windows.go": "//go:build windows\n\npackage plat\n\n// Handle provides the handle.\nfunc (f *File) Handle() uintptr { return 0 }\n",
+		"unix.go":    "//go:build linux || darwin\n\npackage plat\n\n// Signal is on unix.\nfunc Signal() {}\n",
+	} {
+		is.NoErr(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	var platforms []lang.Platform
+	for _, s := range []string{"linux/amd64", "darwin/arm64", "windows/amd64:netgo"} {
+		p, err := lang.ParsePlatform(s)
+		is.NoErr(err)
+		platforms = append(platforms, p)
+	}
+
+	buildPkg, err := build.ImportDir(dir, build.ImportComment)
+	is.NoErr(err)
+
+	pkg, err := lang.NewPackageFromBuild(logger.New(logger.ErrorLevel), buildPkg, lang.PackageWithPlatforms(platforms...))
+	is.NoErr(err)
+
+	m, err := pkg.Model()
+	is.NoErr(err)
+
+	is.Equal(len(m.Consts), 1)
+	is.Equal(m.Consts[0].Platforms, []string{"linux/amd64"})
+	is.Equal(m.Consts[0].Constraint, "")
+
+	is.Equal(len(m.Funcs), 1)
+	is.Equal(m.Funcs[0].Name, "Signal")
+	is.Equal(m.Funcs[0].Platforms, []string{"linux/amd64", "darwin/arm64"})
+
+	is.Equal(len(m.Types), 1)
+	is.Equal(len(m.Types[0].Platforms), 0)
+	is.Equal(len(m.Types[0].Funcs[0].Platforms), 0)
+
+	methods := make(map[string][]string)
+	for _, method := range m.Types[0].Methods {
+		methods[method.Name] = method.Platforms
+	}
+
+	is.Equal(methods, map[string][]string{
+		"Fd":     {"linux/amd64"},
+		"Handle": {"windows/amd64:netgo"},
+	})
+}
+
+func TestParsePlatform(t *testing.T) {
+	is := is.New(t)
+
+	p, err := lang.ParsePlatform("linux/amd64:netgo+osusergo")
+	is.NoErr(err)
+	is.Equal(p, lang.Platform{GOOS: "linux", GOARCH: "amd64", Tags: []string{"netgo", "osusergo"}})
+	is.Equal(p.String(), "linux/amd64:netgo+osusergo")
+
+	for _, s := range []string{"linux", "linux/", "/amd64", "linux/amd64/v2", "linux/amd64:"} {
+		_, err := lang.ParsePlatform(s)
+		is.True(err != nil) // Expected an error
+	}
+}
+
+func TestPackage_commandUsage(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	is.NoErr(os.WriteFile(filepath.Join(dir, "main.go"), []byte(`// Command tool does things.
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var fs = flag.NewFlagSet("tool", flag.ExitOnError)
+
+func main() {
+	verbose := flag.Bool("verbose", false, "Log more " + "output.")
+	flag.DurationVar(new(time.Duration), "timeout", 5*time.Second, "How long to wait.")
+	_ = fs.String("name", "world", "Who to greet.")
+	_ = verbose
+
+	root := &cobra.Command{Use: "tool [file ...]", Short: "do things"}
+	root.Flags().StringSliceVarP(new([]string), "tags", "t", nil, "Tags to apply.")
+	root.AddCommand(&cobra.Command{Use: "serve"})
+}
+`), 0644))
+
+	buildPkg, err := build.ImportDir(dir, build.ImportComment)
+	is.NoErr(err)
+
+	log := logger.New(logger.ErrorLevel)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, lang.PackageWithCommandUsage())
+	is.NoErr(err)
+	is.True(pkg.IsCommand())
+
+	var flags []string
+	for _, f := range pkg.CommandFlags() {
+		flags = append(flags, strings.Join([]string{f.Syntax(), f.Type(), f.Default(), f.Usage()}, "|"))
+	}
+
+	is.Equal(flags, []string{
+		"-name|string|\"world\"|Who to greet.",
+		"--tags, -t|stringSlice||Tags to apply.",
+		"-timeout|duration|5 * time.Second|How long to wait.",
+		"-verbose|bool||Log more output.",
+	})
+
+	var commands []string
+	for _, c := range pkg.Subcommands() {
+		commands = append(commands, c.Use()+"|"+c.Short())
+	}
+
+	is.Equal(commands, []string{"tool [file ...]|do things", "serve|"})
+
+	// Main packages are documented as libraries without the option
+	pkg, err = lang.NewPackageFromBuild(log, buildPkg)
+	is.NoErr(err)
+	is.True(!pkg.IsCommand())
+	is.Equal(len(pkg.CommandFlags()), 0)
+}
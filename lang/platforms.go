@@ -0,0 +1,206 @@
+package lang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/logger"
+)
+
+// Platform identifies a target for which a package is built: an operating
+// system and architecture, along with any additional build tags.
+type Platform struct {
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+// ParsePlatform parses a platform in the form GOOS/GOARCH, optionally followed
+// by a colon and a list of build tags separated by plus signs, such as
+// linux/amd64:netgo+osusergo.
+func ParsePlatform(s string) (Platform, error) {
+	target, tags, hasTags := strings.Cut(s, ":")
+
+	goos, goarch, ok := strings.Cut(target, "/")
+	if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+		return Platform{}, fmt.Errorf("gomarkdoc: invalid platform %q: expected GOOS/GOARCH", s)
+	}
+
+	p := Platform{GOOS: goos, GOARCH: goarch}
+	if hasTags {
+		for _, tag := range strings.Split(tags, "+") {
+			if tag == "" {
+				return Platform{}, fmt.Errorf("gomarkdoc: invalid platform %q: empty build tag", s)
+			}
+
+			p.Tags = append(p.Tags, tag)
+		}
+	}
+
+	return p, nil
+}
+
+// String provides the platform in the form accepted by ParsePlatform.
+func (p Platform) String() string {
+	s := p.GOOS + "/" + p.GOARCH
+	if len(p.Tags) > 0 {
+		s += ":" + strings.Join(p.Tags, "+")
+	}
+
+	return s
+}
+
+// mergePlatforms loads the package at the path for each of the platforms and
+// combines the files of all of them into a single package. It also provides
+// the platforms on which each top-level symbol is declared, keyed by its name
+// (or Type.Method for methods). Symbols declared on all of the platforms the
+// package exists on are omitted. Platforms on which the package has no files
+// are skipped. The package's types, if requested, are those of the first
+// platform it exists on.
+func mergePlatforms(log logger.Logger, fset *token.FileSet, path string, platforms []Platform, tags []string, typed bool) (*sourcePackage, map[string][]string, error) {
+	var (
+		merged        *sourcePackage
+		loaded        []string
+		goFiles       = make(map[string]bool)
+		testGoFiles   = make(map[string]bool)
+		xtestGoFiles  = make(map[string]bool)
+		ignored       = make(map[string]bool)
+		fileLocations = make(map[string][]string)
+	)
+
+	for i := range platforms {
+		p := platforms[i]
+
+		platformPkg, err := loadSourcePackage(log, fset, path, tags, &p, typed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gomarkdoc: failed to load package for platform %s: %w", p, err)
+		}
+
+		if len(platformPkg.goFiles) == 0 {
+			continue
+		}
+
+		label := p.String()
+		loaded = append(loaded, label)
+
+		if merged == nil {
+			merged = platformPkg
+		} else {
+			// Files shared by the platforms keep the syntax of the first
+			// platform, which the types of the package are checked against
+			for fileName, f := range platformPkg.syntax {
+				if _, ok := merged.syntax[fileName]; !ok {
+					merged.syntax[fileName] = f
+				}
+			}
+		}
+
+		for _, name := range platformPkg.goFiles {
+			goFiles[name] = true
+			fileLocations[name] = append(fileLocations[name], label)
+		}
+
+		for _, name := range platformPkg.testGoFiles {
+			testGoFiles[name] = true
+		}
+
+		for _, name := range platformPkg.xtestGoFiles {
+			xtestGoFiles[name] = true
+		}
+
+		for _, name := range platformPkg.ignoredGoFiles {
+			ignored[name] = true
+		}
+	}
+
+	if merged == nil {
+		return nil, nil, fmt.Errorf("gomarkdoc: package at %s has no files for any of the platforms", path)
+	}
+
+	merged.goFiles = sortedKeys(goFiles)
+	merged.testGoFiles = sortedKeys(testGoFiles)
+	merged.xtestGoFiles = sortedKeys(xtestGoFiles)
+
+	// Files are only ignored if none of the platforms include them
+	for _, name := range merged.allGoFiles() {
+		delete(ignored, name)
+	}
+
+	merged.ignoredGoFiles = sortedKeys(ignored)
+
+	declared := make(map[string]map[string]bool)
+	for name, labels := range fileLocations {
+		for _, key := range declKeys(merged.syntax[filepath.Join(merged.dir, name)]) {
+			if declared[key] == nil {
+				declared[key] = make(map[string]bool)
+			}
+
+			for _, label := range labels {
+				declared[key][label] = true
+			}
+		}
+	}
+
+	symbolPlatforms := make(map[string][]string)
+	for key, labels := range declared {
+		if len(labels) == len(loaded) {
+			continue
+		}
+
+		// Keep the order in which the platforms were requested
+		for _, label := range loaded {
+			if labels[label] {
+				symbolPlatforms[key] = append(symbolPlatforms[key], label)
+			}
+		}
+	}
+
+	return merged, symbolPlatforms, nil
+}
+
+// declKeys lists the keys of the top-level symbols declared in the file, using
+// the name of each symbol or Type.Method for methods.
+func declKeys(f *ast.File) []string {
+	var keys []string
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				if recv, ok := unwrapTypeExpr(d.Recv.List[0].Type).(*ast.Ident); ok {
+					keys = append(keys, recv.Name+"."+d.Name.Name)
+				}
+
+				continue
+			}
+
+			keys = append(keys, d.Name.Name)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					keys = append(keys, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						keys = append(keys, name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return keys
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
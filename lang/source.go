@@ -0,0 +1,53 @@
+package lang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+)
+
+// declSource provides the full source text of the declaration that begins at
+// the position of the provided node. The source is read from the declaring
+// file rather than printed from the node so that it includes the function
+// bodies and unexported fields that are dropped from the documentation. Type
+// specs are provided as standalone declarations, even when they are declared
+// within a group.
+func declSource(cfg *Config, node ast.Node) (string, error) {
+	pos := cfg.FileSet.Position(node.Pos())
+
+	src, err := os.ReadFile(pos.Filename)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: failed to read source file %s: %w", pos.Filename, err)
+	}
+
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, pos.Filename, src, 0)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: failed to parse source file %s: %w", pos.Filename, err)
+	}
+
+	text := func(n ast.Node) string {
+		return string(src[fs.Position(n.Pos()).Offset:fs.Position(n.End()).Offset])
+	}
+
+	for _, decl := range f.Decls {
+		if fs.Position(decl.Pos()).Offset == pos.Offset {
+			return text(decl), nil
+		}
+
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			if fs.Position(spec.Pos()).Offset == pos.Offset {
+				return "type " + text(spec), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("gomarkdoc: no declaration found at %s", pos)
+}
@@ -0,0 +1,232 @@
+package lang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type (
+	// Symbol identifies a named declaration within a package that can be
+	// referenced from other parts of the documentation.
+	Symbol struct {
+		Kind     SymbolKind
+		Name     string
+		Receiver string
+		Location Location
+
+		// HeaderText holds the plain text of the header under which the
+		// symbol is documented, which can be used to generate a link to the
+		// symbol's documentation.
+		HeaderText string
+	}
+
+	// SymbolKind identifies the kind of declaration represented by a Symbol.
+	SymbolKind string
+)
+
+const (
+	// TypeSymbol identifies a type declaration.
+	TypeSymbol SymbolKind = "type"
+
+	// FuncSymbol identifies a function without a receiver.
+	FuncSymbol SymbolKind = "func"
+
+	// MethodSymbol identifies a function with a receiver.
+	MethodSymbol SymbolKind = "method"
+
+	// ConstSymbol identifies a constant.
+	ConstSymbol SymbolKind = "const"
+
+	// VarSymbol identifies a variable.
+	VarSymbol SymbolKind = "var"
+)
+
+// Key provides the name used to look up the symbol within its package. Methods
+// are identified by their receiver type and name separated by a period (e.g.
+// Type.Method), while all other symbols are identified by their name.
+func (s *Symbol) Key() string {
+	if s.Receiver != "" {
+		return fmt.Sprintf("%s.%s", s.Receiver, s.Name)
+	}
+
+	return s.Name
+}
+
+// newSymbols builds a lookup table of all of the symbols declared in the
+// package, keyed by Symbol.Key().
+func newSymbols(cfg *Config, pkg *doc.Package) map[string]*Symbol {
+	symbols := make(map[string]*Symbol)
+	add := func(s *Symbol) {
+		symbols[s.Key()] = s
+	}
+
+	addValues := func(kind SymbolKind, values []*doc.Value, headerText string) {
+		for _, v := range values {
+			for _, name := range v.Names {
+				add(&Symbol{
+					Kind:       kind,
+					Name:       name,
+					Location:   NewLocation(cfg, v.Decl),
+					HeaderText: headerText,
+				})
+			}
+		}
+	}
+
+	addFuncs := func(funcs []*doc.Func) {
+		for _, fn := range funcs {
+			add(&Symbol{
+				Kind:       FuncSymbol,
+				Name:       fn.Name,
+				Location:   NewLocation(cfg, fn.Decl),
+				HeaderText: fmt.Sprintf("func %s", fn.Name),
+			})
+		}
+	}
+
+	addValues(ConstSymbol, pkg.Consts, "Constants")
+	addValues(VarSymbol, pkg.Vars, "Variables")
+	addFuncs(pkg.Funcs)
+
+	for _, typ := range pkg.Types {
+		typeHeader := fmt.Sprintf("type %s", typ.Name)
+		add(&Symbol{
+			Kind:       TypeSymbol,
+			Name:       typ.Name,
+			Location:   NewLocation(cfg, typ.Decl),
+			HeaderText: typeHeader,
+		})
+		addValues(ConstSymbol, typ.Consts, typeHeader)
+		addValues(VarSymbol, typ.Vars, typeHeader)
+		addFuncs(typ.Funcs)
+
+		for _, m := range typ.Methods {
+			// Promoted methods are documented under the type that originally
+			// declares them
+			recv := m.Recv
+			if m.Level > 0 {
+				recv = m.Orig
+			}
+
+			add(&Symbol{
+				Kind:       MethodSymbol,
+				Name:       m.Name,
+				Receiver:   typ.Name,
+				Location:   NewLocation(cfg, m.Decl),
+				HeaderText: fmt.Sprintf("func (%s) %s", recv, m.Name),
+			})
+		}
+	}
+
+	return symbols
+}
+
+// newImports builds a lookup table of the import paths used by the package,
+// keyed by the name each package is assumed to have. It is used when the
+// import declarations of the package's files aren't available.
+func newImports(pkg *doc.Package) map[string]string {
+	imports := make(map[string]string)
+	for _, importPath := range pkg.Imports {
+		imports[assumedPackageName(importPath)] = importPath
+	}
+
+	return imports
+}
+
+// packageImportNames builds a lookup table of the import paths used by the
+// package's files, keyed by the name each file refers to the package by. Blank
+// and dot imports are left out.
+func packageImportNames(pkg *ast.Package) map[string]string {
+	imports := make(map[string]string)
+	for _, file := range pkg.Files {
+		for name, importPath := range fileImportNames(file) {
+			if name != "_" && name != "." {
+				imports[name] = importPath
+			}
+		}
+	}
+
+	return imports
+}
+
+// assumedPackageName provides the name of the package with the provided import
+// path as go/doc assumes it to be without loading the package: the last
+// element of the path, skipping a major version suffix such as /v2 and
+// dropping a go- prefix and anything following the first character that
+// can't appear in an identifier, as with gopkg.in/yaml.v2.
+func assumedPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if strings.HasPrefix(base, "v") {
+		if _, err := strconv.Atoi(base[1:]); err == nil {
+			if dir := path.Dir(importPath); dir != "." {
+				base = path.Base(dir)
+			}
+		}
+	}
+
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); i >= 0 {
+		base = base[:i]
+	}
+
+	return base
+}
+
+// typeLink provides a link to the documentation of the type referenced by the
+// provided expression, or nil if the type isn't declared in the package or one
+// of its imports.
+func typeLink(cfg *Config, expr ast.Expr) *DocLink {
+	if s, ok := lookupTypeSymbol(cfg, expr); ok {
+		return &DocLink{Name: s.Name, Symbol: s}
+	}
+
+	sel, ok := unwrapTypeExpr(expr).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	importPath, ok := cfg.Imports[pkg.Name]
+	if !ok {
+		return nil
+	}
+
+	return &DocLink{ImportPath: importPath, Name: sel.Sel.Name}
+}
+
+// lookupTypeSymbol finds the type symbol referenced by the provided
+// expression, if the expression is a reference to a type declared within the
+// package. Instantiations of generic types (e.g. List[T]) resolve to the
+// generic type itself.
+func lookupTypeSymbol(cfg *Config, expr ast.Expr) (*Symbol, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		s, ok := cfg.Symbols[e.Name]
+		if !ok || s.Kind != TypeSymbol {
+			return nil, false
+		}
+
+		return s, true
+	case *ast.IndexExpr:
+		return lookupTypeSymbol(cfg, e.X)
+	case *ast.IndexListExpr:
+		return lookupTypeSymbol(cfg, e.X)
+	case *ast.StarExpr:
+		return lookupTypeSymbol(cfg, e.X)
+	case *ast.ParenExpr:
+		return lookupTypeSymbol(cfg, e.X)
+	default:
+		return nil, false
+	}
+}
@@ -2,6 +2,7 @@ package lang
 
 import (
 	"fmt"
+	"go/ast"
 	"go/doc"
 	"strings"
 )
@@ -32,9 +33,15 @@ func (typ *Type) Name() string {
 }
 
 // Title provides a formatted name suitable for use in a header identifying the
-// type.
+// type. Generic types include their type parameter list (e.g. type List[T
+// any]).
 func (typ *Type) Title() string {
-	return fmt.Sprintf("type %s", typ.doc.Name)
+	params, err := typ.TypeParamsDecl()
+	if err != nil {
+		params = ""
+	}
+
+	return fmt.Sprintf("type %s%s", typ.doc.Name, params)
 }
 
 // Location returns a representation of the node's location in a file within a
@@ -43,6 +50,25 @@ func (typ *Type) Location() Location {
 	return NewLocation(typ.cfg, typ.doc.Decl)
 }
 
+// BuildConstraint provides the build constraint of the file declaring the
+// type, including the GOOS and GOARCH implied by the file's name, or the empty
+// string if the file has no constraint. It is always empty when the package is
+// documented for several platforms, in which case Platforms applies instead.
+func (typ *Type) BuildConstraint() string {
+	if typ.cfg.SymbolPlatforms != nil {
+		return ""
+	}
+
+	return typ.cfg.FileConstraints[typ.Location().Filepath]
+}
+
+// Platforms provides the platforms on which the type is declared when the
+// package is documented for several platforms, or nil if it is declared on all
+// of them.
+func (typ *Type) Platforms() []string {
+	return typ.cfg.SymbolPlatforms[typ.doc.Name]
+}
+
 // Summary provides the one-sentence summary of the type's documentation
 // comment.
 func (typ *Type) Summary() string {
@@ -55,9 +81,106 @@ func (typ *Type) Doc() *Doc {
 	return NewDoc(typ.cfg.Inc(1), typ.doc.Doc)
 }
 
+// TypeParams lists the type parameters of the type, or nil if the type is not
+// generic.
+func (typ *Type) TypeParams() []*TypeParam {
+	return newTypeParams(typ.cfg, typ.typeParamList())
+}
+
+// TypeParamsDecl provides the raw text representation of the type's type
+// parameter list (e.g. [K comparable, V any]), or the empty string if the type
+// is not generic.
+func (typ *Type) TypeParamsDecl() (string, error) {
+	return typeParamsDecl(typ.typeParamList())
+}
+
+func (typ *Type) typeParamList() *ast.FieldList {
+	ts, ok := typ.typeSpec()
+	if !ok {
+		return nil
+	}
+
+	return ts.TypeParams
+}
+
+// IsAlias indicates whether the type is an alias for another type (e.g. type
+// Foo = Bar) rather than a defined type.
+func (typ *Type) IsAlias() bool {
+	ts, ok := typ.typeSpec()
+	return ok && ts.Assign.IsValid()
+}
+
+// AliasTarget provides the type that the type is an alias for as it is written
+// in the declaration, or the empty string if the type isn't an alias.
+func (typ *Type) AliasTarget() (string, error) {
+	ts, ok := typ.typeSpec()
+	if !ok || !ts.Assign.IsValid() {
+		return "", nil
+	}
+
+	return printNode(ts.Type, typ.cfg.FileSet)
+}
+
+// AliasLink provides a link to the documentation of the type that the type is
+// an alias for, or nil if the type isn't an alias or its target isn't
+// declared in the package or one of its imports.
+func (typ *Type) AliasLink() *DocLink {
+	ts, ok := typ.typeSpec()
+	if !ok || !ts.Assign.IsValid() {
+		return nil
+	}
+
+	return typeLink(typ.cfg, ts.Type)
+}
+
+// Implements provides links to the interfaces that the type implements, either
+// directly or through a pointer to the type. It is only populated if the
+// package was created using PackageWithImplements.
+func (typ *Type) Implements() []*DocLink {
+	return typ.cfg.Implements[typ.doc.Name]
+}
+
+// Source provides the full source code of the type's declaration, including
+// any unexported fields that are omitted from the documentation.
+func (typ *Type) Source() (string, error) {
+	ts, ok := typ.typeSpec()
+	if !ok {
+		return "", nil
+	}
+
+	return declSource(typ.cfg, ts)
+}
+
+// ExternalLinks provides links to the documentation of the types from other
+// packages that appear in the type's declaration, in the order in which they
+// first appear. It is only populated if the package was created using
+// PackageWithExternalLinks.
+func (typ *Type) ExternalLinks() []*DocLink {
+	ts, ok := typ.typeSpec()
+	if !ok {
+		return nil
+	}
+
+	return externalLinks(typ.cfg, ts)
+}
+
 // Decl provides the raw text representation of the code for the type's
-// declaration.
+// declaration. Struct and interface types written on a single line, such as
+// the types of anonymous struct fields, are expanded with one field or method
+// per line.
 func (typ *Type) Decl() (string, error) {
+	decl, err := printNode(typ.doc.Decl, typ.cfg.FileSet)
+	if err != nil {
+		return "", err
+	}
+
+	return expandAnonymousTypes(decl), nil
+}
+
+// CompactDecl provides the raw text representation of the code for the type's
+// declaration as it's written, without expanding the struct and interface
+// types written on a single line.
+func (typ *Type) CompactDecl() (string, error) {
 	return printNode(typ.doc.Decl, typ.cfg.FileSet)
 }
 
@@ -70,7 +193,9 @@ func (typ *Type) Examples() (examples []*Example) {
 		switch {
 		case example.Name == typ.doc.Name:
 			name = ""
-		case strings.HasPrefix(example.Name, underscorePrefix) && !typ.isSubexample(example.Name):
+		case strings.HasPrefix(example.Name, underscorePrefix) &&
+			isExampleSuffix(example.Name[len(underscorePrefix):]) &&
+			!typ.isSubexample(example.Name):
 			name = example.Name[len(underscorePrefix):]
 		default:
 			// TODO: better filtering
@@ -107,15 +232,77 @@ func (typ *Type) Funcs() []*Func {
 }
 
 // Methods lists the funcs that use the type as a value or pointer receiver.
+// Methods promoted from embedded types are listed by PromotedMethods instead.
 func (typ *Type) Methods() []*Func {
-	methods := make([]*Func, len(typ.doc.Methods))
-	for i, fn := range typ.doc.Methods {
-		methods[i] = NewFunc(typ.cfg.Inc(1), fn, typ.examples)
+	var methods []*Func
+	for _, fn := range typ.doc.Methods {
+		if fn.Level == 0 {
+			methods = append(methods, NewFunc(typ.cfg.Inc(1), fn, typ.examples))
+		}
+	}
+
+	return methods
+}
+
+// PromotedMethods lists the methods of types embedded within the type which
+// are promoted to the type itself. Promoted methods are only detected for
+// embedded types declared within the same package.
+func (typ *Type) PromotedMethods() []*Func {
+	var methods []*Func
+	for _, fn := range typ.doc.Methods {
+		if fn.Level > 0 {
+			methods = append(methods, NewFunc(typ.cfg.Inc(1), fn, typ.examples))
+		}
 	}
 
 	return methods
 }
 
+// Unions lists the union and approximation elements of the type if it is a
+// constraint interface, such as ~int | ~int64. Embedded constraints are listed
+// by Embedded instead.
+func (typ *Type) Unions() []*Union {
+	ts, ok := typ.typeSpec()
+	if !ok {
+		return nil
+	}
+
+	return newUnions(typ.cfg, ts.Type)
+}
+
+// Embedded lists the types embedded within the type if it is a struct or
+// interface type.
+func (typ *Type) Embedded() []*Embedded {
+	ts, ok := typ.typeSpec()
+	if !ok {
+		return nil
+	}
+
+	return newEmbedded(typ.cfg, ts.Type)
+}
+
+// Fields lists the fields of the type if it is a struct type.
+func (typ *Type) Fields() []*Field {
+	ts, ok := typ.typeSpec()
+	if !ok {
+		return nil
+	}
+
+	return newFields(typ.cfg, ts.Type)
+}
+
+// typeSpec finds the spec declaring the type within its declaration.
+func (typ *Type) typeSpec() (*ast.TypeSpec, bool) {
+	for _, spec := range typ.doc.Decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if ok && ts.Name.Name == typ.doc.Name {
+			return ts, true
+		}
+	}
+
+	return nil, false
+}
+
 // Consts lists the const declaration blocks containing values of this type.
 func (typ *Type) Consts() []*Value {
 	consts := make([]*Value, len(typ.doc.Consts))
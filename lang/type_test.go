@@ -2,6 +2,7 @@ package lang_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/ag5denis/gomarkdoc/lang"
@@ -22,6 +23,67 @@ func TestType_Examples(t *testing.T) {
 	is.Equal(ex[1].Name(), "Sub Test")
 }
 
+func TestType_Decl_anonymous(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Settings")
+	is.NoErr(err)
+
+	decl, err := typ.Decl()
+	is.NoErr(err)
+	is.Equal(decl, `type Settings struct {
+    Limits struct {
+        Max, Min int
+    }
+    Hooks interface {
+        Run() error
+    }
+}`)
+}
+
+func TestType_CompactDecl(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Settings")
+	is.NoErr(err)
+
+	decl, err := typ.CompactDecl()
+	is.NoErr(err)
+	is.Equal(decl, `type Settings struct {
+    Limits struct{ Max, Min int }
+    Hooks  interface{ Run() error }
+}`)
+}
+
+func TestType_Decl_typeParams(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Pair")
+	is.NoErr(err)
+
+	decl, err := typ.Decl()
+	is.NoErr(err)
+	is.True(strings.HasPrefix(decl, "type Pair[K comparable, V any] struct {"))
+}
+
+func TestType_TypeParams(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Pair")
+	is.NoErr(err)
+
+	is.Equal(typ.Title(), "type Pair[K comparable, V any]")
+
+	params := typ.TypeParams()
+	is.Equal(len(params), 2)
+	is.Equal(params[0].Name(), "K")
+	is.Equal(params[1].Name(), "V")
+
+	constraint, err := params[1].Constraint()
+	is.NoErr(err)
+	is.Equal(constraint, "any")
+}
+
 func TestFunc_netHttpResponseWriter(t *testing.T) {
 	is := is.New(t)
 
@@ -235,14 +297,328 @@ func TestFunc_netHttpResponse(t *testing.T) {
 	is.True(len(typ.Methods()) > 0)
 }
 
-func loadType(dir, name string) (*lang.Type, error) {
+func TestType_Implements(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Square", lang.PackageWithImplements(true))
+	is.NoErr(err)
+
+	impls := typ.Implements()
+	is.Equal(len(impls), 2)
+
+	is.True(impls[0].IsLocal())
+	is.Equal(impls[0].Text(), "Shape")
+	is.Equal(impls[0].Symbol.HeaderText, "type Shape")
+
+	is.True(!impls[1].IsLocal())
+	is.Equal(impls[1].Text(), "fmt.Stringer")
+	is.Equal(impls[1].URL(), "https://pkg.go.dev/fmt#Stringer")
+}
+
+func TestType_Implements_disabled(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Square")
+	is.NoErr(err)
+
+	is.Equal(len(typ.Implements()), 0)
+}
+
+func TestType_Embedded(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Derived")
+	is.NoErr(err)
+
+	embedded := typ.Embedded()
+	is.Equal(len(embedded), 2)
+
+	name, err := embedded[0].Name()
+	is.NoErr(err)
+	is.Equal(name, "*Base")
+	is.True(embedded[0].Link().IsLocal())
+	is.Equal(embedded[0].Link().Symbol.HeaderText, "type Base")
+
+	name, err = embedded[1].Name()
+	is.NoErr(err)
+	is.Equal(name, "fmt.Stringer")
+	is.Equal(embedded[1].Link().URL(), "https://pkg.go.dev/fmt#Stringer")
+}
+
+func TestType_Unions(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Number")
+	is.NoErr(err)
+
+	unions := typ.Unions()
+	is.Equal(len(unions), 1)
+
+	terms := unions[0].Terms()
+	is.Equal(len(terms), 3)
+	for i, want := range []string{"int", "int64", "float64"} {
+		name, err := terms[i].Name()
+		is.NoErr(err)
+		is.Equal(name, want)
+		is.True(terms[i].Tilde())
+		is.True(terms[i].Link() == nil) // predeclared types aren't linked
+	}
+}
+
+func TestType_Unions_embedded(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Temperature")
+	is.NoErr(err)
+
+	unions := typ.Unions()
+	is.Equal(len(unions), 1)
+
+	terms := unions[0].Terms()
+	is.Equal(len(terms), 2)
+
+	name, err := terms[0].Name()
+	is.NoErr(err)
+	is.Equal(name, "Celsius")
+	is.True(!terms[0].Tilde())
+	is.True(terms[0].Link().IsLocal())
+	is.Equal(terms[0].Link().Symbol.HeaderText, "type Celsius")
+
+	name, err = terms[1].Name()
+	is.NoErr(err)
+	is.Equal(name, "float32")
+	is.True(terms[1].Tilde())
+
+	embedded := typ.Embedded()
+	is.Equal(len(embedded), 1)
+
+	name, err = embedded[0].Name()
+	is.NoErr(err)
+	is.Equal(name, "fmt.Stringer")
+}
+
+func TestType_Unions_none(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Derived")
+	is.NoErr(err)
+
+	is.Equal(len(typ.Unions()), 0)
+}
+
+func TestType_PromotedMethods(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Derived")
+	is.NoErr(err)
+
+	is.Equal(len(typ.Methods()), 0)
+
+	promoted := typ.PromotedMethods()
+	is.Equal(len(promoted), 1)
+
+	fn := promoted[0]
+	is.Equal(fn.Name(), "Describe")
+	is.True(fn.Promoted())
+	is.Equal(fn.Origin(), "*Base")
+	is.Equal(fn.OriginSymbol().Key(), "Base.Describe")
+}
+
+func TestType_Fields(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Options")
+	is.NoErr(err)
+
+	fields := typ.Fields()
+	is.Equal(len(fields), 4)
+
+	is.Equal(fields[0].Name(), "Addr")
+	is.Equal(fields[0].Tag("json"), "addr")
+	is.Equal(fields[0].Tag("env"), "ADDR")
+	is.Equal(fields[0].Tag("toml"), "")
+	is.Equal(fields[0].Description(), "Addr is the address to listen on.")
+
+	is.Equal(fields[1].Name(), "Timeout")
+	is.Equal(fields[1].Tag("json"), "timeout,omitempty")
+	is.Equal(fields[2].Name(), "Retries")
+	is.Equal(fields[2].Tag("json"), "retries,omitempty")
+	is.Equal(fields[2].Description(), "Retries limits the attempts for each request.")
+
+	typeText, err := fields[2].Type()
+	is.NoErr(err)
+	is.Equal(typeText, "int")
+
+	is.Equal(fields[3].Name(), "Base")
+	is.True(fields[3].Embedded())
+	is.True(!fields[0].Embedded())
+}
+
+func TestType_Consts_values(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Weekday", lang.PackageWithConstValues())
+	is.NoErr(err)
+
+	consts := typ.Consts()
+	is.Equal(len(consts), 1)
+
+	values := consts[0].ConstValues()
+	is.Equal(len(values), 3)
+	is.Equal(*values[0], lang.ConstValue{Name: "Sunday", Value: "0"})
+	is.Equal(*values[1], lang.ConstValue{Name: "Monday", Value: "1"})
+	is.Equal(*values[2], lang.ConstValue{Name: "Tuesday", Value: "2"})
+}
+
+func TestType_Consts_valuesDisabled(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Weekday")
+	is.NoErr(err)
+
+	is.Equal(len(typ.Consts()[0].ConstValues()), 0)
+}
+
+func TestType_Source(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Coord")
+	is.NoErr(err)
+
+	src, err := typ.Source()
+	is.NoErr(err)
+	is.Equal(src, "type Coord = Point")
+}
+
+func TestType_ExternalLinks(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Derived", lang.PackageWithExternalLinks())
+	is.NoErr(err)
+
+	links := typ.ExternalLinks()
+	is.Equal(len(links), 1)
+	is.Equal(links[0].Text(), "fmt.Stringer")
+	is.Equal(links[0].URL(), "https://pkg.go.dev/fmt#Stringer")
+}
+
+func TestType_ExternalLinks_disabled(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Derived")
+	is.NoErr(err)
+
+	is.Equal(len(typ.ExternalLinks()), 0)
+}
+
+func TestType_Alias(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Coord")
+	is.NoErr(err)
+
+	is.True(typ.IsAlias())
+
+	target, err := typ.AliasTarget()
+	is.NoErr(err)
+	is.Equal(target, "Point")
+
+	link := typ.AliasLink()
+	is.True(link.IsLocal())
+	is.Equal(link.Symbol.HeaderText, "type Point")
+
+	// The aliased type's methods are only listed under the aliased type
+	is.Equal(len(typ.Methods()), 0)
+}
+
+func TestType_Alias_builtin(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Amount")
+	is.NoErr(err)
+
+	is.True(typ.IsAlias())
+	is.True(typ.AliasLink() == nil)
+
+	typ, err = loadType("../testData/lang/function", "Point")
+	is.NoErr(err)
+
+	is.True(!typ.IsAlias())
+	is.Equal(len(typ.Methods()), 1)
+}
+
+func TestType_Methods_order(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []lang.PackageOption
+		names []string
+	}{
+		{"default", nil, []string{"Add", "Value"}},
+		{"source", []lang.PackageOption{lang.PackageWithMethodOrder(lang.MethodOrderSource)}, []string{"Value", "Add"}},
+		{"followsOrder", []lang.PackageOption{lang.PackageWithOrder(lang.OrderSource)}, []string{"Value", "Add"}},
+		{"overridesOrder", []lang.PackageOption{
+			lang.PackageWithOrder(lang.OrderSource),
+			lang.PackageWithMethodOrder(lang.MethodOrderAlphabetical),
+		}, []string{"Add", "Value"}},
+		{"alphabeticalUnexported", []lang.PackageOption{
+			lang.PackageWithUnexportedIncluded(),
+			lang.PackageWithMethodOrder(lang.MethodOrderAlphabetical),
+		}, []string{"Add", "Value", "reset"}},
+		{"sourceUnexported", []lang.PackageOption{
+			lang.PackageWithUnexportedIncluded(),
+			lang.PackageWithMethodOrder(lang.MethodOrderSource),
+		}, []string{"reset", "Value", "Add"}},
+		{"exportedFirst", []lang.PackageOption{
+			lang.PackageWithUnexportedIncluded(),
+			lang.PackageWithMethodOrder(lang.MethodOrderExportedFirst),
+		}, []string{"Value", "Add", "reset"}},
+		{"groupReceivers", []lang.PackageOption{lang.PackageWithMethodsGroupedByReceiver()}, []string{"Value", "Add"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			is := is.New(t)
+
+			typ, err := loadType("../testData/lang/function", "Counter", test.opts...)
+			is.NoErr(err)
+
+			var names []string
+			for _, m := range typ.Methods() {
+				names = append(names, m.Name())
+			}
+
+			is.Equal(names, test.names)
+		})
+	}
+}
+
+func TestType_Methods_invalidOrder(t *testing.T) {
+	is := is.New(t)
+
+	_, err := loadType("../testData/lang/function", "Counter", lang.PackageWithMethodOrder("random"))
+	is.True(err != nil)
+}
+
+func TestFunc_PointerReceiver(t *testing.T) {
+	is := is.New(t)
+
+	typ, err := loadType("../testData/lang/function", "Counter")
+	is.NoErr(err)
+
+	methods := typ.Methods()
+	is.Equal(len(methods), 2)
+	is.True(methods[0].PointerReceiver())  // Add
+	is.True(!methods[1].PointerReceiver()) // Value
+}
+
+func loadType(dir, name string, opts ...lang.PackageOption) (*lang.Type, error) {
 	buildPkg, err := getBuildPackage(dir)
 	if err != nil {
 		return nil, err
 	}
 
 	log := logger.New(logger.ErrorLevel)
-	pkg, err := lang.NewPackageFromBuild(log, buildPkg)
+	pkg, err := lang.NewPackageFromBuild(log, buildPkg, opts...)
 	if err != nil {
 		return nil, err
 	}
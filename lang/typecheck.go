@@ -0,0 +1,61 @@
+package lang
+
+import (
+	"fmt"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// packagesImporter imports the packages that the type checked package depends
+// on from those loaded along with it. Other packages, such as the standard
+// library packages checked for implemented interfaces, are loaded with the go
+// tool as they're needed.
+type packagesImporter struct {
+	conf     *packages.Config
+	packages map[string]*types.Package
+}
+
+func newPackagesImporter(conf *packages.Config, root *packages.Package) *packagesImporter {
+	imp := &packagesImporter{
+		conf:     conf,
+		packages: make(map[string]*types.Package),
+	}
+
+	packages.Visit([]*packages.Package{root}, nil, func(p *packages.Package) {
+		if p.Types != nil {
+			imp.packages[p.PkgPath] = p.Types
+		}
+	})
+
+	return imp
+}
+
+// Import implements types.Importer.
+func (imp *packagesImporter) Import(path string) (*types.Package, error) {
+	if tpkg, ok := imp.packages[path]; ok {
+		return tpkg, nil
+	}
+
+	// Only the exported API of the package is needed, which is read from the
+	// export data compiled by the go tool
+	conf := *imp.conf
+	conf.Mode = packages.NeedName | packages.NeedTypes
+	conf.Tests = false
+
+	pkgs, err := packages.Load(&conf, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("gomarkdoc: unable to load package %s", path)
+	}
+
+	if len(pkgs[0].Errors) > 0 {
+		return nil, pkgs[0].Errors[0]
+	}
+
+	imp.packages[path] = pkgs[0].Types
+	return pkgs[0].Types, nil
+}
@@ -0,0 +1,96 @@
+package lang
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// TypeParam holds documentation information for a single type parameter of a
+// generic type or function.
+type TypeParam struct {
+	cfg        *Config
+	name       string
+	constraint ast.Expr
+}
+
+// NewTypeParam creates a new TypeParam from the name of the type parameter and
+// the expression for its constraint.
+func NewTypeParam(cfg *Config, name string, constraint ast.Expr) *TypeParam {
+	return &TypeParam{cfg, name, constraint}
+}
+
+// Name provides the name of the type parameter.
+func (tp *TypeParam) Name() string {
+	return tp.name
+}
+
+// Constraint provides the raw text representation of the type parameter's
+// constraint (e.g. any, comparable or ~int | ~float64).
+func (tp *TypeParam) Constraint() (string, error) {
+	return printNode(tp.constraint, token.NewFileSet())
+}
+
+// ConstraintSymbol provides the symbol for the constraint if the constraint
+// is a type declared within the same package, which allows the constraint to
+// be linked to its documentation. If the constraint is not declared within
+// the package, nil is returned.
+func (tp *TypeParam) ConstraintSymbol() *Symbol {
+	s, ok := lookupTypeSymbol(tp.cfg, tp.constraint)
+	if !ok {
+		return nil
+	}
+
+	return s
+}
+
+// Union splits the type parameter's constraint into its terms if it is
+// written as a union or approximation element (e.g. ~int | ~float64), which
+// allows each term to be linked to its documentation. If the constraint is
+// written any other way, nil is returned.
+func (tp *TypeParam) Union() *Union {
+	return newUnion(tp.cfg, tp.constraint)
+}
+
+// newTypeParams flattens the provided type parameter list into one TypeParam
+// per name.
+func newTypeParams(cfg *Config, list *ast.FieldList) []*TypeParam {
+	if list == nil {
+		return nil
+	}
+
+	var params []*TypeParam
+	for _, field := range list.List {
+		for _, name := range field.Names {
+			params = append(params, NewTypeParam(cfg, name.Name, field.Type))
+		}
+	}
+
+	return params
+}
+
+// typeParamsDecl prints a type parameter list in the form used by
+// declarations (e.g. [K comparable, V any]). The empty string is returned if
+// there are no type parameters.
+func typeParamsDecl(list *ast.FieldList) (string, error) {
+	if list == nil || len(list.List) == 0 {
+		return "", nil
+	}
+
+	var fields []string
+	for _, field := range list.List {
+		names := make([]string, len(field.Names))
+		for i, name := range field.Names {
+			names[i] = name.Name
+		}
+
+		constraint, err := printNode(field.Type, token.NewFileSet())
+		if err != nil {
+			return "", err
+		}
+
+		fields = append(fields, strings.Join(names, ", ")+" "+constraint)
+	}
+
+	return "[" + strings.Join(fields, ", ") + "]", nil
+}
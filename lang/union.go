@@ -0,0 +1,159 @@
+package lang
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+type (
+	// Union holds documentation information for a union element of a
+	// constraint interface, such as ~int | ~int64, or for a single
+	// approximation element such as ~string.
+	Union struct {
+		terms []*UnionTerm
+	}
+
+	// UnionTerm holds documentation information for one of the terms of a
+	// union element.
+	UnionTerm struct {
+		cfg   *Config
+		tilde bool
+		expr  ast.Expr
+	}
+)
+
+// NewUnionTerm creates a new UnionTerm from the type expression of the term,
+// without the tilde of an approximation term.
+func NewUnionTerm(cfg *Config, tilde bool, expr ast.Expr) *UnionTerm {
+	return &UnionTerm{cfg, tilde, expr}
+}
+
+// Terms lists the terms of the union in the order they are written.
+func (u *Union) Terms() []*UnionTerm {
+	return u.terms
+}
+
+// Tilde indicates whether the term is an approximation term, which matches all
+// types whose underlying type is the term's type.
+func (t *UnionTerm) Tilde() bool {
+	return t.tilde
+}
+
+// Name provides the type of the term as it is written in the declaration,
+// without the tilde of an approximation term.
+func (t *UnionTerm) Name() (string, error) {
+	return printNode(t.expr, t.cfg.FileSet)
+}
+
+// Link provides a link to the documentation of the term's type, or nil if the
+// type isn't declared in the package or one of its imports.
+func (t *UnionTerm) Link() *DocLink {
+	return typeLink(t.cfg, t.expr)
+}
+
+// newUnion splits the provided constraint element into its terms. It returns
+// nil if the element is neither a union nor an approximation element, as is
+// the case for embedded constraints and methods.
+func newUnion(cfg *Config, expr ast.Expr) *Union {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		if e.Op != token.OR {
+			return nil
+		}
+	case *ast.UnaryExpr:
+		if e.Op != token.TILDE {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	var terms []*UnionTerm
+	var split func(expr ast.Expr)
+	split = func(expr ast.Expr) {
+		switch e := expr.(type) {
+		case *ast.BinaryExpr:
+			if e.Op == token.OR {
+				split(e.X)
+				split(e.Y)
+				return
+			}
+		case *ast.UnaryExpr:
+			if e.Op == token.TILDE {
+				terms = append(terms, NewUnionTerm(cfg, true, e.X))
+				return
+			}
+		}
+
+		terms = append(terms, NewUnionTerm(cfg, false, expr))
+	}
+
+	split(expr)
+
+	return &Union{terms}
+}
+
+// newUnions lists the union elements of the provided interface type, which
+// make up its type set along with its embedded constraints. Other kinds of
+// types have no union elements.
+func newUnions(cfg *Config, typeExpr ast.Expr) []*Union {
+	iface, ok := typeExpr.(*ast.InterfaceType)
+	if !ok || iface.Methods == nil {
+		return nil
+	}
+
+	var unions []*Union
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+
+		if union := newUnion(cfg, field.Type); union != nil {
+			unions = append(unions, union)
+		}
+	}
+
+	return unions
+}
+
+// packageExports trims the package's AST to its exported declarations, like
+// ast.PackageExports, but keeps the union and approximation elements of
+// interfaces. Those don't have a name of their own and would otherwise be
+// filtered out, leaving constraints such as ~int | ~int64 empty.
+func packageExports(pkg *ast.Package) {
+	type interfaceState struct {
+		fields     []*ast.Field
+		incomplete bool
+	}
+
+	interfaces := make(map[*ast.InterfaceType]interfaceState)
+	ast.Inspect(pkg, func(n ast.Node) bool {
+		if iface, ok := n.(*ast.InterfaceType); ok && iface.Methods != nil {
+			interfaces[iface] = interfaceState{
+				fields:     append([]*ast.Field(nil), iface.Methods.List...),
+				incomplete: iface.Incomplete,
+			}
+		}
+
+		return true
+	})
+
+	ast.PackageExports(pkg)
+
+	for iface, state := range interfaces {
+		kept := make(map[*ast.Field]bool)
+		for _, field := range iface.Methods.List {
+			kept[field] = true
+		}
+
+		var list []*ast.Field
+		for _, field := range state.fields {
+			if kept[field] || (len(field.Names) == 0 && newUnion(nil, field.Type) != nil) {
+				list = append(list, field)
+			}
+		}
+
+		iface.Methods.List = list
+		iface.Incomplete = state.incomplete || len(list) < len(state.fields)
+	}
+}
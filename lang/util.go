@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 func printNode(node ast.Node, fs *token.FileSet) (string, error) {
@@ -23,6 +24,14 @@ func printNode(node ast.Node, fs *token.FileSet) (string, error) {
 	return out.String(), nil
 }
 
+// isExampleSuffix reports whether the provided text is a valid example suffix.
+// Following the conventions of the testing package, a suffix must begin with a
+// lowercase letter. This distinguishes ExampleT_suffix from ExampleT_Method.
+func isExampleSuffix(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsLower(r)
+}
+
 func runeIsUpper(r rune) bool {
 	return r >= 'A' && r <= 'Z'
 }
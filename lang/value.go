@@ -1,7 +1,9 @@
 package lang
 
 import (
+	"go/ast"
 	"go/doc"
+	"go/token"
 )
 
 // Value holds documentation for a var or const declaration within a package.
@@ -28,6 +30,25 @@ func (v *Value) Location() Location {
 	return NewLocation(v.cfg, v.doc.Decl)
 }
 
+// BuildConstraint provides the build constraint of the file declaring the
+// value, including the GOOS and GOARCH implied by the file's name, or the empty
+// string if the file has no constraint. It is always empty when the package is
+// documented for several platforms, in which case Platforms applies instead.
+func (v *Value) BuildConstraint() string {
+	if v.cfg.SymbolPlatforms != nil {
+		return ""
+	}
+
+	return v.cfg.FileConstraints[v.Location().Filepath]
+}
+
+// Platforms provides the platforms on which the value is declared when the
+// package is documented for several platforms, or nil if it is declared on all
+// of them.
+func (v *Value) Platforms() []string {
+	return v.cfg.SymbolPlatforms[v.doc.Names[0]]
+}
+
 // Summary provides the one-sentence summary of the value's documentation
 // comment.
 func (v *Value) Summary() string {
@@ -45,3 +66,67 @@ func (v *Value) Doc() *Doc {
 func (v *Value) Decl() (string, error) {
 	return printNode(v.doc.Decl, v.cfg.FileSet)
 }
+
+// ConstValues provides the resolved values of the constants in the
+// declaration whose values aren't evident from the declaration itself, such as
+// constants defined using iota or other expressions. It is only populated for
+// const declarations if the package was created using
+// PackageWithConstValues.
+func (v *Value) ConstValues() []*ConstValue {
+	if v.doc.Decl.Tok != token.CONST || v.cfg.ConstValues == nil {
+		return nil
+	}
+
+	var values []*ConstValue
+	for _, spec := range v.doc.Decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		for i, name := range vs.Names {
+			if i < len(vs.Values) && isLiteral(vs.Values[i]) {
+				continue
+			}
+
+			if val, ok := v.cfg.ConstValues[name.Name]; ok && name.Name != "_" {
+				values = append(values, &ConstValue{Name: name.Name, Value: val})
+			}
+		}
+	}
+
+	return values
+}
+
+// VarValues provides the initializers of the variables in the declaration
+// that are simple enough to be shown next to their names, such as literals and
+// sentinel errors like errors.New("not found"). It is only populated for var
+// declarations if the package was created using PackageWithConstValues.
+func (v *Value) VarValues() ([]*ConstValue, error) {
+	if v.doc.Decl.Tok != token.VAR || !v.cfg.VarValues {
+		return nil, nil
+	}
+
+	var values []*ConstValue
+	for _, spec := range v.doc.Decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Values) != len(vs.Names) {
+			continue
+		}
+
+		for i, name := range vs.Names {
+			if name.Name == "_" || !isSimpleInitializer(vs.Values[i]) {
+				continue
+			}
+
+			val, err := printNode(vs.Values[i], v.cfg.FileSet)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, &ConstValue{Name: name.Name, Value: val})
+		}
+	}
+
+	return values, nil
+}
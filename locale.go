@@ -0,0 +1,165 @@
+package gomarkdoc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// locales holds the built-in translations of the fixed text emitted by the
+// default templates, keyed by locale and then by the English text.
+var locales = map[string]map[string]string{
+	"de": {
+		"Code Generation":       "Codegenerierung",
+		"Commands":              "Befehle",
+		"Constants":             "Konstanten",
+		"Deprecated":            "Veraltet",
+		"Embedded Types":        "Eingebettete Typen",
+		"Example":               "Beispiel",
+		"Examples":              "Beispiele",
+		"External Test Package": "Externes Testpaket",
+		"External Types":        "Externe Typen",
+		"Fields":                "Felder",
+		"Flags":                 "Optionen",
+		"Fuzzing":               "Fuzzing",
+		"Implements":            "Implementiert",
+		"Index":                 "Index",
+		"Notes":                 "Hinweise",
+		"Output":                "Ausgabe",
+		"Packages":              "Pakete",
+		"Promoted Methods":      "Übernommene Methoden",
+		"Run on Go Playground":  "Im Go Playground ausführen",
+		"Source":                "Quelltext",
+		"Subpackages":           "Unterpakete",
+		"Type Parameters":       "Typparameter",
+		"Type Set":              "Typmenge",
+		"Values":                "Werte",
+		"Variables":             "Variablen",
+	},
+	"es": {
+		"Code Generation":       "Generación de código",
+		"Commands":              "Comandos",
+		"Constants":             "Constantes",
+		"Deprecated":            "Obsoleto",
+		"Embedded Types":        "Tipos incrustados",
+		"Example":               "Ejemplo",
+		"Examples":              "Ejemplos",
+		"External Test Package": "Paquete de pruebas externo",
+		"External Types":        "Tipos externos",
+		"Fields":                "Campos",
+		"Flags":                 "Opciones",
+		"Fuzzing":               "Fuzzing",
+		"Implements":            "Implementa",
+		"Index":                 "Índice",
+		"Notes":                 "Notas",
+		"Output":                "Salida",
+		"Packages":              "Paquetes",
+		"Promoted Methods":      "Métodos promovidos",
+		"Run on Go Playground":  "Ejecutar en Go Playground",
+		"Source":                "Código fuente",
+		"Subpackages":           "Subpaquetes",
+		"Type Parameters":       "Parámetros de tipo",
+		"Type Set":              "Conjunto de tipos",
+		"Values":                "Valores",
+		"Variables":             "Variables",
+	},
+	"fr": {
+		"Code Generation":       "Génération de code",
+		"Commands":              "Commandes",
+		"Constants":             "Constantes",
+		"Deprecated":            "Obsolète",
+		"Embedded Types":        "Types intégrés",
+		"Example":               "Exemple",
+		"Examples":              "Exemples",
+		"External Test Package": "Paquet de test externe",
+		"External Types":        "Types externes",
+		"Fields":                "Champs",
+		"Flags":                 "Options",
+		"Fuzzing":               "Fuzzing",
+		"Implements":            "Implémente",
+		"Index":                 "Index",
+		"Notes":                 "Notes",
+		"Output":                "Sortie",
+		"Packages":              "Paquets",
+		"Promoted Methods":      "Méthodes promues",
+		"Run on Go Playground":  "Exécuter sur le Go Playground",
+		"Source":                "Source",
+		"Subpackages":           "Sous-paquets",
+		"Type Parameters":       "Paramètres de type",
+		"Type Set":              "Ensemble de types",
+		"Values":                "Valeurs",
+		"Variables":             "Variables",
+	},
+}
+
+// Locales lists the locales with built-in translations that can be passed to
+// WithLocale, in sorted order.
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// WithLocale translates the headings and labels emitted by the default
+// templates, such as Index, Constants and Example, using the built-in
+// translations for the provided locale. See Locales for the locales that are
+// supported. Translations provided with WithTranslations take precedence over
+// the built-in ones.
+func WithLocale(locale string) RendererOption {
+	return func(renderer *Renderer) error {
+		translations, ok := locales[locale]
+		if !ok {
+			return fmt.Errorf(`gomarkdoc: unsupported locale "%s"`, locale)
+		}
+
+		renderer.locale = translations
+		return nil
+	}
+}
+
+// WithTranslations translates the headings and labels emitted by the default
+// templates using the provided map from the English text to its translation,
+// so documentation can be generated in languages without built-in
+// translations. Templates can translate text of their own in the same way with
+// the tr function:
+//
+//	{{ header 2 (tr "Constants") }}
+//
+// The English text is matched case-insensitively, and text without a
+// translation is left as is.
+func WithTranslations(translations map[string]string) RendererOption {
+	return func(renderer *Renderer) error {
+		if renderer.translations == nil {
+			renderer.translations = make(map[string]string)
+		}
+
+		for text, translation := range translations {
+			renderer.translations[text] = translation
+		}
+
+		return nil
+	}
+}
+
+// translate provides the translation of the text for the configured locale
+// and translations, or the text itself if it has none.
+func (out *Renderer) translate(text string) string {
+	for _, translations := range []map[string]string{out.translations, out.locale} {
+		if translation, ok := translations[text]; ok {
+			return translation
+		}
+
+		// Configuration keys aren't case-sensitive, so neither is the text
+		for k, translation := range translations {
+			if strings.EqualFold(k, text) {
+				return translation
+			}
+		}
+	}
+
+	return text
+}
@@ -1,6 +1,10 @@
 package logger
 
 import (
+	"io"
+	"os"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 )
@@ -21,12 +25,17 @@ type (
 	// Level defines valid logging levels for a Logger.
 	Level int
 
+	// Format defines the valid formats of the records emitted by a Logger.
+	Format string
+
 	// Option defines an option for configuring the logger.
 	Option func(opts *options)
 
 	// options defines options for configuring the logger
 	options struct {
 		fields map[string]interface{}
+		format Format
+		out    io.Writer
 	}
 )
 
@@ -38,6 +47,16 @@ const (
 	ErrorLevel
 )
 
+// Valid logging formats
+const (
+	// TextFormat emits human-readable records. This is the default.
+	TextFormat Format = "text"
+
+	// JSONFormat emits one JSON object per record, holding the level,
+	// message, time and fields of the record.
+	JSONFormat Format = "json"
+)
+
 // New initializes a new Logger.
 func New(level Level, opts ...Option) Logger {
 	var options options
@@ -46,16 +65,26 @@ func New(level Level, opts ...Option) Logger {
 	}
 
 	log := logrus.New()
-
-	formatter := &prefixed.TextFormatter{
-		DisableTimestamp: true,
+	log.Out = os.Stderr
+	if options.out != nil {
+		log.Out = options.out
 	}
-	formatter.SetColorScheme(&prefixed.ColorScheme{
-		DebugLevelStyle: "cyan",
-		PrefixStyle:     "black+h",
-	})
 
-	log.Formatter = formatter
+	if options.format == JSONFormat {
+		log.Formatter = &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		}
+	} else {
+		formatter := &prefixed.TextFormatter{
+			DisableTimestamp: true,
+		}
+		formatter.SetColorScheme(&prefixed.ColorScheme{
+			DebugLevelStyle: "cyan",
+			PrefixStyle:     "black+h",
+		})
+
+		log.Formatter = formatter
+	}
 
 	switch level {
 	case DebugLevel:
@@ -87,3 +116,19 @@ func WithField(key string, value interface{}) Option {
 		opts.fields[key] = value
 	}
 }
+
+// WithFormat sets the format of the records emitted by the logger. The default
+// is TextFormat.
+func WithFormat(format Format) Option {
+	return func(opts *options) {
+		opts.format = format
+	}
+}
+
+// WithOutput sets the writer that records are written to. The default is
+// stderr.
+func WithOutput(w io.Writer) Option {
+	return func(opts *options) {
+		opts.out = w
+	}
+}
@@ -38,7 +38,7 @@ func Doc() error {
 		`go run ./cmd/gomarkdoc .`,
 		`go run ./cmd/gomarkdoc --header "" ./lang/...`,
 		`go run ./cmd/gomarkdoc --header "" ./format/...`,
-		`go run ./cmd/gomarkdoc --header "" ./cmd/...`,
+		`go run ./cmd/gomarkdoc --header "" --include-main ./cmd/...`,
 	)
 }
 
@@ -47,7 +47,7 @@ func DocVerify() error {
 		`go run ./cmd/gomarkdoc -c .`,
 		`go run ./cmd/gomarkdoc -c --header "" ./lang/...`,
 		`go run ./cmd/gomarkdoc -c --header "" ./format/...`,
-		`go run ./cmd/gomarkdoc -c --header "" ./cmd/...`,
+		`go run ./cmd/gomarkdoc -c --header "" --include-main ./cmd/...`,
 	)
 }
 
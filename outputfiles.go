@@ -0,0 +1,57 @@
+package gomarkdoc
+
+import (
+	"path/filepath"
+)
+
+// WithOutputFiles provides the file that the documentation of each package is
+// written to, keyed by import path. This allows templates to link between the
+// documentation of different packages using the outputFile and
+// relativeOutputPath template functions, regardless of how the output files
+// are laid out.
+func WithOutputFiles(files map[string]string) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.outputFiles = files
+		return nil
+	}
+}
+
+// RelativePath computes the path of the file at to relative to the directory
+// containing the file at from. The result uses forward slashes so that it can
+// be used as a link href.
+func RelativePath(from, to string) (string, error) {
+	fromAbs, err := filepath.Abs(from)
+	if err != nil {
+		return "", err
+	}
+
+	toAbs, err := filepath.Abs(to)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(fromAbs), toAbs)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// relativeOutputPath computes the path of the output file of the package with
+// the import path to relative to the output file of the package with the
+// import path from. The empty string is returned if either output file is
+// unknown.
+func (out *Renderer) relativeOutputPath(from, to string) (string, error) {
+	fromFile, ok := out.outputFiles[from]
+	if !ok {
+		return "", nil
+	}
+
+	toFile, ok := out.outputFiles[to]
+	if !ok {
+		return "", nil
+	}
+
+	return RelativePath(fromFile, toFile)
+}
@@ -0,0 +1,88 @@
+package gomarkdoc
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// WithPackageTable adds a table to the documentation of each package at the
+// root of a Go Module listing the provided packages that are located within
+// it, along with their synopses. Packages with an output file provided by
+// WithOutputFiles are linked to their generated documentation. Tables are only
+// rendered for formats that implement format.TableFormat.
+func WithPackageTable(pkgs ...*lang.Package) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.tablePkgs = append(renderer.tablePkgs, pkgs...)
+		return nil
+	}
+}
+
+// renderPackageTable renders the table of the packages beneath the provided
+// module root package, or the empty string if the package isn't a module root,
+// has no packages beneath it or the format doesn't support tables.
+func (out *Renderer) renderPackageTable(root *lang.Package) (string, error) {
+	pkgs := out.packageTablePackages(root)
+	if len(pkgs) == 0 {
+		return "", nil
+	}
+
+	t, ok := out.format.(format.TableFormat)
+	if !ok {
+		return "", nil
+	}
+
+	prefix := root.ImportPath() + "/"
+
+	rows := make([][]string, len(pkgs))
+	for i, pkg := range pkgs {
+		name := t.Escape(strings.TrimPrefix(pkg.ImportPath(), prefix))
+
+		href, err := out.relativeOutputPath(root.ImportPath(), pkg.ImportPath())
+		if err != nil {
+			return "", err
+		}
+
+		if href != "" {
+			if name, err = t.Link(name, href); err != nil {
+				return "", err
+			}
+		}
+
+		rows[i] = []string{name, t.Escape(pkg.Summary())}
+	}
+
+	table, err := t.Table([]string{"Package", "Synopsis"}, rows)
+	if errors.Is(err, format.ErrUnsupported) {
+		return "", nil
+	}
+
+	return table, err
+}
+
+// packageTablePackages lists the packages in the table of the provided module
+// root package, sorted by import path. No packages are listed if the package
+// isn't a module root.
+func (out *Renderer) packageTablePackages(root *lang.Package) []*lang.Package {
+	if len(out.tablePkgs) == 0 || !root.IsModuleRoot() {
+		return nil
+	}
+
+	prefix := root.ImportPath() + "/"
+
+	var pkgs []*lang.Package
+	for _, pkg := range out.tablePkgs {
+		if strings.HasPrefix(pkg.ImportPath(), prefix) {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].ImportPath() < pkgs[j].ImportPath()
+	})
+
+	return pkgs
+}
@@ -0,0 +1,104 @@
+package gomarkdoc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	playgroundShareURL = "https://go.dev/_/share"
+	playgroundURL      = "https://go.dev/play/p/%s"
+
+	// playgroundSalt is prepended to code by the Go Playground when deriving
+	// the ID of a snippet.
+	playgroundSalt = "Go playground salt\n"
+)
+
+// PlaygroundShareFunc shares the provided source code with a Go Playground
+// and provides the URL at which the shared code can be run.
+type PlaygroundShareFunc func(code string) (string, error)
+
+// SharePlayground uploads the provided source code to the public Go
+// Playground and provides the URL at which it can be run. Shared code is
+// public, so this should only be used for the examples of public modules.
+func SharePlayground(code string) (string, error) {
+	res, err := http.Post(playgroundShareURL, "text/plain; charset=utf-8", strings.NewReader(code))
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: failed to share code with the Go Playground: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("gomarkdoc: failed to read Go Playground response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gomarkdoc: Go Playground responded with status %d: %s", res.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	return fmt.Sprintf(playgroundURL, strings.TrimSpace(string(b))), nil
+}
+
+// PlaygroundLink provides the URL at which the public Go Playground runs the
+// provided source code once it has been shared, without sharing it. The
+// playground derives the ID of a snippet from its content, so this can be used
+// to check documentation generated using SharePlayground without reaching the
+// network.
+func PlaygroundLink(code string) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(playgroundSalt))
+	h.Write([]byte(code))
+	id := base64.URLEncoding.EncodeToString(h.Sum(nil))
+
+	// The playground extends IDs ending with an underscore so that they are
+	// linkified correctly.
+	n := 11
+	for n <= len(id) && id[n-1] == '_' {
+		n++
+	}
+
+	return fmt.Sprintf(playgroundURL, id[:n]), nil
+}
+
+// WithPlaygroundLinks adds a link to run each standalone example on the Go
+// Playground. The provided function is used to share the example code, such
+// as SharePlayground, and must not be nil. Each unique example is only shared
+// once per renderer.
+func WithPlaygroundLinks(share PlaygroundShareFunc) RendererOption {
+	return func(renderer *Renderer) error {
+		if share == nil {
+			return errors.New("gomarkdoc: a function to share examples with the Go Playground is required")
+		}
+
+		var (
+			mux   sync.Mutex
+			cache = make(map[string]string)
+		)
+		renderer.playground = func(code string) (string, error) {
+			mux.Lock()
+			defer mux.Unlock()
+
+			if href, ok := cache[code]; ok {
+				return href, nil
+			}
+
+			href, err := share(code)
+			if err != nil {
+				return "", err
+			}
+
+			cache[code] = href
+			return href, nil
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package gomarkdoc_test
+
+import (
+	"errors"
+	"go/build"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ag5denis/gomarkdoc"
+	"github.com/ag5denis/gomarkdoc/lang"
+	"github.com/ag5denis/gomarkdoc/logger"
+	"github.com/matryer/is"
+)
+
+func TestWithPlaygroundLinks(t *testing.T) {
+	is := is.New(t)
+
+	pkg := loadPlayablePackage(t)
+
+	var shared []string
+	share := func(code string) (string, error) {
+		shared = append(shared, code)
+		return "https://play.example.com/p/1", nil
+	}
+
+	renderer, err := gomarkdoc.NewRenderer(gomarkdoc.WithPlaygroundLinks(share))
+	is.NoErr(err)
+
+	doc, err := renderer.Package(pkg)
+	is.NoErr(err)
+	is.True(strings.Contains(doc, "[Run on Go Playground](<https://play.example.com/p/1>)"))
+
+	// Each example is shared once, as a standalone program
+	is.Equal(len(shared), 1)
+	is.True(strings.Contains(shared[0], "package main"))
+	is.True(strings.Contains(shared[0], "lib.Hello()"))
+
+	_, err = renderer.Package(pkg)
+	is.NoErr(err)
+	is.Equal(len(shared), 1)
+}
+
+func TestWithPlaygroundLinks_shareError(t *testing.T) {
+	is := is.New(t)
+
+	renderer, err := gomarkdoc.NewRenderer(gomarkdoc.WithPlaygroundLinks(func(code string) (string, error) {
+		return "", errors.New("unavailable")
+	}))
+	is.NoErr(err)
+
+	_, err = renderer.Package(loadPlayablePackage(t))
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "unavailable"))
+}
+
+func TestWithPlaygroundLinks_noShareFunc(t *testing.T) {
+	is := is.New(t)
+
+	_, err := gomarkdoc.NewRenderer(gomarkdoc.WithPlaygroundLinks(nil))
+	is.Equal(err.Error(), "gomarkdoc: a function to share examples with the Go Playground is required")
+}
+
+func TestPlaygroundLink(t *testing.T) {
+	is := is.New(t)
+
+	href, err := gomarkdoc.PlaygroundLink("package main\n")
+	is.NoErr(err)
+	is.True(regexp.MustCompile(`^https://go\.dev/play/p/[A-Za-z0-9_-]{11,}$`).MatchString(href))
+
+	again, err := gomarkdoc.PlaygroundLink("package main\n")
+	is.NoErr(err)
+	is.Equal(again, href)
+
+	other, err := gomarkdoc.PlaygroundLink("package main\n\nfunc main() {}\n")
+	is.NoErr(err)
+	is.True(other != href)
+}
+
+func loadPlayablePackage(t *testing.T) *lang.Package {
+	t.Helper()
+	is := is.New(t)
+
+	dir := t.TempDir()
+	is.NoErr(os.WriteFile(filepath.Join(dir, "lib.go"), []byte("// Package lib is a library.\npackage lib\n\n// Hello says hello.\nfunc Hello() {}\n"), 0644))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "lib_test.go"), []byte("package lib_test\n\nimport \"example.com/lib\"\n\nfunc ExampleHello() {\n\tlib.Hello()\n}\n"), 0644))
+
+	buildPkg, err := build.ImportDir(dir, build.ImportComment)
+	is.NoErr(err)
+
+	pkg, err := lang.NewPackageFromBuild(logger.New(logger.ErrorLevel), buildPkg)
+	is.NoErr(err)
+
+	return pkg
+}
@@ -1,11 +1,13 @@
 package gomarkdoc
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"text/template"
 
 	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/format/formatcore"
 	"github.com/ag5denis/gomarkdoc/lang"
 )
 
@@ -13,15 +15,46 @@ type (
 	// Renderer provides capabilities for rendering various types of
 	// documentation with the configured format and templates.
 	Renderer struct {
-		templateOverrides map[string]string
-		tmpl              *template.Template
-		format            format.Format
+		templateOverrides  map[string]string
+		tmpl               *template.Template
+		format             format.Format
+		examplesFile       string
+		playground         PlaygroundShareFunc
+		normalize          bool
+		fieldTable         bool
+		includeSource      bool
+		referenceLinks     bool
+		outputFiles        map[string]string
+		linkedPkgs         map[string]*lang.Package
+		tablePkgs          []*lang.Package
+		subPkgs            []*lang.Package
+		degradationHandler DegradationHandler
+		revertedChanges    map[string]bool
+		singleFile         bool
+		anchors            *anchorTracker
+		sections           []*FileSection
+		hiddenSections     map[string]bool
+		headingOffset      int
+		templateVars       map[string]interface{}
+		locale             map[string]string
+		translations       map[string]string
+		includeRoot        string
+		includeHandler     IncludeHandler
+		generation         GenerationInfo
+		anchorStyle        string
+		anchorTmpl         *template.Template
+		templateDebug      bool
+		templateStack      []templateFrame
 	}
 
 	// RendererOption configures the renderer's behavior.
 	RendererOption func(renderer *Renderer) error
 )
 
+// ErrSymbolNotFound is returned by SymbolExamples when the package does not
+// contain the requested symbol.
+var ErrSymbolNotFound = errors.New("gomarkdoc: symbol not found")
+
 //go:generate ./gentmpl.sh templates templates
 
 // NewRenderer initializes a Renderer configured using the provided options. If
@@ -39,13 +72,22 @@ func NewRenderer(opts ...RendererOption) (*Renderer, error) {
 		}
 	}
 
+	renderer.format = renderer.compatFormat(renderer.format)
+
 	for name, tmplStr := range templates {
 		// Use the override if present
 		if val, ok := renderer.templateOverrides[name]; ok {
 			tmplStr = val
 		}
 
+		if renderer.templateDebug {
+			tmplStr = debugTemplateText(name, tmplStr)
+		}
+
 		if renderer.tmpl == nil {
+			// Consult the format's fallback chain for unsupported primitives
+			f := format.Chain(renderer.format)
+
 			tmpl := template.New(name)
 			tmpl.Funcs(map[string]interface{}{
 				"add": func(n1, n2 int) int {
@@ -54,22 +96,77 @@ func NewRenderer(opts ...RendererOption) (*Renderer, error) {
 				"spacer": func() string {
 					return "\n\n"
 				},
+				"examplesFile": func() string {
+					return renderer.examplesFile
+				},
+				"playgroundHref": func(ex *lang.Example) (string, error) {
+					if renderer.playground == nil || !ex.Playable() {
+						return "", nil
+					}
 
-				"bold":                renderer.format.Bold,
-				"header":              renderer.format.Header,
-				"rawHeader":           renderer.format.RawHeader,
-				"codeBlock":           renderer.format.CodeBlock,
-				"link":                renderer.format.Link,
-				"listEntry":           renderer.format.ListEntry,
-				"accordion":           renderer.format.Accordion,
-				"accordionHeader":     renderer.format.AccordionHeader,
-				"accordionTerminator": renderer.format.AccordionTerminator,
-				"localHref":           renderer.format.LocalHref,
-				"codeHref":            renderer.format.CodeHref,
-				"paragraph":           renderer.format.Paragraph,
-				"escape":              renderer.format.Escape,
+					code, err := ex.Code()
+					if err != nil {
+						return "", err
+					}
+
+					return renderer.playground(code)
+				},
+				"fieldTable": func(fields []*lang.Field) (string, error) {
+					return renderer.renderFieldTable(fields)
+				},
+				"packageTable": func(pkg *lang.Package) (string, error) {
+					return renderer.renderPackageTable(pkg)
+				},
+				"subpackages": renderer.subpackages,
+				"includeSource": func() bool {
+					return renderer.includeSource
+				},
+				"outputFile": func(importPath string) string {
+					return renderer.outputFiles[importPath]
+				},
+				"relativeOutputPath": renderer.relativeOutputPath,
+				"outputChange":       renderer.outputChange,
+				"funcLocation":       renderer.funcLocation,
+				"singleFile": func() bool {
+					return renderer.singleFile
+				},
+				"sections": func() []*FileSection {
+					return renderer.sections
+				},
+				"showSection": renderer.showSection,
+				"var":         renderer.templateVar,
+				"tr":          renderer.translate,
+				"include":     renderer.include,
+				"generation": func() GenerationInfo {
+					return renderer.generation
+				},
+				"toc": func() (string, error) {
+					if t, ok := renderer.format.(format.TOCFormat); ok {
+						return t.TOC()
+					}
+
+					return "", nil
+				},
+
+				"bold":                f.Bold,
+				"header":              renderer.trackHeader(f, f.Header),
+				"rawHeader":           renderer.trackHeader(f, f.RawHeader),
+				"codeBlock":           f.CodeBlock,
+				"link":                f.Link,
+				"listEntry":           f.ListEntry,
+				"accordion":           f.Accordion,
+				"accordionHeader":     f.AccordionHeader,
+				"accordionTerminator": f.AccordionTerminator,
+				"localHref":           renderer.trackedLocalHref(f),
+				"codeHref":            renderer.sectionCodeHref(f.CodeHref),
+				"paragraph":           f.Paragraph,
+				"escape":              f.Escape,
 			})
 
+			if renderer.templateDebug {
+				tmpl.Funcs(renderer.templateDebugFuncs())
+			}
+
 			if _, err := tmpl.Parse(tmplStr); err != nil {
 				return nil, err
 			}
@@ -106,11 +203,78 @@ func WithFormat(format format.Format) RendererOption {
 	}
 }
 
+// WithExamplesFile moves the examples out of the documentation rendered for
+// files, packages, types and funcs. Each construct with examples links to the
+// corresponding section of the provided file instead, which can be rendered
+// using Examples.
+func WithExamplesFile(href string) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.examplesFile = href
+		return nil
+	}
+}
+
+// WithWhitespaceNormalization cleans up the whitespace of rendered files so
+// that they satisfy common markdown linting rules. Trailing whitespace is
+// removed, consecutive blank lines are collapsed and each file ends with a
+// single newline. See formatcore.NormalizeWhitespace for details.
+func WithWhitespaceNormalization() RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.normalize = true
+		return nil
+	}
+}
+
+// WithReferenceLinks converts the links in rendered files into
+// reference-style links, with the link definitions collected at the bottom of
+// each file. This keeps headers, tables and signatures readable in the raw
+// markdown and limits the diff to the definitions when URLs change. See
+// formatcore.ReferenceLinks for details.
+func WithReferenceLinks() RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.referenceLinks = true
+		return nil
+	}
+}
+
+// WithSource includes the full source code of each type and func declaration
+// in a collapsible block beneath its documentation, for documentation that is
+// read without access to the repository.
+func WithSource() RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.includeSource = true
+		return nil
+	}
+}
+
+// WithHeadingOffset increases the level of every heading in the rendered
+// documentation by the provided offset, so that documentation starting with a
+// level 1 heading starts at level 2 with an offset of 1 and so on. This is
+// useful when embedding the documentation in a file that already has a title.
+// Headings are never nested deeper than level 6, so the offset must be
+// between 0 and 5.
+func WithHeadingOffset(offset int) RendererOption {
+	return func(renderer *Renderer) error {
+		if offset < 0 || offset > 5 {
+			return fmt.Errorf("gomarkdoc: invalid heading offset %d: must be between 0 and 5", offset)
+		}
+
+		renderer.headingOffset = offset
+		return nil
+	}
+}
+
 // File renders a file containing one or more packages to document to a string.
 // You can change the rendering of the file by overriding the "file" template
 // or one of the templates it references.
 func (out *Renderer) File(file *lang.File) (string, error) {
-	return out.writeTemplate("file", file)
+	out.reportDegradations(file)
+
+	if out.singleFile {
+		return out.combinedFile(file)
+	}
+
+	return out.writeDocument("file", file)
 }
 
 // Package renders a package's documentation to a string. You can change the
@@ -141,13 +305,87 @@ func (out *Renderer) Example(ex *lang.Example) (string, error) {
 	return out.writeTemplate("example", ex)
 }
 
+// Examples renders the examples for all of the packages in the file to a
+// string, grouped by the symbol they document. It is intended to be used in
+// conjunction with WithExamplesFile. You can change the rendering of the
+// examples by overriding the "examples" template or one of the templates it
+// references.
+func (out *Renderer) Examples(file *lang.File) (string, error) {
+	return out.writeDocument("examples", file)
+}
+
+// SymbolExamples renders just the code of each example for the symbol of the
+// provided name within the package, with each example's code as a code block
+// in the configured format. The symbol is named as it would be in a doc link,
+// such as "Func", "Type" or "Type.Method", and the empty string refers to the
+// package itself. This is useful for surfacing examples outside of the full
+// documentation, such as in search results. If the package has no such
+// symbol, an error wrapping ErrSymbolNotFound is returned.
+func (out *Renderer) SymbolExamples(pkg *lang.Package, symbol string) (string, error) {
+	examples, ok := pkg.SymbolExamples(symbol)
+	if !ok {
+		return "", fmt.Errorf("%w: %s in package %s", ErrSymbolNotFound, symbol, pkg.ImportPath())
+	}
+
+	f := format.Chain(out.format)
+
+	var result strings.Builder
+	for _, ex := range examples {
+		code, err := ex.Code()
+		if err != nil {
+			return "", err
+		}
+
+		block, err := f.CodeBlock("go", code)
+		if err != nil {
+			return "", err
+		}
+
+		result.WriteString(block)
+	}
+
+	return result.String(), nil
+}
+
+// writeDocument renders the template of the provided name in the same way as
+// writeTemplate, applying any post-processing configured for complete
+// documents.
+func (out *Renderer) writeDocument(name string, data interface{}) (string, error) {
+	text, err := out.writeTemplate(name, data)
+	if err != nil {
+		return "", err
+	}
+
+	if file, ok := data.(*lang.File); ok {
+		if text, err = out.crossPackageLinks(file, text); err != nil {
+			return "", err
+		}
+	}
+
+	if out.referenceLinks {
+		text = formatcore.ReferenceLinks(text)
+	}
+
+	if n, ok := out.format.(format.NormalizingFormat); ok {
+		text = n.Normalize(text)
+	}
+
+	if out.normalize {
+		text = formatcore.NormalizeWhitespace(text)
+	}
+
+	return text, nil
+}
+
 // writeTemplate renders the template of the provided name using the provided
 // data object to a string. It uses the set of templates provided to the
 // renderer as a template library.
 func (out *Renderer) writeTemplate(name string, data interface{}) (string, error) {
+	out.templateStack = nil
+
 	var result strings.Builder
 	if err := out.tmpl.ExecuteTemplate(&result, name, data); err != nil {
-		return "", err
+		return "", out.templateError(name, data, err)
 	}
 
 	return result.String(), nil
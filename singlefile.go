@@ -0,0 +1,166 @@
+package gomarkdoc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/format"
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+type (
+	// FileSection holds the rendered documentation of one of the packages in
+	// a file rendered with WithSingleFile. The sections are available to the
+	// "file" and "contents" templates through the sections template function.
+	FileSection struct {
+		// Package is the package documented by the section.
+		Package *lang.Package
+
+		// Href links to the header of the section, or is empty for formats
+		// that don't support anchors.
+		Href string
+
+		// Text is the rendered documentation of the package.
+		Text string
+	}
+
+	// anchorTracker keeps track of the anchors of the headers rendered into a
+	// combined file so that duplicate anchors can be told apart. Markdown
+	// renderers such as GitHub's give repeated headers the anchor of the first
+	// one with a "-1", "-2", etc. suffix in the order they appear.
+	anchorTracker struct {
+		counts  map[string]int
+		pending []string
+	}
+)
+
+// WithSingleFile renders each file as a single combined document, for when
+// the documentation of several packages is written to the same file. The file
+// begins with a table of contents linking to the section of each package, and
+// the anchors of the headers in each section are deduplicated against the
+// sections before it so that links within a section point to its own headers.
+// The table of contents can be customized by overriding the "contents"
+// template.
+func WithSingleFile() RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.singleFile = true
+		return nil
+	}
+}
+
+// combinedFile renders a file with the section of each package rendered ahead
+// of the file itself, so that the anchors of every section are known by the
+// time the table of contents is rendered.
+func (out *Renderer) combinedFile(file *lang.File) (string, error) {
+	out.anchors = &anchorTracker{counts: make(map[string]int)}
+	defer func() {
+		out.anchors = nil
+		out.sections = nil
+	}()
+
+	sections := make([]*FileSection, 0, len(file.Packages))
+	for _, pkg := range file.Packages {
+		text, err := out.writeTemplate("package", pkg)
+		if err != nil {
+			return "", fmt.Errorf("gomarkdoc: failed to render package %s: %w", pkg.ImportPath(), err)
+		}
+
+		sections = append(sections, &FileSection{
+			Package: pkg,
+			Href:    out.anchors.commit(),
+			Text:    text,
+		})
+	}
+
+	// The anchors of the sections are final, so links rendered by the file
+	// template itself are left as they are
+	out.anchors = nil
+	out.sections = sections
+
+	return out.writeDocument("file", file)
+}
+
+// trackHeader wraps a header function of the format to record the anchor of
+// each header while the sections of a combined file are rendered. It also
+// applies the heading offset configured with WithHeadingOffset and adds the
+// tags of the explicit anchor style.
+func (out *Renderer) trackHeader(f format.Format, header func(int, string) (string, error)) func(int, string) (string, error) {
+	return func(level int, text string) (string, error) {
+		if out.anchors == nil && out.anchorStyle != AnchorsExplicit {
+			return header(level+out.headingOffset, text)
+		}
+
+		href, err := out.localHref(f, text)
+		if err != nil {
+			return "", err
+		}
+
+		var anchor string
+		if out.anchorStyle == AnchorsExplicit && href != "" {
+			if out.anchors != nil {
+				anchor = explicitAnchor(out.anchors.href(href))
+			} else {
+				anchor = explicitAnchor(href)
+			}
+		}
+
+		if out.anchors != nil {
+			out.anchors.record(href)
+		}
+
+		h, err := header(level+out.headingOffset, text)
+		if err != nil {
+			return "", err
+		}
+
+		return anchor + h, nil
+	}
+}
+
+// trackedLocalHref wraps the LocalHref function of the format to point to the
+// headers of the section being rendered in a combined file, accounting for
+// headers with the same anchor in the sections before it.
+func (out *Renderer) trackedLocalHref(f format.Format) func(string) (string, error) {
+	return func(headerText string) (string, error) {
+		href, err := out.localHref(f, headerText)
+		if err != nil || out.anchors == nil {
+			return href, err
+		}
+
+		return out.anchors.href(href), nil
+	}
+}
+
+// href adjusts a local href for the headers of the sections committed so far.
+func (a *anchorTracker) href(href string) string {
+	anchor := strings.TrimPrefix(href, "#")
+	if anchor == href || a.counts[anchor] == 0 {
+		return href
+	}
+
+	return fmt.Sprintf("#%s-%d", anchor, a.counts[anchor])
+}
+
+// record adds the local href of a header to the section being rendered.
+func (a *anchorTracker) record(href string) {
+	if strings.HasPrefix(href, "#") {
+		a.pending = append(a.pending, strings.TrimPrefix(href, "#"))
+	}
+}
+
+// commit completes the section being rendered and returns the href of its
+// first header, which is the header of the section itself.
+func (a *anchorTracker) commit() string {
+	var first string
+	for i, anchor := range a.pending {
+		if i == 0 {
+			first = a.href("#" + anchor)
+		}
+
+		a.counts[anchor]++
+	}
+
+	a.pending = nil
+
+	return first
+}
@@ -0,0 +1,78 @@
+package gomarkdoc
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// Subpackage identifies an immediate child package of a documented package.
+// Name is the import path of the child relative to its parent, Href is the
+// relative path to its generated documentation (or empty if it has none) and
+// Synopsis is the summary of its documentation.
+type Subpackage struct {
+	Name     string
+	Href     string
+	Synopsis string
+}
+
+// WithSubpackages adds a Subpackages section to the documentation of each
+// package, listing the provided packages that are its immediate children,
+// similar to the directory listing of pkg.go.dev. A package is an immediate
+// child if it is located beneath the parent and no other provided package lies
+// between them. Packages with an output file provided by WithOutputFiles are
+// linked to their generated documentation.
+func WithSubpackages(pkgs ...*lang.Package) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.subPkgs = append(renderer.subPkgs, pkgs...)
+		return nil
+	}
+}
+
+// subpackages lists the immediate child packages of the provided package,
+// sorted by import path.
+func (out *Renderer) subpackages(parent *lang.Package) ([]*Subpackage, error) {
+	prefix := parent.ImportPath() + "/"
+
+	var children []*lang.Package
+	for _, pkg := range out.subPkgs {
+		if strings.HasPrefix(pkg.ImportPath(), prefix) && !out.hasSubpackageBetween(parent, pkg) {
+			children = append(children, pkg)
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].ImportPath() < children[j].ImportPath()
+	})
+
+	subpackages := make([]*Subpackage, len(children))
+	for i, pkg := range children {
+		href, err := out.relativeOutputPath(parent.ImportPath(), pkg.ImportPath())
+		if err != nil {
+			return nil, err
+		}
+
+		subpackages[i] = &Subpackage{
+			Name:     strings.TrimPrefix(pkg.ImportPath(), prefix),
+			Href:     href,
+			Synopsis: pkg.Summary(),
+		}
+	}
+
+	return subpackages, nil
+}
+
+// hasSubpackageBetween reports whether any of the subpackages lies between
+// the parent and the descendant package, making the descendant a grandchild
+// rather than an immediate child.
+func (out *Renderer) hasSubpackageBetween(parent, descendant *lang.Package) bool {
+	for _, pkg := range out.subPkgs {
+		if strings.HasPrefix(pkg.ImportPath(), parent.ImportPath()+"/") &&
+			strings.HasPrefix(descendant.ImportPath(), pkg.ImportPath()+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package gomarkdoc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+)
+
+// templateBaseComment matches the comment in an override template that records
+// the digest of the default template it was based on.
+const templateBaseComment = `{{-?\s*/\*\s*gomarkdoc:base\s+([0-9a-f]+)\s*\*/\s*-?}}`
+
+var (
+	templateBasePattern = regexp.MustCompile(templateBaseComment)
+	templateBaseLine    = regexp.MustCompile(`(?m)^[ \t]*` + templateBaseComment + `[ \t]*(?:\r?\n|$)|` + templateBaseComment)
+)
+
+// TemplateNames lists the names of the templates that can be overridden, in
+// sorted order.
+func TemplateNames() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// DefaultTemplate provides the default template with the provided name, as
+// shipped with this version of gomarkdoc.
+func DefaultTemplate(name string) (string, bool) {
+	tmpl, ok := templates[name]
+	return tmpl, ok
+}
+
+// TemplateDigest provides a short digest of the provided template text. An
+// override can record the digest of the default template it was copied from in
+// a comment of the form {{/* gomarkdoc:base <digest> */}}, which allows changes
+// to the default to be detected when gomarkdoc is upgraded.
+func TemplateDigest(tmpl string) string {
+	sum := sha256.Sum256([]byte(tmpl))
+	return hex.EncodeToString(sum[:6])
+}
+
+// TemplateBase finds the digest of the default template that the override
+// template was based on, if the override records one. See TemplateDigest.
+func TemplateBase(tmpl string) (string, bool) {
+	m := templateBasePattern.FindStringSubmatch(tmpl)
+	if m == nil {
+		return "", false
+	}
+
+	return m[1], true
+}
+
+// StripTemplateBase removes the comment recording the base of the override
+// template, along with the line it occupies if it has one to itself.
+func StripTemplateBase(tmpl string) string {
+	return templateBaseLine.ReplaceAllString(tmpl, "")
+}
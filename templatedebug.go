@@ -0,0 +1,160 @@
+package gomarkdoc
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"text/template"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+type (
+	// TemplateError is returned when executing a template fails while
+	// template debugging is enabled. It identifies the innermost template that
+	// was executing, the line of the template that failed and the data that
+	// was passed to it.
+	TemplateError struct {
+		// Template is the name of the template that failed.
+		Template string
+
+		// Line is the line of the template that failed, or 0 if it is
+		// unknown.
+		Line int
+
+		// Data describes the data that was passed to the template, such as
+		// *lang.Func "New" (client.go:42).
+		Data string
+
+		// Err is the underlying execution error.
+		Err error
+	}
+
+	// templateFrame records a template that is executing along with the data
+	// it was passed.
+	templateFrame struct {
+		name string
+		data interface{}
+	}
+)
+
+// execErrorRegex matches the location that text/template reports for an
+// execution error, capturing the name of the template and the line.
+var execErrorRegex = regexp.MustCompile(`template: ([^:\s]+):(\d+):`)
+
+// WithTemplateDebug annotates the rendered output with the template that
+// produced each part of it, wrapping the output of each template in
+// <!-- gomarkdoc:template NAME --> and <!-- /gomarkdoc:template NAME -->
+// comments. Template execution errors are reported as a *TemplateError
+// identifying the template, line and data involved. This is intended for
+// developing template overrides and should not be used for published
+// documentation.
+func WithTemplateDebug() RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.templateDebug = true
+		return nil
+	}
+}
+
+// Error implements the error interface.
+func (e *TemplateError) Error() string {
+	location := fmt.Sprintf(`template "%s"`, e.Template)
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s line %d", location, e.Line)
+	}
+
+	return fmt.Sprintf("gomarkdoc: failed to execute %s with data %s: %s", location, e.Data, e.Err)
+}
+
+// Unwrap provides the underlying execution error.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// debugTemplateText wraps the text of the template of the provided name so
+// that it reports when it starts and finishes executing. The opening action
+// is kept on the first line so that line numbers in errors still match the
+// template as written.
+func debugTemplateText(name, text string) string {
+	return fmt.Sprintf(`{{templateEnter "%s" .}}%s{{templateExit "%s"}}`, name, text, name)
+}
+
+// templateDebugFuncs provides the functions used by templates wrapped with
+// debugTemplateText, which track the templates that are executing and emit
+// the comments annotating the output.
+func (out *Renderer) templateDebugFuncs() template.FuncMap {
+	return template.FuncMap{
+		"templateEnter": func(name string, data interface{}) string {
+			out.templateStack = append(out.templateStack, templateFrame{name, data})
+			return fmt.Sprintf("<!-- gomarkdoc:template %s -->\n", name)
+		},
+		"templateExit": func(name string) string {
+			if n := len(out.templateStack); n > 0 {
+				out.templateStack = out.templateStack[:n-1]
+			}
+
+			return fmt.Sprintf("<!-- /gomarkdoc:template %s -->\n", name)
+		},
+	}
+}
+
+// templateError adds the details of the template that was executing to an
+// error returned while executing the template of the provided name. Errors
+// are returned unchanged unless template debugging is enabled.
+func (out *Renderer) templateError(name string, data interface{}, err error) error {
+	if !out.templateDebug {
+		return err
+	}
+
+	var execErr template.ExecError
+	if !errors.As(err, &execErr) {
+		return err
+	}
+
+	// The stack is left as it was when execution stopped, so the last frame
+	// is the innermost template that was executing
+	if n := len(out.templateStack); n > 0 {
+		name, data = out.templateStack[n-1].name, out.templateStack[n-1].data
+	}
+
+	tmplErr := &TemplateError{
+		Template: name,
+		Data:     describeTemplateData(data),
+		Err:      err,
+	}
+
+	if m := execErrorRegex.FindStringSubmatch(err.Error()); m != nil && m[1] == name {
+		tmplErr.Line, _ = strconv.Atoi(m[2])
+	}
+
+	return tmplErr
+}
+
+// describeTemplateData provides a short description of data passed to a
+// template: its type, followed by its name and location where available.
+func describeTemplateData(data interface{}) string {
+	if data == nil {
+		return "nil"
+	}
+
+	desc := fmt.Sprintf("%T", data)
+
+	if named, ok := data.(interface{ Name() string }); ok {
+		desc = fmt.Sprintf("%s %q", desc, named.Name())
+	}
+
+	if located, ok := data.(interface{ Location() lang.Location }); ok {
+		loc := located.Location()
+
+		file := loc.Filepath
+		if rel, err := filepath.Rel(loc.WorkDir, file); err == nil && loc.WorkDir != "" {
+			file = rel
+		}
+
+		desc = fmt.Sprintf("%s (%s:%d)", desc, filepath.ToSlash(file), loc.Start.Line)
+	}
+
+	return desc
+}
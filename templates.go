@@ -3,8 +3,34 @@
 package gomarkdoc
 
 var templates = map[string]string{
+	"contents": `{{- range . -}}
+	{{- if .Href -}}
+		{{- link (escape .Package.ImportPath) .Href | listEntry 0 -}}
+	{{- else -}}
+		{{- escape .Package.ImportPath | listEntry 0 -}}
+	{{- end -}}
+{{- end -}}
+
+{{- spacer -}}
+`,
 	"doc": `{{- range .Blocks -}}
-	{{- if eq .Kind "paragraph" -}}
+	{{- if .IsDeprecation -}}
+		{{- escape .DeprecationText | printf "%s %s" (bold "Deprecated:") -}}
+		{{- spacer -}}
+	{{- else if and (eq .Kind "paragraph") .HasLinks -}}
+		{{- range .Spans -}}
+			{{- if eq .Kind "link" -}}
+				{{- if .Link.IsLocal -}}
+					{{- localHref (tr .Link.Symbol.HeaderText) | link (escape .Text) -}}
+				{{- else -}}
+					{{- link (escape .Text) .Link.URL -}}
+				{{- end -}}
+			{{- else -}}
+				{{- escape .Text -}}
+			{{- end -}}
+		{{- end -}}
+		{{- spacer -}}
+	{{- else if eq .Kind "paragraph" -}}
 		{{- paragraph .Text -}}
 	{{- else if eq .Kind "code" -}}
 		{{- codeBlock "" .Text -}}
@@ -14,15 +40,28 @@ var templates = map[string]string{
 {{- end -}}
 
 `,
-	"example": `{{- accordionHeader .Title -}}
+	"example": `{{- $title := tr "Example" -}}
+{{- with .Name -}}
+	{{- $title = printf "%s (%s)" $title . -}}
+{{- end -}}
+
+{{- accordionHeader $title -}}
+
+{{- with .RequiredTags -}}
+	{{- escape . | printf "%s %s" (bold "Requires tags:") -}}{{- spacer -}}
+{{- end -}}
 
 {{- template "doc" .Doc -}}
 
 {{- codeBlock "go" .Code -}}
 
+{{- with playgroundHref . -}}
+	{{- link (tr "Run on Go Playground") . -}}{{- spacer -}}
+{{- end -}}
+
 {{- if .HasOutput -}}
 
-	{{- header 4 "Output" -}}
+	{{- header 4 (tr "Output") -}}
 
 	{{- codeBlock "" .Output -}}
     
@@ -31,30 +70,156 @@ var templates = map[string]string{
 {{- accordionTerminator -}}
 
 `,
-	"file": `<!-- Code generated by gomarkdoc. DO NOT EDIT -->
+	"examples": `{{ template "frontmatter" . }}<!-- Code generated by gomarkdoc. DO NOT EDIT -->
+
+{{- range .Packages -}}
+
+	{{- if eq .Name "main" -}}
+		{{- header 1 .Dirname -}}
+	{{- else -}}
+		{{- header 1 .Name -}}
+	{{- end -}}
+
+	{{- range .Examples -}}
+		{{- template "example" . -}}
+	{{- end -}}
+
+	{{- range .Funcs -}}
+		{{- if len .Examples -}}
+			{{- header 2 .Title -}}
+			{{- range .Examples -}}
+				{{- template "example" . -}}
+			{{- end -}}
+		{{- end -}}
+	{{- end -}}
+
+	{{- range .Types -}}
+		{{- if len .Examples -}}
+			{{- header 2 .Title -}}
+			{{- range .Examples -}}
+				{{- template "example" . -}}
+			{{- end -}}
+		{{- end -}}
+
+		{{- range .Funcs -}}
+			{{- if len .Examples -}}
+				{{- header 2 .Title -}}
+				{{- range .Examples -}}
+					{{- template "example" . -}}
+				{{- end -}}
+			{{- end -}}
+		{{- end -}}
+
+		{{- range .Methods -}}
+			{{- if len .Examples -}}
+				{{- header 2 .Title -}}
+				{{- range .Examples -}}
+					{{- template "example" . -}}
+				{{- end -}}
+			{{- end -}}
+		{{- end -}}
+	{{- end -}}
+
+{{- end -}}
+
+Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
+`,
+	"file": `{{ template "frontmatter" . }}<!-- Code generated by gomarkdoc. DO NOT EDIT -->
 
 {{.Header -}}
 
-{{- range .Packages -}}
-	{{- template "package" . -}}
+{{- toc -}}
+
+{{- if singleFile -}}
+	{{- template "contents" sections -}}
+
+	{{- range sections -}}
+		{{- .Text -}}
+	{{- end -}}
+{{- else -}}
+	{{- range .Packages -}}
+		{{- template "package" . -}}
+	{{- end -}}
 {{- end -}}
 
 {{- .Footer}}
 
 Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
+`,
+	"frontmatter": `{{- /*
+	Rendered at the very top of each file, before the generated code notice
+	and the header. It renders nothing unless it is overridden, for example to
+	add the front matter read by a static site generator.
+*/ -}}
 `,
 	"func": `{{- if .Receiver -}}
-	{{- codeHref .Location | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | rawHeader .Level -}}
+	{{- codeHref (funcLocation .) | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | rawHeader .Level -}}
 {{- else -}}
-	{{- codeHref .Location | link (escape .Name) | printf "func %s" | rawHeader .Level -}}
+	{{- codeHref (funcLocation .) | link (escape .Name) | printf "func %s" | rawHeader .Level -}}
+{{- end -}}
+
+{{- with and (outputChange "build-constraints") .BuildConstraint -}}
+	{{- escape . | printf "%s %s" (bold "Build constraints:") -}}{{- spacer -}}
+{{- end -}}
+
+{{- with .Platforms -}}
+	{{- bold "Platforms:" }} {{ range $i, $p := . }}{{ if $i }}, {{ end }}{{ escape $p }}{{ end -}}{{- spacer -}}
 {{- end -}}
 
-{{- codeBlock "go" .Signature -}}
+{{- if outputChange "expanded-types" -}}
+	{{- codeBlock "go" .SignatureBlock -}}
+{{- else -}}
+	{{- codeBlock "go" .Signature -}}
+{{- end -}}
 
 {{- template "doc" .Doc -}}
 
-{{- range .Examples -}}
-	{{- template "example" . -}}
+{{- if includeSource -}}
+	{{- accordionHeader (tr "Source") -}}
+	{{- codeBlock "go" .Source -}}
+	{{- accordionTerminator -}}
+{{- end -}}
+
+{{- template "typeparams" . -}}
+
+{{- if len .ExternalLinks -}}
+
+	{{- bold (tr "External Types") -}}{{- spacer -}}
+
+	{{- range .ExternalLinks -}}
+		{{- link (escape .Text) .URL | listEntry 0 -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- range .OpaqueTypes -}}
+
+	{{- escape .Name | printf "Methods of %s" | bold -}}{{- spacer -}}
+
+	{{- range .Methods -}}
+		{{- if outputChange "expanded-types" -}}
+			{{- codeBlock "go" .SignatureBlock -}}
+		{{- else -}}
+			{{- codeBlock "go" .Signature -}}
+		{{- end -}}
+		{{- with .Summary -}}
+			{{- paragraph . -}}
+		{{- end -}}
+	{{- end -}}
+
+{{- end -}}
+
+{{- if not (showSection "examples") -}}
+{{- else if examplesFile -}}
+	{{- if len .Examples -}}
+		{{- localHref .Title | printf "%s%s" examplesFile | link (tr "Examples") -}}{{- spacer -}}
+	{{- end -}}
+{{- else -}}
+	{{- range .Examples -}}
+		{{- template "example" . -}}
+	{{- end -}}
 {{- end -}}
 
 `,
@@ -63,43 +228,57 @@ Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
 `,
 	"index": `{{- if len .Consts -}}
 
-	{{- localHref "Constants" | link "Constants" | listEntry 0 -}}
+	{{- localHref (tr "Constants") | link (tr "Constants") | listEntry 0 -}}
 	
 {{- end -}}
 
 {{- if len .Vars -}}
 
-	{{- localHref "Variables" | link "Variables" | listEntry 0 -}}
+	{{- localHref (tr "Variables") | link (tr "Variables") | listEntry 0 -}}
 
 {{- end -}}
 
 {{- range .Funcs -}}
 
 	{{- if .Receiver -}}
-		{{- codeHref .Location | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | localHref | link .Signature | listEntry 0 -}}
+		{{- $entry := codeHref (funcLocation .) | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | localHref | link .Signature -}}
+		{{- if .Doc.Deprecated -}}{{- $entry = bold (tr "Deprecated") | printf "%s %s" $entry -}}{{- end -}}
+		{{- listEntry 0 $entry -}}
 	{{- else -}}
-		{{- codeHref .Location | link (escape .Name) | printf "func %s" | localHref | link .Signature | listEntry 0 -}}
+		{{- $entry := codeHref (funcLocation .) | link (escape .Name) | printf "func %s" | localHref | link .Signature -}}
+		{{- if .Doc.Deprecated -}}{{- $entry = bold (tr "Deprecated") | printf "%s %s" $entry -}}{{- end -}}
+		{{- listEntry 0 $entry -}}
 	{{- end -}}
 
 {{- end -}}
 
 {{- range .Types -}}
 
-	{{- codeHref .Location | link (escape .Name) | printf "type %s" | localHref | link .Title | listEntry 0 -}}
+	{{- $entry := codeHref .Location | link (escape .Name) | printf "type %s" | localHref | link .Title -}}
+	{{- if .Doc.Deprecated -}}{{- $entry = bold (tr "Deprecated") | printf "%s %s" $entry -}}{{- end -}}
+	{{- listEntry 0 $entry -}}
 
 	{{- range .Funcs -}}
 		{{- if .Receiver -}}
-			{{- codeHref .Location | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | localHref | link .Signature | listEntry 1 -}}
+			{{- $entry := codeHref (funcLocation .) | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | localHref | link .Signature -}}
+			{{- if .Doc.Deprecated -}}{{- $entry = bold (tr "Deprecated") | printf "%s %s" $entry -}}{{- end -}}
+			{{- listEntry 1 $entry -}}
 		{{- else -}}
-			{{- codeHref .Location | link (escape .Name) | printf "func %s" | localHref | link .Signature | listEntry 1 -}}
+			{{- $entry := codeHref (funcLocation .) | link (escape .Name) | printf "func %s" | localHref | link .Signature -}}
+			{{- if .Doc.Deprecated -}}{{- $entry = bold (tr "Deprecated") | printf "%s %s" $entry -}}{{- end -}}
+			{{- listEntry 1 $entry -}}
 		{{- end -}}
 	{{- end -}}
 
 	{{- range .Methods -}}
 		{{- if .Receiver -}}
-			{{- codeHref .Location | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | localHref | link .Signature | listEntry 1 -}}
+			{{- $entry := codeHref (funcLocation .) | link (escape .Name) | printf "func \\(%s\\) %s" (escape .Receiver) | localHref | link .Signature -}}
+			{{- if .Doc.Deprecated -}}{{- $entry = bold (tr "Deprecated") | printf "%s %s" $entry -}}{{- end -}}
+			{{- listEntry 1 $entry -}}
 		{{- else -}}
-			{{- codeHref .Location | link (escape .Name) | printf "func %s" | localHref | link .Signature | listEntry 1 -}}
+			{{- $entry := codeHref (funcLocation .) | link (escape .Name) | printf "func %s" | localHref | link .Signature -}}
+			{{- if .Doc.Deprecated -}}{{- $entry = bold (tr "Deprecated") | printf "%s %s" $entry -}}{{- end -}}
+			{{- listEntry 1 $entry -}}
 		{{- end -}}
 	{{- end -}}
 
@@ -113,21 +292,80 @@ Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
 	{{- header .Level .Name -}}
 {{- end -}}
 
-{{- template "import" . -}}
+{{- if and .IsInternal (outputChange "internal-annotation") -}}
+	{{- if .InternalRoot -}}
+		{{- escape .InternalRoot | printf "%s This package can only be imported by packages within %s." (bold "Internal:") -}}
+	{{- else -}}
+		{{- printf "%s This package can only be imported by packages within the same tree." (bold "Internal:") -}}
+	{{- end -}}
+	{{- spacer -}}
+{{- end -}}
+
+{{- if .IsCommand -}}
+	{{- printf "go install %s@latest" .ImportPath | codeBlock "sh" -}}
+{{- else if showSection "import" -}}
+	{{- template "import" . -}}
+{{- end -}}
 
 {{- template "doc" .Doc -}}
 
-{{- range .Examples -}}
-	{{- template "example" . -}}
+{{- if len .Subcommands -}}
+
+	{{- header (add .Level 1) (tr "Commands") -}}
+
+	{{- range .Subcommands -}}
+		{{- if .Short -}}
+			{{- listEntry 0 (printf "%s: %s" (escape .Use) (escape .Short)) -}}
+		{{- else -}}
+			{{- escape .Use | listEntry 0 -}}
+		{{- end -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
 {{- end -}}
 
-{{- header (add .Level 1) "Index" -}}
+{{- if len .CommandFlags -}}
+
+	{{- header (add .Level 1) (tr "Flags") -}}
+
+	{{- range .CommandFlags -}}
+		{{- $entry := printf "%s %s" (bold .Syntax) (escape .Type) -}}
+		{{- if .Default -}}
+			{{- $entry = printf "%s (default %s)" $entry (escape .Default) -}}
+		{{- end -}}
+		{{- if .Usage -}}
+			{{- $entry = printf "%s: %s" $entry (escape .Usage) -}}
+		{{- end -}}
+		{{- listEntry 0 $entry -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
 
-{{- template "index" . -}}
+{{- if not (showSection "examples") -}}
+{{- else if examplesFile -}}
+	{{- if len .Examples -}}
+		{{- link (tr "Examples") examplesFile -}}{{- spacer -}}
+	{{- end -}}
+{{- else -}}
+	{{- range .Examples -}}
+		{{- template "example" . -}}
+	{{- end -}}
+{{- end -}}
 
-{{- if len .Consts -}}
+{{- if and (not toc) (showSection "index") -}}
 
-	{{- header (add .Level 1) "Constants" -}}
+	{{- header (add .Level 1) (tr "Index") -}}
+
+	{{- template "index" . -}}
+
+{{- end -}}
+
+{{- if and (len .Consts) (showSection "constants") -}}
+
+	{{- header (add .Level 1) (tr "Constants") -}}
 
 	{{- range .Consts -}}
 		{{- template "value" . -}}
@@ -135,9 +373,9 @@ Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
 
 {{- end -}}
 
-{{- if len .Vars -}}
+{{- if and (len .Vars) (showSection "variables") -}}
 
-	{{- header (add .Level 1) "Variables" -}}
+	{{- header (add .Level 1) (tr "Variables") -}}
 
 	{{- range .Vars -}}
 		{{- template "value" . -}}
@@ -145,19 +383,265 @@ Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
 
 {{- end -}}
 
-{{- range .Funcs -}}
-	{{- template "func" . -}}
+{{- range .Declarations -}}
+	{{- with .Func -}}
+		{{- template "func" . -}}
+	{{- end -}}
+	{{- with .Type -}}
+		{{- template "type" . -}}
+	{{- end -}}
 {{- end -}}
 
-{{- range .Types -}}
-	{{- template "type" . -}}
+{{- range .Categories -}}
+
+	{{- header .Level .Name -}}
+
+	{{- range .Declarations -}}
+		{{- with .Func -}}
+			{{- template "func" . -}}
+		{{- end -}}
+		{{- with .Type -}}
+			{{- template "type" . -}}
+		{{- end -}}
+	{{- end -}}
+
+{{- end -}}
+
+{{- if len .FuzzTargets -}}
+
+	{{- header (add .Level 1) (tr "Fuzzing") -}}
+
+	{{- range .FuzzTargets -}}
+		{{- template "func" . -}}
+	{{- end -}}
+
+{{- end -}}
+
+{{- if len .GenerateDirectives -}}
+
+	{{- header (add .Level 1) (tr "Code Generation") -}}
+
+	{{- range .GenerateDirectives -}}
+		{{- $file := printf "%s:%d" .File .Line | escape -}}
+		{{- $link := codeHref .Location | link $file -}}
+		{{- listEntry 0 (printf "%s: %s" $link (escape .Command)) -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- with .ExternalTest -}}
+
+	{{- header .Level (tr "External Test Package") -}}
+
+	{{- escape .Name | printf "Symbols declared by the black-box tests in package %s." -}}
+	{{- spacer -}}
+
+	{{- range .Consts -}}
+		{{- template "value" . -}}
+	{{- end -}}
+
+	{{- range .Vars -}}
+		{{- template "value" . -}}
+	{{- end -}}
+
+	{{- range .Funcs -}}
+		{{- template "func" . -}}
+	{{- end -}}
+
+	{{- range .Types -}}
+		{{- template "type" . -}}
+	{{- end -}}
+
+{{- end -}}
+
+{{- $packages := packageTable . -}}
+{{- if $packages -}}
+
+	{{- header (add .Level 1) (tr "Packages") -}}
+
+	{{- $packages -}}
+
+{{- end -}}
+
+{{- $subpackages := subpackages . -}}
+{{- if $subpackages -}}
+
+	{{- header (add .Level 1) (tr "Subpackages") -}}
+
+	{{- range $subpackages -}}
+		{{- $name := escape .Name -}}
+		{{- if .Href -}}
+			{{- $name = link $name .Href -}}
+		{{- end -}}
+
+		{{- if .Synopsis -}}
+			{{- listEntry 0 (printf "%s: %s" $name (escape .Synopsis)) -}}
+		{{- else -}}
+			{{- listEntry 0 $name -}}
+		{{- end -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- if and (len .Notes) (outputChange "notes") (showSection "notes") -}}
+
+	{{- header (add .Level 1) (tr "Notes") -}}
+
+	{{- range .Notes -}}
+		{{- header .Level .Title -}}
+
+		{{- range .Notes -}}
+			{{- listEntry 0 (printf "%s (%s)" (escape .Text) (escape .UID)) -}}
+		{{- end -}}
+
+		{{- spacer -}}
+	{{- end -}}
+
 {{- end -}}
 `,
 	"type": `{{- codeHref .Location | link (escape .Name) | printf "type %s" | rawHeader .Level -}}
 
+{{- with and (outputChange "build-constraints") .BuildConstraint -}}
+	{{- escape . | printf "%s %s" (bold "Build constraints:") -}}{{- spacer -}}
+{{- end -}}
+
+{{- with .Platforms -}}
+	{{- bold "Platforms:" }} {{ range $i, $p := . }}{{ if $i }}, {{ end }}{{ escape $p }}{{ end -}}{{- spacer -}}
+{{- end -}}
+
 {{- template "doc" .Doc -}}
 
-{{- codeBlock "go" .Decl -}}
+{{- if outputChange "expanded-types" -}}
+	{{- codeBlock "go" .Decl -}}
+{{- else -}}
+	{{- codeBlock "go" .CompactDecl -}}
+{{- end -}}
+
+{{- if includeSource -}}
+	{{- accordionHeader (tr "Source") -}}
+	{{- codeBlock "go" .Source -}}
+	{{- accordionTerminator -}}
+{{- end -}}
+
+{{- if .IsAlias -}}
+
+	{{- $target := .AliasLink -}}
+	{{- if not $target -}}
+		{{- escape .AliasTarget | printf "Alias for %s" -}}
+	{{- else if $target.IsLocal -}}
+		{{- localHref $target.Symbol.HeaderText | link (escape .AliasTarget) | printf "Alias for %s" -}}
+	{{- else -}}
+		{{- link (escape .AliasTarget) $target.URL | printf "Alias for %s" -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- template "typeparams" . -}}
+
+{{- $fields := fieldTable .Fields -}}
+{{- if $fields -}}
+
+	{{- bold (tr "Fields") -}}{{- spacer -}}
+
+	{{- $fields -}}
+
+{{- end -}}
+
+{{- if and (len .Unions) (outputChange "constraint-unions") -}}
+
+	{{- bold (tr "Type Set") -}}{{- spacer -}}
+
+	{{- range .Unions -}}
+		{{- $entry := "" -}}
+		{{- range $i, $term := .Terms -}}
+			{{- $name := escape .Name -}}
+			{{- if not .Link -}}
+			{{- else if .Link.IsLocal -}}
+				{{- $name = localHref .Link.Symbol.HeaderText | link $name -}}
+			{{- else -}}
+				{{- $name = link $name .Link.URL -}}
+			{{- end -}}
+			{{- if .Tilde -}}{{- $name = printf "%s%s" (escape "~") $name -}}{{- end -}}
+			{{- if $i -}}
+				{{- $entry = printf "%s %s %s" $entry (escape "|") $name -}}
+			{{- else -}}
+				{{- $entry = $name -}}
+			{{- end -}}
+		{{- end -}}
+		{{- listEntry 0 $entry -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- if len .Embedded -}}
+
+	{{- bold (tr "Embedded Types") -}}{{- spacer -}}
+
+	{{- range .Embedded -}}
+		{{- if not .Link -}}
+			{{- escape .Name | listEntry 0 -}}
+		{{- else if .Link.IsLocal -}}
+			{{- localHref .Link.Symbol.HeaderText | link (escape .Name) | listEntry 0 -}}
+		{{- else -}}
+			{{- link (escape .Name) .Link.URL | listEntry 0 -}}
+		{{- end -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- if len .PromotedMethods -}}
+
+	{{- bold (tr "Promoted Methods") -}}{{- spacer -}}
+
+	{{- range .PromotedMethods -}}
+		{{- if .OriginSymbol -}}
+			{{- escape .Origin | printf "%s from %s" (localHref .OriginSymbol.HeaderText | link (escape .Name)) | listEntry 0 -}}
+		{{- else -}}
+			{{- escape .Origin | printf "%s from %s" (escape .Name) | listEntry 0 -}}
+		{{- end -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- if len .Implements -}}
+
+	{{- bold (tr "Implements") -}}{{- spacer -}}
+
+	{{- range .Implements -}}
+		{{- if .IsLocal -}}
+			{{- localHref .Symbol.HeaderText | link (escape .Text) | listEntry 0 -}}
+		{{- else -}}
+			{{- link (escape .Text) .URL | listEntry 0 -}}
+		{{- end -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+
+{{- if len .ExternalLinks -}}
+
+	{{- bold (tr "External Types") -}}{{- spacer -}}
+
+	{{- range .ExternalLinks -}}
+		{{- link (escape .Text) .URL | listEntry 0 -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
 
 {{- range .Consts -}}
 	{{- template "value" . -}}
@@ -167,8 +651,15 @@ Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
 	{{- template "value" . -}}
 {{- end -}}
 
-{{- range .Examples -}}
-	{{- template "example" . -}}
+{{- if not (showSection "examples") -}}
+{{- else if examplesFile -}}
+	{{- if len .Examples -}}
+		{{- localHref .Title | printf "%s%s" examplesFile | link (tr "Examples") -}}{{- spacer -}}
+	{{- end -}}
+{{- else -}}
+	{{- range .Examples -}}
+		{{- template "example" . -}}
+	{{- end -}}
 {{- end -}}
 
 {{- range .Funcs -}}
@@ -180,9 +671,63 @@ Generated by {{link "gomarkdoc" "https://github.com/princjef/gomarkdoc"}}
 {{- end -}}
 
 `,
-	"value": `{{- template "doc" .Doc -}}
+	"typeparams": `{{- if len .TypeParams -}}
+
+	{{- bold (tr "Type Parameters") -}}{{- spacer -}}
+
+	{{- range .TypeParams -}}
+		{{- if .ConstraintSymbol -}}
+			{{- codeHref .ConstraintSymbol.Location | link (escape .ConstraintSymbol.Name) | printf "type %s" | localHref | link (escape .Constraint) | printf "%s %s" (escape .Name) | listEntry 0 -}}
+		{{- else if and .Union (outputChange "constraint-unions") -}}
+			{{- $entry := escape .Name -}}
+			{{- range $i, $term := .Union.Terms -}}
+				{{- $name := escape .Name -}}
+				{{- if not .Link -}}
+				{{- else if .Link.IsLocal -}}
+					{{- $name = localHref .Link.Symbol.HeaderText | link $name -}}
+				{{- else -}}
+					{{- $name = link $name .Link.URL -}}
+				{{- end -}}
+				{{- if .Tilde -}}{{- $name = printf "%s%s" (escape "~") $name -}}{{- end -}}
+				{{- if $i -}}
+					{{- $entry = printf "%s %s %s" $entry (escape "|") $name -}}
+				{{- else -}}
+					{{- $entry = printf "%s %s" $entry $name -}}
+				{{- end -}}
+			{{- end -}}
+			{{- listEntry 0 $entry -}}
+		{{- else -}}
+			{{- printf "%s %s" (escape .Name) (escape .Constraint) | listEntry 0 -}}
+		{{- end -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
+`,
+	"value": `{{- with and (outputChange "build-constraints") .BuildConstraint -}}
+	{{- escape . | printf "%s %s" (bold "Build constraints:") -}}{{- spacer -}}
+{{- end -}}
+
+{{- with .Platforms -}}
+	{{- bold "Platforms:" }} {{ range $i, $p := . }}{{ if $i }}, {{ end }}{{ escape $p }}{{ end -}}{{- spacer -}}
+{{- end -}}
+
+{{- template "doc" .Doc -}}
 
 {{- codeBlock "go" .Decl -}}
 
+
+{{- with or .ConstValues .VarValues -}}
+
+	{{- bold (tr "Values") -}}{{- spacer -}}
+
+	{{- range . -}}
+		{{- printf "%s = %s" .Name .Value | escape | listEntry 0 -}}
+	{{- end -}}
+
+	{{- spacer -}}
+
+{{- end -}}
 `,
 }
@@ -0,0 +1,28 @@
+package gomarkdoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+// The templates are generated from the files in the templates directory with
+// gentmpl.sh, so they have to be generated again whenever the files change.
+func TestTemplates_generated(t *testing.T) {
+	is := is.New(t)
+
+	files, err := filepath.Glob(filepath.Join("templates", "*.gotxt"))
+	is.NoErr(err)
+	is.Equal(len(files), len(templates))
+
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		is.NoErr(err)
+
+		name := strings.TrimSuffix(filepath.Base(file), ".gotxt")
+		is.Equal(templates[name], string(b)) // run GOPACKAGE=gomarkdoc bash gentmpl.sh templates templates
+	}
+}
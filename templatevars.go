@@ -0,0 +1,36 @@
+package gomarkdoc
+
+import "strings"
+
+// WithTemplateVars makes the provided variables available to every template
+// through the var function, so that a single set of templates can be shared
+// between projects that differ only in details such as a product name or a
+// support address:
+//
+//	{{ var "productName" }}
+//
+// Variable names are matched case-insensitively. Looking up a variable that
+// isn't set produces nil, so optional variables can be checked with if or
+// with.
+func WithTemplateVars(vars map[string]interface{}) RendererOption {
+	return func(renderer *Renderer) error {
+		renderer.templateVars = vars
+		return nil
+	}
+}
+
+// templateVar looks up the template variable with the provided name.
+// Configuration keys aren't case-sensitive, so neither are the names.
+func (out *Renderer) templateVar(name string) interface{} {
+	if v, ok := out.templateVars[name]; ok {
+		return v
+	}
+
+	for k, v := range out.templateVars {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+// Package docfx exercises the kinds of items emitted for DocFX metadata.
+package docfx
+
+import "strings"
+
+// DefaultName is the name used when none is provided.
+const DefaultName = "docfx"
+
+// Separators used when joining names.
+var (
+	Comma = ","
+	Space = " "
+)
+
+// Namer provides names.
+type Namer interface {
+	// Name provides the name.
+	Name() string
+}
+
+// Greeter greets people by name.
+type Greeter struct {
+	Greeting string
+}
+
+// NewGreeter creates a Greeter with the provided greeting.
+func NewGreeter(greeting string) *Greeter {
+	return &Greeter{greeting}
+}
+
+// Greet greets the namer.
+func (g *Greeter) Greet(n Namer) string {
+	return g.Greeting + Space + n.Name()
+}
+
+// Join joins the names with commas.
+func Join(names ...string) string {
+	return strings.Join(names, Comma)
+}
@@ -0,0 +1,27 @@
+// Package doclinks exercises doc links to imported packages.
+package doclinks
+
+import (
+	"github.com/go-git/go-git/v5"
+	md "github.com/russross/blackfriday/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// Open opens the repository at the path using [git.PlainOpen].
+func Open(path string) (*git.Repository, error) {
+	return git.PlainOpen(path)
+}
+
+// Render renders the markdown input using [md.Run], which is also available
+// as [github.com/russross/blackfriday/v2.Run].
+func Render(input []byte) []byte {
+	return md.Run(input)
+}
+
+// Decode decodes the settings using [yaml.Unmarshal] or
+// [gopkg.in/yaml.v2.Unmarshal]. Settings may also be decoded using
+// [encoding/json.Unmarshal], but not with [blackfriday.Run], [v2.Run],
+// [github.com/spf13/cobra.Command] or [example.com/missing.Thing].
+func Decode(b []byte, v interface{}) error {
+	return yaml.Unmarshal(b, v)
+}
@@ -0,0 +1,17 @@
+package function
+
+// Point is a location in two dimensions.
+type Point struct {
+	X, Y int
+}
+
+// Shift moves the point by the provided offsets.
+func (p Point) Shift(dx, dy int) Point {
+	return Point{p.X + dx, p.Y + dy}
+}
+
+// Coord is an alias for Point that is retained for compatibility.
+type Coord = Point
+
+// Amount is an alias for a builtin type.
+type Amount = float64
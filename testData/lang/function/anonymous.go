@@ -0,0 +1,12 @@
+package function
+
+// Settings groups settings in anonymous structs.
+type Settings struct {
+	Limits struct{ Max, Min int }
+	Hooks  interface{ Run() error }
+}
+
+// Configure accepts its settings as an anonymous struct.
+func Configure(opts struct{ Name string }) interface{ Close() error } {
+	return nil
+}
@@ -0,0 +1,20 @@
+package function
+
+// Dial opens a connection to the address.
+//
+//gomarkdoc:category Networking
+func Dial(addr string) error {
+	return nil
+}
+
+// Listener accepts connections.
+//
+//gomarkdoc:category Networking
+type Listener struct{}
+
+// Checksum computes a checksum of the data.
+//
+//gomarkdoc:category Encoding
+func Checksum(data []byte) uint32 {
+	return 0
+}
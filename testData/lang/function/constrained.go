@@ -0,0 +1,6 @@
+//go:build !purego
+
+package function
+
+// Accelerated is only available when the purego build tag isn't set.
+func Accelerated() {}
@@ -0,0 +1,10 @@
+package function
+
+// Widget is constructed by a factory function that follows the Make
+// convention.
+type Widget struct{}
+
+// MakeWidget creates a Widget, returning it without its concrete type.
+func MakeWidget() any {
+	return Widget{}
+}
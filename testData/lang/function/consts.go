@@ -0,0 +1,12 @@
+package function
+
+// Weekday identifies a day of the week.
+type Weekday int
+
+// The days of the week.
+const (
+	Sunday Weekday = iota
+	Monday
+	Tuesday
+	Week Weekday = 7
+)
@@ -0,0 +1,13 @@
+package function
+
+// Total adds up all of the values in the slice.
+//
+// Deprecated: Use Sum instead, which supports any numeric type.
+func Total(values []int) int {
+	var total int
+	for _, v := range values {
+		total += v
+	}
+
+	return total
+}
@@ -0,0 +1,19 @@
+package function
+
+import "fmt"
+
+// Base provides behavior that is shared by embedding it in other types.
+type Base struct{}
+
+// Describe describes the value.
+func (b *Base) Describe() string {
+	return "base"
+}
+
+// Derived embeds Base, gaining its methods.
+type Derived struct {
+	*Base
+	fmt.Stringer
+
+	Name string
+}
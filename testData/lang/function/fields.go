@@ -0,0 +1,12 @@
+package function
+
+// Options holds the settings for a server.
+type Options struct {
+	// Addr is the address to listen on.
+	Addr string `json:"addr" yaml:"addr" env:"ADDR"`
+
+	Timeout int `json:"timeout,omitempty"` // Timeout limits the duration of each request.
+	Retries int `json:"retries,omitempty"` // Retries limits the attempts for each request.
+
+	Base
+}
@@ -2,6 +2,7 @@ package function_test
 
 import (
 	"fmt"
+	"testing"
 
 	"github.com/ag5denis/gomarkdoc/testData/lang/function"
 )
@@ -32,3 +33,21 @@ func ExampleGeneric_WithGenericReceiver() {
 	r := function.Generic[int]{}
 	r.WithGenericReceiver()
 }
+
+func Example_packageLevel() {
+	fmt.Println(function.Variable)
+	// Output: 5
+}
+
+// NewTestReceiver creates a receiver for use in tests.
+func NewTestReceiver() *function.Receiver {
+	return &function.Receiver{}
+}
+
+// FuzzStandalone checks that Standalone accepts arbitrary input.
+func FuzzStandalone(f *testing.F) {
+	f.Add(2, "abc")
+	f.Fuzz(func(t *testing.T, p1 int, p2 string) {
+		_, _ = function.Standalone(p1, p2)
+	})
+}
@@ -0,0 +1,3 @@
+package function
+
+//go:generate echo "regenerating fixtures"
@@ -0,0 +1,25 @@
+package function
+
+// Number is a constraint satisfied by the numeric types.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Sum adds up all of the values in the map.
+//
+// The values may be of any type satisfying [Number]. For arbitrary precision
+// arithmetic, see [math/big.Int] instead.
+func Sum[K comparable, V Number](m map[K]V) V {
+	var total V
+	for _, v := range m {
+		total += v
+	}
+
+	return total
+}
+
+// Pair holds two values of arbitrary types.
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
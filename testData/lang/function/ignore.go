@@ -0,0 +1,21 @@
+package function
+
+// Visible is documented, but one of its fields is hidden.
+type Visible struct {
+	Shown string
+
+	//gomarkdoc:ignore
+	Hidden string
+
+	Internal int //gomarkdoc:ignore
+}
+
+// Ignored is left out of the documentation.
+//
+//gomarkdoc:ignore
+type Ignored struct{}
+
+// IgnoredFunc is left out of the documentation.
+//
+//gomarkdoc:ignore
+func IgnoredFunc() {}
@@ -0,0 +1,23 @@
+package function
+
+import "fmt"
+
+// Shape is implemented by types with an area.
+type Shape interface {
+	Area() float64
+}
+
+// Square is a square with sides of the provided length.
+type Square struct {
+	Side float64
+}
+
+// Area provides the area of the square.
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+// String describes the square.
+func (s *Square) String() string {
+	return fmt.Sprintf("square with side %g", s.Side)
+}
@@ -0,0 +1,15 @@
+//go:build integration
+
+package function_test
+
+import (
+	"fmt"
+
+	"github.com/ag5denis/gomarkdoc/testData/lang/function"
+)
+
+func ExampleStandalone_integration() {
+	res, _ := function.Standalone(3, "ghi")
+	fmt.Println(res)
+	// Output: 3
+}
@@ -0,0 +1,21 @@
+package function
+
+// Counter has methods declared out of alphabetical order, with both value and
+// pointer receivers.
+type Counter struct {
+	n int
+}
+
+func (c *Counter) reset() {
+	c.n = 0
+}
+
+// Value provides the current count.
+func (c Counter) Value() int {
+	return c.n
+}
+
+// Add increases the count by n.
+func (c *Counter) Add(n int) {
+	c.n += n
+}
@@ -0,0 +1,6 @@
+package function
+
+// BUG(ag5denis): Standalone ignores its second
+// parameter.
+
+// TODO(ag5denis): Add a variant of Standalone that accepts a context.
@@ -0,0 +1,23 @@
+package function
+
+// Open opens a connection to the provided address.
+func Open(addr string) *conn {
+	return &conn{addr}
+}
+
+// conn is an open connection, which is only accessible through Open.
+type conn struct {
+	addr string
+}
+
+// Addr provides the address of the connection.
+func (c *conn) Addr() string {
+	return c.addr
+}
+
+// Close closes the connection.
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) reset() {}
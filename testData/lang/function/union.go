@@ -0,0 +1,26 @@
+package function
+
+import "fmt"
+
+// Celsius is a temperature in degrees Celsius.
+type Celsius float64
+
+// Temperature is a constraint satisfied by temperature types that can be
+// printed.
+type Temperature interface {
+	Celsius | ~float32
+	fmt.Stringer
+}
+
+// Clamp limits the value to the range from lo to hi.
+func Clamp[T ~int | Celsius](v, lo, hi T) T {
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
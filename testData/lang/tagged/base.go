@@ -0,0 +1,5 @@
+//go:build !large
+
+package tagged
+
+const base = 2
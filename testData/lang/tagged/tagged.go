@@ -0,0 +1,5 @@
+// Package tagged exercises type checking with build tags.
+package tagged
+
+// Size is the size of the buffer, which depends on the build tags.
+const Size = base * 2
@@ -0,0 +1,26 @@
+// Package values exercises the values shown alongside variable declarations.
+package values
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by the package.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrClosed   = errors.New("closed")
+	ErrTimeout  = fmt.Errorf("timed out after %s", time.Second)
+)
+
+// Defaults used when no options are provided.
+var (
+	DefaultRetries         = 3
+	DefaultName, Separator = "values", "/"
+	DefaultTimeout         = 5 * time.Second
+	Verbose                = false
+)
+
+// Names lists the supported names.
+var Names = []string{"a", "b"}
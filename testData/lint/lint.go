@@ -0,0 +1,29 @@
+package lint
+
+// Documented is documented correctly.
+func Documented() {}
+
+func Undocumented() {}
+
+// Returns the wrong prefix.
+func WrongPrefix() {}
+
+// A Thing may start with an article.
+type Thing struct{}
+
+// DocumentedMethod is documented correctly.
+func (t *Thing) DocumentedMethod() {}
+
+func (t *Thing) UndocumentedMethod() {}
+
+// Deprecated: use Documented instead.
+func Old() {}
+
+// Limits of the package.
+const (
+	Min = 1
+	Max = 10
+)
+
+// The default name.
+var Name = "lint"
@@ -0,0 +1,83 @@
+package gomarkdoc
+
+import (
+	"fmt"
+
+	"github.com/ag5denis/gomarkdoc/lang"
+)
+
+// Names of the sections of the documentation that can be hidden using
+// WithHiddenSections.
+const (
+	// SectionImport is the import statement at the top of each package.
+	SectionImport = "import"
+
+	// SectionIndex is the index of the symbols of each package.
+	SectionIndex = "index"
+
+	// SectionExamples is the examples of packages, types and funcs.
+	SectionExamples = "examples"
+
+	// SectionConstants is the Constants section of each package.
+	SectionConstants = "constants"
+
+	// SectionVariables is the Variables section of each package.
+	SectionVariables = "variables"
+
+	// SectionSourceLinks is the links from symbols to their source code.
+	SectionSourceLinks = "source-links"
+
+	// SectionNotes is the Notes section of each package.
+	SectionNotes = "notes"
+)
+
+var hideableSections = map[string]bool{
+	SectionImport:      true,
+	SectionIndex:       true,
+	SectionExamples:    true,
+	SectionConstants:   true,
+	SectionVariables:   true,
+	SectionSourceLinks: true,
+	SectionNotes:       true,
+}
+
+// WithHiddenSections leaves the sections of the provided names out of the
+// rendered documentation, which is useful for small adjustments that don't
+// warrant overriding whole templates. See the Section constants for the names
+// that are supported. Templates can check whether a section is shown with the
+// showSection function.
+func WithHiddenSections(sections ...string) RendererOption {
+	return func(renderer *Renderer) error {
+		for _, section := range sections {
+			if !hideableSections[section] {
+				return fmt.Errorf(`gomarkdoc: invalid section name "%s"`, section)
+			}
+
+			if renderer.hiddenSections == nil {
+				renderer.hiddenSections = make(map[string]bool)
+			}
+
+			renderer.hiddenSections[section] = true
+		}
+
+		return nil
+	}
+}
+
+// showSection reports whether the section of the provided name should be
+// rendered.
+func (out *Renderer) showSection(section string) bool {
+	return !out.hiddenSections[section]
+}
+
+// sectionCodeHref wraps the CodeHref function of a format so that it provides
+// no href when source links are hidden, leaving the link text on its own.
+func (out *Renderer) sectionCodeHref(codeHref func(lang.Location) (string, error)) func(lang.Location) (string, error) {
+	return func(loc lang.Location) (string, error) {
+		if !out.showSection(SectionSourceLinks) {
+			return "", nil
+		}
+
+		return codeHref(loc)
+	}
+}